@@ -2,6 +2,7 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
 	"strings"
 
 	"github.com/AvicennaJr/Nuru/token"
@@ -9,7 +10,8 @@ import (
 
 type Node interface {
 	TokenLiteral() string
-	String() string // to help debug the many errors lmao
+	Pos() token.Position // where the node starts in the source
+	String() string      // to help debug the many errors lmao
 }
 
 type Statement interface {
@@ -34,6 +36,13 @@ func (p *Program) TokenLiteral() string {
 	}
 }
 
+func (p *Program) Pos() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+	return token.Position{}
+}
+
 func (p *Program) String() string {
 	var out bytes.Buffer
 
@@ -45,13 +54,15 @@ func (p *Program) String() string {
 }
 
 type LetStatement struct {
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Token    token.Token
+	Name     *Identifier
+	Value    Expression
+	Constant bool // made with 'thabiti', so the name can't be given another value
 }
 
 func (ls *LetStatement) statementNode()       {}
 func (ls *LetStatement) TokenLiteral() string { return ls.Token.Literal }
+func (ls *LetStatement) Pos() token.Position  { return ls.Token.Position }
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
@@ -67,6 +78,49 @@ func (ls *LetStatement) String() string {
 	return out.String()
 }
 
+// DestructuringStatement gives names to the elements of an array, like
+// 'fanya [a, b] = orodha' or 'fanya a, b = f()', or to the values of a dict
+// with the same keys, like 'fanya {jina, umri} = kamusi'
+type DestructuringStatement struct {
+	Token token.Token // the 'fanya' token
+	Names []*Identifier
+	Dict  bool
+	Bare  bool // written without brackets, like 'fanya x, y = f()'
+	Value Expression
+}
+
+func (ds *DestructuringStatement) statementNode()       {}
+func (ds *DestructuringStatement) TokenLiteral() string { return ds.Token.Literal }
+func (ds *DestructuringStatement) Pos() token.Position  { return ds.Token.Position }
+func (ds *DestructuringStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ds.TokenLiteral() + " ")
+	out.WriteString(ds.Pattern())
+	out.WriteString(" = ")
+	if ds.Value != nil {
+		out.WriteString(ds.Value.String())
+	}
+	out.WriteString(";")
+
+	return out.String()
+}
+
+// Pattern is how the names are written, like '[a, b]'
+func (ds *DestructuringStatement) Pattern() string {
+	names := make([]string, len(ds.Names))
+	for i, name := range ds.Names {
+		names[i] = name.Value
+	}
+	switch {
+	case ds.Dict:
+		return "{" + strings.Join(names, ", ") + "}"
+	case ds.Bare:
+		return strings.Join(names, ", ")
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
 type Identifier struct {
 	Token token.Token
 	Value string
@@ -74,21 +128,30 @@ type Identifier struct {
 
 func (i *Identifier) expressionNode()      {}
 func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
+func (i *Identifier) Pos() token.Position  { return i.Token.Position }
 func (i *Identifier) String() string       { return i.Value }
 
 type ReturnStatement struct {
 	Token       token.Token
 	ReturnValue Expression
+	Multiple    bool // 'rudisha a, b', whose ReturnValue is the array [a, b]
 }
 
 func (rs *ReturnStatement) statementNode()       {}
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
+func (rs *ReturnStatement) Pos() token.Position  { return rs.Token.Position }
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(rs.TokenLiteral() + " ")
 
-	if rs.ReturnValue != nil {
+	if array, ok := rs.ReturnValue.(*ArrayLiteral); ok && rs.Multiple {
+		values := make([]string, len(array.Elements))
+		for i, el := range array.Elements {
+			values[i] = el.String()
+		}
+		out.WriteString(strings.Join(values, ", "))
+	} else if rs.ReturnValue != nil {
 		out.WriteString(rs.ReturnValue.String())
 	}
 	out.WriteString(";")
@@ -102,6 +165,7 @@ type ExpressionStatement struct {
 
 func (es *ExpressionStatement) statementNode()       {}
 func (es *ExpressionStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExpressionStatement) Pos() token.Position  { return es.Token.Position }
 func (es *ExpressionStatement) String() string {
 	if es.Expression != nil {
 		return es.Expression.String()
@@ -117,8 +181,20 @@ type IntegerLiteral struct {
 
 func (il *IntegerLiteral) expressionNode()      {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
+func (il *IntegerLiteral) Pos() token.Position  { return il.Token.Position }
 func (il *IntegerLiteral) String() string       { return il.Token.Literal }
 
+// BigIntegerLiteral is an integer literal too big to fit in an int64
+type BigIntegerLiteral struct {
+	Token token.Token
+	Value *big.Int
+}
+
+func (bl *BigIntegerLiteral) expressionNode()      {}
+func (bl *BigIntegerLiteral) TokenLiteral() string { return bl.Token.Literal }
+func (bl *BigIntegerLiteral) Pos() token.Position  { return bl.Token.Position }
+func (bl *BigIntegerLiteral) String() string       { return bl.Token.Literal }
+
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -127,6 +203,7 @@ type PrefixExpression struct {
 
 func (pe *PrefixExpression) expressionNode()      {}
 func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PrefixExpression) Pos() token.Position  { return pe.Token.Position }
 func (pe *PrefixExpression) String() string {
 	var out bytes.Buffer
 
@@ -147,6 +224,7 @@ type InfixExpression struct {
 
 func (oe *InfixExpression) expressionNode()      {}
 func (oe *InfixExpression) TokenLiteral() string { return oe.Token.Literal }
+func (oe *InfixExpression) Pos() token.Position  { return oe.Token.Position }
 func (oe *InfixExpression) String() string {
 	var out bytes.Buffer
 
@@ -166,6 +244,7 @@ type Boolean struct {
 
 func (b *Boolean) expressionNode()      {}
 func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
+func (b *Boolean) Pos() token.Position  { return b.Token.Position }
 func (b *Boolean) String() string       { return b.Token.Literal }
 
 type IfExpression struct {
@@ -177,6 +256,7 @@ type IfExpression struct {
 
 func (ie *IfExpression) expressionNode()      {}
 func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IfExpression) Pos() token.Position  { return ie.Token.Position }
 func (ie *IfExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("kama")
@@ -192,13 +272,53 @@ func (ie *IfExpression) String() string {
 	return out.String()
 }
 
+// ComparisonChain is two or more comparisons in a row, like 0 < x < 10, which
+// holds when every comparison does. Each operand is worked out once.
+type ComparisonChain struct {
+	Token     token.Token // the first operator
+	Operands  []Expression
+	Operators []string
+}
+
+func (cc *ComparisonChain) expressionNode()      {}
+func (cc *ComparisonChain) TokenLiteral() string { return cc.Token.Literal }
+func (cc *ComparisonChain) Pos() token.Position  { return cc.Token.Position }
+func (cc *ComparisonChain) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(cc.Operands[0].String())
+	for i, op := range cc.Operators {
+		out.WriteString(" " + op + " ")
+		out.WriteString(cc.Operands[i+1].String())
+	}
+	out.WriteString(")")
+	return out.String()
+}
+
+// ConditionalExpression is 'sharti ? thamani1 : thamani2'
+type ConditionalExpression struct {
+	Token       token.Token // the '?'
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (ce *ConditionalExpression) expressionNode()      {}
+func (ce *ConditionalExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *ConditionalExpression) Pos() token.Position  { return ce.Token.Position }
+func (ce *ConditionalExpression) String() string {
+	return "(" + ce.Condition.String() + " ? " + ce.Consequence.String() + " : " + ce.Alternative.String() + ")"
+}
+
 type BlockStatement struct {
 	Token      token.Token
 	Statements []Statement
+	End        token.Position // the closing '}'
 }
 
 func (bs *BlockStatement) statementNode()       {}
 func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) Pos() token.Position  { return bs.Token.Position }
 func (bs *BlockStatement) String() string {
 	var out bytes.Buffer
 
@@ -212,11 +332,15 @@ func (bs *BlockStatement) String() string {
 type FunctionLiteral struct {
 	Token      token.Token
 	Parameters []*Identifier
+	Variadic   bool // the last parameter, written '...hoja', collects the extra arguments
 	Body       *BlockStatement
+	Arrow      bool // written '(x) => x * 2', whose Body is just that expression unless it was given a block
+	Generator  bool // its body uses 'toa', so calling it gives a generator
 }
 
 func (fl *FunctionLiteral) expressionNode()      {}
 func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FunctionLiteral) Pos() token.Position  { return fl.Token.Position }
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
@@ -225,6 +349,19 @@ func (fl *FunctionLiteral) String() string {
 	for _, p := range fl.Parameters {
 		params = append(params, p.String())
 	}
+	if fl.Variadic {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
+
+	if fl.Arrow {
+		out.WriteString("(" + strings.Join(params, ", ") + ") => ")
+		if fl.Body.Token.Type == token.LBRACE {
+			out.WriteString("{ " + fl.Body.String() + " }")
+		} else {
+			out.WriteString(fl.Body.String())
+		}
+		return out.String()
+	}
 
 	out.WriteString(fl.TokenLiteral())
 	out.WriteString("(")
@@ -235,14 +372,33 @@ func (fl *FunctionLiteral) String() string {
 	return out.String()
 }
 
+// DocString is the string the body starts with, which describes the
+// function. A body holding nothing else has none, since that string is what
+// the function gives back.
+func (fl *FunctionLiteral) DocString() string {
+	if fl.Body == nil || len(fl.Body.Statements) < 2 {
+		return ""
+	}
+	stmt, ok := fl.Body.Statements[0].(*ExpressionStatement)
+	if !ok {
+		return ""
+	}
+	if str, ok := stmt.Expression.(*StringLiteral); ok {
+		return strings.TrimSpace(str.Value)
+	}
+	return ""
+}
+
 type CallExpression struct {
 	Token     token.Token
 	Function  Expression // can be Identifier or FunctionLiteral
 	Arguments []Expression
+	Tail      bool // the last thing its function does, so its frame can be reused
 }
 
 func (ce *CallExpression) expressionNode()      {}
 func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CallExpression) Pos() token.Position  { return ce.Token.Position }
 func (ce *CallExpression) String() string {
 	var out bytes.Buffer
 
@@ -259,6 +415,33 @@ func (ce *CallExpression) String() string {
 	return out.String()
 }
 
+// SpreadExpression puts the elements of an array in its place, in an array
+// literal like '[1, ...orodha]' or in the arguments of a call like 'f(...hoja)'
+type SpreadExpression struct {
+	Token token.Token // the '...' token
+	Value Expression
+}
+
+func (se *SpreadExpression) expressionNode()      {}
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadExpression) Pos() token.Position  { return se.Token.Position }
+func (se *SpreadExpression) String() string       { return "..." + se.Value.String() }
+
+// NamedArgument is an argument given to the parameter with its name, like
+// 'upana=10' in 'chora(upana=10)'
+type NamedArgument struct {
+	Token token.Token // the name
+	Name  *Identifier
+	Value Expression
+}
+
+func (na *NamedArgument) expressionNode()      {}
+func (na *NamedArgument) TokenLiteral() string { return na.Token.Literal }
+func (na *NamedArgument) Pos() token.Position  { return na.Token.Position }
+func (na *NamedArgument) String() string {
+	return na.Name.String() + "=" + na.Value.String()
+}
+
 type StringLiteral struct {
 	Token token.Token
 	Value string
@@ -266,8 +449,21 @@ type StringLiteral struct {
 
 func (sl *StringLiteral) expressionNode()      {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *StringLiteral) Pos() token.Position  { return sl.Token.Position }
 func (sl *StringLiteral) String() string       { return sl.Token.Literal }
 
+// TemplateLiteral is a string with ${...} interpolations. Parts holds the
+// text as StringLiterals and the interpolated expressions in source order.
+type TemplateLiteral struct {
+	Token token.Token
+	Parts []Expression
+}
+
+func (tl *TemplateLiteral) expressionNode()      {}
+func (tl *TemplateLiteral) TokenLiteral() string { return tl.Token.Literal }
+func (tl *TemplateLiteral) Pos() token.Position  { return tl.Token.Position }
+func (tl *TemplateLiteral) String() string       { return tl.Token.Literal }
+
 type ArrayLiteral struct {
 	Token    token.Token
 	Elements []Expression
@@ -275,6 +471,7 @@ type ArrayLiteral struct {
 
 func (al *ArrayLiteral) expressionNode()      {}
 func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Literal }
+func (al *ArrayLiteral) Pos() token.Position  { return al.Token.Position }
 func (al *ArrayLiteral) String() string {
 	var out bytes.Buffer
 
@@ -291,18 +488,23 @@ func (al *ArrayLiteral) String() string {
 }
 
 type IndexExpression struct {
-	Token token.Token
-	Left  Expression
-	Index Expression
+	Token    token.Token
+	Left     Expression
+	Index    Expression
+	Optional bool // 'a?[i]', which is tupu when a is
 }
 
 func (ie *IndexExpression) expressionNode()      {}
 func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IndexExpression) Pos() token.Position  { return ie.Token.Position }
 func (ie *IndexExpression) String() string {
 	var out bytes.Buffer
 
 	out.WriteString("(")
 	out.WriteString(ie.Left.String())
+	if ie.Optional {
+		out.WriteString("?")
+	}
 	out.WriteString("[")
 	out.WriteString(ie.Index.String())
 	out.WriteString("])")
@@ -310,13 +512,94 @@ func (ie *IndexExpression) String() string {
 	return out.String()
 }
 
+// SliceExpression is 'a[start:stop:step]', where any of the three may be left
+// out and is then nil
+type SliceExpression struct {
+	Token    token.Token
+	Left     Expression
+	Start    Expression
+	Stop     Expression
+	Step     Expression
+	Optional bool // 'a?[1:]', which is tupu when a is
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SliceExpression) Pos() token.Position  { return se.Token.Position }
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	if se.Optional {
+		out.WriteString("?")
+	}
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.Stop != nil {
+		out.WriteString(se.Stop.String())
+	}
+	if se.Step != nil {
+		out.WriteString(":")
+		out.WriteString(se.Step.String())
+	}
+	out.WriteString("])")
+
+	return out.String()
+}
+
+// Comprehension is '[x * 2 kwa x ktk orodha kama x > 0]', or for a dict
+// '{k: v * 2 kwa k, v ktk kamusi}', which has a Key as well
+type Comprehension struct {
+	Token     token.Token // the '[' or '{'
+	Key       Expression
+	Value     Expression
+	LoopKey   string
+	LoopValue string
+	Iterable  Expression
+	Condition Expression // nil without 'kama'
+}
+
+func (c *Comprehension) expressionNode()      {}
+func (c *Comprehension) TokenLiteral() string { return c.Token.Literal }
+func (c *Comprehension) Pos() token.Position  { return c.Token.Position }
+func (c *Comprehension) String() string {
+	var out bytes.Buffer
+
+	if c.Key != nil {
+		out.WriteString("{" + c.Key.String() + ": ")
+	} else {
+		out.WriteString("[")
+	}
+	out.WriteString(c.Value.String() + " kwa ")
+	if c.LoopKey != "" {
+		out.WriteString(c.LoopKey + ", ")
+	}
+	out.WriteString(c.LoopValue + " ktk " + c.Iterable.String())
+	if c.Condition != nil {
+		out.WriteString(" kama " + c.Condition.String())
+	}
+	if c.Key != nil {
+		out.WriteString("}")
+	} else {
+		out.WriteString("]")
+	}
+
+	return out.String()
+}
+
 type DictLiteral struct {
 	Token token.Token
 	Pairs map[Expression]Expression
+	Keys  []Expression // the keys of Pairs in the order they were written
 }
 
 func (dl *DictLiteral) expressionNode()      {}
 func (dl *DictLiteral) TokenLiteral() string { return dl.Token.Literal }
+func (dl *DictLiteral) Pos() token.Position  { return dl.Token.Position }
 func (dl *DictLiteral) String() string {
 	var out bytes.Buffer
 	pairs := []string{}
@@ -339,6 +622,7 @@ type AssignmentExpression struct {
 
 func (ae *AssignmentExpression) expressionNode()      {}
 func (ae *AssignmentExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignmentExpression) Pos() token.Position  { return ae.Token.Position }
 func (ae *AssignmentExpression) String() string {
 	var out bytes.Buffer
 
@@ -357,6 +641,7 @@ type WhileExpression struct {
 
 func (we *WhileExpression) expressionNode()      {}
 func (we *WhileExpression) TokenLiteral() string { return we.Token.Literal }
+func (we *WhileExpression) Pos() token.Position  { return we.Token.Position }
 func (we *WhileExpression) String() string {
 	var out bytes.Buffer
 
@@ -368,12 +653,35 @@ func (we *WhileExpression) String() string {
 	return out.String()
 }
 
+// DoWhileExpression is 'fanya { ... } wakati (masharti)', which runs its
+// body once before checking the condition
+type DoWhileExpression struct {
+	Token       token.Token // the 'fanya' token
+	Consequence *BlockStatement
+	Condition   Expression
+}
+
+func (dw *DoWhileExpression) expressionNode()      {}
+func (dw *DoWhileExpression) TokenLiteral() string { return dw.Token.Literal }
+func (dw *DoWhileExpression) Pos() token.Position  { return dw.Token.Position }
+func (dw *DoWhileExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("fanya ")
+	out.WriteString(dw.Consequence.String())
+	out.WriteString(" wakati")
+	out.WriteString(dw.Condition.String())
+
+	return out.String()
+}
+
 type Null struct {
 	Token token.Token
 }
 
 func (n *Null) expressionNode()      {}
 func (n *Null) TokenLiteral() string { return n.Token.Literal }
+func (n *Null) Pos() token.Position  { return n.Token.Position }
 func (n *Null) String() string       { return n.Token.Literal }
 
 type Break struct {
@@ -383,6 +691,7 @@ type Break struct {
 
 func (b *Break) expressionNode()      {}
 func (b *Break) TokenLiteral() string { return b.Token.Literal }
+func (b *Break) Pos() token.Position  { return b.Token.Position }
 func (b *Break) String() string       { return b.Token.Literal }
 
 type Continue struct {
@@ -392,6 +701,7 @@ type Continue struct {
 
 func (c *Continue) expressionNode()      {}
 func (c *Continue) TokenLiteral() string { return c.Token.Literal }
+func (c *Continue) Pos() token.Position  { return c.Token.Position }
 func (c *Continue) String() string       { return c.Token.Literal }
 
 type PostfixExpression struct {
@@ -401,6 +711,7 @@ type PostfixExpression struct {
 
 func (pe *PostfixExpression) expressionNode()      {}
 func (pe *PostfixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PostfixExpression) Pos() token.Position  { return pe.Token.Position }
 func (pe *PostfixExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("(")
@@ -417,6 +728,7 @@ type FloatLiteral struct {
 
 func (fl *FloatLiteral) expressionNode()      {}
 func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) Pos() token.Position  { return fl.Token.Position }
 func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
 
 type For struct {
@@ -441,6 +753,7 @@ type ForIn struct {
 
 func (fi *ForIn) expressionNode()      {}
 func (fi *ForIn) TokenLiteral() string { return fi.Token.Literal }
+func (fi *ForIn) Pos() token.Position  { return fi.Token.Position }
 func (fi *ForIn) String() string {
 	var out bytes.Buffer
 
@@ -466,6 +779,7 @@ type CaseExpression struct {
 
 func (ce *CaseExpression) expressionNode()      {}
 func (ce *CaseExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CaseExpression) Pos() token.Position  { return ce.Token.Position }
 func (ce *CaseExpression) String() string {
 	var out bytes.Buffer
 
@@ -488,10 +802,12 @@ type SwitchExpression struct {
 	Token   token.Token
 	Value   Expression
 	Choices []*CaseExpression
+	End     token.Position // the closing '}'
 }
 
 func (se *SwitchExpression) expressionNode()      {}
 func (se *SwitchExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SwitchExpression) Pos() token.Position  { return se.Token.Position }
 func (se *SwitchExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("\nbadili (")
@@ -507,3 +823,227 @@ func (se *SwitchExpression) String() string {
 
 	return out.String()
 }
+
+// MatchExpression is a 'linganisha', which runs the body of the first arm
+// whose pattern fits its value
+type MatchExpression struct {
+	Token token.Token // the 'linganisha' token
+	Value Expression
+	Arms  []*MatchArm
+	End   token.Position // the closing '}'
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) Pos() token.Position  { return me.Token.Position }
+func (me *MatchExpression) String() string {
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+	return "linganisha (" + me.Value.String() + ") { " + strings.Join(arms, ", ") + " }"
+}
+
+// MatchArm is a pattern of a 'linganisha' with the body run when it fits.
+// A pattern is a literal, a name that takes whatever it meets, '_' which
+// fits anything, or an array or dict of patterns. Arrays may end with
+// '...jina' to take the elements that are left.
+type MatchArm struct {
+	Pattern Expression
+	Guard   Expression // the condition after 'kama', if any
+	Body    *BlockStatement
+}
+
+// Names gives the names the pattern takes a value for, in the order they
+// are matched. '_' is left out.
+func (ma *MatchArm) Names() []*Identifier {
+	var names []*Identifier
+	var walk func(pattern Expression)
+	walk = func(pattern Expression) {
+		switch pattern := pattern.(type) {
+		case *Identifier:
+			if pattern.Value != "_" {
+				names = append(names, pattern)
+			}
+		case *ArrayLiteral:
+			for _, element := range pattern.Elements {
+				walk(element)
+			}
+		case *SpreadExpression:
+			walk(pattern.Value)
+		case *DictLiteral:
+			for _, key := range pattern.Keys {
+				walk(pattern.Pairs[key])
+			}
+		}
+	}
+	walk(ma.Pattern)
+	return names
+}
+
+func (ma *MatchArm) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" kama (" + ma.Guard.String() + ")")
+	}
+	out.WriteString(" => ")
+	if ma.Body.Token.Type == token.LBRACE {
+		out.WriteString("{ " + ma.Body.String() + " }")
+	} else {
+		out.WriteString(ma.Body.String())
+	}
+
+	return out.String()
+}
+
+type TryExpression struct {
+	Token      token.Token // the 'jaribu' token
+	Block      *BlockStatement
+	Identifier *Identifier // optional, holds the caught error
+	Catch      *BlockStatement
+}
+
+func (te *TryExpression) expressionNode()      {}
+func (te *TryExpression) TokenLiteral() string { return te.Token.Literal }
+func (te *TryExpression) Pos() token.Position  { return te.Token.Position }
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("jaribu ")
+	out.WriteString(te.Block.String())
+	out.WriteString(" shika ")
+	if te.Identifier != nil {
+		out.WriteString("(" + te.Identifier.String() + ") ")
+	}
+	out.WriteString(te.Catch.String())
+
+	return out.String()
+}
+
+type ThrowStatement struct {
+	Token token.Token // the 'tupa' token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *ThrowStatement) Pos() token.Position  { return ts.Token.Position }
+func (ts *ThrowStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ts.TokenLiteral() + " ")
+	if ts.Value != nil {
+		out.WriteString(ts.Value.String())
+	}
+	out.WriteString(";")
+
+	return out.String()
+}
+
+type ImportStatement struct {
+	Token token.Token // the 'tumia' token
+	Name  *Identifier // the name the module will be bound to
+	Path  string
+}
+
+func (is *ImportStatement) statementNode()       {}
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImportStatement) Pos() token.Position  { return is.Token.Position }
+func (is *ImportStatement) String() string {
+	return is.TokenLiteral() + " \"" + is.Path + "\";"
+}
+
+type PropertyExpression struct {
+	Token    token.Token // the '.' token
+	Object   Expression
+	Property *Identifier
+}
+
+func (pe *PropertyExpression) expressionNode()      {}
+func (pe *PropertyExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PropertyExpression) Pos() token.Position  { return pe.Token.Position }
+func (pe *PropertyExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(pe.Object.String())
+	out.WriteString(".")
+	out.WriteString(pe.Property.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// Method is a named function declared inside a 'muundo'
+type Method struct {
+	Name     *Identifier
+	Function *FunctionLiteral
+}
+
+type ClassStatement struct {
+	Token       token.Token // the 'muundo' token
+	Name        *Identifier
+	Fields      []*LetStatement
+	Constructor *FunctionLiteral // the 'unda' method, if any
+	Methods     []*Method
+	End         token.Position // the closing '}'
+}
+
+func (cs *ClassStatement) statementNode()       {}
+func (cs *ClassStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ClassStatement) Pos() token.Position  { return cs.Token.Position }
+func (cs *ClassStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("muundo ")
+	out.WriteString(cs.Name.String())
+	out.WriteString(" {")
+	for _, f := range cs.Fields {
+		out.WriteString(f.String())
+	}
+	if cs.Constructor != nil {
+		out.WriteString(cs.Constructor.String())
+	}
+	for _, m := range cs.Methods {
+		out.WriteString(m.Name.String())
+		out.WriteString(m.Function.String())
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+type ThisExpression struct {
+	Token token.Token // the 'hii' token
+}
+
+func (te *ThisExpression) expressionNode()      {}
+func (te *ThisExpression) TokenLiteral() string { return te.Token.Literal }
+func (te *ThisExpression) Pos() token.Position  { return te.Token.Position }
+func (te *ThisExpression) String() string       { return "hii" }
+
+// SpawnExpression is a call made with 'sambamba', which runs while the code
+// after it carries on
+type SpawnExpression struct {
+	Token token.Token // the 'sambamba' token
+	Call  *CallExpression
+}
+
+func (se *SpawnExpression) expressionNode()      {}
+func (se *SpawnExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpawnExpression) Pos() token.Position  { return se.Token.Position }
+func (se *SpawnExpression) String() string       { return "sambamba " + se.Call.String() }
+
+// YieldExpression hands a value to whoever is looping over the generator
+// made by the function it is in, and waits there until the next value is wanted
+type YieldExpression struct {
+	Token token.Token // the 'toa' token
+	Value Expression
+}
+
+func (ye *YieldExpression) expressionNode()      {}
+func (ye *YieldExpression) TokenLiteral() string { return ye.Token.Literal }
+func (ye *YieldExpression) Pos() token.Position  { return ye.Token.Position }
+func (ye *YieldExpression) String() string       { return "toa " + ye.Value.String() }
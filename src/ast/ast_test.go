@@ -27,3 +27,58 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. got=%q", program.String())
 	}
 }
+
+func TestDump(t *testing.T) {
+	at := func(line, column int) token.Token {
+		return token.Token{Position: token.Position{Line: line, Column: column}}
+	}
+	key := &StringLiteral{Token: at(1, 12), Value: "a"}
+	program := &Program{
+		Statements: []Statement{
+			&LetStatement{
+				Token: at(1, 1),
+				Name:  &Identifier{Token: at(1, 7), Value: "d"},
+				Value: &DictLiteral{
+					Token: at(1, 11),
+					Pairs: map[Expression]Expression{key: &ArrayLiteral{Token: at(1, 17)}},
+					Keys:  []Expression{key},
+				},
+			},
+			&ExpressionStatement{Token: at(2, 1), Expression: &PostfixExpression{Token: token.Token{Literal: "i", Position: token.Position{Line: 2, Column: 1}}, Operator: "++"}},
+		},
+	}
+
+	expected := `Program 1:1
+  Statements[0]: LetStatement 1:1 Constant=false
+    Name: Identifier 1:7 Value="d"
+    Value: DictLiteral 1:11
+      Pairs[0]: Pair 1:12
+        Key: StringLiteral 1:12 Value="a"
+        Value: ArrayLiteral 1:17
+  Statements[1]: ExpressionStatement 2:1
+    Expression: PostfixExpression 2:1 Name="i" Operator="++"
+`
+	if got := Dump(program); got != expected {
+		t.Errorf("Dump wrong.\nwant=%q\ngot= %q", expected, got)
+	}
+
+	data, err := DumpJSON(program.Statements[1])
+	if err != nil {
+		t.Fatalf("DumpJSON failed: %s", err)
+	}
+	expectedJSON := `{
+  "type": "ExpressionStatement",
+  "line": 2,
+  "column": 1,
+  "Expression": {
+    "type": "PostfixExpression",
+    "line": 2,
+    "column": 1,
+    "Name": "i",
+    "Operator": "++"
+  }
+}`
+	if string(data) != expectedJSON {
+		t.Errorf("DumpJSON wrong.\nwant=%s\ngot= %s", expectedJSON, data)
+	}
+}
@@ -0,0 +1,164 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+// Dump shows node and everything in it as an indented tree, one node per line
+// along with where it starts, which makes it easy to see how code was parsed:
+//
+//	LetStatement 1:1 Constant=false
+//	  Name: Identifier 1:7 Value="x"
+//	  Value: IntegerLiteral 1:11 Value=5
+func Dump(node Node) string {
+	var out bytes.Buffer
+	dumpTree(&out, "", toDumped(reflect.ValueOf(node)), 0)
+	return out.String()
+}
+
+// DumpJSON is like Dump, but gives the tree as JSON for other tools to read.
+// Every node is an object with its "type", "line" and "column", and a key for
+// each of its fields.
+func DumpJSON(node Node) ([]byte, error) {
+	return json.MarshalIndent(toDumped(reflect.ValueOf(node)), "", "  ")
+}
+
+// dumped is a node made ready to be shown. The value of a field is a plain
+// Go value, a *dumped or a []*dumped.
+type dumped struct {
+	kind   string
+	pos    token.Position
+	fields []dumpedField
+}
+
+type dumpedField struct {
+	name  string
+	value interface{}
+}
+
+var (
+	nodeType     = reflect.TypeOf((*Node)(nil)).Elem()
+	tokenType    = reflect.TypeOf(token.Token{})
+	positionType = reflect.TypeOf(token.Position{})
+	bigIntType   = reflect.TypeOf(&big.Int{})
+)
+
+// toDumped turns the node in v into a *dumped, going through its fields with
+// reflection so that every kind of node is covered
+func toDumped(v reflect.Value) *dumped {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.IsNil() {
+		return nil
+	}
+
+	d := &dumped{kind: v.Elem().Type().Name()}
+	if node, ok := v.Interface().(Node); ok {
+		d.pos = node.Pos()
+	}
+
+	s := v.Elem()
+	for i := 0; i < s.NumField(); i++ {
+		name, f := s.Type().Field(i).Name, s.Field(i)
+		switch {
+		case f.Type() == tokenType:
+			// the postfix operator is the only node whose token holds its operand
+			if _, ok := v.Interface().(*PostfixExpression); ok {
+				d.fields = append(d.fields, dumpedField{"Name", f.Interface().(token.Token).Literal})
+			}
+		case f.Type() == positionType:
+		case f.Type() == bigIntType:
+			d.fields = append(d.fields, dumpedField{name, f.Interface().(*big.Int).String()})
+		case f.Kind() == reflect.Map:
+			// a dict's pairs are shown in the order of its keys instead
+		case name == "Keys":
+			dict := v.Interface().(*DictLiteral)
+			var pairs []*dumped
+			for _, key := range dict.Keys {
+				pairs = append(pairs, &dumped{
+					kind:   "Pair",
+					pos:    key.Pos(),
+					fields: []dumpedField{{"Key", toDumped(reflect.ValueOf(key))}, {"Value", toDumped(reflect.ValueOf(dict.Pairs[key]))}},
+				})
+			}
+			d.fields = append(d.fields, dumpedField{"Pairs", pairs})
+		case f.Kind() == reflect.Slice:
+			var items []*dumped
+			for j := 0; j < f.Len(); j++ {
+				items = append(items, toDumped(f.Index(j)))
+			}
+			d.fields = append(d.fields, dumpedField{name, items})
+		case f.Kind() == reflect.Ptr || f.Kind() == reflect.Interface:
+			if child := toDumped(f); child != nil {
+				d.fields = append(d.fields, dumpedField{name, child})
+			}
+		default:
+			d.fields = append(d.fields, dumpedField{name, f.Interface()})
+		}
+	}
+	return d
+}
+
+func dumpTree(out *bytes.Buffer, label string, d *dumped, depth int) {
+	indent := strings.Repeat("  ", depth)
+	if d == nil {
+		fmt.Fprintf(out, "%s%s<nil>\n", indent, label)
+		return
+	}
+
+	out.WriteString(indent + label + d.kind)
+	if d.pos.Line != 0 {
+		fmt.Fprintf(out, " %d:%d", d.pos.Line, d.pos.Column)
+	}
+	for _, f := range d.fields {
+		switch f.value.(type) {
+		case *dumped, []*dumped:
+		case string:
+			fmt.Fprintf(out, " %s=%q", f.name, f.value)
+		default:
+			fmt.Fprintf(out, " %s=%v", f.name, f.value)
+		}
+	}
+	out.WriteString("\n")
+
+	for _, f := range d.fields {
+		switch value := f.value.(type) {
+		case *dumped:
+			dumpTree(out, f.name+": ", value, depth+1)
+		case []*dumped:
+			for i, item := range value {
+				dumpTree(out, fmt.Sprintf("%s[%d]: ", f.name, i), item, depth+1)
+			}
+		}
+	}
+}
+
+func (d *dumped) MarshalJSON() ([]byte, error) {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, `{"type":%q`, d.kind)
+	if d.pos.Line != 0 {
+		fmt.Fprintf(&out, `,"line":%d,"column":%d`, d.pos.Line, d.pos.Column)
+	}
+	for _, f := range d.fields {
+		value := f.value
+		// an empty list is [] rather than null
+		if items, ok := value.([]*dumped); ok && items == nil {
+			value = []*dumped{}
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&out, `,%q:%s`, f.name, data)
+	}
+	out.WriteString("}")
+	return out.Bytes(), nil
+}
@@ -0,0 +1,169 @@
+// Package bundle turns a Nuru program into a single executable, the way
+// 'nuru jenga' does. The program, along with the Nuru files in its folder
+// that it may import, is zipped and added to the end of a copy of the
+// interpreter. When that copy starts, it finds the zip and runs the program
+// in it instead of behaving like nuru.
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// magic ends every executable holding a program, right after the size of the
+// zip that comes before it
+const magic = "NURUJENGA"
+
+const trailerSize = 8 + len(magic)
+
+// Bundle is a program found in an executable
+type Bundle struct {
+	Main  string // the path of the program in Files
+	Files fs.FS
+}
+
+// Build writes to out a copy of the interpreter exe that runs the program in
+// script. Any program already held by exe is left out of the copy.
+func Build(exe, script, out string) error {
+	interpreter, err := os.ReadFile(exe)
+	if err != nil {
+		return fmt.Errorf("Nimeshindwa kusoma %s", exe)
+	}
+	if size, ok := payloadSize(interpreter); ok && size <= uint64(len(interpreter)-trailerSize) {
+		interpreter = interpreter[:len(interpreter)-trailerSize-int(size)]
+	}
+
+	payload, err := pack(script)
+	if err != nil {
+		return err
+	}
+
+	var trailer [trailerSize]byte
+	binary.LittleEndian.PutUint64(trailer[:8], uint64(len(payload)))
+	copy(trailer[8:], magic)
+
+	data := append(interpreter, payload...)
+	data = append(data, trailer[:]...)
+	if err := os.WriteFile(out, data, 0755); err != nil {
+		return fmt.Errorf("Nimeshindwa kuandika %s", out)
+	}
+	return nil
+}
+
+// pack zips script and the Nuru files in its folder and the folders below it,
+// leaving out tests and hidden folders. The name of the script is kept as the
+// comment of the zip.
+func pack(script string) ([]byte, error) {
+	root := filepath.Dir(script)
+	main := filepath.Base(script)
+	if info, err := os.Stat(script); err != nil || info.IsDir() {
+		return nil, fmt.Errorf("Faili %q halipatikani", script)
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	err := filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if name != "." && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(name); ext != ".nr" && ext != ".sw" {
+			return nil
+		}
+		if strings.HasSuffix(name, "_jaribu.nr") && name != main {
+			return nil
+		}
+
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		f, err := w.Create(filepath.ToSlash(name))
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(contents)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Nimeshindwa kukusanya mafaili ya %s: %s", root, err)
+	}
+
+	if err := w.SetComment(main); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Open looks for a program at the end of the executable exe. It returns nil
+// if there isn't one.
+func Open(exe string) (*Bundle, error) {
+	f, err := os.Open(exe)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	end := info.Size()
+	if end < int64(trailerSize) {
+		f.Close()
+		return nil, nil
+	}
+
+	var trailer [trailerSize]byte
+	if _, err := f.ReadAt(trailer[:], end-int64(trailerSize)); err != nil {
+		f.Close()
+		return nil, err
+	}
+	size, ok := payloadSize(trailer[:])
+	if !ok {
+		f.Close()
+		return nil, nil
+	}
+	if size > uint64(end)-uint64(trailerSize) {
+		f.Close()
+		return nil, errors.New("Programu iliyo ndani ya faili hili imeharibika")
+	}
+
+	// the file stays open for as long as the program runs, since its
+	// modules are read from it when they are imported
+	zipped := io.NewSectionReader(f, end-int64(trailerSize)-int64(size), int64(size))
+	r, err := zip.NewReader(zipped, int64(size))
+	if err != nil {
+		f.Close()
+		return nil, errors.New("Programu iliyo ndani ya faili hili imeharibika")
+	}
+	return &Bundle{Main: r.Comment, Files: r}, nil
+}
+
+// payloadSize reads the trailer at the end of data, reporting the size of
+// the zip before it, if there is one
+func payloadSize(data []byte) (uint64, bool) {
+	if len(data) < trailerSize || string(data[len(data)-len(magic):]) != magic {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(data[len(data)-trailerSize:]), true
+}
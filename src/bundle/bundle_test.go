@@ -0,0 +1,92 @@
+package bundle
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"interpreter":                      "not really an interpreter",
+		"mradi/kuu.nr":                     "tumia hesabu",
+		"mradi/lib/msaada.nr":              "fanya x = 1",
+		"mradi/vifurushi/hesabu/hesabu.nr": "fanya y = 2",
+		"mradi/kuu_jaribu.nr":              "fanya jaribu_kuu = unda() {}",
+		"mradi/maelezo.txt":                "not Nuru",
+		"mradi/.git/config.nr":             "hidden",
+	})
+
+	exe, out := filepath.Join(dir, "interpreter"), filepath.Join(dir, "kuu")
+	if err := Build(exe, filepath.Join(dir, "mradi", "kuu.nr"), out); err != nil {
+		t.Fatalf("Build failed: %s", err)
+	}
+
+	b, err := Open(out)
+	if err != nil || b == nil {
+		t.Fatalf("no program found, err=%v", err)
+	}
+	if b.Main != "kuu.nr" {
+		t.Errorf("wrong main file. got=%q", b.Main)
+	}
+
+	var names []string
+	fs.WalkDir(b.Files, ".", func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			names = append(names, path)
+		}
+		return err
+	})
+	expected := []string{"kuu.nr", "lib/msaada.nr", "vifurushi/hesabu/hesabu.nr"}
+	if len(names) != len(expected) {
+		t.Fatalf("wrong files. want=%v, got=%v", expected, names)
+	}
+	for i, name := range expected {
+		if names[i] != name {
+			t.Errorf("wrong files. want=%v, got=%v", expected, names)
+			break
+		}
+	}
+	if contents, _ := fs.ReadFile(b.Files, "kuu.nr"); string(contents) != "tumia hesabu" {
+		t.Errorf("wrong contents for kuu.nr. got=%q", contents)
+	}
+
+	// building from a built program replaces its program instead of adding another
+	again := filepath.Join(dir, "tena")
+	if err := Build(out, filepath.Join(dir, "mradi", "lib", "msaada.nr"), again); err != nil {
+		t.Fatalf("Build failed: %s", err)
+	}
+	data, _ := os.ReadFile(again)
+	size, _ := payloadSize(data)
+	if prefix := string(data[:len(data)-trailerSize-int(size)]); prefix != "not really an interpreter" {
+		t.Errorf("the old program was kept. got=%q", prefix)
+	}
+	if b, _ := Open(again); b == nil || b.Main != "msaada.nr" {
+		t.Errorf("wrong program after building again")
+	}
+}
+
+func TestOpenPlainFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "nuru")
+	if err := os.WriteFile(file, []byte("just an interpreter"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := Open(file); b != nil || err != nil {
+		t.Errorf("expected no program, got=%v, %v", b, err)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+// runAst is 'nuru ast', which shows how a file was parsed, as an indented
+// tree or, with -json, as JSON
+func runAst(args []string) int {
+	flags := flag.NewFlagSet("ast", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "onyesha mti kama JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Tumia: nuru ast [-json] faili.nr")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	file := flags.Arg(0)
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Nimeshindwa kusoma %s\n", file)
+		return 1
+	}
+
+	p := parser.New(lexer.NewFile(file, string(contents)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		fmt.Fprintf(os.Stderr, "%s ina makosa:\n", file)
+		for _, msg := range p.Errors() {
+			fmt.Fprintln(os.Stderr, "\t"+msg)
+		}
+		return 1
+	}
+
+	if !*asJSON {
+		fmt.Print(ast.Dump(program))
+		return 0
+	}
+	data, err := ast.DumpJSON(program)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(string(data))
+	return 0
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AvicennaJr/Nuru/debugger"
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+// runDebug is 'nuru debug', which runs a file under the debugger. The program
+// stops before its first statement, waiting for commands.
+func runDebug(args []string) int {
+	flags := flag.NewFlagSet("debug", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Tumia: nuru debug faili.nr")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	file := flags.Arg(0)
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Nimeshindwa kusoma %s\n", file)
+		return 1
+	}
+
+	p := parser.New(lexer.NewFile(file, string(contents)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		fmt.Fprintf(os.Stderr, "%s ina makosa:\n", file)
+		for _, msg := range p.Errors() {
+			fmt.Fprintln(os.Stderr, "\t"+msg)
+		}
+		return 1
+	}
+
+	evaluator.ModulePaths = append([]string{filepath.Dir(file)}, evaluator.ModulePaths...)
+	fmt.Println("Andika 'h' kupata msaada")
+	evaluator.SetDebugger(debugger.New(file, os.Stdin, os.Stdout))
+
+	result := evaluator.Eval(program, object.NewEnvironment())
+	evaluator.SetDebugger(nil)
+	if err, ok := result.(*object.Error); ok {
+		fmt.Println(err.Inspect())
+		return 1
+	}
+	fmt.Println("Programu imeisha")
+	return 0
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/doc"
+)
+
+// runDoc is 'nuru doc', which shows the documentation written in the comments
+// of Nuru files as Markdown, or as HTML with -html. With -o every file gets a
+// page of its own in the given folder.
+func runDoc(args []string) int {
+	flags := flag.NewFlagSet("doc", flag.ContinueOnError)
+	asHTML := flags.Bool("html", false, "tengeneza HTML badala ya Markdown")
+	outDir := flags.String("o", "", "andika kila ukurasa kwenye folda hii")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Tumia: nuru doc [-html] [-o folda] faili.nr ...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	files, err := nuruFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	render, ext := doc.Markdown, ".md"
+	if *asHTML {
+		render, ext = doc.HTML, ".html"
+	}
+
+	status := 0
+	for i, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Nimeshindwa kusoma %s\n", file)
+			status = 1
+			continue
+		}
+
+		page, errs := doc.Extract(file, string(contents))
+		if len(errs) != 0 {
+			fmt.Fprintf(os.Stderr, "%s ina makosa:\n", file)
+			for _, msg := range errs {
+				fmt.Fprintln(os.Stderr, "\t"+msg)
+			}
+			status = 1
+			continue
+		}
+
+		if *outDir == "" {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Print(render(page))
+			continue
+		}
+
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)) + ext
+		if err := os.MkdirAll(*outDir, 0755); err == nil {
+			err = os.WriteFile(filepath.Join(*outDir, name), []byte(render(page)), 0644)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Nimeshindwa kuandika %s\n", filepath.Join(*outDir, name))
+			status = 1
+		}
+	}
+	return status
+}
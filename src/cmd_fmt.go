@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AvicennaJr/Nuru/formatter"
+)
+
+// runFmt is 'nuru fmt', which prints Nuru files in the standard style. With
+// -w the files are rewritten in place, and with -c nothing is changed but the
+// files that need formatting are listed and the exit code is 1.
+func runFmt(args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	write := flags.Bool("w", false, "andika matokeo kwenye faili lenyewe")
+	check := flags.Bool("c", false, "orodhesha mafaili yasiyopangwa bila kuyabadilisha")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Tumia: nuru fmt [-w | -c] faili.nr ...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	files, err := nuruFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	status := 0
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Nimeshindwa kusoma %s\n", file)
+			status = 1
+			continue
+		}
+
+		formatted, errs := formatter.Format(string(contents))
+		if len(errs) != 0 {
+			fmt.Fprintf(os.Stderr, "%s ina makosa:\n", file)
+			for _, msg := range errs {
+				fmt.Fprintln(os.Stderr, "\t"+msg)
+			}
+			status = 1
+			continue
+		}
+
+		switch {
+		case *check:
+			if formatted != string(contents) {
+				fmt.Println(file)
+				status = 1
+			}
+		case *write:
+			if formatted != string(contents) {
+				if err := os.WriteFile(file, []byte(formatted), 0644); err != nil {
+					fmt.Fprintf(os.Stderr, "Nimeshindwa kuandika %s\n", file)
+					status = 1
+				}
+			}
+		default:
+			fmt.Print(formatted)
+		}
+	}
+	return status
+}
+
+// nuruFiles expands the directories in paths into the .nr and .sw files inside them
+func nuruFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("Faili %q halipatikani", path)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if ext := filepath.Ext(file); !info.IsDir() && (ext == ".nr" || ext == ".sw") {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/parser"
+	"github.com/AvicennaJr/Nuru/tester"
+)
+
+// testSuffix ends the name of every test file
+const testSuffix = "_jaribu.nr"
+
+// runJaribu is 'nuru jaribu', which runs the tests in the given test files,
+// or in the test files under the current directory. The exit code is 1 if
+// any test fails.
+func runJaribu(args []string) int {
+	flags := flag.NewFlagSet("jaribu", flag.ContinueOnError)
+	verbose := flags.Bool("v", false, "onyesha kila jaribio, hata lililopita")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Tumia: nuru jaribu [-v] [faili"+testSuffix+" | folda ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := testFiles(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(os.Stderr, "Hakuna mafaili ya majaribio ("+testSuffix+")")
+		return 1
+	}
+
+	modulePaths := evaluator.ModulePaths
+	passed, failed := 0, 0
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Nimeshindwa kusoma %s\n", file)
+			failed++
+			continue
+		}
+
+		p := parser.New(lexer.NewFile(file, string(contents)))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			fmt.Fprintf(os.Stderr, "%s ina makosa:\n", file)
+			for _, msg := range p.Errors() {
+				fmt.Fprintln(os.Stderr, "\t"+msg)
+			}
+			failed++
+			continue
+		}
+
+		// modules imported by the tests are looked up next to them first
+		evaluator.ModulePaths = append([]string{filepath.Dir(file)}, modulePaths...)
+
+		fileFailed := false
+		for _, result := range tester.Run(program) {
+			duration := fmt.Sprintf("(%.2fs)", result.Duration.Seconds())
+			if result.Err != nil {
+				fmt.Printf("--- IMESHINDWA: %s %s\n", result.Name, duration)
+				fmt.Println("    " + strings.ReplaceAll(result.Err.Inspect(), "\n", "\n    "))
+				fileFailed = true
+				failed++
+				continue
+			}
+			if *verbose {
+				fmt.Printf("--- IMEPITA: %s %s\n", result.Name, duration)
+			}
+			passed++
+		}
+
+		status := "sawa"
+		if fileFailed {
+			status = "IMESHINDWA"
+		}
+		fmt.Printf("%-10s %s\n", status, file)
+	}
+	evaluator.ModulePaths = modulePaths
+
+	fmt.Printf("\nMajaribio %d: %d yamepita, %d yameshindwa\n", passed+failed, passed, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// testFiles finds the test files in the directories in paths. Files named in
+// paths are run whatever they are called.
+func testFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("Faili %q halipatikani", path)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		found, err := nuruFiles([]string{path})
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range found {
+			if strings.HasSuffix(file, testSuffix) {
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
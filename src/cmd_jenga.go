@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/bundle"
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/repl"
+)
+
+// runJenga is 'nuru jenga', which turns a program into an executable that
+// runs without nuru being installed
+func runJenga(args []string) int {
+	flags := flag.NewFlagSet("jenga", flag.ContinueOnError)
+	out := flags.String("o", "", "jina la faili la kuandika")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Tumia: nuru jenga [-o jina] faili.nr")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	script := flags.Arg(0)
+	if *out == "" {
+		*out = strings.TrimSuffix(filepath.Base(script), filepath.Ext(script))
+		if runtime.GOOS == "windows" {
+			*out += ".exe"
+		}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Nimeshindwa kupata faili la nuru")
+		return 1
+	}
+	if err := bundle.Build(exe, script, *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("Imejengwa %s\n", *out)
+	return 0
+}
+
+// runBundle runs the program held by this executable, if it was built with
+// 'nuru jenga', and reports whether there was one
+func runBundle() bool {
+	exe, err := os.Executable()
+	if err != nil {
+		return false
+	}
+	b, err := bundle.Open(exe)
+	if err != nil {
+		fmt.Printf("\x1b[%dm%s%s\x1b[0m\n", 31, "Error: ", err)
+		os.Exit(1)
+	}
+	if b == nil {
+		return false
+	}
+
+	contents, err := fs.ReadFile(b.Files, b.Main)
+	if err != nil {
+		fmt.Printf("\x1b[%dm%s%s\x1b[0m\n", 31, "Error: Nimeshindwa kusoma file: ", b.Main)
+		os.Exit(1)
+	}
+	evaluator.ModuleFS = b.Files
+	evaluator.SetArgs(os.Args[0], os.Args[1:])
+	repl.Read(b.Main, string(contents))
+	return true
+}
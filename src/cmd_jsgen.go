@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/AvicennaJr/Nuru/jsgen"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+// runJs is 'nuru js', which turns a program into JavaScript
+func runJs(args []string) int {
+	flags := flag.NewFlagSet("js", flag.ContinueOnError)
+	out := flags.String("o", "", "andika JavaScript kwenye faili hili badala ya kuionyesha")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Tumia: nuru js [-o faili.js] faili.nr")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	file := flags.Arg(0)
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Nimeshindwa kusoma %s\n", file)
+		return 1
+	}
+
+	p := parser.New(lexer.NewFile(file, string(contents)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		fmt.Fprintf(os.Stderr, "%s ina makosa:\n", file)
+		for _, msg := range p.Errors() {
+			fmt.Fprintln(os.Stderr, "\t"+msg)
+		}
+		return 1
+	}
+
+	js, err := jsgen.Generate(program)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *out == "" {
+		fmt.Print(js)
+		return 0
+	}
+	if err := os.WriteFile(*out, []byte(js), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Nimeshindwa kuandika %s\n", *out)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/lint"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+// runLint is 'nuru lint', which reports likely mistakes in Nuru files without
+// running them. The exit code is 1 if anything was found.
+func runLint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Tumia: nuru lint faili.nr ...")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	files, err := nuruFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	status := 0
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Nimeshindwa kusoma %s\n", file)
+			status = 1
+			continue
+		}
+
+		p := parser.New(lexer.NewFile(file, string(contents)))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			fmt.Fprintf(os.Stderr, "%s ina makosa:\n", file)
+			for _, msg := range p.Errors() {
+				fmt.Fprintln(os.Stderr, "\t"+msg)
+			}
+			status = 1
+			continue
+		}
+
+		for _, problem := range lint.Lint(program) {
+			fmt.Println(problem)
+			status = 1
+		}
+	}
+	return status
+}
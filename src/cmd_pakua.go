@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/AvicennaJr/Nuru/packages"
+)
+
+// runPakua is 'nuru pakua', which fetches the given packages into the
+// project in the current folder and adds them to its manifest. Without
+// arguments it fetches every package the manifest lists.
+func runPakua(args []string) int {
+	flags := flag.NewFlagSet("pakua", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Tumia: nuru pakua [anwani_ya_git[@toleo] | jina[@toleo] ...]")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	manifest, err := packages.ReadManifest(root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if flags.NArg() == 0 {
+		fetched, err := packages.InstallAll(root, manifest)
+		printFetched(fetched)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	status := 0
+	for _, spec := range flags.Args() {
+		src, err := packages.ParseSource(spec)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+			continue
+		}
+
+		fetched, err := packages.Install(root, src)
+		printFetched(fetched)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+			continue
+		}
+		manifest.Dependencies[src.Name] = src.String()
+	}
+
+	if err := manifest.Write(root); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return status
+}
+
+func printFetched(fetched []packages.Source) {
+	for _, src := range fetched {
+		fmt.Printf("Imepakuliwa %s (%s)\n", src.Name, src)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+// runTokens is 'nuru tokens', which prints the tokens the lexer reads from a
+// file, one per line with where it starts, its type and its text
+func runTokens(args []string) int {
+	flags := flag.NewFlagSet("tokens", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Tumia: nuru tokens faili.nr")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	file := flags.Arg(0)
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Nimeshindwa kusoma %s\n", file)
+		return 1
+	}
+
+	if illegal := writeTokens(os.Stdout, lexer.NewFile(file, string(contents))); illegal {
+		return 1
+	}
+	return 0
+}
+
+// writeTokens writes every token from l up to the end of the input, and
+// reports whether any of them was illegal
+func writeTokens(w io.Writer, l *lexer.Lexer) bool {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintln(tw, "MSTARI:SAFU\tAINA\tNENO")
+	illegal := false
+	for {
+		tok := l.NextToken()
+		fmt.Fprintf(tw, "%d:%d\t%s\t%q\n", tok.Line, tok.Column, tok.Type, tok.Literal)
+		switch tok.Type {
+		case token.EOF:
+			return illegal
+		case token.ILLEGAL:
+			illegal = true
+		}
+	}
+}
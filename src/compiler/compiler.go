@@ -0,0 +1,976 @@
+package compiler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/code"
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+var infixOperators = map[string]code.Opcode{
+	"+":   code.OpAdd,
+	"-":   code.OpSub,
+	"*":   code.OpMul,
+	"/":   code.OpDiv,
+	"%":   code.OpMod,
+	"**":  code.OpPow,
+	"==":  code.OpEqual,
+	"!=":  code.OpNotEqual,
+	"<":   code.OpLessThan,
+	"<=":  code.OpLessEqual,
+	">":   code.OpGreaterThan,
+	">=":  code.OpGreaterEqual,
+	"&&":  code.OpAnd,
+	"||":  code.OpOr,
+	"ktk": code.OpIn,
+	"&":   code.OpBitAnd,
+	"|":   code.OpBitOr,
+	"^":   code.OpBitXor,
+	"<<":  code.OpShiftLeft,
+	">>":  code.OpShiftRight,
+	"\\":  code.OpFloorDiv,
+}
+
+var prefixOperators = map[string]code.Opcode{
+	"-": code.OpMinus,
+	"+": code.OpPlus,
+	"!": code.OpBang,
+	"~": code.OpBitNot,
+}
+
+type EmittedInstruction struct {
+	Opcode   code.Opcode
+	Position int
+}
+
+// loop keeps track of the jumps that 'vunja' and 'endelea' need
+type loop struct {
+	continuePos int   // -1 while the target of 'endelea' isn't known yet
+	continues   []int // jumps to patch once it is
+	breaks      []int
+}
+
+type CompilationScope struct {
+	instructions        code.Instructions
+	positions           []token.Position
+	lastInstruction     EmittedInstruction
+	previousInstruction EmittedInstruction
+	loops               []*loop
+}
+
+type Compiler struct {
+	constants []object.Object
+
+	symbolTable *SymbolTable
+
+	scopes     []CompilationScope
+	scopeIndex int
+
+	pos token.Position // position of the node being compiled
+}
+
+type Bytecode struct {
+	Instructions code.Instructions
+	Positions    []token.Position
+	Constants    []object.Object
+	GlobalNames  []string
+}
+
+func New() *Compiler {
+	mainScope := CompilationScope{}
+
+	symbolTable := NewSymbolTable()
+	for i, name := range evaluator.BuiltinNames() {
+		symbolTable.DefineBuiltin(i, name)
+	}
+
+	return &Compiler{
+		constants:   []object.Object{},
+		symbolTable: symbolTable,
+		scopes:      []CompilationScope{mainScope},
+		scopeIndex:  0,
+	}
+}
+
+func (c *Compiler) Compile(node ast.Node) error {
+	switch node := node.(type) {
+	case *ast.Program:
+		return c.compileStatements(node.Statements)
+
+	case *ast.ExpressionStatement:
+		if err := c.Compile(node.Expression); err != nil {
+			return err
+		}
+		c.emit(code.OpPop)
+
+	case *ast.BlockStatement:
+		return c.compileStatements(node.Statements)
+
+	case *ast.InfixExpression:
+		c.pos = node.Token.Position
+		if node.Operator == "??" {
+			return c.compileCoalesce(node)
+		}
+		op, ok := infixOperators[node.Operator]
+		if !ok {
+			return fmt.Errorf("Mstari %d: Operesheni haieleweki: %s", node.Token.Line, node.Operator)
+		}
+		if err := c.Compile(node.Left); err != nil {
+			return err
+		}
+		if err := c.Compile(node.Right); err != nil {
+			return err
+		}
+		c.pos = node.Token.Position
+		c.emit(op)
+
+	case *ast.PrefixExpression:
+		op, ok := prefixOperators[node.Operator]
+		if !ok {
+			return fmt.Errorf("Mstari %d: Operesheni haieleweki: %s", node.Token.Line, node.Operator)
+		}
+		if err := c.Compile(node.Right); err != nil {
+			return err
+		}
+		c.pos = node.Token.Position
+		c.emit(op)
+
+	case *ast.IntegerLiteral:
+		c.emit(code.OpConstant, c.addConstant(&object.Integer{Value: node.Value}))
+
+	case *ast.BigIntegerLiteral:
+		c.emit(code.OpConstant, c.addConstant(&object.BigInt{Value: node.Value}))
+
+	case *ast.FloatLiteral:
+		c.emit(code.OpConstant, c.addConstant(&object.Float{Value: node.Value}))
+
+	case *ast.StringLiteral:
+		c.emit(code.OpConstant, c.addConstant(&object.String{Value: node.Value}))
+
+	case *ast.Boolean:
+		if node.Value {
+			c.emit(code.OpTrue)
+		} else {
+			c.emit(code.OpFalse)
+		}
+
+	case *ast.Null:
+		c.emit(code.OpNull)
+
+	case *ast.IfExpression:
+		return c.compileIfExpression(node)
+
+	case *ast.ConditionalExpression:
+		return c.compileConditionalExpression(node)
+
+	case *ast.ComparisonChain:
+		return c.compileComparisonChain(node)
+
+	case *ast.Comprehension:
+		return c.compileComprehension(node)
+
+	case *ast.LetStatement:
+		if node.Constant {
+			return fmt.Errorf("%s haitumiki na VM bado, tumia nuru bila --vm", node.TokenLiteral())
+		}
+		c.pos = node.Token.Position
+		if err := c.compileValue(node.Value, node.Name.Value); err != nil {
+			return err
+		}
+		// defined after the value so that 'fanya x = x + 1' reads the old x
+		c.storeSymbol(c.symbolTable.Define(node.Name.Value))
+
+	case *ast.DestructuringStatement:
+		if err := c.Compile(node.Value); err != nil {
+			return err
+		}
+		names := make([]object.Object, len(node.Names))
+		for i, name := range node.Names {
+			names[i] = &object.String{Value: name.Value}
+		}
+		dict := 0
+		if node.Dict {
+			dict = 1
+		}
+		c.pos = node.Token.Position
+		c.emit(code.OpUnpack, c.addConstant(&object.Array{Elements: names}), dict)
+		// the values are on the stack in order, so the last name is set first
+		for i := len(node.Names) - 1; i >= 0; i-- {
+			c.storeSymbol(c.symbolTable.Define(node.Names[i].Value))
+		}
+
+	case *ast.Identifier:
+		c.pos = node.Token.Position
+		c.loadSymbol(c.resolve(node.Value))
+
+	case *ast.AssignmentExpression:
+		return c.compileAssignment(node)
+
+	case *ast.PostfixExpression:
+		return c.compilePostfixExpression(node)
+
+	case *ast.TemplateLiteral:
+		c.pos = node.Token.Position
+		for _, part := range node.Parts {
+			if err := c.Compile(part); err != nil {
+				return err
+			}
+		}
+		c.emit(code.OpTemplate, len(node.Parts))
+
+	case *ast.ArrayLiteral:
+		if hasSpread(node.Elements) {
+			return c.compileSpreadList(node.Elements)
+		}
+		for _, el := range node.Elements {
+			if err := c.Compile(el); err != nil {
+				return err
+			}
+		}
+		c.emit(code.OpArray, len(node.Elements))
+
+	case *ast.DictLiteral:
+		c.pos = node.Token.Position
+		keys := []ast.Expression{}
+		for k := range node.Pairs {
+			keys = append(keys, k)
+		}
+		// map iteration order is random, keep the bytecode deterministic
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+
+		for _, k := range keys {
+			if err := c.Compile(k); err != nil {
+				return err
+			}
+			if err := c.Compile(node.Pairs[k]); err != nil {
+				return err
+			}
+		}
+		c.pos = node.Token.Position
+		c.emit(code.OpDict, len(node.Pairs)*2)
+
+	case *ast.IndexExpression:
+		if err := c.Compile(node.Left); err != nil {
+			return err
+		}
+		jumpPos := -1
+		if node.Optional {
+			jumpPos = c.emit(code.OpJumpNull, 9999)
+		}
+		if err := c.Compile(node.Index); err != nil {
+			return err
+		}
+		c.pos = node.Token.Position
+		c.emit(code.OpIndex)
+		if jumpPos >= 0 {
+			c.changeOperand(jumpPos, len(c.currentInstructions()))
+		}
+
+	case *ast.SliceExpression:
+		if err := c.Compile(node.Left); err != nil {
+			return err
+		}
+		jumpPos := -1
+		if node.Optional {
+			jumpPos = c.emit(code.OpJumpNull, 9999)
+		}
+		for _, bound := range []ast.Expression{node.Start, node.Stop, node.Step} {
+			if bound == nil {
+				c.emit(code.OpNull)
+				continue
+			}
+			if err := c.Compile(bound); err != nil {
+				return err
+			}
+		}
+		c.pos = node.Token.Position
+		c.emit(code.OpSlice)
+		if jumpPos >= 0 {
+			c.changeOperand(jumpPos, len(c.currentInstructions()))
+		}
+
+	case *ast.FunctionLiteral:
+		return c.compileFunction(node, "")
+
+	case *ast.ReturnStatement:
+		if err := c.Compile(node.ReturnValue); err != nil {
+			return err
+		}
+		c.emit(code.OpReturnValue)
+
+	case *ast.CallExpression:
+		if err := c.Compile(node.Function); err != nil {
+			return err
+		}
+		if hasSpread(node.Arguments) {
+			if err := c.compileSpreadList(node.Arguments); err != nil {
+				return err
+			}
+			c.pos = node.Token.Position
+			c.emit(code.OpCallSpread)
+			return nil
+		}
+		for _, a := range node.Arguments {
+			if _, ok := a.(*ast.NamedArgument); ok {
+				return fmt.Errorf("Mstari %d: hoja zenye majina hazitumiki na VM bado, tumia nuru bila --vm", a.Pos().Line)
+			}
+			if err := c.Compile(a); err != nil {
+				return err
+			}
+		}
+		c.pos = node.Token.Position
+		c.emit(code.OpCall, len(node.Arguments))
+
+	case *ast.WhileExpression:
+		return c.compileWhileExpression(node)
+
+	case *ast.DoWhileExpression:
+		return c.compileDoWhileExpression(node)
+
+	case *ast.ForIn:
+		return c.compileForInExpression(node)
+
+	case *ast.Break:
+		lp := c.currentLoop()
+		if lp == nil {
+			return fmt.Errorf("Mstari %d: 'vunja' inatumika ndani ya kitanzi tu", node.Token.Line)
+		}
+		lp.breaks = append(lp.breaks, c.emit(code.OpJump, 9999))
+
+	case *ast.Continue:
+		lp := c.currentLoop()
+		if lp == nil {
+			return fmt.Errorf("Mstari %d: 'endelea' inatumika ndani ya kitanzi tu", node.Token.Line)
+		}
+		if lp.continuePos < 0 {
+			lp.continues = append(lp.continues, c.emit(code.OpJump, 9999))
+		} else {
+			c.emit(code.OpJump, lp.continuePos)
+		}
+
+	case nil:
+		return fmt.Errorf("Mstari %d: Umekosea hapa", c.pos.Line)
+
+	default:
+		return fmt.Errorf("%s haitumiki na VM bado, tumia nuru bila --vm", node.TokenLiteral())
+	}
+
+	return nil
+}
+
+func (c *Compiler) compileStatements(statements []ast.Statement) error {
+	for _, s := range statements {
+		if err := c.Compile(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileBlockExpression compiles a block whose value is used, like the
+// branches of 'kama', leaving exactly one value on the stack
+func (c *Compiler) compileBlockExpression(block *ast.BlockStatement) error {
+	if err := c.Compile(block); err != nil {
+		return err
+	}
+
+	if c.lastInstructionIs(code.OpPop) {
+		c.removeLastPop()
+	} else {
+		c.emit(code.OpNull)
+	}
+
+	return nil
+}
+
+// hasSpread reports whether any of exps is spread with '...'
+func hasSpread(exps []ast.Expression) bool {
+	for _, exp := range exps {
+		if _, ok := exp.(*ast.SpreadExpression); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// compileSpreadList leaves a single array of exps on the stack. The elements
+// between spreads are gathered with OpArray, each spread value is copied with
+// OpSpread, and the pieces are joined as they come.
+func (c *Compiler) compileSpreadList(exps []ast.Expression) error {
+	pieces, run := 0, 0
+	join := func() {
+		pieces++
+		if pieces > 1 {
+			c.emit(code.OpAdd)
+		}
+	}
+	for _, exp := range exps {
+		spread, ok := exp.(*ast.SpreadExpression)
+		if !ok {
+			if err := c.Compile(exp); err != nil {
+				return err
+			}
+			run++
+			continue
+		}
+		if run > 0 {
+			c.emit(code.OpArray, run)
+			run = 0
+			join()
+		}
+		if err := c.Compile(spread.Value); err != nil {
+			return err
+		}
+		c.pos = spread.Token.Position
+		c.emit(code.OpSpread)
+		join()
+	}
+	if run > 0 {
+		c.emit(code.OpArray, run)
+		join()
+	}
+	return nil
+}
+
+// compileValue compiles the value of a binding, passing the name along so
+// that a function can refer to itself
+func (c *Compiler) compileValue(value ast.Expression, name string) error {
+	if fn, ok := value.(*ast.FunctionLiteral); ok {
+		return c.compileFunction(fn, name)
+	}
+	return c.Compile(value)
+}
+
+// compileCoalesce compiles 'a ?? b' so that b is only run when a is tupu
+func (c *Compiler) compileCoalesce(node *ast.InfixExpression) error {
+	if err := c.Compile(node.Left); err != nil {
+		return err
+	}
+	jumpPos := c.emit(code.OpJumpNotNull, 9999)
+	if err := c.Compile(node.Right); err != nil {
+		return err
+	}
+	c.changeOperand(jumpPos, len(c.currentInstructions()))
+	return nil
+}
+
+// compileComparisonChain keeps each operand on the stack for the comparison
+// after it, and jumps out leaving sikweli as soon as one doesn't hold
+func (c *Compiler) compileComparisonChain(node *ast.ComparisonChain) error {
+	if err := c.Compile(node.Operands[0]); err != nil {
+		return err
+	}
+	jumpPositions := []int{}
+	for i, operator := range node.Operators {
+		if err := c.Compile(node.Operands[i+1]); err != nil {
+			return err
+		}
+		c.emit(code.OpChainCompare, int(infixOperators[operator]))
+		jumpPositions = append(jumpPositions, c.emit(code.OpJumpNotTruthy, 9999))
+	}
+	c.emit(code.OpPop)
+	c.emit(code.OpTrue)
+	endPos := c.emit(code.OpJump, 9999)
+	for _, pos := range jumpPositions {
+		c.changeOperand(pos, len(c.currentInstructions()))
+	}
+	c.emit(code.OpPop)
+	c.emit(code.OpFalse)
+	c.changeOperand(endPos, len(c.currentInstructions()))
+	return nil
+}
+
+// compileConditionalExpression compiles 'sharti ? a : b' like a 'kama' whose
+// blocks are a single expression each
+func (c *Compiler) compileConditionalExpression(node *ast.ConditionalExpression) error {
+	if err := c.Compile(node.Condition); err != nil {
+		return err
+	}
+	jumpNotTruthyPos := c.emit(code.OpJumpNotTruthy, 9999)
+	if err := c.Compile(node.Consequence); err != nil {
+		return err
+	}
+	jumpPos := c.emit(code.OpJump, 9999)
+	c.changeOperand(jumpNotTruthyPos, len(c.currentInstructions()))
+	if err := c.Compile(node.Alternative); err != nil {
+		return err
+	}
+	c.changeOperand(jumpPos, len(c.currentInstructions()))
+	return nil
+}
+
+func (c *Compiler) compileIfExpression(node *ast.IfExpression) error {
+	if err := c.Compile(node.Condition); err != nil {
+		return err
+	}
+
+	jumpNotTruthyPos := c.emit(code.OpJumpNotTruthy, 9999)
+
+	if err := c.compileBlockExpression(node.Consequence); err != nil {
+		return err
+	}
+
+	jumpPos := c.emit(code.OpJump, 9999)
+
+	c.changeOperand(jumpNotTruthyPos, len(c.currentInstructions()))
+
+	if node.Alternative == nil {
+		c.emit(code.OpNull)
+	} else {
+		if err := c.compileBlockExpression(node.Alternative); err != nil {
+			return err
+		}
+	}
+
+	c.changeOperand(jumpPos, len(c.currentInstructions()))
+
+	return nil
+}
+
+func (c *Compiler) compileAssignment(node *ast.AssignmentExpression) error {
+	c.pos = node.Token.Position
+
+	// the operator of a shorthand assignment like += is everything but the '='
+	op := node.Token.Literal
+	compound := len(op) >= 2
+	var infix code.Opcode
+	if compound {
+		var ok bool
+		infix, ok = infixOperators[op[:len(op)-1]]
+		if !ok {
+			return fmt.Errorf("Mstari %d: Operesheni haieleweki: %s", node.Token.Line, op)
+		}
+	}
+
+	switch left := node.Left.(type) {
+	case *ast.Identifier:
+		// the target must exist before it can be assigned to
+		c.loadSymbol(c.resolve(left.Value))
+		if !compound {
+			c.emit(code.OpPop)
+		}
+		if err := c.Compile(node.Value); err != nil {
+			return err
+		}
+		if compound {
+			c.pos = node.Token.Position
+			c.emit(infix)
+		}
+
+		symbol, ok := c.symbolTable.ResolveLocal(left.Value)
+		if !ok {
+			symbol = c.symbolTable.Define(left.Value)
+		}
+		c.storeSymbol(symbol)
+
+	case *ast.IndexExpression:
+		if err := c.Compile(left.Left); err != nil {
+			return err
+		}
+		if err := c.Compile(left.Index); err != nil {
+			return err
+		}
+		if compound {
+			if err := c.Compile(left); err != nil {
+				return err
+			}
+		}
+		if err := c.Compile(node.Value); err != nil {
+			return err
+		}
+		c.pos = node.Token.Position
+		if compound {
+			c.emit(infix)
+		}
+		c.emit(code.OpSetIndex)
+
+	default:
+		return fmt.Errorf("Mstari %d: Tumia neno kama variable, sio %s", node.Token.Line, node.Left.TokenLiteral())
+	}
+
+	c.emit(code.OpNull)
+	return nil
+}
+
+func (c *Compiler) compilePostfixExpression(node *ast.PostfixExpression) error {
+	c.pos = node.Token.Position
+
+	var op code.Opcode
+	switch node.Operator {
+	case "++":
+		op = code.OpAdd
+	case "--":
+		op = code.OpSub
+	default:
+		return fmt.Errorf("Haifahamiki: %s", node.Operator)
+	}
+
+	// the old value is left on the stack as the value of the expression
+	old := c.resolve(node.Token.Literal)
+	c.loadSymbol(old)
+	c.loadSymbol(old)
+	c.emit(code.OpConstant, c.addConstant(&object.Integer{Value: 1}))
+	c.emit(op)
+
+	symbol, ok := c.symbolTable.ResolveLocal(node.Token.Literal)
+	if !ok {
+		symbol = c.symbolTable.Define(node.Token.Literal)
+	}
+	c.storeSymbol(symbol)
+
+	return nil
+}
+
+func (c *Compiler) compileFunction(node *ast.FunctionLiteral, name string) error {
+	c.enterScope()
+
+	if name != "" {
+		c.symbolTable.DefineFunctionName(name)
+	}
+
+	for _, p := range node.Parameters {
+		c.symbolTable.Define(p.Value)
+	}
+
+	if err := c.Compile(node.Body); err != nil {
+		return err
+	}
+
+	// like the evaluator, a function gives back the value of its last statement
+	if c.lastInstructionIs(code.OpPop) {
+		c.replaceLastPopWithReturn()
+	}
+	if !c.lastInstructionIs(code.OpReturnValue) {
+		c.emit(code.OpReturn)
+	}
+
+	freeSymbols := c.symbolTable.FreeSymbols
+	numLocals := c.symbolTable.numDefinitions
+	instructions, positions := c.leaveScope()
+
+	for _, s := range freeSymbols {
+		c.loadSymbol(s)
+	}
+
+	compiledFn := &object.CompiledFunction{
+		Instructions:  instructions,
+		Positions:     positions,
+		NumLocals:     numLocals,
+		NumParameters: len(node.Parameters),
+		Variadic:      node.Variadic,
+	}
+
+	c.emit(code.OpClosure, c.addConstant(compiledFn), len(freeSymbols))
+
+	return nil
+}
+
+func (c *Compiler) compileWhileExpression(node *ast.WhileExpression) error {
+	loopStart := len(c.currentInstructions())
+
+	if err := c.Compile(node.Condition); err != nil {
+		return err
+	}
+
+	jumpNotTruthyPos := c.emit(code.OpJumpNotTruthy, 9999)
+
+	c.enterLoop(loopStart)
+	if err := c.Compile(node.Consequence); err != nil {
+		return err
+	}
+	c.emit(code.OpJump, loopStart)
+
+	end := len(c.currentInstructions())
+	c.changeOperand(jumpNotTruthyPos, end)
+	c.leaveLoop(end)
+
+	c.emit(code.OpNull)
+	return nil
+}
+
+func (c *Compiler) compileDoWhileExpression(node *ast.DoWhileExpression) error {
+	loopStart := len(c.currentInstructions())
+
+	c.enterLoop(-1)
+	if err := c.Compile(node.Consequence); err != nil {
+		return err
+	}
+
+	// 'endelea' in the body jumps to the condition
+	lp := c.currentLoop()
+	lp.continuePos = len(c.currentInstructions())
+	for _, pos := range lp.continues {
+		c.changeOperand(pos, lp.continuePos)
+	}
+
+	if err := c.Compile(node.Condition); err != nil {
+		return err
+	}
+	jumpNotTruthyPos := c.emit(code.OpJumpNotTruthy, 9999)
+	c.emit(code.OpJump, loopStart)
+
+	end := len(c.currentInstructions())
+	c.changeOperand(jumpNotTruthyPos, end)
+	c.leaveLoop(end)
+
+	c.emit(code.OpNull)
+	return nil
+}
+
+func (c *Compiler) compileForInExpression(node *ast.ForIn) error {
+	if err := c.Compile(node.Iterable); err != nil {
+		return err
+	}
+
+	c.pos = node.Token.Position
+	c.emit(code.OpIterInit)
+
+	loopStart := c.emit(code.OpIterNext, 9999)
+
+	// OpIterNext pushes the key and then the value
+	c.storeSymbol(c.defineLocal(node.Value))
+	if node.Key != "" {
+		c.storeSymbol(c.defineLocal(node.Key))
+	} else {
+		c.emit(code.OpPop)
+	}
+
+	c.enterLoop(loopStart)
+	if err := c.Compile(node.Block); err != nil {
+		return err
+	}
+	c.emit(code.OpJump, loopStart)
+
+	end := len(c.currentInstructions())
+	c.changeOperand(loopStart, end)
+	c.leaveLoop(end)
+
+	c.emit(code.OpIterEnd)
+	c.emit(code.OpNull)
+	return nil
+}
+
+// compileComprehension compiles '[x * 2 kwa x ktk orodha kama x > 0]' like a
+// 'kwa' loop whose body adds to an array, or dict, left on the stack under
+// the loop's iterator
+func (c *Compiler) compileComprehension(node *ast.Comprehension) error {
+	collect := 1
+	if node.Key != nil {
+		collect = 2
+		c.emit(code.OpDict, 0)
+	} else {
+		c.emit(code.OpArray, 0)
+	}
+
+	if err := c.Compile(node.Iterable); err != nil {
+		return err
+	}
+	c.pos = node.Token.Position
+	c.emit(code.OpIterInit)
+
+	loopStart := c.emit(code.OpIterNext, 9999)
+	c.storeSymbol(c.defineLocal(node.LoopValue))
+	if node.LoopKey != "" {
+		c.storeSymbol(c.defineLocal(node.LoopKey))
+	} else {
+		c.emit(code.OpPop)
+	}
+
+	if node.Condition != nil {
+		if err := c.Compile(node.Condition); err != nil {
+			return err
+		}
+		c.emit(code.OpJumpNotTruthy, loopStart)
+	}
+	if node.Key != nil {
+		if err := c.Compile(node.Key); err != nil {
+			return err
+		}
+	}
+	if err := c.Compile(node.Value); err != nil {
+		return err
+	}
+	c.pos = node.Token.Position
+	c.emit(code.OpCollect, collect)
+	c.emit(code.OpJump, loopStart)
+
+	c.changeOperand(loopStart, len(c.currentInstructions()))
+	c.emit(code.OpIterEnd)
+	return nil
+}
+
+func (c *Compiler) defineLocal(name string) Symbol {
+	symbol, ok := c.symbolTable.ResolveLocal(name)
+	if !ok {
+		symbol = c.symbolTable.Define(name)
+	}
+	return symbol
+}
+
+// resolve looks a name up, treating unknown names as globals that will be
+// defined later so functions can call each other regardless of order
+func (c *Compiler) resolve(name string) Symbol {
+	symbol, ok := c.symbolTable.Resolve(name)
+	if !ok {
+		symbol = c.symbolTable.Global().Define(name)
+	}
+	return symbol
+}
+
+func (c *Compiler) loadSymbol(s Symbol) {
+	switch s.Scope {
+	case GlobalScope:
+		c.emit(code.OpGetGlobal, s.Index)
+	case LocalScope:
+		c.emit(code.OpGetLocal, s.Index)
+	case BuiltinScope:
+		c.emit(code.OpGetBuiltin, s.Index)
+	case FreeScope:
+		c.emit(code.OpGetFree, s.Index)
+	case FunctionScope:
+		c.emit(code.OpCurrentClosure)
+	}
+}
+
+func (c *Compiler) storeSymbol(s Symbol) {
+	if s.Scope == GlobalScope {
+		c.emit(code.OpSetGlobal, s.Index)
+	} else {
+		c.emit(code.OpSetLocal, s.Index)
+	}
+}
+
+func (c *Compiler) addConstant(obj object.Object) int {
+	c.constants = append(c.constants, obj)
+	return len(c.constants) - 1
+}
+
+func (c *Compiler) emit(op code.Opcode, operands ...int) int {
+	ins := code.Make(op, operands...)
+	pos := c.addInstruction(ins)
+
+	c.setLastInstruction(op, pos)
+
+	return pos
+}
+
+func (c *Compiler) addInstruction(ins []byte) int {
+	posNewInstruction := len(c.currentInstructions())
+	scope := &c.scopes[c.scopeIndex]
+
+	scope.instructions = append(scope.instructions, ins...)
+	for range ins {
+		scope.positions = append(scope.positions, c.pos)
+	}
+
+	return posNewInstruction
+}
+
+func (c *Compiler) setLastInstruction(op code.Opcode, pos int) {
+	previous := c.scopes[c.scopeIndex].lastInstruction
+	last := EmittedInstruction{Opcode: op, Position: pos}
+
+	c.scopes[c.scopeIndex].previousInstruction = previous
+	c.scopes[c.scopeIndex].lastInstruction = last
+}
+
+func (c *Compiler) lastInstructionIs(op code.Opcode) bool {
+	if len(c.currentInstructions()) == 0 {
+		return false
+	}
+
+	return c.scopes[c.scopeIndex].lastInstruction.Opcode == op
+}
+
+func (c *Compiler) removeLastPop() {
+	scope := &c.scopes[c.scopeIndex]
+	last := scope.lastInstruction
+
+	scope.instructions = scope.instructions[:last.Position]
+	scope.positions = scope.positions[:last.Position]
+	scope.lastInstruction = scope.previousInstruction
+}
+
+func (c *Compiler) replaceLastPopWithReturn() {
+	lastPos := c.scopes[c.scopeIndex].lastInstruction.Position
+	c.replaceInstruction(lastPos, code.Make(code.OpReturnValue))
+
+	c.scopes[c.scopeIndex].lastInstruction.Opcode = code.OpReturnValue
+}
+
+func (c *Compiler) replaceInstruction(pos int, newInstruction []byte) {
+	ins := c.currentInstructions()
+
+	for i := 0; i < len(newInstruction); i++ {
+		ins[pos+i] = newInstruction[i]
+	}
+}
+
+func (c *Compiler) changeOperand(opPos int, operand int) {
+	op := code.Opcode(c.currentInstructions()[opPos])
+	newInstruction := code.Make(op, operand)
+
+	c.replaceInstruction(opPos, newInstruction)
+}
+
+func (c *Compiler) currentInstructions() code.Instructions {
+	return c.scopes[c.scopeIndex].instructions
+}
+
+func (c *Compiler) enterScope() {
+	scope := CompilationScope{}
+	c.scopes = append(c.scopes, scope)
+	c.scopeIndex++
+
+	c.symbolTable = NewEnclosedSymbolTable(c.symbolTable)
+}
+
+func (c *Compiler) leaveScope() (code.Instructions, []token.Position) {
+	scope := c.scopes[c.scopeIndex]
+
+	c.scopes = c.scopes[:len(c.scopes)-1]
+	c.scopeIndex--
+
+	c.symbolTable = c.symbolTable.Outer
+
+	return scope.instructions, scope.positions
+}
+
+func (c *Compiler) currentLoop() *loop {
+	loops := c.scopes[c.scopeIndex].loops
+	if len(loops) == 0 {
+		return nil
+	}
+	return loops[len(loops)-1]
+}
+
+func (c *Compiler) enterLoop(continuePos int) {
+	scope := &c.scopes[c.scopeIndex]
+	scope.loops = append(scope.loops, &loop{continuePos: continuePos})
+}
+
+// leaveLoop points every 'vunja' of the innermost loop at end
+func (c *Compiler) leaveLoop(end int) {
+	scope := &c.scopes[c.scopeIndex]
+	lp := scope.loops[len(scope.loops)-1]
+	scope.loops = scope.loops[:len(scope.loops)-1]
+
+	for _, pos := range lp.breaks {
+		c.changeOperand(pos, end)
+	}
+}
+
+func (c *Compiler) Bytecode() *Bytecode {
+	return &Bytecode{
+		Instructions: c.currentInstructions(),
+		Positions:    c.scopes[c.scopeIndex].positions,
+		Constants:    c.constants,
+		GlobalNames:  c.symbolTable.Global().Names(),
+	}
+}
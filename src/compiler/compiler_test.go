@@ -0,0 +1,157 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/code"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+func TestDefine(t *testing.T) {
+	global := NewSymbolTable()
+
+	a := global.Define("a")
+	if a != (Symbol{Name: "a", Scope: GlobalScope, Index: 0}) {
+		t.Errorf("a wrong: %+v", a)
+	}
+
+	b := global.Define("b")
+	if b != (Symbol{Name: "b", Scope: GlobalScope, Index: 1}) {
+		t.Errorf("b wrong: %+v", b)
+	}
+
+	again := global.Define("a")
+	if again != a {
+		t.Errorf("redefining a should reuse its slot, got %+v", again)
+	}
+
+	local := NewEnclosedSymbolTable(global)
+	c := local.Define("c")
+	if c != (Symbol{Name: "c", Scope: LocalScope, Index: 0}) {
+		t.Errorf("c wrong: %+v", c)
+	}
+}
+
+func TestResolveFree(t *testing.T) {
+	global := NewSymbolTable()
+	global.Define("a")
+
+	first := NewEnclosedSymbolTable(global)
+	first.Define("b")
+
+	second := NewEnclosedSymbolTable(first)
+	second.Define("c")
+
+	expected := map[string]Symbol{
+		"a": {Name: "a", Scope: GlobalScope, Index: 0},
+		"b": {Name: "b", Scope: FreeScope, Index: 0},
+		"c": {Name: "c", Scope: LocalScope, Index: 0},
+	}
+
+	for name, want := range expected {
+		got, ok := second.Resolve(name)
+		if !ok {
+			t.Errorf("name %s not resolvable", name)
+			continue
+		}
+		if got != want {
+			t.Errorf("expected %s to resolve to %+v, got=%+v", name, want, got)
+		}
+	}
+
+	if len(second.FreeSymbols) != 1 || second.FreeSymbols[0].Name != "b" {
+		t.Errorf("wrong free symbols: %+v", second.FreeSymbols)
+	}
+
+	if _, ok := second.ResolveLocal("b"); ok {
+		t.Errorf("free symbol b should not resolve locally")
+	}
+}
+
+func TestCompileInstructions(t *testing.T) {
+	tests := []struct {
+		input        string
+		constants    []int64
+		instructions []code.Instructions
+	}{
+		{
+			"1 + 2",
+			[]int64{1, 2},
+			[]code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpAdd),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			"fanya x = 1; x",
+			[]int64{1},
+			[]code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpSetGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpPop),
+			},
+		},
+		{
+			"-1",
+			[]int64{1},
+			[]code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpMinus),
+				code.Make(code.OpPop),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		comp := New()
+		if err := comp.Compile(parse(t, tt.input)); err != nil {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		bytecode := comp.Bytecode()
+
+		expected := code.Instructions{}
+		for _, ins := range tt.instructions {
+			expected = append(expected, ins...)
+		}
+
+		if bytecode.Instructions.String() != expected.String() {
+			t.Errorf("wrong instructions for %q.\nwant=\n%s\ngot=\n%s", tt.input, expected, bytecode.Instructions)
+		}
+
+		if len(bytecode.Constants) != len(tt.constants) {
+			t.Fatalf("wrong number of constants. want=%d, got=%d", len(tt.constants), len(bytecode.Constants))
+		}
+
+		for i, want := range tt.constants {
+			integer, ok := bytecode.Constants[i].(*object.Integer)
+			if !ok || integer.Value != want {
+				t.Errorf("constant %d wrong. want=%d, got=%s", i, want, bytecode.Constants[i].Inspect())
+			}
+		}
+	}
+}
+
+func TestCompileUnsupported(t *testing.T) {
+	comp := New()
+	err := comp.Compile(parse(t, `jaribu { tupa "kosa" } shika { 1 }`))
+	if err == nil {
+		t.Fatalf("expected an error for jaribu")
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+	return program
+}
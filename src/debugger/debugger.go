@@ -0,0 +1,288 @@
+// Package debugger runs Nuru programs a statement at a time, the way
+// 'nuru debug' does. It stops at breakpoints, steps through the code and lets
+// the user look at and change the variables where the program is paused.
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/parser"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+const PROMPT = "(debug) "
+
+const help = `Amri:
+  s, step                   endesha kauli inayofuata, hata ikiwa ndani ya unda
+  n, next                   endesha kauli inayofuata bila kusimama ndani ya unda
+  c, continue               endelea hadi kituo kinachofuata
+  b, break [faili:]mstari   weka kituo; bila mstari, orodhesha vituo vyote
+  d, delete [faili:]mstari  ondoa kituo
+  p, print msimbo           onyesha thamani ya msimbo hapa ulipo
+  set jina = msimbo         badilisha thamani ya jina
+  vars                      onyesha majina ya hapa na thamani zake
+  l, list                   onyesha msimbo unaozunguka kauli hii
+  q, quit                   toka
+Kubonyeza Enter peke yake kunarudia amri iliyopita.`
+
+type mode int
+
+const (
+	stepping mode = iota // stop at the next statement
+	stepOver             // stop at the next statement that isn't deeper than nextDepth
+	running              // stop only at breakpoints
+)
+
+type breakpoint struct {
+	file string
+	line int
+}
+
+// Debugger is an evaluator.Debugger that asks the user what to do whenever
+// the program stops. It stops before the first statement, so breakpoints can
+// be set before anything runs.
+type Debugger struct {
+	main string // the file being debugged, for breakpoints given without one
+	in   *bufio.Scanner
+	out  io.Writer
+	exit func(code int)
+
+	breakpoints map[breakpoint]bool
+	mode        mode
+	nextDepth   int
+	lastCommand string
+	last        token.Position // where the last statement seen starts
+	busy        bool           // running code for a command, which mustn't stop
+	sources     map[string][]string
+}
+
+func New(main string, in io.Reader, out io.Writer) *Debugger {
+	return &Debugger{
+		main:        main,
+		in:          bufio.NewScanner(in),
+		out:         out,
+		exit:        os.Exit,
+		breakpoints: make(map[breakpoint]bool),
+		sources:     make(map[string][]string),
+	}
+}
+
+func (d *Debugger) Statement(stmt ast.Statement, env *object.Environment, depth int) {
+	if d.busy {
+		return
+	}
+	pos := stmt.Pos()
+	// a line with several statements only hits its breakpoint once
+	newLine := pos.File != d.last.File || pos.Line != d.last.Line
+	d.last = pos
+
+	hit := newLine && d.hasBreakpoint(pos)
+	switch {
+	case d.mode == stepping:
+	case d.mode == stepOver && depth <= d.nextDepth:
+	case hit:
+		fmt.Fprintf(d.out, "Kituo %s:%d\n", pos.File, pos.Line)
+	default:
+		return
+	}
+	d.pause(pos, env, depth)
+}
+
+func (d *Debugger) pause(pos token.Position, env *object.Environment, depth int) {
+	d.showLine(pos)
+	for {
+		fmt.Fprint(d.out, PROMPT)
+		if !d.in.Scan() {
+			d.exit(0)
+			return
+		}
+
+		line := strings.TrimSpace(d.in.Text())
+		if line == "" {
+			line = d.lastCommand
+		}
+		d.lastCommand = line
+		command, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			command, arg = line[:i], strings.TrimSpace(line[i+1:])
+		}
+
+		switch command {
+		case "":
+		case "s", "step":
+			d.mode = stepping
+			return
+		case "n", "next":
+			d.mode, d.nextDepth = stepOver, depth
+			return
+		case "c", "continue":
+			d.mode = running
+			return
+		case "b", "break":
+			if arg == "" {
+				d.listBreakpoints()
+			} else if bp, ok := d.parseBreakpoint(arg); ok {
+				d.breakpoints[bp] = true
+				fmt.Fprintf(d.out, "Kituo kimewekwa %s:%d\n", bp.file, bp.line)
+			}
+		case "d", "delete":
+			if bp, ok := d.parseBreakpoint(arg); ok {
+				if !d.breakpoints[bp] {
+					fmt.Fprintf(d.out, "Hakuna kituo %s:%d\n", bp.file, bp.line)
+				}
+				delete(d.breakpoints, bp)
+			}
+		case "p", "print":
+			if result := d.eval(arg, env); result != nil {
+				fmt.Fprintln(d.out, result.Inspect())
+			}
+		case "set":
+			d.set(arg, env)
+		case "vars":
+			d.showLocals(env)
+		case "l", "list":
+			d.list(pos)
+		case "q", "quit":
+			d.exit(0)
+			return
+		case "h", "help":
+			fmt.Fprintln(d.out, help)
+		default:
+			fmt.Fprintf(d.out, "Amri %q haijulikani. Andika 'h' kupata msaada\n", command)
+		}
+	}
+}
+
+// parseBreakpoint reads a breakpoint written as file:line, or just line for
+// the file being debugged
+func (d *Debugger) parseBreakpoint(arg string) (breakpoint, bool) {
+	file, lineText := d.main, arg
+	if i := strings.LastIndexByte(arg, ':'); i >= 0 {
+		file, lineText = arg[:i], arg[i+1:]
+	}
+	line, err := strconv.Atoi(lineText)
+	if err != nil || line < 1 {
+		fmt.Fprintf(d.out, "%q sio mstari sahihi, tumia [faili:]mstari\n", arg)
+		return breakpoint{}, false
+	}
+	return breakpoint{file: filepath.Clean(file), line: line}, true
+}
+
+func (d *Debugger) hasBreakpoint(pos token.Position) bool {
+	if len(d.breakpoints) == 0 {
+		return false
+	}
+	file := filepath.Clean(pos.File)
+	return d.breakpoints[breakpoint{file, pos.Line}] || d.breakpoints[breakpoint{filepath.Base(file), pos.Line}]
+}
+
+func (d *Debugger) listBreakpoints() {
+	if len(d.breakpoints) == 0 {
+		fmt.Fprintln(d.out, "Hakuna vituo")
+		return
+	}
+	var list []string
+	for bp := range d.breakpoints {
+		list = append(list, fmt.Sprintf("%s:%d", bp.file, bp.line))
+	}
+	sort.Strings(list)
+	fmt.Fprintln(d.out, strings.Join(list, "\n"))
+}
+
+// eval runs code in env, where the program is paused
+func (d *Debugger) eval(code string, env *object.Environment) object.Object {
+	p := parser.New(lexer.New(code))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		fmt.Fprintln(d.out, strings.Join(p.Errors(), "\n"))
+		return nil
+	}
+
+	d.busy = true
+	result := evaluator.Eval(program, env)
+	d.busy = false
+	return result
+}
+
+// set changes a variable where it was made, which may be outside the
+// function the program is paused in
+func (d *Debugger) set(arg string, env *object.Environment) {
+	i := strings.IndexByte(arg, '=')
+	if i < 0 {
+		fmt.Fprintln(d.out, "Tumia: set jina = msimbo")
+		return
+	}
+	name := strings.TrimSpace(arg[:i])
+	if _, ok := env.Get(name); !ok {
+		fmt.Fprintf(d.out, "Neno Halifahamiki: %s\n", name)
+		return
+	}
+
+	val := d.eval(arg[i+1:], env)
+	if val == nil {
+		return
+	}
+	if val.Type() == object.ERROR_OBJ {
+		fmt.Fprintln(d.out, val.Inspect())
+		return
+	}
+	if err, ok := env.Assign(name, val).(*object.Error); ok {
+		fmt.Fprintln(d.out, err.Inspect())
+	}
+}
+
+func (d *Debugger) showLocals(env *object.Environment) {
+	locals := env.Locals()
+	names := make([]string, 0, len(locals))
+	for name := range locals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(d.out, "%s = %s\n", name, locals[name].Inspect())
+	}
+}
+
+func (d *Debugger) source(file string) []string {
+	lines, ok := d.sources[file]
+	if !ok {
+		contents, _ := os.ReadFile(file)
+		lines = strings.Split(string(contents), "\n")
+		d.sources[file] = lines
+	}
+	return lines
+}
+
+func (d *Debugger) showLine(pos token.Position) {
+	line := ""
+	if lines := d.source(pos.File); pos.Line <= len(lines) {
+		line = strings.TrimSpace(lines[pos.Line-1])
+	}
+	fmt.Fprintf(d.out, "> %s:%d\t%s\n", pos.File, pos.Line, line)
+}
+
+// list shows the lines around pos, marking the one the program is paused on
+func (d *Debugger) list(pos token.Position) {
+	lines := d.source(pos.File)
+	for n := pos.Line - 5; n <= pos.Line+5; n++ {
+		if n < 1 || n > len(lines) {
+			continue
+		}
+		marker := "  "
+		if n == pos.Line {
+			marker = "=>"
+		}
+		fmt.Fprintf(d.out, "%s %4d  %s\n", marker, n, lines[n-1])
+	}
+}
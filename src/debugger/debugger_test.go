@@ -0,0 +1,105 @@
+package debugger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+const program = `fanya jumla = 0
+fanya ongeza = unda(a, b) {
+    fanya c = a + b
+    rudisha c
+}
+kwa i ktk mpaka(3) {
+    jumla = ongeza(jumla, i)
+}
+jumla`
+
+func debug(t *testing.T, commands string) (object.Object, string) {
+	t.Helper()
+	return debugProgram(t, program, commands)
+}
+
+func debugProgram(t *testing.T, src, commands string) (object.Object, string) {
+	t.Helper()
+
+	p := parser.New(lexer.NewFile("mfano.nr", src))
+	prog := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	var out bytes.Buffer
+	d := New("mfano.nr", strings.NewReader(commands), &out)
+	d.exit = func(int) { t.Fatalf("debugger quit early, output:\n%s", out.String()) }
+	evaluator.SetDebugger(d)
+	defer evaluator.SetDebugger(nil)
+
+	return evaluator.Eval(prog, object.NewEnvironment()), out.String()
+}
+
+func stops(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if i := strings.Index(line, "> mfano.nr:"); i >= 0 {
+			lines = append(lines, strings.TrimSpace(line[i+2:]))
+		}
+	}
+	return lines
+}
+
+func TestStepping(t *testing.T) {
+	tests := []struct {
+		commands string
+		stops    []string
+	}{
+		{"c\n", []string{"mfano.nr:1"}},
+		{"s\ns\ns\ns\ns\nc\n", []string{"mfano.nr:1", "mfano.nr:2", "mfano.nr:6", "mfano.nr:7", "mfano.nr:3", "mfano.nr:4"}},
+		// next doesn't stop inside ongeza
+		{"n\nn\nn\nn\nn\nc\n", []string{"mfano.nr:1", "mfano.nr:2", "mfano.nr:6", "mfano.nr:7", "mfano.nr:7", "mfano.nr:7"}},
+		// an empty line repeats the last command
+		{"n\n\n\n\n\nc\n", []string{"mfano.nr:1", "mfano.nr:2", "mfano.nr:6", "mfano.nr:7", "mfano.nr:7", "mfano.nr:7"}},
+		{"b 4\nc\nc\nd mfano.nr:4\nc\n", []string{"mfano.nr:1", "mfano.nr:4", "mfano.nr:4"}},
+	}
+
+	for _, tt := range tests {
+		result, output := debug(t, tt.commands)
+		if got := stops(output); strings.Join(got, " ") != strings.Join(tt.stops, " ") {
+			t.Errorf("%q: wrong stops.\nwant=%q\ngot= %q", tt.commands, tt.stops, got)
+		}
+		if integer, ok := result.(*object.Integer); !ok || integer.Value != 3 {
+			t.Errorf("%q: wrong result, got=%s", tt.commands, result.Inspect())
+		}
+	}
+}
+
+func TestInspectAndModify(t *testing.T) {
+	result, output := debug(t, "b 4\nc\nvars\np c * 10\nset c = 100\nset x = 1\nd 4\nc\n")
+
+	for _, want := range []string{"a = 0\nb = 0\nc = 0\n", "(debug) 0\n", "Neno Halifahamiki: x"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output doesn't contain %q:\n%s", want, output)
+		}
+	}
+	// jumla becomes 100 on the first call, then 1 and 2 are added
+	if integer, ok := result.(*object.Integer); !ok || integer.Value != 103 {
+		t.Errorf("wrong result, got=%s", result.Inspect())
+	}
+}
+
+func TestSetRefusesConstants(t *testing.T) {
+	result, output := debugProgram(t, "thabiti PI = 3\nfanya x = 0\nPI + x", "b 3\nc\nset PI = 4\nd 3\nc\n")
+
+	if !strings.Contains(output, "PI ni thabiti") {
+		t.Errorf("output doesn't say PI is a constant:\n%s", output)
+	}
+	if integer, ok := result.(*object.Integer); !ok || integer.Value != 3 {
+		t.Errorf("wrong result, got=%s", result.Inspect())
+	}
+}
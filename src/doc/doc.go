@@ -0,0 +1,183 @@
+// Package doc builds documentation for Nuru files from their comments, the
+// way 'nuru doc' shows it. The comment right above a function, class or
+// variable defined at the top of a file documents it, as does the comment
+// above a method or field inside a class. A comment at the very top of the
+// file, with a blank line after it, documents the file itself.
+package doc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/parser"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+// File is the documentation of one file
+type File struct {
+	Name  string
+	Doc   string
+	Items []*Item
+}
+
+// Item is something defined in a file: a function, a class or a variable.
+// The methods and fields of a class are its Members, and their names start
+// with the name of the class, like 'Mtu.salamu'.
+type Item struct {
+	Name      string
+	Signature string // how it is declared, like 'unda ongeza(a, b)'
+	Doc       string
+	Position  token.Position
+	Members   []*Item
+}
+
+// Extract reads the documentation of the file name, whose source is input.
+// If the file doesn't parse, the parser errors are returned instead.
+func Extract(name, input string) (*File, []string) {
+	l := lexer.NewFile(name, input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, p.Errors()
+	}
+
+	e := &extractor{comments: l.Comments()}
+	file := &File{Name: name}
+	if len(program.Statements) > 0 {
+		file.Doc = e.fileDoc(program.Statements[0].Pos())
+	}
+
+	for _, stmt := range program.Statements {
+		switch stmt := stmt.(type) {
+		case *ast.LetStatement:
+			file.Items = append(file.Items, e.let(stmt, ""))
+		case *ast.ClassStatement:
+			file.Items = append(file.Items, e.class(stmt))
+		}
+	}
+	return file, nil
+}
+
+type extractor struct {
+	comments []lexer.Comment
+}
+
+// docAbove joins the comments that end on the line right above line, and
+// the ones right above those
+func (e *extractor) docAbove(line int) string {
+	var block []string
+	for i := len(e.comments) - 1; i >= 0; i-- {
+		c := e.comments[i]
+		if c.Trailing || c.Position.Line >= line {
+			continue
+		}
+		if endLine(c) != line-1 {
+			if endLine(c) < line-1 {
+				break
+			}
+			continue
+		}
+		block = append([]string{commentText(c.Text)}, block...)
+		line = c.Position.Line
+	}
+	return strings.Join(block, "\n")
+}
+
+// fileDoc is the comment at the top of the file, if a blank line keeps it
+// apart from the first statement, which starts at first
+func (e *extractor) fileDoc(first token.Position) string {
+	if len(e.comments) == 0 || e.comments[0].Position.Line >= first.Line {
+		return ""
+	}
+	var block []string
+	line := e.comments[0].Position.Line
+	for _, c := range e.comments {
+		if c.Position.Line != line || c.Position.Line >= first.Line {
+			break
+		}
+		block = append(block, commentText(c.Text))
+		line = endLine(c) + 1
+	}
+	if line >= first.Line {
+		return ""
+	}
+	return strings.Join(block, "\n")
+}
+
+func (e *extractor) let(stmt *ast.LetStatement, prefix string) *Item {
+	name := stmt.Name.Value
+	item := &Item{Name: prefix + name, Doc: e.docAbove(stmt.Pos().Line), Position: stmt.Pos()}
+	switch value := stmt.Value.(type) {
+	case *ast.FunctionLiteral:
+		item.Signature = "unda " + name + parameters(value)
+		if item.Doc == "" {
+			item.Doc = value.DocString()
+		}
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.Boolean, *ast.Null:
+		item.Signature = stmt.TokenLiteral() + " " + name + " = " + value.String()
+	case *ast.StringLiteral:
+		item.Signature = fmt.Sprintf("%s %s = %q", stmt.TokenLiteral(), name, value.Value)
+	default:
+		item.Signature = stmt.TokenLiteral() + " " + name
+	}
+	return item
+}
+
+func (e *extractor) class(stmt *ast.ClassStatement) *Item {
+	item := &Item{Name: stmt.Name.Value, Doc: e.docAbove(stmt.Pos().Line), Position: stmt.Pos()}
+	item.Signature = "muundo " + item.Name
+	if stmt.Constructor != nil {
+		item.Signature += parameters(stmt.Constructor)
+	}
+
+	prefix := item.Name + "."
+	for _, field := range stmt.Fields {
+		item.Members = append(item.Members, e.let(field, prefix))
+	}
+	for _, method := range stmt.Methods {
+		doc := e.docAbove(method.Name.Pos().Line)
+		if doc == "" {
+			doc = method.Function.DocString()
+		}
+		item.Members = append(item.Members, &Item{
+			Name:      prefix + method.Name.Value,
+			Signature: method.Name.Value + parameters(method.Function),
+			Doc:       doc,
+			Position:  method.Name.Pos(),
+		})
+	}
+	return item
+}
+
+func parameters(fn *ast.FunctionLiteral) string {
+	params := make([]string, len(fn.Parameters))
+	for i, p := range fn.Parameters {
+		params[i] = p.Value
+	}
+	if fn.Variadic {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
+	return "(" + strings.Join(params, ", ") + ")"
+}
+
+func endLine(c lexer.Comment) int {
+	return c.Position.Line + strings.Count(c.Text, "\n")
+}
+
+// commentText takes the comment markers off text, along with the '*' that
+// often starts the lines of a /* */ comment
+func commentText(text string) string {
+	if strings.HasPrefix(text, "//") {
+		return strings.TrimSpace(strings.TrimPrefix(text, "//"))
+	}
+
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		lines[i] = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,100 @@
+package doc
+
+import (
+	"strings"
+	"testing"
+)
+
+const input = `// Hesabu ni moduli ya hesabu.
+
+// PI ni uwiano wa mzingo na kipenyo
+fanya PI = 3.14
+
+/*
+ * ongeza inajumlisha a na b.
+ *
+ * Inarudisha jumla yao.
+ */
+fanya ongeza = unda(a, b) {
+    // si maelezo
+    rudisha a + b
+}
+fanya x = ongeza(1, 2) // si maelezo
+
+// hii pia si maelezo
+
+fanya y = "y"
+
+// Mtu ni mtu yeyote
+muundo Mtu {
+    // jina lake
+    fanya jina = "Asha"
+    unda(jina) { hii.jina = jina }
+    // salamu inasalimia
+    salamu(mgeni) { rudisha "Habari " + mgeni }
+    kwaheri() {
+        "kwaheri inaaga"
+        rudisha "Kwaheri"
+    }
+}
+fanya z = unda() {
+    "z haina maelezo juu yake"
+    rudisha 1
+}`
+
+func TestExtract(t *testing.T) {
+	file, errs := Extract("hesabu.nr", input)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if file.Doc != "Hesabu ni moduli ya hesabu." {
+		t.Errorf("wrong file doc, got=%q", file.Doc)
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+		doc       string
+	}{
+		{"PI", "fanya PI = 3.14", "PI ni uwiano wa mzingo na kipenyo"},
+		{"ongeza", "unda ongeza(a, b)", "ongeza inajumlisha a na b.\n\nInarudisha jumla yao."},
+		{"x", "fanya x", ""},
+		{"y", `fanya y = "y"`, ""},
+		{"Mtu", "muundo Mtu(jina)", "Mtu ni mtu yeyote"},
+		{"Mtu.jina", `fanya jina = "Asha"`, "jina lake"},
+		{"Mtu.salamu", "salamu(mgeni)", "salamu inasalimia"},
+		{"Mtu.kwaheri", "kwaheri()", "kwaheri inaaga"},
+		{"z", "unda z()", "z haina maelezo juu yake"},
+	}
+
+	var items []*Item
+	for _, item := range file.Items {
+		items = append(items, item)
+		items = append(items, item.Members...)
+	}
+	if len(items) != len(tests) {
+		t.Fatalf("wrong number of items. want=%d, got=%d", len(tests), len(items))
+	}
+	for i, tt := range tests {
+		item := items[i]
+		if item.Name != tt.name || item.Signature != tt.signature || item.Doc != tt.doc {
+			t.Errorf("item %d wrong.\nwant=%q %q %q\ngot= %q %q %q", i, tt.name, tt.signature, tt.doc, item.Name, item.Signature, item.Doc)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	file, _ := Extract("mfano.nr", "// f ni <muhimu>\nfanya f = unda() {}")
+
+	expected := "# mfano.nr\n\n## f\n\n```\nunda f()\n```\n\nf ni <muhimu>\n"
+	if got := Markdown(file); got != expected {
+		t.Errorf("wrong markdown.\nwant=%q\ngot= %q", expected, got)
+	}
+
+	page := HTML(file)
+	for _, want := range []string{"<title>mfano.nr</title>", `<h2 id="f">f</h2>`, "<pre><code>unda f()</code></pre>", "<p>f ni &lt;muhimu&gt;</p>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("html doesn't contain %q:\n%s", want, page)
+		}
+	}
+}
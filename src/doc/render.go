@@ -0,0 +1,66 @@
+package doc
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Markdown renders f as a Markdown page
+func Markdown(f *File) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "# %s\n", f.Name)
+	if f.Doc != "" {
+		fmt.Fprintf(&out, "\n%s\n", f.Doc)
+	}
+
+	var item func(it *Item, level int)
+	item = func(it *Item, level int) {
+		fmt.Fprintf(&out, "\n%s %s\n\n```\n%s\n```\n", strings.Repeat("#", level), it.Name, it.Signature)
+		if it.Doc != "" {
+			fmt.Fprintf(&out, "\n%s\n", it.Doc)
+		}
+		for _, member := range it.Members {
+			item(member, level+1)
+		}
+	}
+	for _, it := range f.Items {
+		item(it, 2)
+	}
+	return out.String()
+}
+
+// HTML renders f as a standalone HTML page
+func HTML(f *File) string {
+	var out strings.Builder
+	name := html.EscapeString(f.Name)
+	fmt.Fprintf(&out, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n<h1>%s</h1>\n", name, name)
+	out.WriteString(paragraphs(f.Doc))
+
+	var item func(it *Item, level int)
+	item = func(it *Item, level int) {
+		fmt.Fprintf(&out, "<h%d id=\"%s\">%s</h%d>\n", level, html.EscapeString(it.Name), html.EscapeString(it.Name), level)
+		fmt.Fprintf(&out, "<pre><code>%s</code></pre>\n", html.EscapeString(it.Signature))
+		out.WriteString(paragraphs(it.Doc))
+		for _, member := range it.Members {
+			item(member, level+1)
+		}
+	}
+	for _, it := range f.Items {
+		item(it, 2)
+	}
+
+	out.WriteString("</body>\n</html>\n")
+	return out.String()
+}
+
+// paragraphs turns text into HTML paragraphs, which blank lines separate
+func paragraphs(text string) string {
+	var out strings.Builder
+	for _, p := range strings.Split(text, "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			fmt.Fprintf(&out, "<p>%s</p>\n", html.EscapeString(p))
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,191 @@
+package evaluator
+
+import (
+	"sort"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// like ramani_sambamba, panga can't go in the builtins map without making an
+// initialization cycle
+func init() {
+	builtins["panga"] = &object.Builtin{Fn: sortArray}
+}
+
+// callback checks that the argument at i of an array method can be called
+func callback(method string, args []object.Object, i int) (object.Object, *object.Error) {
+	switch args[i].(type) {
+	case *object.Function, *object.Builtin, *object.BoundMethod, *object.Class:
+		return args[i], nil
+	default:
+		return nil, newError("Samahani, %s inahitaji unda, sio %s", method, args[i].Type())
+	}
+}
+
+func arrayMethod(arr *object.Array, name string) (object.Object, bool) {
+	switch name {
+	case "ramani":
+		// ramani gives a new array of what the function returns for each element
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+			fn, err := callback("ramani", args, 0)
+			if err != nil {
+				return err
+			}
+			elements := make([]object.Object, len(arr.Elements))
+			for i, el := range arr.Elements {
+				result := applyFunction(fn, []object.Object{el})
+				if isError(result) {
+					return result
+				}
+				if result == nil {
+					result = NULL
+				}
+				elements[i] = result
+			}
+			return &object.Array{Elements: elements}
+		}}, true
+	case "chuja":
+		// chuja keeps the elements the function returns something true for
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+			fn, err := callback("chuja", args, 0)
+			if err != nil {
+				return err
+			}
+			elements := []object.Object{}
+			for _, el := range arr.Elements {
+				result := applyFunction(fn, []object.Object{el})
+				if isError(result) {
+					return result
+				}
+				if isTruthy(result) {
+					elements = append(elements, el)
+				}
+			}
+			return &object.Array{Elements: elements}
+		}}, true
+	case "punguza":
+		// punguza combines the elements into one value, calling the function
+		// with what it has so far and the next element. Without a starting
+		// value it starts from the first element.
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return newError("Samahani, hii function inapokea hoja 1 au 2, wewe umeweka %d", len(args))
+			}
+			fn, err := callback("punguza", args, 0)
+			if err != nil {
+				return err
+			}
+			elements := arr.Elements
+			var acc object.Object
+			if len(args) == 2 {
+				acc = args[1]
+			} else if len(elements) == 0 {
+				return newError("Samahani, huwezi kupunguza orodha tupu bila thamani ya kuanzia")
+			} else {
+				acc, elements = elements[0], elements[1:]
+			}
+			for _, el := range elements {
+				acc = applyFunction(fn, []object.Object{acc, el})
+				if isError(acc) {
+					return acc
+				}
+				if acc == nil {
+					acc = NULL
+				}
+			}
+			return acc
+		}}, true
+	}
+	return nil, false
+}
+
+// sortArray backs panga(). The sort is stable, so elements that compare equal
+// keep their order. Without a function, numbers are sorted by value and
+// strings alphabetically. A function is given two elements and returns kweli,
+// or a number below 0, when the first should come before the second.
+func sortArray(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("Samahani, panga inapokea hoja 1 au 2, wewe umeweka %d", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("Samahani, panga inahitaji ORODHA, sio %s", args[0].Type())
+	}
+
+	less := defaultLess
+	if len(args) == 2 {
+		fn, err := callback("panga", args, 1)
+		if err != nil {
+			return err
+		}
+		less = func(a, b object.Object) (bool, *object.Error) {
+			return comparatorLess(fn, a, b)
+		}
+	}
+
+	elements := make([]object.Object, len(arr.Elements))
+	copy(elements, arr.Elements)
+
+	// sort.SliceStable can't be stopped, so the first error is kept and
+	// the rest of the comparisons are skipped
+	var failed *object.Error
+	sort.SliceStable(elements, func(i, j int) bool {
+		if failed != nil {
+			return false
+		}
+		result, err := less(elements[i], elements[j])
+		if err != nil {
+			failed = err
+		}
+		return result
+	})
+	if failed != nil {
+		return failed
+	}
+	return &object.Array{Elements: elements}
+}
+
+func defaultLess(a, b object.Object) (bool, *object.Error) {
+	if as, ok := a.(*object.String); ok {
+		if bs, ok := b.(*object.String); ok {
+			return as.Value < bs.Value, nil
+		}
+	}
+	if !isNumber(a) || !isNumber(b) {
+		return false, newError("Samahani, siwezi kulinganisha %s na %s", a.Type(), b.Type())
+	}
+	result := evalInfixExpression("<", a, b)
+	if err, ok := result.(*object.Error); ok {
+		return false, err
+	}
+	return result == TRUE, nil
+}
+
+func isNumber(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.Float, *object.BigInt, *object.Decimal:
+		return true
+	}
+	return false
+}
+
+func comparatorLess(fn, a, b object.Object) (bool, *object.Error) {
+	switch result := applyFunction(fn, []object.Object{a, b}).(type) {
+	case *object.Error:
+		return false, result
+	case *object.Boolean:
+		return result.Value, nil
+	case *object.Integer:
+		return result.Value < 0, nil
+	case *object.Float:
+		return result.Value < 0, nil
+	default:
+		return false, newError("Samahani, unda ya panga inahitaji kurudisha BOOLEAN au namba, sio %s", result.Type())
+	}
+}
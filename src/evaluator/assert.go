@@ -0,0 +1,78 @@
+package evaluator
+
+import (
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/formatter"
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// assert is the 'thibitisha' builtin. It fails with an error unless its first
+// argument is true, adding the message in the second argument if there is one.
+func assert(args ...object.Object) object.Object {
+	return checkAssertion("", args)
+}
+
+// evalAssertion calls 'thibitisha' for node. When it fails, the error shows
+// the code of the condition and points at it rather than at the call.
+func evalAssertion(node *ast.CallExpression, args []object.Object) object.Object {
+	condition := node.Arguments[0]
+	if _, ok := condition.(*ast.SpreadExpression); ok {
+		return applyFunction(builtins["thibitisha"], args)
+	}
+
+	result := checkAssertion(formatter.Expression(condition), args)
+	if err, ok := result.(*object.Error); ok {
+		err.Position = condition.Pos()
+		return err
+	}
+	return NULL
+}
+
+func checkAssertion(code string, args []object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("Samahani, hii function inapokea hoja 1 au 2, wewe umeweka %d", len(args))
+	}
+
+	if isTruthy(args[0]) {
+		return nil
+	}
+	msg := "Uthibitisho umeshindwa"
+	if len(args) == 2 {
+		msg += ": " + message(args[1])
+	}
+	if code != "" {
+		msg += " (sharti: " + code + ")"
+	}
+	return newError("%s", msg)
+}
+
+// assertEqual is the 'thibitisha_sawa' builtin. It fails with an error showing
+// both values unless the first, what was got, equals the second, what was
+// expected. A message can be added as a third argument.
+func assertEqual(args ...object.Object) object.Object {
+	if len(args) < 2 || len(args) > 3 {
+		return newError("Samahani, hii function inapokea hoja 2 au 3, wewe umeweka %d", len(args))
+	}
+
+	equal := evalInfixExpression("==", args[0], args[1])
+	if isError(equal) {
+		return equal
+	}
+	if isTruthy(equal) {
+		return nil
+	}
+
+	msg := ""
+	if len(args) == 3 {
+		msg = message(args[2]) + ": "
+	}
+	return newError("Uthibitisho umeshindwa: %stumepata=%s, tulitarajia=%s", msg, args[0].Inspect(), args[1].Inspect())
+}
+
+// message is the text of a message argument, without quotes if it is a string
+func message(obj object.Object) string {
+	if str, ok := obj.(*object.String); ok {
+		return str.Value
+	}
+	return obj.Inspect()
+}
@@ -0,0 +1,114 @@
+package evaluator
+
+import (
+	"sort"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// The functions below expose the evaluator's semantics to other execution
+// backends, like the bytecode VM, so that every backend agrees on the result
+// of an operation and on the error it reports.
+
+func EvalInfix(operator string, left, right object.Object) object.Object {
+	return evalInfixExpression(operator, left, right)
+}
+
+func EvalPrefix(operator string, right object.Object) object.Object {
+	return evalPrefixExpression(operator, right)
+}
+
+func EvalIndex(left, index object.Object) object.Object {
+	return evalIndexExpression(left, index)
+}
+
+func EvalSlice(left, start, stop, step object.Object) object.Object {
+	return evalSliceExpression(left, start, stop, step)
+}
+
+func OwnIterator(obj object.Object) object.Object {
+	return ownIterator(obj)
+}
+
+func IteratorMethod(obj object.Object, name string) (object.Object, bool) {
+	return iteratorMethod(obj, name)
+}
+
+func AbsoluteIndex(idx int64, length int) (int, bool) {
+	return absoluteIndex(idx, length)
+}
+
+func IntegerOverflows(operator string, left, right int64) bool {
+	return integerOverflows(operator, left, right)
+}
+
+func IsTruthy(obj object.Object) bool {
+	return isTruthy(obj)
+}
+
+func NewError(format string, a ...interface{}) *object.Error {
+	return newError(format, a...)
+}
+
+// ErrorMessage is the message of err without its colour codes
+func ErrorMessage(err *object.Error) string {
+	return errorMessage(err)
+}
+
+// BuiltinNames returns the names of all builtin functions in a stable order
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func LookupBuiltin(name string) (*object.Builtin, bool) {
+	builtin, ok := builtins[name]
+	return builtin, ok
+}
+
+// ModuleNames returns the names of the modules that come with Nuru, like json,
+// in a stable order
+func ModuleNames() []string {
+	names := make([]string, 0, len(stdModules))
+	for name := range stdModules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GlobalNames returns the names of the values every program can read, like
+// HOJA, in a stable order
+func GlobalNames() []string {
+	names := make([]string, 0, len(globals))
+	for name := range globals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func LookupGlobal(name string) (object.Object, bool) {
+	val, ok := globals[name]
+	return val, ok
+}
+
+// Destructure gives the values that 'fanya [a, b] = val', or with dict set
+// 'fanya {a, b} = val', binds to names
+func Destructure(val object.Object, names []string, dict bool) ([]object.Object, *object.Error) {
+	return destructure(val, names, dict)
+}
+
+// Spread gives the elements '...val' puts in its place
+func Spread(val object.Object) ([]object.Object, *object.Error) {
+	return spreadElements(val)
+}
+
+// ApplyFunction calls fn, which may be anything that can be called, with args
+func ApplyFunction(fn object.Object, args []object.Object) object.Object {
+	return applyFunction(fn, args)
+}
@@ -0,0 +1,131 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// toBigInt gives the value of an Integer or a BigInt as a big.Int
+func toBigInt(obj object.Object) *big.Int {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value)
+	case *object.BigInt:
+		return obj.Value
+	}
+	return nil
+}
+
+// normalizeBigInt gives back an Integer when the value fits in one, so
+// that BigInts only appear when they are needed
+func normalizeBigInt(value *big.Int) object.Object {
+	if value.IsInt64() {
+		return &object.Integer{Value: value.Int64()}
+	}
+	return &object.BigInt{Value: value}
+}
+
+func isInteger(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.BIGINT_OBJ
+}
+
+// integerOverflows reports whether an operation on two Integers would not
+// fit in an int64 and has to be done with BigInts instead
+func integerOverflows(operator string, leftVal, rightVal int64) bool {
+	switch operator {
+	case "+":
+		result := leftVal + rightVal
+		return (leftVal > 0 && rightVal > 0 && result < 0) || (leftVal < 0 && rightVal < 0 && result >= 0)
+	case "-":
+		result := leftVal - rightVal
+		return (leftVal >= 0 && rightVal < 0 && result < 0) || (leftVal < 0 && rightVal > 0 && result >= 0)
+	case "*":
+		if leftVal == 0 || rightVal == 0 {
+			return false
+		}
+		result := leftVal * rightVal
+		return result/rightVal != leftVal || (leftVal == -1 && rightVal == math.MinInt64) || (rightVal == -1 && leftVal == math.MinInt64)
+	case "**":
+		return rightVal >= 0
+	case "/", "%":
+		// the only division whose answer is too big for an int64
+		return leftVal == math.MinInt64 && rightVal == -1
+	case "<<":
+		// a shift that loses bits, or the sign, needs more than 64 of them
+		return rightVal >= 0 && leftVal != 0 && (rightVal >= 63 || (leftVal<<rightVal)>>rightVal != leftVal)
+	}
+	return false
+}
+
+func evalBigIntInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := toBigInt(left)
+	rightVal := toBigInt(right)
+
+	switch operator {
+	case "+":
+		return normalizeBigInt(new(big.Int).Add(leftVal, rightVal))
+	case "-":
+		return normalizeBigInt(new(big.Int).Sub(leftVal, rightVal))
+	case "*":
+		return normalizeBigInt(new(big.Int).Mul(leftVal, rightVal))
+	case "**":
+		if rightVal.Sign() < 0 {
+			if leftVal.Sign() == 0 {
+				return newError("Huwezi kugawanya kwa sifuri")
+			}
+			return &object.Float{Value: math.Pow(bigToFloat(leftVal), bigToFloat(rightVal))}
+		}
+		return normalizeBigInt(new(big.Int).Exp(leftVal, rightVal, nil))
+	case "/":
+		if rightVal.Sign() == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
+		quo, rem := new(big.Int).QuoRem(leftVal, rightVal, new(big.Int))
+		if rem.Sign() == 0 {
+			return normalizeBigInt(quo)
+		}
+		result, _ := new(big.Float).Quo(new(big.Float).SetInt(leftVal), new(big.Float).SetInt(rightVal)).Float64()
+		return &object.Float{Value: result}
+	case "%":
+		if rightVal.Sign() == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
+		return normalizeBigInt(new(big.Int).Rem(leftVal, rightVal))
+	case "&":
+		return normalizeBigInt(new(big.Int).And(leftVal, rightVal))
+	case "|":
+		return normalizeBigInt(new(big.Int).Or(leftVal, rightVal))
+	case "^":
+		return normalizeBigInt(new(big.Int).Xor(leftVal, rightVal))
+	case "<<", ">>":
+		if !rightVal.IsUint64() || rightVal.Uint64() > math.MaxUint32 {
+			return newError("Samahani, huwezi kusogeza biti mara %s", rightVal)
+		}
+		if operator == "<<" {
+			return normalizeBigInt(new(big.Int).Lsh(leftVal, uint(rightVal.Uint64())))
+		}
+		return normalizeBigInt(new(big.Int).Rsh(leftVal, uint(rightVal.Uint64())))
+	case "<":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) < 0)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) <= 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) > 0)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) >= 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) != 0)
+	default:
+		return newError("Operesheni Haielweki: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
+
+func bigToFloat(value *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(value).Float64()
+	return f
+}
@@ -8,10 +8,19 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/AvicennaJr/Nuru/object"
 )
 
+// Stdout and Stdin are where andika writes and jaza reads. Programs that run
+// Nuru code, like the browser playground, can change them to capture output.
+var (
+	Stdout io.Writer = os.Stdout
+	Stdin  io.Reader = os.Stdin
+)
+
 var builtins = map[string]*object.Builtin{
 	"idadi": {
 		Fn: func(args ...object.Object) object.Object {
@@ -23,6 +32,12 @@ var builtins = map[string]*object.Builtin{
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
 			case *object.String:
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+			case *object.Set:
+				return &object.Integer{Value: int64(len(arg.Elements))}
+			case *object.Tuple:
+				return &object.Integer{Value: int64(len(arg.Elements))}
+			case *object.Bytes:
 				return &object.Integer{Value: int64(len(arg.Value))}
 			default:
 				return newError("Samahani, hii function haitumiki na %s", args[0].Type())
@@ -117,23 +132,46 @@ var builtins = map[string]*object.Builtin{
 			}
 			if len(args) == 1 {
 				prompt := args[0].(*object.String).Value
-				fmt.Fprint(os.Stdout, prompt)
+				fmt.Fprint(Stdout, prompt)
 			}
 
-			buffer := bufio.NewReader(os.Stdin)
-
-			line, _, err := buffer.ReadLine()
-			if err != nil && err != io.EOF {
+			line, _, err := readLine()
+			if err != nil {
 				return newError("Nimeshindwa kusoma uliyo yajaza")
 			}
 
-			return &object.String{Value: string(line)}
+			return &object.String{Value: line}
+		},
+	},
+	"soma": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newError("Samahani, hii function inapokea hoja 0 au 1, wewe umeweka %d", len(args))
+			}
+			if len(args) == 1 {
+				prompt, ok := args[0].(*object.String)
+				if !ok {
+					return newError("Samahani, swali la soma linahitaji kuwa NENO, sio %s", args[0].Type())
+				}
+				fmt.Fprint(Stdout, prompt.Value)
+			}
+
+			// unlike jaza, soma tells the end of the input apart from an
+			// empty line, so a script can read until there is nothing left
+			line, ok, err := readLine()
+			if err != nil {
+				return newError("Nimeshindwa kusoma uliyo yajaza")
+			}
+			if !ok {
+				return NULL
+			}
+			return &object.String{Value: line}
 		},
 	},
 	"andika": {
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) == 0 {
-				fmt.Println("")
+				fmt.Fprintln(Stdout)
 			} else {
 				var arr []string
 				for _, arg := range args {
@@ -143,7 +181,7 @@ var builtins = map[string]*object.Builtin{
 					arr = append(arr, arg.Inspect())
 				}
 				str := strings.Join(arr, " ")
-				print(str + "\n")
+				fmt.Fprintln(Stdout, str)
 			}
 			return nil
 		},
@@ -157,4 +195,283 @@ var builtins = map[string]*object.Builtin{
 			return &object.String{Value: string(args[0].Type())}
 		},
 	},
+	"mpaka": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) < 1 || len(args) > 3 {
+				return newError("Samahani, hii function inapokea hoja 1 hadi 3, wewe umeweka %d", len(args))
+			}
+
+			nums := make([]int64, len(args))
+			for i, arg := range args {
+				num, ok := arg.(*object.Integer)
+				if !ok {
+					return newError("Samahani, mpaka inahitaji namba, sio %s", arg.Type())
+				}
+				nums[i] = num.Value
+			}
+
+			r := &object.Range{Start: 0, Step: 1}
+			switch len(nums) {
+			case 1:
+				r.End = nums[0]
+			case 2:
+				r.Start, r.End = nums[0], nums[1]
+			case 3:
+				r.Start, r.End, r.Step = nums[0], nums[1], nums[2]
+			}
+
+			if r.Step == 0 {
+				return newError("Samahani, hatua ya mpaka haiwezi kuwa 0")
+			}
+
+			return r
+		},
+	},
+	"soma_faili": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+
+			path, ok := args[0].(*object.String)
+			if !ok {
+				return newError("Samahani, jina la faili linahitaji kuwa NENO, sio %s", args[0].Type())
+			}
+
+			contents, err := os.ReadFile(path.Value)
+			if err != nil {
+				return newError("Nimeshindwa kusoma faili %q", path.Value)
+			}
+
+			return &object.String{Value: string(contents)}
+		},
+	},
+	"andika_faili": {
+		Fn: func(args ...object.Object) object.Object {
+			return writeFile("andika_faili", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, args)
+		},
+	},
+	"ongeza_faili": {
+		Fn: func(args ...object.Object) object.Object {
+			return writeFile("ongeza_faili", os.O_WRONLY|os.O_CREATE|os.O_APPEND, args)
+		},
+	},
+	"fungua_faili": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+
+			path, ok := args[0].(*object.String)
+			if !ok {
+				return newError("Samahani, jina la faili linahitaji kuwa NENO, sio %s", args[0].Type())
+			}
+
+			if info, err := os.Stat(path.Value); err != nil || info.IsDir() {
+				return newError("Nimeshindwa kufungua faili %q", path.Value)
+			}
+
+			return &object.File{Path: path.Value}
+		},
+	},
+	"regex": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+
+			re, err := toRegex(args[0])
+			if err != nil {
+				return err
+			}
+			return re
+		},
+	},
+	"kagua": {
+		Fn: func(args ...object.Object) object.Object {
+			re, str, err := regexArgs("kagua", 2, args)
+			if err != nil {
+				return err
+			}
+			return nativeBoolToBooleanObject(re.Value.MatchString(str))
+		},
+	},
+	"tafuta_zote": {
+		Fn: func(args ...object.Object) object.Object {
+			re, str, err := regexArgs("tafuta_zote", 2, args)
+			if err != nil {
+				return err
+			}
+
+			matches := re.Value.FindAllString(str, -1)
+			elements := make([]object.Object, len(matches))
+			for i, m := range matches {
+				elements[i] = &object.String{Value: m}
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"badilisha_regex": {
+		Fn: func(args ...object.Object) object.Object {
+			re, str, err := regexArgs("badilisha_regex", 3, args)
+			if err != nil {
+				return err
+			}
+
+			replacement, ok := args[2].(*object.String)
+			if !ok {
+				return newError("Samahani, badilisha_regex inahitaji NENO la kubadilisha, sio %s", args[2].Type())
+			}
+			return &object.String{Value: re.Value.ReplaceAllString(str, replacement.Value)}
+		},
+	},
+	"seti": {Fn: newSet},
+	"jozi": {Fn: newTuple},
+	"baiti": {Fn: newBytes},
+	"desimali": {Fn: newDecimal},
+	"namba": {Fn: toNumber},
+	"neno": {Fn: toString},
+	"boolean": {Fn: toBoolean},
+	"funguo": {Fn: dictKeys},
+	"thamani": {Fn: dictValues},
+	"vipengele": {Fn: dictItems},
+	"unganisha": {Fn: dictMerge},
+	"mfereji": {Fn: newChannel},
+	"kufuli": {Fn: newLock},
+	"kikundi": {Fn: newWaitGroup},
+	"endesha": {Fn: runCommand},
+	"endesha_mkondo": {Fn: startCommand},
+	"thibitisha": {Fn: assert},
+	"thibitisha_sawa": {Fn: assertEqual},
+	"sawa_kabisa": {Fn: strictEqual},
+	"nakili": {Fn: copyValue},
+	"panga_neno": {Fn: formatString},
+	"chapisha_vizuri": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+			fmt.Fprintln(Stdout, object.Pretty(args[0]))
+			return nil
+		},
+	},
+	"soma_baiti": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+
+			path, ok := args[0].(*object.String)
+			if !ok {
+				return newError("Samahani, jina la faili linahitaji kuwa NENO, sio %s", args[0].Type())
+			}
+
+			contents, err := os.ReadFile(path.Value)
+			if err != nil {
+				return newError("Nimeshindwa kusoma faili %q", path.Value)
+			}
+
+			return &object.Bytes{Value: contents}
+		},
+	},
+}
+
+// toRegex accepts either a regex object or a pattern string
+func toRegex(obj object.Object) (*object.Regex, *object.Error) {
+	switch obj := obj.(type) {
+	case *object.Regex:
+		return obj, nil
+	case *object.String:
+		re, err := object.CompileRegex(obj.Value)
+		if err != nil {
+			return nil, newError("Samahani, regex %q si sahihi", obj.Value)
+		}
+		return re, nil
+	default:
+		return nil, newError("Samahani, regex inahitaji kuwa NENO, sio %s", obj.Type())
+	}
+}
+
+// regexArgs checks the arguments shared by the regex builtins: a pattern
+// followed by the string to search
+func regexArgs(name string, want int, args []object.Object) (*object.Regex, string, *object.Error) {
+	if len(args) != want {
+		return nil, "", newError("Hoja hazilingani, tunahitaji=%d, tumepewa=%d", want, len(args))
+	}
+
+	re, err := toRegex(args[0])
+	if err != nil {
+		return nil, "", err
+	}
+
+	str, ok := args[1].(*object.String)
+	if !ok {
+		return nil, "", newError("Samahani, %s inahitaji NENO, sio %s", name, args[1].Type())
+	}
+
+	return re, str.Value, nil
+}
+
+var (
+	stdinReader *bufio.Reader
+	stdinSource io.Reader // the Stdin stdinReader reads from
+	stdinMu     sync.Mutex
+)
+
+// readLine reads a line from Stdin, without its line ending. The reader is
+// kept between calls, since a new one would lose what the last one had read
+// ahead, which matters when the input is piped in. ok is false once there is
+// nothing left to read.
+func readLine() (line string, ok bool, err error) {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+
+	if stdinReader == nil || stdinSource != Stdin {
+		stdinReader = bufio.NewReader(Stdin)
+		stdinSource = Stdin
+	}
+
+	line, err = stdinReader.ReadString('\n')
+	if err == io.EOF {
+		return strings.TrimRight(line, "\r\n"), line != "", nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimRight(line, "\r\n"), true, nil
+}
+
+// writeFile backs andika_faili and ongeza_faili, which only differ in
+// whether the file is truncated or appended to
+func writeFile(name string, flag int, args []object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+	}
+
+	path, ok := args[0].(*object.String)
+	if !ok {
+		return newError("Samahani, jina la faili linahitaji kuwa NENO, sio %s", args[0].Type())
+	}
+
+	var data []byte
+	switch arg := args[1].(type) {
+	case *object.String:
+		data = []byte(arg.Value)
+	case *object.Bytes:
+		data = arg.Value
+	default:
+		return newError("Samahani, %s inaandika NENO au BAITI tu, sio %s", name, args[1].Type())
+	}
+
+	file, err := os.OpenFile(path.Value, flag, 0644)
+	if err != nil {
+		return newError("Nimeshindwa kufungua faili %q", path.Value)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return newError("Nimeshindwa kuandika faili %q", path.Value)
+	}
+
+	return nil
 }
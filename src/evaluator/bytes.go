@@ -0,0 +1,190 @@
+package evaluator
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// newBytes is the 'baiti' builtin. It takes a string and an optional
+// encoding, an array of numbers from 0 to 255, or other bytes to copy.
+func newBytes(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("Samahani, hii function inapokea hoja 1 au 2, wewe umeweka %d", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.String:
+		encoding, err := encodingArg(args[1:])
+		if err != nil {
+			return err
+		}
+		return encodeString(arg.Value, encoding)
+	case *object.Array:
+		if len(args) == 2 {
+			return newError("Samahani, usimbaji unatumika na NENO tu")
+		}
+		data := make([]byte, len(arg.Elements))
+		for i, el := range arg.Elements {
+			n, ok := el.(*object.Integer)
+			if !ok || n.Value < 0 || n.Value > 255 {
+				return newError("Samahani, baiti inahitaji namba kati ya 0 na 255, sio %s", el.Inspect())
+			}
+			data[i] = byte(n.Value)
+		}
+		return &object.Bytes{Value: data}
+	case *object.Bytes:
+		return &object.Bytes{Value: append([]byte{}, arg.Value...)}
+	default:
+		return newError("Samahani, baiti haziwezi kutengenezwa kutoka %s", args[0].Type())
+	}
+}
+
+// encodingArg reads the optional encoding name, which defaults to utf-8
+func encodingArg(args []object.Object) (string, *object.Error) {
+	if len(args) == 0 {
+		return "utf-8", nil
+	}
+	name, ok := args[0].(*object.String)
+	if !ok {
+		return "", newError("Samahani, usimbaji unahitaji kuwa NENO, sio %s", args[0].Type())
+	}
+	switch strings.ToLower(name.Value) {
+	case "utf-8", "utf8":
+		return "utf-8", nil
+	case "ascii":
+		return "ascii", nil
+	case "latin-1", "latin1":
+		return "latin-1", nil
+	default:
+		return "", newError("Samahani, usimbaji %q haujulikani", name.Value)
+	}
+}
+
+func encodeString(str, encoding string) object.Object {
+	if encoding == "utf-8" {
+		return &object.Bytes{Value: []byte(str)}
+	}
+
+	max := rune(255)
+	if encoding == "ascii" {
+		max = 127
+	}
+	data := make([]byte, 0, len(str))
+	for _, r := range str {
+		if r > max {
+			return newError("Samahani, herufi %q haiwezi kusimbwa kwa %s", r, encoding)
+		}
+		data = append(data, byte(r))
+	}
+	return &object.Bytes{Value: data}
+}
+
+func decodeBytes(data []byte, encoding string) object.Object {
+	switch encoding {
+	case "utf-8":
+		if !utf8.Valid(data) {
+			return newError("Samahani, baiti hizi si utf-8 sahihi")
+		}
+		return &object.String{Value: string(data)}
+	case "ascii":
+		for _, b := range data {
+			if b > 127 {
+				return newError("Samahani, baiti hizi si ascii sahihi")
+			}
+		}
+		return &object.String{Value: string(data)}
+	default:
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return &object.String{Value: string(runes)}
+	}
+}
+
+func evalBytesIndexExpression(data, index object.Object) object.Object {
+	value := data.(*object.Bytes).Value
+	idx := index.(*object.Integer).Value
+
+	if idx < 0 || idx > int64(len(value)-1) {
+		return NULL
+	}
+	return &object.Integer{Value: int64(value[idx])}
+}
+
+func evalBytesInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.Bytes).Value
+	rightVal := right.(*object.Bytes).Value
+
+	switch operator {
+	case "+":
+		data := make([]byte, 0, len(leftVal)+len(rightVal))
+		data = append(data, leftVal...)
+		return &object.Bytes{Value: append(data, rightVal...)}
+	case "==":
+		return nativeBoolToBooleanObject(bytes.Equal(leftVal, rightVal))
+	case "!=":
+		return nativeBoolToBooleanObject(!bytes.Equal(leftVal, rightVal))
+	default:
+		return newError("Operesheni Haielweki: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
+
+func evalInBytesExpression(left object.Object, data *object.Bytes) object.Object {
+	switch left := left.(type) {
+	case *object.Integer:
+		if left.Value < 0 || left.Value > 255 {
+			return FALSE
+		}
+		return nativeBoolToBooleanObject(bytes.IndexByte(data.Value, byte(left.Value)) >= 0)
+	case *object.Bytes:
+		return nativeBoolToBooleanObject(bytes.Contains(data.Value, left.Value))
+	default:
+		return FALSE
+	}
+}
+
+// bytesMethod returns the method called name bound to data
+func bytesMethod(data *object.Bytes, name string) (object.Object, bool) {
+	switch name {
+	case "neno":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newError("Samahani, hii function inapokea hoja 0 au 1, wewe umeweka %d", len(args))
+			}
+			encoding, err := encodingArg(args)
+			if err != nil {
+				return err
+			}
+			return decodeBytes(data.Value, encoding)
+		}}, true
+	case "kata":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return newError("Samahani, hii function inapokea hoja 1 au 2, wewe umeweka %d", len(args))
+			}
+			bounds := []int64{0, int64(len(data.Value))}
+			for i, arg := range args {
+				n, ok := arg.(*object.Integer)
+				if !ok {
+					return newError("Samahani, kata inahitaji namba, sio %s", arg.Type())
+				}
+				bounds[i] = n.Value
+				if bounds[i] < 0 {
+					bounds[i] = 0
+				} else if bounds[i] > int64(len(data.Value)) {
+					bounds[i] = int64(len(data.Value))
+				}
+			}
+			if bounds[0] > bounds[1] {
+				bounds[0] = bounds[1]
+			}
+			return &object.Bytes{Value: append([]byte{}, data.Value[bounds[0]:bounds[1]]...)}
+		}}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,113 @@
+package evaluator
+
+import (
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+func evalClassStatement(node *ast.ClassStatement, env *object.Environment) object.Object {
+	class := &object.Class{
+		Name:    node.Name.Value,
+		Fields:  node.Fields,
+		Methods: make(map[string]*object.Function),
+		Env:     env,
+	}
+
+	if node.Constructor != nil {
+		class.Constructor = &object.Function{Name: class.Name, Parameters: node.Constructor.Parameters, Variadic: node.Constructor.Variadic, Generator: node.Constructor.Generator, Doc: node.Constructor.DocString(), Body: node.Constructor.Body, Env: env}
+	}
+
+	for _, m := range node.Methods {
+		class.Methods[m.Name.Value] = &object.Function{Name: class.Name + "." + m.Name.Value, Parameters: m.Function.Parameters, Variadic: m.Function.Variadic, Generator: m.Function.Generator, Doc: m.Function.DocString(), Body: m.Function.Body, Env: env}
+	}
+
+	if err := env.Set(node.Name.Value, class); isError(err) {
+		return err
+	}
+	return nil
+}
+
+func instantiateClass(class *object.Class, args []object.Object) object.Object {
+	instance := &object.Instance{Class: class, Fields: make(map[string]object.Object)}
+
+	// every instance gets its own copy of the field defaults
+	for _, field := range class.Fields {
+		val := Eval(field.Value, class.Env)
+		if isError(val) {
+			return val
+		}
+		instance.Fields[field.Name.Value] = val
+	}
+
+	if class.Constructor == nil {
+		if len(args) != 0 {
+			return newError("Muundo %s hauna 'unda', hauwezi kupewa hoja %d", class.Name, len(args))
+		}
+		return instance
+	}
+
+	if result := applyMethod(instance, class.Constructor, args); isError(result) {
+		return result
+	}
+
+	return instance
+}
+
+func applyMethod(instance *object.Instance, method *object.Function, args []object.Object) object.Object {
+	env := extendedFunctionEnv(method, args)
+	env.Set("hii", instance)
+	if method.Generator {
+		return newGenerator(method, env)
+	}
+
+	if profile != nil {
+		enterProfile(functionName(method), method.Body)
+	}
+	enterCall()
+	evaluated := unwrapReturnValue(Eval(method.Body, env))
+	leaveCall()
+	if profile != nil {
+		leaveProfile(method.Body)
+	}
+	if call, ok := evaluated.(*object.TailCall); ok {
+		result := callFunction(call.Function, call.Arguments)
+		if err, ok := result.(*object.Error); ok {
+			err.Trace = append(err.Trace, object.Frame{Function: functionName(call.Function), Position: call.Position})
+		}
+		return result
+	}
+	return evaluated
+}
+
+func evalPropertyAssignment(node *ast.AssignmentExpression, pe *ast.PropertyExpression, env *object.Environment) object.Object {
+	obj := Eval(pe.Object, env)
+	if isError(obj) {
+		return obj
+	}
+
+	instance, ok := obj.(*object.Instance)
+	if !ok {
+		return newError("Huwezi kubadilisha %s ya %s", pe.Property.Value, obj.Type())
+	}
+
+	value := Eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	// compound operators like += work on the current value of the field
+	op := node.Token.Literal
+	if len(op) >= 2 {
+		current, ok := instance.Fields[pe.Property.Value]
+		if !ok {
+			return newError("%s haina %s", instance.Class.Name, pe.Property.Value)
+		}
+		value = evalInfixExpression(op[:len(op)-1], current, value)
+		if isError(value) {
+			return value
+		}
+	}
+
+	instance.Fields[pe.Property.Value] = value
+	return nil
+}
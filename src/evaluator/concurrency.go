@@ -0,0 +1,295 @@
+package evaluator
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// ramani_sambamba is added here rather than in the builtins map, since it
+// calls back into the evaluator, which looks builtins up
+func init() {
+	builtins["ramani_sambamba"] = &object.Builtin{Fn: parallelMap}
+}
+
+// evalSpawnExpression runs the call on its own goroutine. The function and
+// its arguments are evaluated first, so any error in them is reported at
+// once instead of by subiri().
+func evalSpawnExpression(node *ast.SpawnExpression, env *object.Environment) object.Object {
+	function := Eval(node.Call.Function, env)
+	if isError(function) {
+		return function
+	}
+	args := evalArguments(function, node.Call.Arguments, env)
+	if len(args) == 1 && isError(args[0]) {
+		return args[0]
+	}
+
+	future := &object.Future{Done: make(chan struct{})}
+	go func() {
+		defer close(future.Done)
+
+		result := applyFunction(function, args)
+		if err, ok := result.(*object.Error); ok {
+			addTraceFrame(err, function, node.Call)
+		}
+		if result == nil {
+			result = NULL
+		}
+		future.Result = result
+	}()
+
+	return future
+}
+
+func futureMethod(future *object.Future, name string) (object.Object, bool) {
+	switch name {
+	case "subiri":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			<-future.Done
+			return future.Result
+		}}, true
+	}
+	return nil, false
+}
+
+// newChannel backs mfereji(). A channel made without a size holds nothing,
+// so sending waits until another function receives.
+func newChannel(args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newError("Samahani, mfereji inapokea hoja 0 au 1, wewe umeweka %d", len(args))
+	}
+
+	size := int64(0)
+	if len(args) == 1 {
+		n, ok := args[0].(*object.Integer)
+		if !ok {
+			return newError("Samahani, ukubwa wa mfereji unahitaji kuwa NAMBA, sio %s", args[0].Type())
+		}
+		if n.Value < 0 {
+			return newError("Samahani, ukubwa wa mfereji hauwezi kuwa %d", n.Value)
+		}
+		size = n.Value
+	}
+
+	return &object.Channel{Value: make(chan object.Object, size)}
+}
+
+func channelMethod(ch *object.Channel, name string) (object.Object, bool) {
+	switch name {
+	case "tuma":
+		return &object.Builtin{Fn: func(args ...object.Object) (result object.Object) {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+			// sending on a closed channel panics, even when it is closed
+			// while the send is waiting
+			defer func() {
+				if recover() != nil {
+					result = newError("Huwezi kutuma kwenye mfereji uliofungwa")
+				}
+			}()
+			ch.Value <- args[0]
+			return nil
+		}}, true
+	case "pokea":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			if val, ok := <-ch.Value; ok {
+				return val
+			}
+			return NULL
+		}}, true
+	case "funga":
+		return &object.Builtin{Fn: func(args ...object.Object) (result object.Object) {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			defer func() {
+				if recover() != nil {
+					result = newError("Mfereji umeshafungwa")
+				}
+			}()
+			close(ch.Value)
+			return nil
+		}}, true
+	}
+	return nil, false
+}
+
+func newLock(args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+	}
+	return object.NewLock()
+}
+
+func lockMethod(lock *object.Lock, name string) (object.Object, bool) {
+	switch name {
+	case "funga":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			lock.Held <- struct{}{}
+			return nil
+		}}, true
+	case "fungua":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			select {
+			case <-lock.Held:
+				return nil
+			default:
+				return newError("Kufuli haijafungwa")
+			}
+		}}, true
+	case "linda":
+		// linda runs a function while holding the lock, and lets go of it
+		// even if the function fails
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+			lock.Held <- struct{}{}
+			defer func() { <-lock.Held }()
+			return applyFunction(args[0], nil)
+		}}, true
+	}
+	return nil, false
+}
+
+func newWaitGroup(args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+	}
+	return &object.WaitGroup{}
+}
+
+func waitGroupMethod(wg *object.WaitGroup, name string) (object.Object, bool) {
+	switch name {
+	case "ongeza":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newError("Samahani, ongeza inapokea hoja 0 au 1, wewe umeweka %d", len(args))
+			}
+			n := int64(1)
+			if len(args) == 1 {
+				num, ok := args[0].(*object.Integer)
+				if !ok {
+					return newError("Samahani, ongeza inahitaji NAMBA, sio %s", args[0].Type())
+				}
+				n = num.Value
+			}
+			return addToWaitGroup(wg, int(n))
+		}}, true
+	case "maliza":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			return addToWaitGroup(wg, -1)
+		}}, true
+	case "subiri":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			wg.Value.Wait()
+			return nil
+		}}, true
+	}
+	return nil, false
+}
+
+// addToWaitGroup turns the panic of a count going below zero into an error
+func addToWaitGroup(wg *object.WaitGroup, n int) (result object.Object) {
+	defer func() {
+		if recover() != nil {
+			result = newError("Kikundi kimemaliza zaidi ya kilivyoongeza")
+		}
+	}()
+	wg.Value.Add(n)
+	return nil
+}
+
+// parallelMap backs ramani_sambamba(), which calls a function on every
+// element of an array using a pool of goroutines, one per CPU unless told
+// otherwise. Each call gets its own environment enclosing the function's, and
+// environments lock themselves, so the workers can share what they close over.
+func parallelMap(args ...object.Object) object.Object {
+	if len(args) < 2 || len(args) > 3 {
+		return newError("Samahani, ramani_sambamba inapokea hoja 2 au 3, wewe umeweka %d", len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("Samahani, ramani_sambamba inahitaji ORODHA, sio %s", args[0].Type())
+	}
+	fn := args[1]
+	switch fn.(type) {
+	case *object.Function, *object.Builtin, *object.BoundMethod, *object.Class:
+	default:
+		return newError("Hii sio function: %s", fn.Type())
+	}
+
+	workers := runtime.NumCPU()
+	if len(args) == 3 {
+		n, ok := args[2].(*object.Integer)
+		if !ok || n.Value < 1 {
+			return newError("Samahani, idadi ya wafanyakazi inahitaji kuwa NAMBA kubwa kuliko 0, sio %s", args[2].Inspect())
+		}
+		workers = int(n.Value)
+	}
+	if workers > len(arr.Elements) {
+		workers = len(arr.Elements)
+	}
+
+	elements := arr.Elements
+	results := make([]object.Object, len(elements))
+	jobs := make(chan int)
+	var failed int32 // set once a call fails, so the rest are skipped
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if atomic.LoadInt32(&failed) != 0 {
+					continue
+				}
+				result := applyFunction(fn, []object.Object{elements[i]})
+				if result == nil {
+					result = NULL
+				}
+				if isError(result) {
+					atomic.StoreInt32(&failed, 1)
+				}
+				results[i] = result
+			}
+		}()
+	}
+	for i := range elements {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// the error of the earliest element is given, whichever failed first
+	for _, result := range results {
+		if isError(result) {
+			return result
+		}
+	}
+	return &object.Array{Elements: results}
+}
@@ -0,0 +1,88 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// toNumber backs namba(). A string becomes a NAMBA if it is a whole number,
+// a NAMBA_KUBWA if it is too big for one, or else a DESIMALI.
+func toNumber(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Integer, *object.Float, *object.BigInt, *object.Decimal:
+		return arg
+	case *object.Boolean:
+		if arg.Value {
+			return &object.Integer{Value: 1}
+		}
+		return &object.Integer{Value: 0}
+	case *object.String:
+		str := strings.TrimSpace(arg.Value)
+		if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return &object.Integer{Value: n}
+		}
+		if n, ok := new(big.Int).SetString(str, 10); ok {
+			return &object.BigInt{Value: n}
+		}
+		// ParseFloat also reads words like "Inf" and "NaN", which aren't
+		// numbers a user would write
+		if f, err := strconv.ParseFloat(str, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return &object.Float{Value: f}
+		}
+		return newError("Samahani, %q haiwezi kugeuzwa kuwa namba", arg.Value)
+	default:
+		return newError("Samahani, %s haiwezi kugeuzwa kuwa namba", arg.Type())
+	}
+}
+
+// toString backs neno(), giving anything as it would be printed
+func toString(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	if str, ok := args[0].(*object.String); ok {
+		return str
+	}
+	return &object.String{Value: args[0].Inspect()}
+}
+
+// toBoolean backs boolean(). Numbers are kweli unless they are 0, and only
+// the strings "kweli" and "sikweli" can be turned into a boolean.
+func toBoolean(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Boolean:
+		return arg
+	case *object.Null:
+		return FALSE
+	case *object.Integer:
+		return nativeBoolToBooleanObject(arg.Value != 0)
+	case *object.Float:
+		return nativeBoolToBooleanObject(arg.Value != 0)
+	case *object.BigInt:
+		return nativeBoolToBooleanObject(arg.Value.Sign() != 0)
+	case *object.Decimal:
+		return nativeBoolToBooleanObject(arg.Value.Sign() != 0)
+	case *object.String:
+		switch strings.TrimSpace(arg.Value) {
+		case "kweli":
+			return TRUE
+		case "sikweli":
+			return FALSE
+		}
+		return newError("Samahani, %q haiwezi kugeuzwa kuwa BOOLEAN", arg.Value)
+	default:
+		return newError("Samahani, %s haiwezi kugeuzwa kuwa BOOLEAN", arg.Type())
+	}
+}
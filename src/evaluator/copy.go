@@ -0,0 +1,57 @@
+package evaluator
+
+import (
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// deepCopy copies obj and everything it holds, so that changing the copy
+// never changes obj. copies maps what has been copied already to its copy,
+// which keeps collections that hold themselves, or share an element, the
+// same way in the copy. Values that can't be changed, and things like files
+// and connections, are not copied.
+func deepCopy(obj object.Object, copies map[object.Object]object.Object) object.Object {
+	if c, ok := copies[obj]; ok {
+		return c
+	}
+
+	switch obj := obj.(type) {
+	case *object.Array:
+		c := &object.Array{Elements: make([]object.Object, len(obj.Elements))}
+		copies[obj] = c
+		for i, el := range obj.Elements {
+			c.Elements[i] = deepCopy(el, copies)
+		}
+		return c
+	case *object.Dict:
+		c := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair, len(obj.Pairs))}
+		copies[obj] = c
+		for hash, pair := range obj.Pairs {
+			c.Pairs[hash] = object.DictPair{Key: pair.Key, Value: deepCopy(pair.Value, copies)}
+		}
+		return c
+	case *object.Set:
+		c := &object.Set{Elements: make(map[object.HashKey]object.Object, len(obj.Elements))}
+		copies[obj] = c
+		for hash, el := range obj.Elements {
+			c.Elements[hash] = el
+		}
+		return c
+	case *object.Instance:
+		c := &object.Instance{Class: obj.Class, Fields: make(map[string]object.Object, len(obj.Fields))}
+		copies[obj] = c
+		for name, value := range obj.Fields {
+			c.Fields[name] = deepCopy(value, copies)
+		}
+		return c
+	default:
+		return obj
+	}
+}
+
+// copyValue is the 'nakili' builtin
+func copyValue(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	return deepCopy(args[0], make(map[object.Object]object.Object))
+}
@@ -0,0 +1,229 @@
+package evaluator
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+func init() {
+	registerModule("csv", map[string]object.BuiltinFunction{
+		"soma":   csvRead,
+		"andika": csvWrite,
+	})
+}
+
+// csvOptions are set by the dict the csv functions take as their last
+// argument
+type csvOptions struct {
+	comma   rune     // kitenganishi: what separates the fields
+	header  bool     // kichwa: whether soma turns rows into dicts
+	columns []string // vichwa: the columns andika writes dicts in
+}
+
+func parseCSVOptions(name string, obj object.Object) (csvOptions, *object.Error) {
+	opts := csvOptions{comma: ','}
+
+	dict, ok := obj.(*object.Dict)
+	if !ok {
+		return opts, newError("Samahani, machaguo ya csv.%s yanahitaji kuwa KAMUSI, sio %s", name, obj.Type())
+	}
+	for _, pair := range dict.Pairs {
+		key := plainString(pair.Key)
+		switch {
+		case key == "kitenganishi":
+			sep, ok := pair.Value.(*object.String)
+			if !ok || utf8.RuneCountInString(sep.Value) != 1 {
+				return opts, newError("Samahani, kitenganishi kinahitaji kuwa herufi moja")
+			}
+			opts.comma, _ = utf8.DecodeRuneInString(sep.Value)
+		case key == "kichwa" && name == "soma":
+			header, ok := pair.Value.(*object.Boolean)
+			if !ok {
+				return opts, newError("Samahani, kichwa kinahitaji kuwa kweli au sikweli, sio %s", pair.Value.Type())
+			}
+			opts.header = header.Value
+		case key == "vichwa" && name == "andika":
+			arr, ok := pair.Value.(*object.Array)
+			if !ok {
+				return opts, newError("Samahani, vichwa vinahitaji kuwa ORODHA, sio %s", pair.Value.Type())
+			}
+			for _, col := range arr.Elements {
+				opts.columns = append(opts.columns, plainString(col))
+			}
+		default:
+			return opts, newError("Samahani, csv.%s haina chaguo %q", name, key)
+		}
+	}
+	return opts, nil
+}
+
+// csvRead reads a CSV file into an array of rows. Each row is an array of
+// strings, or with kichwa a dict keyed by the names in the first row.
+func csvRead(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("Samahani, csv.soma inapokea hoja 1 au 2, wewe umeweka %d", len(args))
+	}
+
+	path, errObj := stringArg("csv.soma", args, 0)
+	if errObj != nil {
+		return errObj
+	}
+	opts := csvOptions{comma: ','}
+	if len(args) == 2 {
+		if opts, errObj = parseCSVOptions("soma", args[1]); errObj != nil {
+			return errObj
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return newError("Nimeshindwa kufungua faili %q", path)
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.Comma = opts.comma
+	if !opts.header {
+		// without a header nothing says how many fields a row should have
+		r.FieldsPerRecord = -1
+	}
+	records, err := r.ReadAll()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return newError("Samahani, %q si CSV sahihi, kosa liko mstari %d", path, parseErr.Line)
+		}
+		return newError("Nimeshindwa kusoma faili %q", path)
+	}
+
+	rows := make([]object.Object, 0, len(records))
+	if !opts.header {
+		for _, record := range records {
+			rows = append(rows, stringArray(record))
+		}
+		return &object.Array{Elements: rows}
+	}
+
+	if len(records) == 0 {
+		return &object.Array{Elements: rows}
+	}
+	columns := records[0]
+	for _, record := range records[1:] {
+		fields := make(map[string]object.Object, len(columns))
+		for i, col := range columns {
+			fields[col] = &object.String{Value: record[i]}
+		}
+		rows = append(rows, newDict(fields))
+	}
+	return &object.Array{Elements: rows}
+}
+
+func stringArray(strs []string) *object.Array {
+	elements := make([]object.Object, len(strs))
+	for i, s := range strs {
+		elements[i] = &object.String{Value: s}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// csvWrite writes an array of rows to a CSV file, replacing what was in it.
+// Rows may be arrays, or dicts, which are written under a first row of
+// column names.
+func csvWrite(args ...object.Object) object.Object {
+	if len(args) < 2 || len(args) > 3 {
+		return newError("Samahani, csv.andika inapokea hoja 2 au 3, wewe umeweka %d", len(args))
+	}
+
+	path, errObj := stringArg("csv.andika", args, 0)
+	if errObj != nil {
+		return errObj
+	}
+	data, ok := args[1].(*object.Array)
+	if !ok {
+		return newError("Samahani, csv.andika inahitaji ORODHA ya safu, sio %s", args[1].Type())
+	}
+	opts := csvOptions{comma: ','}
+	if len(args) == 3 {
+		if opts, errObj = parseCSVOptions("andika", args[2]); errObj != nil {
+			return errObj
+		}
+	}
+
+	records, errObj := csvRecords(data, opts.columns)
+	if errObj != nil {
+		return errObj
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return newError("Nimeshindwa kuandika faili %q", path)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Comma = opts.comma
+	if err := w.WriteAll(records); err != nil {
+		return newError("Nimeshindwa kuandika faili %q", path)
+	}
+	return NULL
+}
+
+// csvRecords turns rows into the fields to write. When the rows are dicts
+// and no columns are given, the columns are the keys of the first row,
+// sorted.
+func csvRecords(data *object.Array, columns []string) ([][]string, *object.Error) {
+	if len(data.Elements) == 0 {
+		return nil, nil
+	}
+
+	if _, ok := data.Elements[0].(*object.Dict); !ok {
+		records := make([][]string, len(data.Elements))
+		for i, row := range data.Elements {
+			arr, ok := row.(*object.Array)
+			if !ok {
+				return nil, newError("Samahani, safu za csv zinahitaji kuwa ORODHA zote au KAMUSI zote, sio %s", row.Type())
+			}
+			for _, field := range arr.Elements {
+				records[i] = append(records[i], csvField(field))
+			}
+		}
+		return records, nil
+	}
+
+	if columns == nil {
+		for _, pair := range data.Elements[0].(*object.Dict).Pairs {
+			columns = append(columns, plainString(pair.Key))
+		}
+		sort.Strings(columns)
+	}
+
+	records := [][]string{columns}
+	for _, row := range data.Elements {
+		dict, ok := row.(*object.Dict)
+		if !ok {
+			return nil, newError("Samahani, safu za csv zinahitaji kuwa ORODHA zote au KAMUSI zote, sio %s", row.Type())
+		}
+		record := make([]string, len(columns))
+		for i, col := range columns {
+			if pair, ok := dict.Pairs[(&object.String{Value: col}).HashKey()]; ok {
+				record[i] = csvField(pair.Value)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// csvField is how a value is written to a CSV file, where tupu is an empty
+// field
+func csvField(obj object.Object) string {
+	if obj == NULL {
+		return ""
+	}
+	return plainString(obj)
+}
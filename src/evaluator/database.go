@@ -0,0 +1,179 @@
+package evaluator
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+func init() {
+	registerModule("hifadhidata", map[string]object.BuiltinFunction{
+		"fungua": openDatabase,
+	})
+}
+
+// openDatabase opens the SQLite database in a file, making it if it isn't
+// there. ":memory:" opens one that is only kept in memory.
+func openDatabase(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	path, errObj := stringArg("hifadhidata.fungua", args, 0)
+	if errObj != nil {
+		return errObj
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err == nil {
+		// sql.Open doesn't touch the file, so a bad path is found here
+		err = db.Ping()
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "unknown driver") {
+			return newError("Samahani, hifadhidata haipatikani kwenye mfumo huu")
+		}
+		return newError("Nimeshindwa kufungua hifadhidata %q: %s", path, err)
+	}
+	if path == ":memory:" {
+		// every connection to :memory: gets a database of its own
+		db.SetMaxOpenConns(1)
+	}
+	return &object.Database{Path: path, Value: db}
+}
+
+func databaseMethod(db *object.Database, name string) (object.Object, bool) {
+	switch name {
+	case "tekeleza":
+		// tekeleza runs a statement that doesn't return rows, like INSERT
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			query, params, errObj := queryArgs("tekeleza", args)
+			if errObj != nil {
+				return errObj
+			}
+			result, err := db.Value.Exec(query, params...)
+			if err != nil {
+				return newError("Hifadhidata imeshindwa: %s", err)
+			}
+			changed, _ := result.RowsAffected()
+			lastID, _ := result.LastInsertId()
+			return newDict(map[string]object.Object{
+				"zilizobadilika": &object.Integer{Value: changed},
+				"id_ya_mwisho":   &object.Integer{Value: lastID},
+			})
+		}}, true
+	case "uliza":
+		// uliza runs a query and gives its rows as dicts keyed by column
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			query, params, errObj := queryArgs("uliza", args)
+			if errObj != nil {
+				return errObj
+			}
+			rows, err := db.Value.Query(query, params...)
+			if err != nil {
+				return newError("Hifadhidata imeshindwa: %s", err)
+			}
+			defer rows.Close()
+			return readRows(rows)
+		}}, true
+	case "funga":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			if err := db.Value.Close(); err != nil {
+				return newError("Nimeshindwa kufunga hifadhidata: %s", err)
+			}
+			return NULL
+		}}, true
+	}
+	return nil, false
+}
+
+// queryArgs reads the SQL of a statement and the values for its ? marks.
+// The values are passed to SQLite apart from the SQL, so they can't change
+// what it does.
+func queryArgs(method string, args []object.Object) (string, []interface{}, *object.Error) {
+	if len(args) == 0 {
+		return "", nil, newError("Samahani, %s inahitaji SQL", method)
+	}
+	query, errObj := stringArg(method, args, 0)
+	if errObj != nil {
+		return "", nil, errObj
+	}
+
+	params := make([]interface{}, len(args)-1)
+	for i, arg := range args[1:] {
+		switch arg := arg.(type) {
+		case *object.Null:
+			params[i] = nil
+		case *object.Boolean:
+			params[i] = arg.Value
+		case *object.Integer:
+			params[i] = arg.Value
+		case *object.Float:
+			params[i] = arg.Value
+		case *object.String:
+			params[i] = arg.Value
+		case *object.Bytes:
+			params[i] = arg.Value
+		case *object.Time:
+			params[i] = arg.Value
+		default:
+			return "", nil, newError("Samahani, %s haiwezi kuhifadhiwa kwenye hifadhidata", arg.Type())
+		}
+	}
+	return query, params, nil
+}
+
+func readRows(rows *sql.Rows) object.Object {
+	columns, err := rows.Columns()
+	if err != nil {
+		return newError("Hifadhidata imeshindwa: %s", err)
+	}
+
+	result := []object.Object{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return newError("Hifadhidata imeshindwa: %s", err)
+		}
+
+		fields := make(map[string]object.Object, len(columns))
+		for i, col := range columns {
+			fields[col] = fromSQL(values[i])
+		}
+		result = append(result, newDict(fields))
+	}
+	if err := rows.Err(); err != nil {
+		return newError("Hifadhidata imeshindwa: %s", err)
+	}
+	return &object.Array{Elements: result}
+}
+
+func fromSQL(value interface{}) object.Object {
+	switch value := value.(type) {
+	case nil:
+		return NULL
+	case bool:
+		return nativeBoolToBooleanObject(value)
+	case int64:
+		return &object.Integer{Value: value}
+	case float64:
+		return &object.Float{Value: value}
+	case string:
+		return &object.String{Value: value}
+	case []byte:
+		return &object.Bytes{Value: append([]byte{}, value...)}
+	case time.Time:
+		return &object.Time{Value: value}
+	default:
+		return &object.String{Value: fmt.Sprint(value)}
+	}
+}
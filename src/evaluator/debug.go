@@ -0,0 +1,39 @@
+package evaluator
+
+import (
+	"sync/atomic"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// Debugger is told about every statement just before it runs, along with the
+// environment it runs in and how many function calls deep it is, which is
+// what 'nuru debug' needs to stop at breakpoints and step through code.
+type Debugger interface {
+	Statement(stmt ast.Statement, env *object.Environment, depth int)
+}
+
+var (
+	debugger  Debugger
+	callDepth int64 // the number of function calls being run, changed atomically
+)
+
+// SetDebugger makes d see every statement before it runs. A nil d, which is
+// the default, turns debugging off.
+func SetDebugger(d Debugger) {
+	debugger = d
+}
+
+func enterCall() {
+	atomic.AddInt64(&callDepth, 1)
+}
+
+func leaveCall() {
+	atomic.AddInt64(&callDepth, -1)
+}
+
+// depth is how many function calls are being run, for the debugger
+func depth() int {
+	return int(atomic.LoadInt64(&callDepth))
+}
@@ -0,0 +1,137 @@
+package evaluator
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// decimalPlaces is how many decimal places are kept when a division does
+// not give an exact answer, like 1 / 3
+const decimalPlaces = 28
+
+// newDecimal is the 'desimali' builtin
+func newDecimal(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	if str, ok := args[0].(*object.String); ok {
+		value, ok := new(big.Rat).SetString(strings.TrimSpace(str.Value))
+		if !ok || strings.Contains(str.Value, "/") {
+			return newError("Samahani, %q si desimali sahihi", str.Value)
+		}
+		return &object.Decimal{Value: value}
+	}
+
+	value := toDecimal(args[0])
+	if value == nil {
+		return newError("Samahani, desimali haiwezi kutengenezwa kutoka %s", args[0].Type())
+	}
+	return &object.Decimal{Value: value}
+}
+
+// toDecimal gives the exact value of a number as a big.Rat. A Float is
+// read the way it prints, so 0.1 becomes exactly 0.1.
+func toDecimal(obj object.Object) *big.Rat {
+	switch obj := obj.(type) {
+	case *object.Decimal:
+		return obj.Value
+	case *object.Integer:
+		return new(big.Rat).SetInt64(obj.Value)
+	case *object.BigInt:
+		return new(big.Rat).SetInt(obj.Value)
+	case *object.Float:
+		value, ok := new(big.Rat).SetString(strconv.FormatFloat(obj.Value, 'g', -1, 64))
+		if !ok {
+			return nil
+		}
+		return value
+	}
+	return nil
+}
+
+func isDecimalOperand(obj object.Object) bool {
+	switch obj.Type() {
+	case object.DECIMAL_OBJ, object.INTEGER_OBJ, object.BIGINT_OBJ, object.FLOAT_OBJ:
+		return true
+	}
+	return false
+}
+
+// roundDecimal keeps only decimalPlaces decimal places of value
+func roundDecimal(value *big.Rat) *big.Rat {
+	rounded, _ := new(big.Rat).SetString(value.FloatString(decimalPlaces))
+	return rounded
+}
+
+func evalDecimalInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := toDecimal(left)
+	if leftVal == nil {
+		return newError("%s haiwezi kuwa desimali", left.Inspect())
+	}
+	rightVal := toDecimal(right)
+	if rightVal == nil {
+		return newError("%s haiwezi kuwa desimali", right.Inspect())
+	}
+
+	switch operator {
+	case "+":
+		return &object.Decimal{Value: new(big.Rat).Add(leftVal, rightVal)}
+	case "-":
+		return &object.Decimal{Value: new(big.Rat).Sub(leftVal, rightVal)}
+	case "*":
+		return &object.Decimal{Value: new(big.Rat).Mul(leftVal, rightVal)}
+	case "/":
+		if rightVal.Sign() == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
+		return &object.Decimal{Value: roundDecimal(new(big.Rat).Quo(leftVal, rightVal))}
+	case "%":
+		if rightVal.Sign() == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
+		quo := new(big.Rat).Quo(leftVal, rightVal)
+		truncated := new(big.Int).Quo(quo.Num(), quo.Denom())
+		product := new(big.Rat).Mul(rightVal, new(big.Rat).SetInt(truncated))
+		return &object.Decimal{Value: new(big.Rat).Sub(leftVal, product)}
+	case "**":
+		if !rightVal.IsInt() || !rightVal.Num().IsInt64() {
+			return newError("Desimali inaweza kupandishwa kwa namba kamili tu")
+		}
+		exp := rightVal.Num().Int64()
+		negative := exp < 0
+		if negative {
+			exp = -exp
+		}
+		e := big.NewInt(exp)
+		result := new(big.Rat).SetFrac(
+			new(big.Int).Exp(leftVal.Num(), e, nil),
+			new(big.Int).Exp(leftVal.Denom(), e, nil),
+		)
+		if negative {
+			if result.Sign() == 0 {
+				return newError("Huwezi kugawanya kwa sifuri")
+			}
+			result = roundDecimal(result.Inv(result))
+		}
+		return &object.Decimal{Value: result}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) < 0)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) <= 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) > 0)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) >= 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal.Cmp(rightVal) != 0)
+	default:
+		return newError("Operesheni Haielweki: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
@@ -0,0 +1,70 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+func evalDestructuringStatement(node *ast.DestructuringStatement, env *object.Environment) object.Object {
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	names := make([]string, len(node.Names))
+	for i, name := range node.Names {
+		names[i] = name.Value
+	}
+	values, err := destructure(val, names, node.Dict)
+	if err != nil {
+		return err
+	}
+
+	for i, name := range names {
+		if err := env.Set(name, values[i]); isError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// destructure gives the values for names from val: the elements of an array
+// or tuple with exactly as many, or the values of a dict under the names
+func destructure(val object.Object, names []string, dict bool) ([]object.Object, *object.Error) {
+	pattern := "[" + strings.Join(names, ", ") + "]"
+	if dict {
+		pattern = "{" + strings.Join(names, ", ") + "}"
+	}
+
+	if dict {
+		d, ok := val.(*object.Dict)
+		if !ok {
+			return nil, newError("Samahani, %s inahitaji KAMUSI, sio %s", pattern, val.Type())
+		}
+		values := make([]object.Object, len(names))
+		for i, name := range names {
+			pair, ok := d.Pairs[(&object.String{Value: name}).HashKey()]
+			if !ok {
+				return nil, newError("Samahani, kamusi haina ufunguo %q", name)
+			}
+			values[i] = pair.Value
+		}
+		return values, nil
+	}
+
+	var elements []object.Object
+	switch val := val.(type) {
+	case *object.Array:
+		elements = val.Elements
+	case *object.Tuple:
+		elements = val.Elements
+	default:
+		return nil, newError("Samahani, %s inahitaji ORODHA, sio %s", pattern, val.Type())
+	}
+	if len(elements) != len(names) {
+		return nil, newError("Samahani, %s inahitaji vitu %d, lakini imepewa %d", pattern, len(names), len(elements))
+	}
+	return elements, nil
+}
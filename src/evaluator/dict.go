@@ -0,0 +1,92 @@
+package evaluator
+
+import (
+	"sort"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// sortedPairs returns the pairs of a dict ordered by how their keys print,
+// the same order 'kwa' loops over them in
+func sortedPairs(dict *object.Dict) []object.DictPair {
+	pairs := make([]object.DictPair, 0, len(dict.Pairs))
+	for _, pair := range dict.Pairs {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Key.Inspect() < pairs[j].Key.Inspect()
+	})
+	return pairs
+}
+
+// dictArg checks the arguments of the dict builtins, which take one dict
+func dictArg(name string, args []object.Object) (*object.Dict, *object.Error) {
+	if len(args) != 1 {
+		return nil, newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	dict, ok := args[0].(*object.Dict)
+	if !ok {
+		return nil, newError("Samahani, %s inahitaji KAMUSI, sio %s", name, args[0].Type())
+	}
+	return dict, nil
+}
+
+func dictKeys(args ...object.Object) object.Object {
+	dict, err := dictArg("funguo", args)
+	if err != nil {
+		return err
+	}
+	pairs := sortedPairs(dict)
+	keys := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+	}
+	return &object.Array{Elements: keys}
+}
+
+func dictValues(args ...object.Object) object.Object {
+	dict, err := dictArg("thamani", args)
+	if err != nil {
+		return err
+	}
+	pairs := sortedPairs(dict)
+	values := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		values[i] = pair.Value
+	}
+	return &object.Array{Elements: values}
+}
+
+// dictItems gives every pair of a dict as a [key, value] array
+func dictItems(args ...object.Object) object.Object {
+	dict, err := dictArg("vipengele", args)
+	if err != nil {
+		return err
+	}
+	pairs := sortedPairs(dict)
+	items := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		items[i] = &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+	}
+	return &object.Array{Elements: items}
+}
+
+// dictMerge makes a new dict with the pairs of every dict given. When a key
+// is in more than one, the value of the last one is kept.
+func dictMerge(args ...object.Object) object.Object {
+	if len(args) < 2 {
+		return newError("Samahani, unganisha inahitaji kamusi 2 au zaidi, wewe umeweka %d", len(args))
+	}
+
+	merged := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+	for _, arg := range args {
+		dict, ok := arg.(*object.Dict)
+		if !ok {
+			return newError("Samahani, unganisha inahitaji KAMUSI, sio %s", arg.Type())
+		}
+		for key, pair := range dict.Pairs {
+			merged.Pairs[key] = pair
+		}
+	}
+	return merged
+}
@@ -0,0 +1,107 @@
+package evaluator
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// Like json, the base64 and hex modules fungua (encode) bytes into a string
+// and tengua (decode) such a string back into bytes
+func init() {
+	registerModule("base64", map[string]object.BuiltinFunction{
+		"fungua": func(args ...object.Object) object.Object {
+			return base64Encode("fungua", base64.StdEncoding, args)
+		},
+		"tengua": func(args ...object.Object) object.Object {
+			return base64Decode("tengua", base64.StdEncoding, args)
+		},
+		// the url variants use - and _ instead of + and /, so the result
+		// can go in a url or a file name
+		"fungua_url": func(args ...object.Object) object.Object {
+			return base64Encode("fungua_url", base64.URLEncoding, args)
+		},
+		"tengua_url": func(args ...object.Object) object.Object {
+			return base64Decode("tengua_url", base64.URLEncoding, args)
+		},
+	})
+	registerModule("hex", map[string]object.BuiltinFunction{
+		"fungua": hexEncode,
+		"tengua": hexDecode,
+	})
+}
+
+// encodingInput is the data to encode, which may be bytes, or a string
+// whose utf-8 bytes are encoded
+func encodingInput(name string, args []object.Object) ([]byte, *object.Error) {
+	if len(args) != 1 {
+		return nil, newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.String:
+		return []byte(arg.Value), nil
+	case *object.Bytes:
+		return arg.Value, nil
+	default:
+		return nil, newError("Samahani, %s inahitaji NENO au BAITI, sio %s", name, arg.Type())
+	}
+}
+
+func base64Encode(name string, enc *base64.Encoding, args []object.Object) object.Object {
+	data, err := encodingInput("base64."+name, args)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: enc.EncodeToString(data)}
+}
+
+func base64Decode(name string, enc *base64.Encoding, args []object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	str, errObj := stringArg("base64."+name, args, 0)
+	if errObj != nil {
+		return errObj
+	}
+
+	data, err := enc.DecodeString(str)
+	if err != nil {
+		var corrupt base64.CorruptInputError
+		if errors.As(err, &corrupt) {
+			return newError("Base64 si sahihi karibu na herufi %d", int64(corrupt))
+		}
+		return newError("Base64 si sahihi")
+	}
+	return &object.Bytes{Value: data}
+}
+
+func hexEncode(args ...object.Object) object.Object {
+	data, err := encodingInput("hex.fungua", args)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: hex.EncodeToString(data)}
+}
+
+func hexDecode(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	str, errObj := stringArg("hex.tengua", args, 0)
+	if errObj != nil {
+		return errObj
+	}
+
+	data, err := hex.DecodeString(str)
+	if err != nil {
+		var invalid hex.InvalidByteError
+		if errors.As(err, &invalid) {
+			return newError("Hex si sahihi: %q si herufi ya hex", rune(invalid))
+		}
+		return newError("Hex si sahihi: idadi ya herufi zake ni witiri")
+	}
+	return &object.Bytes{Value: data}
+}
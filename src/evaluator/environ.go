@@ -0,0 +1,51 @@
+package evaluator
+
+import (
+	"os"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+func init() {
+	registerModule("mazingira", map[string]object.BuiltinFunction{
+		"pata": getEnv,
+		"weka": setEnv,
+	})
+}
+
+// getEnv gives the value of an environment variable, or tupu when it isn't
+// set, which an empty value can be told apart from
+func getEnv(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	name, ok := args[0].(*object.String)
+	if !ok {
+		return newError("Samahani, mazingira.pata inahitaji NENO, sio %s", args[0].Type())
+	}
+	value, ok := os.LookupEnv(name.Value)
+	if !ok {
+		return NULL
+	}
+	return &object.String{Value: value}
+}
+
+// setEnv sets an environment variable for the program and the commands it
+// runs
+func setEnv(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+	}
+
+	for _, arg := range args {
+		if arg.Type() != object.STRING_OBJ {
+			return newError("Samahani, mazingira.weka inahitaji NENO, sio %s", arg.Type())
+		}
+	}
+	name, value := args[0].(*object.String), args[1].(*object.String)
+	if err := os.Setenv(name.Value, value.Value); err != nil {
+		return newError("Nimeshindwa kuweka %s: %s", name.Value, err)
+	}
+	return NULL
+}
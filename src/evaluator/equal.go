@@ -0,0 +1,86 @@
+package evaluator
+
+import (
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// deepEqual reports whether a and b hold the same values, going into arrays,
+// dicts and tuples element by element. A pair already being compared further
+// up counts as equal, so collections that hold themselves don't go on
+// forever. When strict is set the values must also be of the same type, so
+// that 1 and 1.0 differ.
+func deepEqual(a, b object.Object, strict bool, comparing map[[2]object.Object]bool) bool {
+	if a == b {
+		return true
+	}
+	if strict && a.Type() != b.Type() {
+		return false
+	}
+
+	var left, right []object.Object
+	switch a := a.(type) {
+	case *object.Array:
+		other, ok := b.(*object.Array)
+		if !ok {
+			return false
+		}
+		left, right = a.Elements, other.Elements
+	case *object.Tuple:
+		other, ok := b.(*object.Tuple)
+		if !ok {
+			return false
+		}
+		left, right = a.Elements, other.Elements
+	case *object.Dict:
+		other, ok := b.(*object.Dict)
+		if !ok || len(a.Pairs) != len(other.Pairs) {
+			return false
+		}
+		for key, pair := range a.Pairs {
+			otherPair, ok := other.Pairs[key]
+			if !ok {
+				return false
+			}
+			left = append(left, pair.Value)
+			right = append(right, otherPair.Value)
+		}
+	default:
+		return evalInfixExpression("==", a, b) == TRUE
+	}
+
+	if len(left) != len(right) {
+		return false
+	}
+	pair := [2]object.Object{a, b}
+	if comparing[pair] {
+		return true
+	}
+	comparing[pair] = true
+	defer delete(comparing, pair)
+
+	for i := range left {
+		if !deepEqual(left[i], right[i], strict, comparing) {
+			return false
+		}
+	}
+	return true
+}
+
+// evalCollectionEquality is == and != on arrays and dicts, which compare what
+// they hold rather than whether they are the same one
+func evalCollectionEquality(operator string, left, right object.Object) object.Object {
+	equal := deepEqual(left, right, false, make(map[[2]object.Object]bool))
+	if operator == "!=" {
+		return nativeBoolToBooleanObject(!equal)
+	}
+	return nativeBoolToBooleanObject(equal)
+}
+
+// strictEqual is the 'sawa_kabisa' builtin. Unlike ==, the values must be of
+// the same type all the way down.
+func strictEqual(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+	}
+	return nativeBoolToBooleanObject(deepEqual(args[0], args[1], true, make(map[[2]object.Object]bool)))
+}
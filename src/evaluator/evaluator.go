@@ -3,6 +3,7 @@ package evaluator
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"strings"
 
 	"github.com/AvicennaJr/Nuru/ast"
@@ -17,7 +18,17 @@ var (
 	CONTINUE = &object.Continue{}
 )
 
+// Eval evaluates node. An error gets the position of the innermost node it
+// came from, so it can tell the user exactly where things went wrong.
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	result := eval(node, env)
+	if err, ok := result.(*object.Error); ok && err.Position.Line == 0 && node != nil {
+		err.Position = node.Pos()
+	}
+	return result
+}
+
+func eval(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
@@ -28,6 +39,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
 
+	case *ast.BigIntegerLiteral:
+		return &object.BigInt{Value: node.Value}
+
 	case *ast.FloatLiteral:
 		return &object.Float{Value: node.Value}
 
@@ -39,18 +53,25 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(right) {
 			return right
 		}
-		return evalPrefixExpression(node.Operator, right, node.Token.Line)
+		return evalPrefixExpression(node.Operator, right)
 
 	case *ast.InfixExpression:
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
 		}
+		if node.Operator == "??" {
+			// the right side is only worked out when it is needed
+			if left != nil && left != NULL {
+				return left
+			}
+			return Eval(node.Right, env)
+		}
 		right := Eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
-		return evalInfixExpression(node.Operator, left, right, node.Token.Line)
+		return evalInfixExpression(node.Operator, left, right)
 	case *ast.PostfixExpression:
 		return evalPostfixExpression(env, node.Operator, node)
 
@@ -60,6 +81,19 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
 
+	case *ast.ComparisonChain:
+		return evalComparisonChain(node, env)
+
+	case *ast.ConditionalExpression:
+		condition := Eval(node.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if isTruthy(condition) {
+			return Eval(node.Consequence, env)
+		}
+		return Eval(node.Alternative, env)
+
 	case *ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
 		if isError(val) {
@@ -73,7 +107,20 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return val
 		}
 
-		env.Set(node.Name.Value, val)
+		if fn, ok := val.(*object.Function); ok && fn.Name == "" {
+			fn.Name = node.Name.Value
+		}
+		if node.Constant {
+			val = env.SetConst(node.Name.Value, val)
+		} else {
+			val = env.Set(node.Name.Value, val)
+		}
+		if isError(val) {
+			return val
+		}
+
+	case *ast.DestructuringStatement:
+		return evalDestructuringStatement(node, env)
 
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
@@ -81,18 +128,36 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body}
+		return &object.Function{Parameters: params, Variadic: node.Variadic, Generator: node.Generator, Doc: node.DocString(), Env: env, Body: body}
 
 	case *ast.CallExpression:
 		function := Eval(node.Function, env)
 		if isError(function) {
 			return function
 		}
-		args := evalExpressions(node.Arguments, env)
+		args := evalArguments(function, node.Arguments, env)
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(function, args, node.Token.Line)
+		if fn, ok := function.(*object.Function); ok && node.Tail {
+			return &object.TailCall{Function: fn, Arguments: args, Position: node.Pos()}
+		}
+		var result object.Object
+		switch {
+		case function == builtins["thibitisha"] && len(node.Arguments) > 0:
+			result = evalAssertion(node, args)
+		case function == builtins["orodha_ya_majina"] && len(node.Arguments) == 0:
+			result = envNames(env)
+		default:
+			result = applyFunction(function, args)
+		}
+		if err, ok := result.(*object.Error); ok {
+			addTraceFrame(err, function, node)
+		}
+		return result
+	case *ast.TemplateLiteral:
+		return evalTemplateLiteral(node, env)
+
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 
@@ -107,28 +172,95 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(left) {
 			return left
 		}
+		if node.Optional && left == NULL {
+			return NULL
+		}
 		index := Eval(node.Index, env)
 		if isError(index) {
 			return index
 		}
-		return evalIndexExpression(left, index, node.Token.Line)
+		return evalIndexExpression(left, index)
+	case *ast.SliceExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		if node.Optional && left == NULL {
+			return NULL
+		}
+		bounds := []object.Object{NULL, NULL, NULL}
+		for i, exp := range []ast.Expression{node.Start, node.Stop, node.Step} {
+			if exp == nil {
+				continue
+			}
+			bounds[i] = Eval(exp, env)
+			if isError(bounds[i]) {
+				return bounds[i]
+			}
+		}
+		return evalSliceExpression(left, bounds[0], bounds[1], bounds[2])
 	case *ast.DictLiteral:
 		return evalDictLiteral(node, env)
+	case *ast.Comprehension:
+		return evalComprehension(node, env)
 	case *ast.WhileExpression:
 		return evalWhileExpression(node, env)
+	case *ast.DoWhileExpression:
+		return evalDoWhileExpression(node, env)
 	case *ast.Break:
 		return evalBreak(node)
 	case *ast.Continue:
 		return evalContinue(node)
 	case *ast.SwitchExpression:
 		return evalSwitchStatement(node, env)
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+	case *ast.PropertyExpression:
+		obj := Eval(node.Object, env)
+		if isError(obj) {
+			return obj
+		}
+		return evalPropertyExpression(node, obj)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
+	case *ast.ThrowStatement:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		return evalThrowStatement(val)
 	case *ast.Null:
 		return NULL
 	// case *ast.For:
 	// 	return evalForExpression(node, env)
 	case *ast.ForIn:
-		return evalForInExpression(node, env, node.Token.Line)
+		return evalForInExpression(node, env)
+	case *ast.ClassStatement:
+		return evalClassStatement(node, env)
+	case *ast.SpawnExpression:
+		return evalSpawnExpression(node, env)
+
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+
+	case *ast.YieldExpression:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		gen, _ := env.Get("toa")
+		gen.(*object.Generator).Yield(val)
+		return NULL
+	case *ast.ThisExpression:
+		if this, ok := env.Get("hii"); ok {
+			return this
+		}
+		return newError("'hii' inatumika ndani ya muundo tu")
 	case *ast.AssignmentExpression:
+		if pe, ok := node.Left.(*ast.PropertyExpression); ok {
+			return evalPropertyAssignment(node, pe, env)
+		}
+
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -146,14 +278,16 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		op := node.Token.Literal
 		if len(op) >= 2 {
 			op = op[:len(op)-1]
-			value = evalInfixExpression(op, left, value, node.Token.Line)
+			value = evalInfixExpression(op, left, value)
 			if isError(value) {
 				return value
 			}
 		}
 
 		if ident, ok := node.Left.(*ast.Identifier); ok {
-			env.Set(ident.Value, value)
+			if err, ok := env.Update(ident.Value, value).(*object.Error); ok {
+				return err
+			}
 		} else if ie, ok := node.Left.(*ast.IndexExpression); ok {
 			obj := Eval(ie.Left, env)
 			if isError(obj) {
@@ -166,10 +300,11 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 					return index
 				}
 				if idx, ok := index.(*object.Integer); ok {
-					if int(idx.Value) > len(array.Elements) {
+					i, ok := absoluteIndex(idx.Value, len(array.Elements))
+					if !ok {
 						return newError("Index imezidi idadi ya elements")
 					}
-					array.Elements[idx.Value] = value
+					array.Elements[i] = value
 				} else {
 					return newError("Hauwezi kufanya opereshen hii na %#v", index)
 				}
@@ -184,6 +319,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 				} else {
 					return newError("Hauwezi kufanya opereshen hii na %T", key)
 				}
+			} else if _, ok := obj.(*object.Tuple); ok {
+				return newError("JOZI haiwezi kubadilishwa")
 			} else {
 				return newError("%T haifanyi operation hii", obj)
 			}
@@ -200,6 +337,9 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	var result object.Object
 
 	for _, statment := range program.Statements {
+		if debugger != nil {
+			debugger.Statement(statment, env, depth())
+		}
 		result = Eval(statment, env)
 
 		switch result := result.(type) {
@@ -220,16 +360,18 @@ func nativeBoolToBooleanObject(input bool) *object.Boolean {
 	return FALSE
 }
 
-func evalPrefixExpression(operator string, right object.Object, line int) object.Object {
+func evalPrefixExpression(operator string, right object.Object) object.Object {
 	switch operator {
 	case "!":
 		return evalBangOperatorExpression(right)
 	case "-":
-		return evalMinusPrefixOperatorExpression(right, line)
+		return evalMinusPrefixOperatorExpression(right)
 	case "+":
-		return evalPlusPrefixOperatorExpression(right, line)
+		return evalPlusPrefixOperatorExpression(right)
+	case "~":
+		return evalBitNotOperatorExpression(right)
 	default:
-		return newError("Mstari %d: Operesheni haieleweki: %s%s", line, operator, right.Type())
+		return newError("Operesheni haieleweki: %s%s", operator, right.Type())
 	}
 }
 
@@ -246,39 +388,80 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 	}
 }
 
-func evalMinusPrefixOperatorExpression(right object.Object, line int) object.Object {
+func evalBitNotOperatorExpression(right object.Object) object.Object {
+	switch obj := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: ^obj.Value}
+	case *object.BigInt:
+		return normalizeBigInt(new(big.Int).Not(obj.Value))
+	default:
+		return newError("Operesheni haieleweki: ~%s", right.Type())
+	}
+}
+
+func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
 	switch obj := right.(type) {
 
 	case *object.Integer:
+		if obj.Value == math.MinInt64 {
+			return normalizeBigInt(new(big.Int).Neg(toBigInt(obj)))
+		}
 		return &object.Integer{Value: -obj.Value}
 
+	case *object.BigInt:
+		return normalizeBigInt(new(big.Int).Neg(obj.Value))
+
+	case *object.Decimal:
+		return &object.Decimal{Value: new(big.Rat).Neg(obj.Value)}
+
 	case *object.Float:
 		return &object.Float{Value: -obj.Value}
 
 	default:
-		return newError("Mstari %d: Operesheni Haielweki: -%s", line, right.Type())
+		return newError("Operesheni Haielweki: -%s", right.Type())
 	}
 }
-func evalPlusPrefixOperatorExpression(right object.Object, line int) object.Object {
+func evalPlusPrefixOperatorExpression(right object.Object) object.Object {
 	switch obj := right.(type) {
 
 	case *object.Integer:
 		return &object.Integer{Value: obj.Value}
 
+	case *object.BigInt, *object.Decimal:
+		return obj
+
 	case *object.Float:
 		return &object.Float{Value: obj.Value}
 
 	default:
-		return newError("Mstari %d: Operesheni Haielweki: -%s", line, right.Type())
+		return newError("Operesheni Haielweki: -%s", right.Type())
 	}
 }
-func evalInfixExpression(operator string, left, right object.Object, line int) object.Object {
+func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	if left == nil {
-		return newError("Mstari %d: Umekosea hapa", line)
+		return newError("Umekosea hapa")
 	}
 	switch {
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
-		return evalStringInfixExpression(operator, left, right, line)
+		return evalStringInfixExpression(operator, left, right)
+
+	case left.Type() == object.TIME_OBJ && right.Type() == object.TIME_OBJ:
+		return evalTimeInfixExpression(operator, left, right)
+
+	case operator == "ktk" && (right.Type() == object.SET_OBJ || right.Type() == object.TUPLE_OBJ || right.Type() == object.BYTES_OBJ):
+		return evalInExpression(left, right)
+
+	case left.Type() == object.SET_OBJ && right.Type() == object.SET_OBJ:
+		return evalSetInfixExpression(operator, left, right)
+
+	case left.Type() == object.TUPLE_OBJ && right.Type() == object.TUPLE_OBJ:
+		return evalTupleInfixExpression(operator, left, right)
+
+	case left.Type() == object.BYTES_OBJ && right.Type() == object.BYTES_OBJ:
+		return evalBytesInfixExpression(operator, left, right)
+
+	case (operator == "==" || operator == "!=") && (left.Type() == object.ARRAY_OBJ || left.Type() == object.DICT_OBJ):
+		return evalCollectionEquality(operator, left, right)
 
 	case operator == "+" && left.Type() == object.DICT_OBJ && right.Type() == object.DICT_OBJ:
 		leftVal := left.(*object.Dict).Pairs
@@ -327,20 +510,35 @@ func evalInfixExpression(operator string, left, right object.Object, line int) o
 		rightVal := right.(*object.String).Value
 		return &object.String{Value: strings.Repeat(rightVal, int(leftVal))}
 
+	case operator == "\\" && isNumber(left) && isNumber(right):
+		return evalFloorDivision(left, right)
+
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
-		return evalIntegerInfixExpression(operator, left, right, line)
+		return evalIntegerInfixExpression(operator, left, right)
 
 	case left.Type() == object.FLOAT_OBJ && right.Type() == object.FLOAT_OBJ:
-		return evalFloatInfixExpression(operator, left, right, line)
+		return evalFloatInfixExpression(operator, left, right)
+
+	case (left.Type() == object.DECIMAL_OBJ || right.Type() == object.DECIMAL_OBJ) && isDecimalOperand(left) && isDecimalOperand(right):
+		return evalDecimalInfixExpression(operator, left, right)
+
+	case isInteger(left) && isInteger(right):
+		return evalBigIntInfixExpression(operator, left, right)
+
+	case left.Type() == object.BIGINT_OBJ && right.Type() == object.FLOAT_OBJ:
+		return evalFloatInfixExpression(operator, &object.Float{Value: bigToFloat(toBigInt(left))}, right)
+
+	case left.Type() == object.FLOAT_OBJ && right.Type() == object.BIGINT_OBJ:
+		return evalFloatInfixExpression(operator, left, &object.Float{Value: bigToFloat(toBigInt(right))})
 
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.FLOAT_OBJ:
-		return evalFloatIntegerInfixExpression(operator, left, right, line)
+		return evalFloatIntegerInfixExpression(operator, left, right)
 
 	case left.Type() == object.FLOAT_OBJ && right.Type() == object.INTEGER_OBJ:
-		return evalFloatIntegerInfixExpression(operator, left, right, line)
+		return evalFloatIntegerInfixExpression(operator, left, right)
 
 	case operator == "ktk":
-		return evalInExpression(left, right, line)
+		return evalInExpression(left, right)
 
 	case operator == "==":
 		return nativeBoolToBooleanObject(left == right)
@@ -348,22 +546,26 @@ func evalInfixExpression(operator string, left, right object.Object, line int) o
 	case operator == "!=":
 		return nativeBoolToBooleanObject(left != right)
 	case left.Type() == object.BOOLEAN_OBJ && right.Type() == object.BOOLEAN_OBJ:
-		return evalBooleanInfixExpression(operator, left, right, line)
+		return evalBooleanInfixExpression(operator, left, right)
 
 	case left.Type() != right.Type():
-		return newError("Mstari %d: Aina Hazilingani: %s %s %s",
-			line, left.Type(), operator, right.Type())
+		return newError("Aina Hazilingani: %s %s %s",
+			left.Type(), operator, right.Type())
 
 	default:
-		return newError("Mstari %d: Operesheni Haielweki: %s %s %s",
-			line, left.Type(), operator, right.Type())
+		return newError("Operesheni Haielweki: %s %s %s",
+			left.Type(), operator, right.Type())
 	}
 }
 
-func evalIntegerInfixExpression(operator string, left, right object.Object, line int) object.Object {
+func evalIntegerInfixExpression(operator string, left, right object.Object) object.Object {
 	leftVal := left.(*object.Integer).Value
 	rightVal := right.(*object.Integer).Value
 
+	if integerOverflows(operator, leftVal, rightVal) {
+		return evalBigIntInfixExpression(operator, left, right)
+	}
+
 	switch operator {
 	case "+":
 		return &object.Integer{Value: leftVal + rightVal}
@@ -372,16 +574,43 @@ func evalIntegerInfixExpression(operator string, left, right object.Object, line
 	case "*":
 		return &object.Integer{Value: leftVal * rightVal}
 	case "**":
-		return &object.Integer{Value: int64(math.Pow(float64(leftVal), float64(rightVal)))}
+		// only a negative power gets here, the rest are worked out exactly
+		// by evalBigIntInfixExpression
+		if leftVal == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
+		return &object.Float{Value: math.Pow(float64(leftVal), float64(rightVal))}
 	case "/":
-		x := float64(leftVal) / float64(rightVal)
-		if math.Mod(x, 1) == 0 {
-			return &object.Integer{Value: int64(x)}
-		} else {
-			return &object.Float{Value: x}
+		if rightVal == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
+		// a whole answer is worked out in int64, since a float64 can not
+		// hold every int64 exactly
+		if leftVal%rightVal == 0 {
+			return &object.Integer{Value: leftVal / rightVal}
 		}
+		return &object.Float{Value: float64(leftVal) / float64(rightVal)}
 	case "%":
+		if rightVal == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
 		return &object.Integer{Value: leftVal % rightVal}
+	case "&":
+		return &object.Integer{Value: leftVal & rightVal}
+	case "|":
+		return &object.Integer{Value: leftVal | rightVal}
+	case "^":
+		return &object.Integer{Value: leftVal ^ rightVal}
+	case "<<":
+		if rightVal < 0 {
+			return newError("Samahani, huwezi kusogeza biti mara %d", rightVal)
+		}
+		return &object.Integer{Value: leftVal << rightVal}
+	case ">>":
+		if rightVal < 0 {
+			return newError("Samahani, huwezi kusogeza biti mara %d", rightVal)
+		}
+		return &object.Integer{Value: leftVal >> rightVal}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case "<=":
@@ -395,12 +624,12 @@ func evalIntegerInfixExpression(operator string, left, right object.Object, line
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
-		return newError("Mstari %d: Operesheni Haielweki: %s %s %s",
-			line, left.Type(), operator, right.Type())
+		return newError("Operesheni Haielweki: %s %s %s",
+			left.Type(), operator, right.Type())
 	}
 }
 
-func evalFloatInfixExpression(operator string, left, right object.Object, line int) object.Object {
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
 	leftVal := left.(*object.Float).Value
 	rightVal := right.(*object.Float).Value
 
@@ -412,8 +641,14 @@ func evalFloatInfixExpression(operator string, left, right object.Object, line i
 	case "*":
 		return &object.Float{Value: leftVal * rightVal}
 	case "**":
+		if leftVal == 0 && rightVal < 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
 		return &object.Float{Value: math.Pow(float64(leftVal), float64(rightVal))}
 	case "/":
+		if rightVal == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
 		return &object.Float{Value: leftVal / rightVal}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
@@ -428,12 +663,12 @@ func evalFloatInfixExpression(operator string, left, right object.Object, line i
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
-		return newError("Mstari %d: Operesheni Haielweki: %s %s %s",
-			line, left.Type(), operator, right.Type())
+		return newError("Operesheni Haielweki: %s %s %s",
+			left.Type(), operator, right.Type())
 	}
 }
 
-func evalFloatIntegerInfixExpression(operator string, left, right object.Object, line int) object.Object {
+func evalFloatIntegerInfixExpression(operator string, left, right object.Object) object.Object {
 	var leftVal, rightVal float64
 	if left.Type() == object.FLOAT_OBJ {
 		leftVal = left.(*object.Float).Value
@@ -452,8 +687,14 @@ func evalFloatIntegerInfixExpression(operator string, left, right object.Object,
 	case "*":
 		val = leftVal * rightVal
 	case "**":
+		if leftVal == 0 && rightVal < 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
 		val = math.Pow(float64(leftVal), float64(rightVal))
 	case "/":
+		if rightVal == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
 		val = leftVal / rightVal
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
@@ -468,8 +709,8 @@ func evalFloatIntegerInfixExpression(operator string, left, right object.Object,
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
-		return newError("Mstari %d: Operesheni Haielweki: %s %s %s",
-			line, left.Type(), operator, right.Type())
+		return newError("Operesheni Haielweki: %s %s %s",
+			left.Type(), operator, right.Type())
 	}
 
 	if math.Mod(val, 1) == 0 {
@@ -479,7 +720,7 @@ func evalFloatIntegerInfixExpression(operator string, left, right object.Object,
 	}
 }
 
-func evalBooleanInfixExpression(operator string, left, right object.Object, line int) object.Object {
+func evalBooleanInfixExpression(operator string, left, right object.Object) object.Object {
 	leftVal := left.(*object.Boolean).Value
 	rightVal := right.(*object.Boolean).Value
 
@@ -489,42 +730,65 @@ func evalBooleanInfixExpression(operator string, left, right object.Object, line
 	case "||":
 		return nativeBoolToBooleanObject(leftVal || rightVal)
 	default:
-		return newError("Mstari %d: Operesheni Haielweki: %s %s %s", line, left.Type(), operator, right.Type())
+		return newError("Operesheni Haielweki: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
+// evalPostfixExpression changes the number named by 'i++' or 'i--' by one,
+// giving the value it had before
 func evalPostfixExpression(env *object.Environment, operator string, node *ast.PostfixExpression) object.Object {
 	val, ok := env.Get(node.Token.Literal)
 	if !ok {
-		return newError("Tumia KITAMBULISHI CHA NAMBA AU DESIMALI, sio %s", node.Token.Type)
+		return newError("Neno Halifahamiki: %s", node.Token.Literal)
 	}
+	var infix string
 	switch operator {
 	case "++":
-		switch arg := val.(type) {
-		case *object.Integer:
-			v := arg.Value + 1
-			return env.Set(node.Token.Literal, &object.Integer{Value: v})
-		case *object.Float:
-			v := arg.Value + 1
-			return env.Set(node.Token.Literal, &object.Float{Value: v})
-		default:
-			return newError("Mstari %d: %s sio kitambulishi cha namba. Tumia '++' na kitambulishi cha namba au desimali.\nMfano:\tfanya i = 2; i++", node.Token.Line, node.Token.Literal)
-
-		}
+		infix = "+"
 	case "--":
-		switch arg := val.(type) {
-		case *object.Integer:
-			v := arg.Value - 1
-			return env.Set(node.Token.Literal, &object.Integer{Value: v})
-		case *object.Float:
-			v := arg.Value - 1
-			return env.Set(node.Token.Literal, &object.Float{Value: v})
-		default:
-			return newError("Mstari %d: %s sio kitambulishi cha namba. Tumia '--' na kitambulishi cha namba au desimali.\nMfano:\tfanya i = 2; i++", node.Token.Line, node.Token.Literal)
-		}
+		infix = "-"
 	default:
 		return newError("Haifahamiki: %s", operator)
 	}
+	switch val.Type() {
+	case object.INTEGER_OBJ, object.BIGINT_OBJ, object.FLOAT_OBJ, object.DECIMAL_OBJ:
+	default:
+		return newError("%s sio kitambulishi cha namba. Tumia '%s' na kitambulishi cha namba au desimali.\nMfano:\tfanya i = 2; i%s", node.Token.Literal, operator, operator)
+	}
+	// going through the infix operator makes a step past the end of an
+	// int64 give a BigInt, as it does for i += 1
+	updated := evalInfixExpression(infix, val, &object.Integer{Value: 1})
+	if isError(updated) {
+		return updated
+	}
+	if err := env.Update(node.Token.Literal, updated); isError(err) {
+		return err
+	}
+	return val
+}
+
+// evalComparisonChain stops at the first comparison that doesn't hold, so the
+// operands after it are never worked out
+func evalComparisonChain(node *ast.ComparisonChain, env *object.Environment) object.Object {
+	left := Eval(node.Operands[0], env)
+	if isError(left) {
+		return left
+	}
+	for i, operator := range node.Operators {
+		right := Eval(node.Operands[i+1], env)
+		if isError(right) {
+			return right
+		}
+		result := evalInfixExpression(operator, left, right)
+		if isError(result) {
+			return result
+		}
+		if !isTruthy(result) {
+			return FALSE
+		}
+		left = right
+	}
+	return TRUE
 }
 
 func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
@@ -560,6 +824,9 @@ func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) obje
 	var result object.Object
 
 	for _, statment := range block.Statements {
+		if debugger != nil {
+			debugger.Statement(statment, env, depth())
+		}
 		result = Eval(statment, env)
 
 		if result != nil {
@@ -578,6 +845,13 @@ func newError(format string, a ...interface{}) *object.Error {
 	return &object.Error{Message: fmt.Sprintf(format, a...)}
 }
 
+// errorMessage strips the colour codes added by newError so the message can be
+// handed back to a script as a plain string.
+func errorMessage(err *object.Error) string {
+	msg := strings.TrimPrefix(err.Message, "\x1b[31m")
+	return strings.TrimSuffix(msg, "\x1b[0m")
+}
+
 func isError(obj object.Object) bool {
 	if obj != nil {
 		return obj.Type() == object.ERROR_OBJ
@@ -593,14 +867,29 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 	if builtin, ok := builtins[node.Value]; ok {
 		return builtin
 	}
+	if mod, ok := stdModules[node.Value]; ok {
+		return mod
+	}
+	if val, ok := globals[node.Value]; ok {
+		return val
+	}
 
-	return newError("Mstari %d: Neno Halifahamiki: %s", node.Token.Line, node.Value)
+	return newError("Neno Halifahamiki: %s", node.Value)
 }
 
 func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
 	var result []object.Object
 
 	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			elements := evalSpreadExpression(spread, env)
+			if len(elements) == 1 && isError(elements[0]) {
+				return elements
+			}
+			result = append(result, elements...)
+			continue
+		}
+
 		evaluated := Eval(e, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
@@ -612,27 +901,227 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	return result
 }
 
-func applyFunction(fn object.Object, args []object.Object, line int) object.Object {
+// evalSpreadExpression gives the elements that '...' puts in its place
+func evalSpreadExpression(node *ast.SpreadExpression, env *object.Environment) []object.Object {
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return []object.Object{val}
+	}
+	elements, err := spreadElements(val)
+	if err != nil {
+		return []object.Object{err}
+	}
+	return elements
+}
+
+func spreadElements(val object.Object) ([]object.Object, *object.Error) {
+	switch val := val.(type) {
+	case *object.Array:
+		return val.Elements, nil
+	case *object.Tuple:
+		return val.Elements, nil
+	}
+	return nil, newError("Samahani, ... inahitaji ORODHA, sio %s", val.Type())
+}
+
+// evalArguments evaluates the arguments of a call to function. Named
+// arguments are put where the parameter with their name is, leaving nil for
+// the parameters that aren't given.
+func evalArguments(function object.Object, exps []ast.Expression, env *object.Environment) []object.Object {
+	named := false
+	for _, e := range exps {
+		if _, ok := e.(*ast.NamedArgument); ok {
+			named = true
+			break
+		}
+	}
+	if !named {
+		return evalExpressions(exps, env)
+	}
+
+	var fn *object.Function
+	var name string
+	switch function := function.(type) {
+	case *object.Function:
+		fn, name = function, functionName(function)
+	case *object.BoundMethod:
+		fn, name = function.Method, functionName(function.Method)
+	case *object.Class:
+		fn, name = function.Constructor, function.Name
+	case *object.Builtin:
+		return []object.Object{newError("Samahani, vitendakazi vya ndani havipokei hoja zenye majina")}
+	default:
+		return []object.Object{newError("Hii sio function: %s", function.Type())}
+	}
+
+	var params []*ast.Identifier
+	if fn != nil {
+		params = fn.Parameters
+		if fn.Variadic {
+			params = params[:len(params)-1]
+		}
+	}
+
+	var args []object.Object
+	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			elements := evalSpreadExpression(spread, env)
+			if len(elements) == 1 && isError(elements[0]) {
+				return elements
+			}
+			args = append(args, elements...)
+			continue
+		}
+		arg, ok := e.(*ast.NamedArgument)
+		if !ok {
+			evaluated := Eval(e, env)
+			if isError(evaluated) {
+				return []object.Object{evaluated}
+			}
+			args = append(args, evaluated)
+			continue
+		}
+
+		idx := -1
+		for i, param := range params {
+			if param.Value == arg.Name.Value {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return []object.Object{newError("Samahani, %s haina hoja inayoitwa %s", name, arg.Name.Value)}
+		}
+		for len(args) <= idx {
+			args = append(args, nil)
+		}
+		if args[idx] != nil {
+			return []object.Object{newError("Samahani, hoja %s imepewa thamani zaidi ya mara moja", arg.Name.Value)}
+		}
+
+		evaluated := Eval(arg.Value, env)
+		if isError(evaluated) {
+			return []object.Object{evaluated}
+		}
+		args[idx] = evaluated
+	}
+
+	// a parameter skipped over by the named arguments would otherwise be
+	// left unbound, and quietly take the value of a name around the function
+	for i, arg := range args {
+		if arg == nil {
+			return []object.Object{newError("Samahani, %s haikupewa hoja %s", name, params[i].Value)}
+		}
+	}
+	return args
+}
+
+func applyFunction(fn object.Object, args []object.Object) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
-		extendedEnv := extendedFunctionEnv(fn, args)
-		evaluated := Eval(fn.Body, extendedEnv)
-		return unwrapReturnValue(evaluated)
+		return callFunction(fn, args)
 	case *object.Builtin:
 		if result := fn.Fn(args...); result != nil {
 			return result
 		}
 		return NULL
+	case *object.BoundMethod:
+		return applyMethod(fn.Instance, fn.Method, args)
+	case *object.Class:
+		return instantiateClass(fn, args)
 	default:
-		return newError("Mstari %d: Hii sio function: %s", line, fn.Type())
+		return newError("Hii sio function: %s", fn.Type())
+	}
+
+}
+
+// maxTailFrames is how many of the most recent tail calls are remembered for
+// the trace of an error
+const maxTailFrames = 20
+
+// callFunction runs fn. Tail calls come back as an *object.TailCall and are
+// run here in a loop, so recursion in tail position doesn't grow Go's stack.
+func callFunction(fn *object.Function, args []object.Object) object.Object {
+	enterCall()
+	defer leaveCall()
+
+	var tail []object.Frame
+	dropped := 0 // tail calls too old to be kept in tail
+
+	for {
+		if fn.Generator {
+			return newGenerator(fn, extendedFunctionEnv(fn, args))
+		}
+		if profile != nil {
+			enterProfile(functionName(fn), fn.Body)
+		}
+		extendedEnv := extendedFunctionEnv(fn, args)
+		evaluated := unwrapReturnValue(Eval(fn.Body, extendedEnv))
+		if profile != nil {
+			leaveProfile(fn.Body)
+		}
+
+		call, ok := evaluated.(*object.TailCall)
+		if !ok {
+			if err, ok := evaluated.(*object.Error); ok {
+				for i := len(tail) - 1; i >= 0; i-- {
+					err.Trace = append(err.Trace, tail[i])
+				}
+				err.Skipped += dropped
+			}
+			return evaluated
+		}
+
+		tail = append(tail, object.Frame{Function: functionName(call.Function), Position: call.Position})
+		if len(tail) > maxTailFrames {
+			tail = tail[1:]
+			dropped++
+		}
+		fn, args = call.Function, call.Arguments
+	}
+}
+
+func functionName(fn *object.Function) string {
+	if fn.Name == "" {
+		return "unda"
 	}
+	return fn.Name
+}
 
+// addTraceFrame records the call of fn at node in the trace of err as the
+// error unwinds, so the trace ends up holding the call stack at the time of
+// the error. Builtins are left out since the error already points at them.
+func addTraceFrame(err *object.Error, fn object.Object, node *ast.CallExpression) {
+	var name string
+	switch fn := fn.(type) {
+	case *object.Function:
+		name = functionName(fn)
+	case *object.BoundMethod:
+		name = functionName(fn.Method)
+	case *object.Class:
+		name = fn.Name
+	default:
+		return
+	}
+	err.Trace = append(err.Trace, object.Frame{Function: name, Position: node.Pos()})
 }
 
 func extendedFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
 	env := object.NewEnclosedEnvironment(fn.Env)
 
-	for paramIdx, param := range fn.Parameters {
+	params := fn.Parameters
+	if fn.Variadic {
+		// the last parameter gets an array of whatever is left, even if nothing is
+		rest := params[len(params)-1]
+		params = params[:len(params)-1]
+		elements := []object.Object{}
+		if len(args) > len(params) {
+			elements = append(elements, args[len(params):]...)
+		}
+		env.Set(rest.Value, &object.Array{Elements: elements})
+	}
+
+	for paramIdx, param := range params {
 		if paramIdx < len(args) {
 			env.Set(param.Value, args[paramIdx])
 		}
@@ -640,6 +1129,17 @@ func extendedFunctionEnv(fn *object.Function, args []object.Object) *object.Envi
 	return env
 }
 
+// newGenerator gives the generator for a call of fn, whose body is evaluated
+// in env a value at a time as the generator is looped over
+func newGenerator(fn *object.Function, env *object.Environment) *object.Generator {
+	gen := &object.Generator{}
+	env.Set("toa", gen)
+	gen.Run = func() object.Object {
+		return unwrapReturnValue(Eval(fn.Body, env))
+	}
+	return gen
+}
+
 func unwrapReturnValue(obj object.Object) object.Object {
 	if returnValue, ok := obj.(*object.ReturnValue); ok {
 		return returnValue.Value
@@ -648,7 +1148,7 @@ func unwrapReturnValue(obj object.Object) object.Object {
 	return obj
 }
 
-func evalStringInfixExpression(operator string, left, right object.Object, line int) object.Object {
+func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
 
 	leftVal := left.(*object.String).Value
 	rightVal := right.(*object.String).Value
@@ -661,35 +1161,168 @@ func evalStringInfixExpression(operator string, left, right object.Object, line
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
-		return newError("Mstari %d: Operesheni Haielweki: %s %s %s", line, left.Type(), operator, right.Type())
+		return newError("Operesheni Haielweki: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
-func evalIndexExpression(left, index object.Object, line int) object.Object {
+func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalArrayIndexExpression(left, index)
 	case left.Type() == object.ARRAY_OBJ && index.Type() != object.INTEGER_OBJ:
-		return newError("Mstari %d: Tafadhali tumia number, sio: %s", line, index.Type())
+		return newError("Tafadhali tumia number, sio: %s", index.Type())
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalStringIndexExpression(left, index)
+	case left.Type() == object.STRING_OBJ && index.Type() != object.INTEGER_OBJ:
+		return newError("Tafadhali tumia number, sio: %s", index.Type())
+	case left.Type() == object.TUPLE_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalTupleIndexExpression(left, index)
+	case left.Type() == object.TUPLE_OBJ && index.Type() != object.INTEGER_OBJ:
+		return newError("Tafadhali tumia number, sio: %s", index.Type())
+	case left.Type() == object.BYTES_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalBytesIndexExpression(left, index)
+	case left.Type() == object.BYTES_OBJ && index.Type() != object.INTEGER_OBJ:
+		return newError("Tafadhali tumia number, sio: %s", index.Type())
 	case left.Type() == object.DICT_OBJ:
-		return evalDictIndexExpression(left, index, line)
+		return evalDictIndexExpression(left, index)
 	default:
-		return newError("Mstari %d: Operesheni hii haiwezekani kwa: %s", line, left.Type())
+		return newError("Operesheni hii haiwezekani kwa: %s", left.Type())
+	}
+}
+
+// absoluteIndex turns an index counting back from the end, like -1 for the
+// last element, into one counting from the start. ok is false when the index
+// falls outside length elements.
+func absoluteIndex(idx int64, length int) (int, bool) {
+	if idx < 0 {
+		idx += int64(length)
+	}
+	if idx < 0 || idx >= int64(length) {
+		return 0, false
 	}
+	return int(idx), true
 }
 
 func evalArrayIndexExpression(array, index object.Object) object.Object {
 	arrayObject := array.(*object.Array)
-	idx := index.(*object.Integer).Value
-	max := int64(len(arrayObject.Elements) - 1)
-
-	if idx < 0 || idx > max {
+	idx, ok := absoluteIndex(index.(*object.Integer).Value, len(arrayObject.Elements))
+	if !ok {
 		return NULL
 	}
 
 	return arrayObject.Elements[idx]
 }
 
+// evalStringIndexExpression gives the character at index, counting
+// characters rather than bytes
+func evalStringIndexExpression(str, index object.Object) object.Object {
+	chars := []rune(str.(*object.String).Value)
+	idx, ok := absoluteIndex(index.(*object.Integer).Value, len(chars))
+	if !ok {
+		return NULL
+	}
+
+	return &object.String{Value: string(chars[idx])}
+}
+
+// evalSliceExpression gives a new array or string with the elements of left
+// from start up to, but not including, stop, taking every step'th one. Any
+// of the three can be tupu to take the default.
+func evalSliceExpression(left, start, stop, step object.Object) object.Object {
+	switch left := left.(type) {
+	case *object.Array:
+		indexes, err := sliceIndexes(len(left.Elements), start, stop, step)
+		if err != nil {
+			return err
+		}
+		elements := make([]object.Object, len(indexes))
+		for i, idx := range indexes {
+			elements[i] = left.Elements[idx]
+		}
+		return &object.Array{Elements: elements}
+	case *object.String:
+		chars := []rune(left.Value)
+		indexes, err := sliceIndexes(len(chars), start, stop, step)
+		if err != nil {
+			return err
+		}
+		sliced := make([]rune, len(indexes))
+		for i, idx := range indexes {
+			sliced[i] = chars[idx]
+		}
+		return &object.String{Value: string(sliced)}
+	default:
+		return newError("Operesheni hii haiwezekani kwa: %s", left.Type())
+	}
+}
+
+// sliceIndexes works out which of length elements a slice takes. Negative
+// bounds count back from the end and bounds past either end are cut to it.
+func sliceIndexes(length int, start, stop, step object.Object) ([]int, *object.Error) {
+	n := int64(length)
+	by := int64(1)
+	if step != NULL {
+		s, ok := step.(*object.Integer)
+		if !ok {
+			return nil, newError("Tafadhali tumia number, sio: %s", step.Type())
+		}
+		if s.Value == 0 {
+			return nil, newError("Samahani, hatua ya kukata haiwezi kuwa sifuri")
+		}
+		by = s.Value
+	}
+
+	// going backwards, -1 stands for before the first element
+	lower, upper := int64(0), n
+	if by < 0 {
+		lower, upper = -1, n-1
+	}
+	bound := func(obj object.Object, def int64) (int64, *object.Error) {
+		if obj == NULL {
+			return def, nil
+		}
+		i, ok := obj.(*object.Integer)
+		if !ok {
+			return 0, newError("Tafadhali tumia number, sio: %s", obj.Type())
+		}
+		v := i.Value
+		if v < 0 {
+			v += n
+			if v < lower {
+				v = lower
+			}
+		} else if v > upper {
+			v = upper
+		}
+		return v, nil
+	}
+
+	from, to := lower, upper
+	if by < 0 {
+		from, to = upper, lower
+	}
+	from, err := bound(start, from)
+	if err != nil {
+		return nil, err
+	}
+	to, err = bound(stop, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if by > 0 && from < to {
+		count = (to-from-1)/by + 1
+	} else if by < 0 && from > to {
+		count = (from-to-1)/-by + 1
+	}
+	indexes := make([]int, count)
+	for k := range indexes {
+		indexes[k] = int(from + int64(k)*by)
+	}
+	return indexes, nil
+}
+
 func evalDictLiteral(node *ast.DictLiteral, env *object.Environment) object.Object {
 	pairs := make(map[object.HashKey]object.DictPair)
 
@@ -701,7 +1334,7 @@ func evalDictLiteral(node *ast.DictLiteral, env *object.Environment) object.Obje
 
 		hashKey, ok := key.(object.Hashable)
 		if !ok {
-			return newError("Mstari %d: Hashing imeshindikana: %s", node.Token.Line, key.Type())
+			return newError("Hashing imeshindikana: %s", key.Type())
 		}
 
 		value := Eval(valueNode, env)
@@ -716,12 +1349,12 @@ func evalDictLiteral(node *ast.DictLiteral, env *object.Environment) object.Obje
 	return &object.Dict{Pairs: pairs}
 }
 
-func evalDictIndexExpression(dict, index object.Object, line int) object.Object {
+func evalDictIndexExpression(dict, index object.Object) object.Object {
 	dictObject := dict.(*object.Dict)
 
 	key, ok := index.(object.Hashable)
 	if !ok {
-		return newError("Mstari %d: Samahani, %s haitumiki kama key", line, index.Type())
+		return newError("Samahani, %s haitumiki kama key", index.Type())
 	}
 
 	pair, ok := dictObject.Pairs[key.HashKey()]
@@ -750,6 +1383,32 @@ func evalWhileExpression(we *ast.WhileExpression, env *object.Environment) objec
 	return NULL
 }
 
+func evalDoWhileExpression(dw *ast.DoWhileExpression, env *object.Environment) object.Object {
+	for {
+		evaluated := Eval(dw.Consequence, env)
+		if isError(evaluated) {
+			return evaluated
+		}
+		if evaluated != nil {
+			if evaluated.Type() == object.BREAK_OBJ {
+				break
+			}
+			if evaluated.Type() == object.RETURN_VALUE_OBJ {
+				return evaluated
+			}
+		}
+
+		condition := Eval(dw.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !isTruthy(condition) {
+			break
+		}
+	}
+	return NULL
+}
+
 func evalBreak(node *ast.Break) object.Object {
 	return BREAK
 }
@@ -758,14 +1417,20 @@ func evalContinue(node *ast.Continue) object.Object {
 	return CONTINUE
 }
 
-func evalInExpression(left, right object.Object, line int) object.Object {
-	switch right.(type) {
+func evalInExpression(left, right object.Object) object.Object {
+	switch right := right.(type) {
 	case *object.String:
 		return evalInStringExpression(left, right)
 	case *object.Array:
 		return evalInArrayExpression(left, right)
 	case *object.Dict:
-		return evalInDictExpression(left, right, line)
+		return evalInDictExpression(left, right)
+	case *object.Set:
+		return nativeBoolToBooleanObject(setContains(right, left))
+	case *object.Tuple:
+		return evalInTupleExpression(left, right)
+	case *object.Bytes:
+		return evalInBytesExpression(left, right)
 	default:
 		return FALSE
 	}
@@ -781,7 +1446,7 @@ func evalInStringExpression(left, right object.Object) object.Object {
 	return nativeBoolToBooleanObject(found)
 }
 
-func evalInDictExpression(left, right object.Object, line int) object.Object {
+func evalInDictExpression(left, right object.Object) object.Object {
 	leftVal, ok := left.(object.Hashable)
 	if !ok {
 		return newError("Huwezi kutumia kama 'key': %s", left.Type())
@@ -883,7 +1548,7 @@ func evalInArrayExpression(left, right object.Object) object.Object {
 // 	return NULL
 // }
 
-func evalForInExpression(fie *ast.ForIn, env *object.Environment, line int) object.Object {
+func evalForInExpression(fie *ast.ForIn, env *object.Environment) object.Object {
 	iterable := Eval(fie.Iterable, env)
 	existingKeyIdentifier, okk := env.Get(fie.Key) // again, stay safe
 	existingValueIdentifier, okv := env.Get(fie.Value)
@@ -895,22 +1560,176 @@ func evalForInExpression(fie *ast.ForIn, env *object.Environment, line int) obje
 			env.Set(fie.Value, existingValueIdentifier)
 		}
 	}()
-	switch i := iterable.(type) {
+	switch i := ownIterator(iterable).(type) {
 	case object.Iterable:
 		defer func() {
 			i.Reset()
 		}()
 		return loopIterable(i.Next, env, fie)
 	default:
-		return newError("Mstari %d: Huwezi kufanya operesheni hii na %s", line, i.Type())
+		return newError("Huwezi kufanya operesheni hii na %s", i.Type())
+	}
+}
+
+// evalComprehension runs the loop of '[x * 2 kwa x ktk orodha]' in an
+// environment of its own, so its names don't outlive it
+func evalComprehension(node *ast.Comprehension, env *object.Environment) object.Object {
+	iterable := Eval(node.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+	it, ok := ownIterator(iterable).(object.Iterable)
+	if !ok {
+		return newError("Huwezi kufanya operesheni hii na %s", iterable.Type())
+	}
+	defer it.Reset()
+
+	scope := object.NewEnclosedEnvironment(env)
+	elements := []object.Object{}
+	pairs := make(map[object.HashKey]object.DictPair)
+	for k, v := it.Next(); k != nil && v != nil; k, v = it.Next() {
+		if isError(v) {
+			return v
+		}
+		if node.LoopKey != "" {
+			scope.Set(node.LoopKey, k)
+		}
+		scope.Set(node.LoopValue, v)
+
+		if node.Condition != nil {
+			condition := Eval(node.Condition, scope)
+			if isError(condition) {
+				return condition
+			}
+			if !isTruthy(condition) {
+				continue
+			}
+		}
+
+		var key object.Object
+		if node.Key != nil {
+			key = Eval(node.Key, scope)
+			if isError(key) {
+				return key
+			}
+		}
+		value := Eval(node.Value, scope)
+		if isError(value) {
+			return value
+		}
+		if key == nil {
+			elements = append(elements, value)
+			continue
+		}
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return newError("Hashing imeshindikana: %s", key.Type())
+		}
+		pairs[hashKey.HashKey()] = object.DictPair{Key: key, Value: value}
+	}
+
+	if node.Key != nil {
+		return &object.Dict{Pairs: pairs}
 	}
+	return &object.Array{Elements: elements}
 }
 
+// ownIterator returns a copy of obj to loop over, so that loops over the
+// same value, whether nested or run by 'sambamba' at the same time, don't
+// move each other along. Files are read as they are looped over, so they
+// can't be copied.
+func ownIterator(obj object.Object) object.Object {
+	if next, ok := iteratorMethod(obj, "ifuatayo"); ok {
+		start, _ := iteratorMethod(obj, "anzisha")
+		return &methodIterator{Object: obj, next: next, start: start}
+	}
+	switch obj := obj.(type) {
+	case *object.Array:
+		return &object.Array{Elements: obj.Elements}
+	case *object.Tuple:
+		return &object.Tuple{Elements: obj.Elements}
+	case *object.String:
+		return &object.String{Value: obj.Value}
+	case *object.Bytes:
+		return &object.Bytes{Value: obj.Value}
+	case *object.Dict:
+		return &object.Dict{Pairs: obj.Pairs}
+	case *object.Set:
+		return &object.Set{Elements: obj.Elements}
+	case *object.Range:
+		return &object.Range{Start: obj.Start, End: obj.End, Step: obj.Step}
+	}
+	return obj
+}
+
+// iteratorMethod gives the function obj has under name, for dicts and
+// instances that give their own values to 'kwa'
+func iteratorMethod(obj object.Object, name string) (object.Object, bool) {
+	var fn object.Object
+	switch obj := obj.(type) {
+	case *object.Dict:
+		pair, ok := obj.Pairs[(&object.String{Value: name}).HashKey()]
+		if !ok {
+			return nil, false
+		}
+		fn = pair.Value
+	case *object.Instance:
+		if val, ok := obj.Fields[name]; ok {
+			fn = val
+		} else if method, ok := obj.Class.Methods[name]; ok {
+			return &object.BoundMethod{Instance: obj, Method: method}, true
+		}
+	}
+	if fn == nil || (fn.Type() != object.FUNCTION_OBJ && fn.Type() != object.BUILTIN_OBJ) {
+		return nil, false
+	}
+	return fn, true
+}
+
+// methodIterator loops over a dict or instance with an 'ifuatayo' method,
+// which gives the next value or tupu once there are none left. Its
+// 'anzisha' method, if any, is called as the loop starts.
+type methodIterator struct {
+	object.Object
+	next, start object.Object
+	index       int64
+	started     bool
+}
+
+func (it *methodIterator) Next() (object.Object, object.Object) {
+	key := &object.Integer{Value: it.index}
+	if !it.started {
+		it.started = true
+		if it.start != nil {
+			if res := applyFunction(it.start, nil); isError(res) {
+				return key, res
+			}
+		}
+	}
+
+	val := applyFunction(it.next, nil)
+	if val == nil || val == NULL {
+		return nil, nil
+	}
+	it.index++
+	return key, val
+}
+
+// Reset does nothing, since every loop gets a methodIterator of its own
+func (it *methodIterator) Reset() {}
+
 func loopIterable(next func() (object.Object, object.Object), env *object.Environment, fi *ast.ForIn) object.Object {
 	k, v := next()
 	for k != nil && v != nil {
-		env.Set(fi.Key, k)
-		env.Set(fi.Value, v)
+		if isError(v) {
+			return v
+		}
+		if err := env.Set(fi.Key, k); isError(err) {
+			return err
+		}
+		if err := env.Set(fi.Value, v); isError(err) {
+			return err
+		}
 		res := Eval(fi.Block, env)
 		if isError(res) {
 			return res
@@ -932,8 +1751,25 @@ func loopIterable(next func() (object.Object, object.Object), env *object.Enviro
 	return NULL
 }
 
+func evalTemplateLiteral(tl *ast.TemplateLiteral, env *object.Environment) object.Object {
+	var out strings.Builder
+
+	for _, part := range tl.Parts {
+		val := Eval(part, env)
+		if isError(val) {
+			return val
+		}
+		out.WriteString(plainString(val))
+	}
+
+	return &object.String{Value: out.String()}
+}
+
 func evalSwitchStatement(se *ast.SwitchExpression, env *object.Environment) object.Object {
 	obj := Eval(se.Value, env)
+	if isError(obj) {
+		return obj
+	}
 	for _, opt := range se.Choices {
 
 		if opt.Default {
@@ -941,6 +1777,9 @@ func evalSwitchStatement(se *ast.SwitchExpression, env *object.Environment) obje
 		}
 		for _, val := range opt.Expr {
 			out := Eval(val, env)
+			if isError(out) {
+				return out
+			}
 			if obj.Type() == out.Type() && obj.Inspect() == out.Inspect() {
 				blockOut := evalBlockStatement(opt.Block, env)
 				return blockOut
@@ -953,5 +1792,128 @@ func evalSwitchStatement(se *ast.SwitchExpression, env *object.Environment) obje
 			return out
 		}
 	}
-	return nil
+	return NULL
+}
+
+func evalTryExpression(te *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(te.Block, env)
+	if !isError(result) {
+		return result
+	}
+
+	if te.Identifier != nil {
+		message := &object.String{Value: errorMessage(result.(*object.Error))}
+		if err, ok := env.Update(te.Identifier.Value, message).(*object.Error); ok {
+			return err
+		}
+	}
+
+	return Eval(te.Catch, env)
+}
+
+func evalThrowStatement(val object.Object) object.Object {
+	if str, ok := val.(*object.String); ok {
+		return newError("%s", str.Value)
+	}
+	return newError("%s", val.Inspect())
+}
+
+func evalPropertyExpression(node *ast.PropertyExpression, obj object.Object) object.Object {
+	switch obj := obj.(type) {
+	case *object.Module:
+		if val, ok := obj.Env.Get(node.Property.Value); ok {
+			return val
+		}
+		return newError("Moduli %s haina %s", obj.Name, node.Property.Value)
+	case *object.Instance:
+		if val, ok := obj.Fields[node.Property.Value]; ok {
+			return val
+		}
+		if method, ok := obj.Class.Methods[node.Property.Value]; ok {
+			return &object.BoundMethod{Instance: obj, Method: method}
+		}
+		return newError("%s haina %s", obj.Class.Name, node.Property.Value)
+	case *object.Time:
+		if val, ok := timeField(obj, node.Property.Value); ok {
+			return val
+		}
+		return newError("MUDA haina %s", node.Property.Value)
+	case *object.Set:
+		if method, ok := setMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("SETI haina %s", node.Property.Value)
+	case *object.Array:
+		if method, ok := arrayMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("ORODHA haina %s", node.Property.Value)
+	case *object.String:
+		if method, ok := stringMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("NENO haina %s", node.Property.Value)
+	case *object.Bytes:
+		if method, ok := bytesMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("BAITI haina %s", node.Property.Value)
+	case *object.Future:
+		if method, ok := futureMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("AHADI haina %s", node.Property.Value)
+	case *object.Channel:
+		if method, ok := channelMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("MFEREJI haina %s", node.Property.Value)
+	case *object.Lock:
+		if method, ok := lockMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("KUFULI haina %s", node.Property.Value)
+	case *object.WaitGroup:
+		if method, ok := waitGroupMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("KIKUNDI haina %s", node.Property.Value)
+	case *object.Process:
+		if method, ok := processMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("MCHAKATO haina %s", node.Property.Value)
+	case *object.Database:
+		if method, ok := databaseMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("HIFADHIDATA haina %s", node.Property.Value)
+	case *object.Connection:
+		if method, ok := connectionMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("MUUNGANO haina %s", node.Property.Value)
+	case *object.Listener:
+		if method, ok := listenerMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("MSIKILIZAJI haina %s", node.Property.Value)
+	case *object.WebSocket:
+		if method, ok := websocketMethod(obj, node.Property.Value); ok {
+			return method
+		}
+		return newError("WEBSOCKET haina %s", node.Property.Value)
+	default:
+		return newError("%s haina %s", obj.Type(), node.Property.Value)
+	}
+}
+
+// newDict builds a Dict with string keys, used by builtins that return records
+func newDict(fields map[string]object.Object) *object.Dict {
+	dict := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+	for k, v := range fields {
+		key := &object.String{Value: k}
+		dict.Pairs[key.HashKey()] = object.DictPair{Key: key, Value: v}
+	}
+	return dict
 }
@@ -1,12 +1,24 @@
 package evaluator
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/AvicennaJr/Nuru/lexer"
 	"github.com/AvicennaJr/Nuru/object"
 	"github.com/AvicennaJr/Nuru/parser"
+	"github.com/gorilla/websocket"
 )
 
 func TestEvalIntegerExpression(t *testing.T) {
@@ -158,6 +170,36 @@ func TestIfElseExpressions(t *testing.T) {
 	}
 }
 
+// testValue checks obj against an int, float64, bool, nil (null) or string, where a
+// string is compared with either a String's value or an Error's message
+func testValue(t *testing.T, input string, obj object.Object, expected interface{}) {
+	t.Helper()
+
+	switch expected := expected.(type) {
+	case int:
+		testIntegerObject(t, obj, int64(expected))
+	case float64:
+		testFloatObject(t, obj, expected)
+	case bool:
+		testBooleanObject(t, obj, expected)
+	case nil:
+		testNullObject(t, obj)
+	case string:
+		switch obj := obj.(type) {
+		case *object.String:
+			if obj.Value != expected {
+				t.Errorf("wrong value for %q. want=%q, got=%q", input, expected, obj.Value)
+			}
+		case *object.Error:
+			if errorMessage(obj) != expected {
+				t.Errorf("wrong error message for %q. want=%q, got=%q", input, expected, errorMessage(obj))
+			}
+		default:
+			t.Errorf("unexpected object for %q. got=%T (%+v)", input, obj, obj)
+		}
+	}
+}
+
 func testNullObject(t *testing.T, obj object.Object) bool {
 	if obj != NULL {
 		t.Errorf("object is not null, got=%T(+%v)", obj, obj)
@@ -190,27 +232,27 @@ func TestErrorHandling(t *testing.T) {
 	}{
 		{
 			"5 + kweli",
-			"Mstari 0: Aina Hazilingani: NAMBA + BOOLEAN",
+			"Aina Hazilingani: NAMBA + BOOLEAN",
 		},
 		{
 			"5 + kweli; 5;",
-			"Mstari 0: Aina Hazilingani: NAMBA + BOOLEAN",
+			"Aina Hazilingani: NAMBA + BOOLEAN",
 		},
 		{
 			"-kweli",
-			"Mstari 0: Operesheni Haielweki: -BOOLEAN",
+			"Operesheni Haielweki: -BOOLEAN",
 		},
 		{
 			"kweli + sikweli",
-			"Mstari 0: Operesheni Haielweki: BOOLEAN + BOOLEAN",
+			"Operesheni Haielweki: BOOLEAN + BOOLEAN",
 		},
 		{
 			"5; kweli + sikweli; 5",
-			"Mstari 0: Operesheni Haielweki: BOOLEAN + BOOLEAN",
+			"Operesheni Haielweki: BOOLEAN + BOOLEAN",
 		},
 		{
 			"kama (10 > 1) { kweli + sikweli;}",
-			"Mstari 0: Operesheni Haielweki: BOOLEAN + BOOLEAN",
+			"Operesheni Haielweki: BOOLEAN + BOOLEAN",
 		},
 		{
 			`
@@ -222,19 +264,19 @@ kama (10 > 1) {
 	rudisha 1;
 }
 			`,
-			"Mstari 3: Operesheni Haielweki: BOOLEAN + BOOLEAN",
+			"Operesheni Haielweki: BOOLEAN + BOOLEAN",
 		},
 		{
 			"bangi",
-			"Mstari 0: Neno Halifahamiki: bangi",
+			"Neno Halifahamiki: bangi",
 		},
 		{
 			`"Habari" - "Habari"`,
-			"Mstari 0: Operesheni Haielweki: NENO - NENO",
+			"Operesheni Haielweki: NENO - NENO",
 		},
 		{
 			`{"jina": "Avi"}[unda(x) {x}];`,
-			"Mstari 0: Samahani, UNDO (FUNCTION) haitumiki kama key",
+			"Samahani, UNDO (FUNCTION) haitumiki kama key",
 		},
 	}
 
@@ -253,6 +295,91 @@ kama (10 > 1) {
 	}
 }
 
+func TestErrorPositions(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedLine   int
+		expectedColumn int
+	}{
+		{"bangi", 1, 1},
+		{"fanya x = 1\nx + kweli", 2, 3},
+		{"fanya f = unda(a) {\n    rudisha a + kweli\n}\nf(1)", 2, 15},
+		{"fanya x = 1\n  andika(soma_faili(1))", 2, 20},
+		{"jaribu {\n  tupa \"kosa\"\n} shika (e) {\n  tupa e\n}", 4, 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Position.Line != tt.expectedLine || errObj.Position.Column != tt.expectedColumn {
+			t.Errorf("wrong position for %q. want=%d:%d, got=%d:%d", tt.input,
+				tt.expectedLine, tt.expectedColumn, errObj.Position.Line, errObj.Position.Column)
+		}
+	}
+}
+
+func TestErrorInspectShowsPosition(t *testing.T) {
+	l := lexer.NewFile("mfano.nr", "fanya x = 1\nx + kweli")
+	program := parser.New(l).ParseProgram()
+	evaluated := Eval(program, object.NewEnvironment())
+
+	if !strings.Contains(evaluated.Inspect(), "mfano.nr, Mstari 2, Safu 3: ") {
+		t.Errorf("position missing from error. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestErrorTrace(t *testing.T) {
+	input := `fanya ndani = unda() { 1 + kweli }
+fanya nje = unda() { ndani() }
+muundo Mtu {
+  ita() { nje() }
+}
+Mtu().ita()`
+
+	err, ok := testEval(input).(*object.Error)
+	if !ok {
+		t.Fatalf("expected an error")
+	}
+
+	expected := []struct {
+		function string
+		line     int
+		column   int
+	}{
+		{"ndani", 2, 27},
+		{"nje", 4, 14},
+		{"Mtu.ita", 6, 10},
+	}
+	if len(err.Trace) != len(expected) {
+		t.Fatalf("wrong trace length. want=%d, got=%d (%+v)", len(expected), len(err.Trace), err.Trace)
+	}
+	for i, want := range expected {
+		frame := err.Trace[i]
+		if frame.Function != want.function || frame.Position.Line != want.line || frame.Position.Column != want.column {
+			t.Errorf("trace[%d]: want=%s %d:%d, got=%s %d:%d", i, want.function, want.line, want.column,
+				frame.Function, frame.Position.Line, frame.Position.Column)
+		}
+	}
+
+	if !strings.Contains(err.Inspect(), "ndani ya nje, imeitwa Mstari 4, Safu 14") {
+		t.Errorf("trace missing from error. got=%q", err.Inspect())
+	}
+
+	// only the last tail calls are kept, but the others are still counted
+	deep := `fanya f = unda(n) { kama (n == 0) { rudisha 1 + kweli }; rudisha f(n - 1) }; f(100000)`
+	err, ok = testEval(deep).(*object.Error)
+	if !ok {
+		t.Fatalf("expected an error")
+	}
+	if !strings.HasSuffix(err.Inspect(), "... na miito mingine 99981") {
+		t.Errorf("wrong count of calls left out. got=%q", err.Inspect())
+	}
+}
+
 func TestLetStatement(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -269,6 +396,220 @@ func TestLetStatement(t *testing.T) {
 	}
 }
 
+func TestCompoundAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fanya x = 5; x += 2; x -= 1; x *= 3; x", 18},
+		{"fanya x = 9; x /= 2; x", 4.5},
+		{"fanya x = 9; x %= 4; x", 1},
+		{`fanya s = "a"; s += "b"; s`, "ab"},
+		{"fanya a = [1, 2]; a[0] += 10; a[0]", 11},
+		{`fanya d = {"k": 3}; d["k"] *= 4; d["k"]`, 12},
+		{"muundo M { unda() { hii.n = 1 } }; fanya m = M(); m.n += 5; m.n", 6},
+		{"y += 1", "Neno Halifahamiki: y"},
+		{`fanya x = 1; x += "a"`, "Aina Hazilingani: NAMBA + NENO"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestPostfixExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fanya i = 1; i++; i", 2},
+		{"fanya i = 1; i--; i--; i", -1},
+		{"fanya i = 1.5; i++; i", 2.5},
+		{"fanya i = 1; fanya j = i++; j * 10 + i", 12},
+		{"fanya i = 9223372036854775807; i++; i == 2 ** 63", true},
+		{"fanya i = -9223372036854775808; i--; i == -(2 ** 63) - 1", true},
+		{"fanya i = 2 ** 64; i--; i == 2 ** 64 - 1", true},
+		{`fanya i = desimali("0.5"); i++; i == desimali("1.5")`, true},
+		{"fanya i = 5; i-- * 2", 10},
+		{"fanya i = 0; wakati (i < 3) { i++ }; i", 3},
+		{"k++", "Neno Halifahamiki: k"},
+		{`fanya s = "a"; s++`, "s sio kitambulishi cha namba. Tumia '++' na kitambulishi cha namba au desimali.\nMfano:\tfanya i = 2; i++"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestPower(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"(2 ** 3) ** 2", 64},
+		{"-2 ** 2", -4},
+		{"(-2) ** 2", 4},
+		{"2 * 3 ** 2", 18},
+		{"2 ** 3 % 3", 2},
+		{"2 ** -1", 0.5},
+		{"4 ** 0.5", 2},
+		{"2.5 ** 2", 6.25},
+		{"0 ** 0", 1},
+		{"0 ** -1", "Huwezi kugawanya kwa sifuri"},
+		{"0.0 ** -1", "Huwezi kugawanya kwa sifuri"},
+		{"0 ** -1.5", "Huwezi kugawanya kwa sifuri"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestConditionalExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"kweli ? 1 : 2", 1},
+		{"tupu ? 1 : 2", 2},
+		{"0 ? 1 : 2", 1},
+		{"fanya x = 5; x > 3 ? x * 2 : x", 10},
+		{`fanya x = 0; x < 0 ? "hasi" : x == 0 ? "sifuri" : "chanya"`, "sifuri"},
+		{"[kweli ? 1 : 2, sikweli ? 1 : 2][1]", 2},
+		{"fanya f = unda(n) { n <= 1 ? 1 : n * f(n - 1) }; f(5)", 120},
+		{"kweli ? 1 : 1 / 0", 1},
+		{"sikweli ? 1 / 0 : 1", 1},
+		{"y ? 1 : 2", "Neno Halifahamiki: y"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fanya d = {"a": 1}; d["a"] ?? 5`, 1},
+		{`fanya d = {"a": 1}; d["b"] ?? 5`, 5},
+		{"tupu ?? tupu ?? 3", 3},
+		{"tupu ?? tupu", nil},
+		{"sikweli ?? 1", false},
+		{"0 ?? 1", 0},
+		{"tupu ?? 1 + 2", 3},
+		{"1 ?? 1 / 0", 1},
+		{"tupu ?? 1 / 0", "Huwezi kugawanya kwa sifuri"},
+		{"y ?? 1", "Neno Halifahamiki: y"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestComparisonChain(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fanya x = 5; 0 < x < 10", true},
+		{"fanya x = 15; 0 < x < 10", false},
+		{"1 <= 1 < 2 <= 2", true},
+		{"10 > 5 >= 5 > 4.5", true},
+		{"3 > 2 > 1 > 1", false},
+		{"1 > 2 < 1 / 0", false},
+		{`fanya c = {"n": 0}; fanya f = unda() { c["n"] += 1; c["n"] }; 0 < f() < 2; c["n"]`, 1},
+		{"1 < 2 < kweli", "Aina Hazilingani: NAMBA < BOOLEAN"},
+		{"(1 < 2) < 3", "Aina Hazilingani: BOOLEAN < NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestOptionalIndex(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fanya d = {"a": {"b": 2}}; d?["a"]?["b"]`, 2},
+		{`fanya d = {"a": {"b": 2}}; d?["x"]?["b"]`, nil},
+		{`fanya d = tupu; d?["x"]?["y"]`, nil},
+		{"[1, 2]?[5]", nil},
+		{"[[1, 2]]?[0]?[1]", 2},
+		{`fanya d = {}; d?["x"] ?? "hakuna"`, "hakuna"},
+		{`fanya d = tupu; d?[1 / 0]`, nil},
+		{`fanya d = {}; d["x"]["y"]`, "Operesheni hii haiwezekani kwa: TUPU"},
+		{`5?[0]`, "Operesheni hii haiwezekani kwa: NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBitwiseOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"6 & 3", 2},
+		{"6 | 3", 7},
+		{"6 ^ 3", 5},
+		{"~5", -6},
+		{"1 << 4", 16},
+		{"-16 >> 2", -4},
+		{"1 | 2 ^ 3 & 6", 1},
+		{"1 + 1 << 2", 8},
+		{"1 << 62 > 0", true},
+		{"(1 << 64) >> 63", 2},
+		{"(2 ** 70 + 5) & 7", 5},
+		{"~(2 ** 70) + 2 ** 70", -1},
+		{"1 << -1", "Samahani, huwezi kusogeza biti mara -1"},
+		{"1.5 & 1", "Operesheni Haielweki: DESIMALI & NAMBA"},
+		{"~1.5", "Operesheni haieleweki: ~DESIMALI"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFloorDivision(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"7 \\ 2", 3},
+		{"6 \\ 3", 2},
+		{"-7 \\ 2", -4},
+		{"7 \\ -2", -4},
+		{"-7 \\ -2", 3},
+		{"7.5 \\ 2", 3},
+		{"-7.5 \\ 2", -4},
+		{"7 \\ 0.5", 14},
+		{"1 + 7 \\ 2 * 2", 7},
+		{"(2 ** 70 + 1) \\ 2 ** 69", 2},
+		{"-(2 ** 70) \\ 3 == -(2 ** 70 + 2) / 3", true},
+		{`desimali("7.5") \ 2`, 3},
+		{`desimali("-0.5") \ 1`, -1},
+		{"fanya x = 17; x \\= 5; x", 3},
+		{"1 \\ 0", "Huwezi kugawanya kwa sifuri"},
+		{"1.5 \\ 0.0", "Huwezi kugawanya kwa sifuri"},
+		{`desimali("1") \ 0`, "Huwezi kugawanya kwa sifuri"},
+		{`"a" \ 2`, "Aina Hazilingani: NENO \\ NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
 func TestFunctionObject(t *testing.T) {
 	input := "unda(x) { x + 2 ;};"
 
@@ -311,234 +652,2633 @@ func TestFunctionApplication(t *testing.T) {
 	}
 }
 
-func TestClosures(t *testing.T) {
-	input := `
-fanya newAdder = unda(x) {
-	unda(y) { x + y};
-};
+func TestArrowFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fanya mara2 = (x) => x * 2; mara2(5)", 10},
+		{"fanya mara2 = x => x * 2; mara2(5)", 10},
+		{"fanya f = () => 42; f()", 42},
+		{"fanya f = (a, b) => a - b; f(5, 3)", 2},
+		{"fanya f = (a, ...b) => a + idadi(b); f(1, 2, 3)", 3},
+		{"fanya f = (x) => { fanya y = x * 2; y + 1 }; f(3)", 7},
+		{"fanya f = (x) => { rudisha x; 0 }; f(3)", 3},
+		{"((x) => x + 1)(1)", 2},
+		{"fanya ongeza = (x) => (y) => x + y; ongeza(1)(2)", 3},
+		{`fanya f = (k) => ({k: 1}); f("a")["a"]`, 1},
+		{"fanya fact = (n) => n <= 1 ? 1 : n * fact(n - 1); fact(5)", 120},
+		{"[1, 2, 3].ramani((x) => x * x)[2]", 9},
+		{"[1, 2, 3, 4].chuja(x => x % 2 == 0)[1]", 4},
+		{"[1, 2, 3].punguza((a, b) => a + b)", 6},
+		{"aina(() => 1)", "UNDO (FUNCTION)"},
+	}
 
-fanya addTwo = newAdder(2);
-addTwo(2);
-`
-	testIntegerObject(t, testEval(input), 4)
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
 }
 
-func TestStringLiteral(t *testing.T) {
-	input := `"Habari yako!"`
-
-	evaluated := testEval(input)
-	str, ok := evaluated.(*object.String)
-	if !ok {
-		t.Fatalf("Object is not string, got=%T(%+v)", evaluated, evaluated)
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fanya g = unda() { toa 1; toa 2; toa 3 }; neno([x kwa x ktk g()])", "[1, 2, 3]"},
+		{"fanya g = unda(n) { kwa i ktk mpaka(n) { toa i * i } }; neno([x kwa x ktk g(4)])", "[0, 1, 4, 9]"},
+		{"fanya g = (n) => { toa n; toa n + 1 }; neno([x kwa i, x ktk g(5)])", "[5, 6]"},
+		{"fanya g = unda() { toa 1 }; aina(g())", "KIZALISHI"},
+		{"fanya g = unda() { toa 1 }; neno(g())", "<kizalishi>"},
+		{"fanya g = unda() { toa 1; rudisha 5; toa 2 }; neno([x kwa x ktk g()])", "[1]"},
+		{"fanya g = unda() { kama (sikweli) { toa 1 } }; neno([x kwa x ktk g()])", "[]"},
+		// the body only runs as far as the values taken
+		{`fanya c = {"n": 0}
+		  fanya g = unda() { wakati (kweli) { c["n"] += 1; toa c["n"] } }
+		  kwa x ktk g() { kama (x == 3) { vunja } }
+		  c["n"]`, 3},
+		// a loop left with 'vunja' stops the generator, so the next one gets nothing
+		{`fanya asili = unda() { fanya n = 0; wakati (kweli) { toa n; n++ } }
+		  fanya g = asili()
+		  kwa x ktk g { kama (x == 2) { vunja } }
+		  fanya y = -1
+		  kwa x ktk g { y = x; vunja }
+		  y`, -1},
+		{`fanya asili = unda() { fanya n = 0; wakati (kweli) { toa n; n++ } }
+		  fanya mara2 = unda(g) { kwa x ktk g { toa x * 2 } }
+		  fanya chukua = unda(g, n) { kwa i, x ktk g { kama (i >= n) { vunja }; toa x } }
+		  neno([x kwa x ktk chukua(mara2(asili()), 4)])`, "[0, 2, 4, 6]"},
+		{"fanya g = unda() { toa 1; toa 2 }; fanya i = g(); neno([[x kwa x ktk i], [x kwa x ktk i]])", "[[1, 2], []]"},
+		{"fanya g = unda() { fanya y = toa 1; toa y }; neno([x kwa x ktk g()])", "[1, null]"},
+		{"muundo M { fanya n = 2; vyote() { kwa i ktk mpaka(hii.n) { toa i } } }; neno([x kwa x ktk M().vyote()])", "[0, 1]"},
+		{`fanya g = unda() { toa 1; tupa "imeshindwa" }; jaribu { kwa x ktk g() {} } shika (e) { e }`, "imeshindwa"},
+		{`fanya g = unda() { toa 1; 1 / "a" }; [x kwa x ktk g()]`, "Aina Hazilingani: NAMBA / NENO"},
 	}
 
-	if str.Value != "Habari yako!" {
-		t.Errorf("String has wrong value, got=%q", str.Value)
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
 	}
 }
 
-func TestStringconcatenation(t *testing.T) {
-	input := `"Mambo" + " " + "Vipi" + "?"`
-
-	evaluated := testEval(input)
-
-	str, ok := evaluated.(*object.String)
-	if !ok {
-		t.Fatalf("object is not a string, got=%T(%+v)", evaluated, evaluated)
+func TestMatchExpression(t *testing.T) {
+	eleza := `fanya eleza = unda(x) {
+		  linganisha (x) {
+		      0 => "sifuri",
+		      -1 => "hasi",
+		      "habari" => "salamu",
+		      [] => "tupu",
+		      [a] => "moja ${a}",
+		      [a, b] kama (a > b) => "kubwa kwanza",
+		      [a, _, ...baki] => "mwanzo ${a}, baki ${idadi(baki)}",
+		      {"aina": "mtu", jina} => "mtu ${jina}",
+		      {"x": 0, "y": y} => {
+		          fanya z = y * 2
+		          "mhimili ${z}"
+		      },
+		      tupu => "hakuna",
+		      _ => "kingine",
+		  }
+		}
+		`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{eleza + "eleza(0)", "sifuri"},
+		{eleza + "eleza(-1)", "hasi"},
+		{eleza + `eleza("habari")`, "salamu"},
+		{eleza + "eleza([])", "tupu"},
+		{eleza + "eleza([5])", "moja 5"},
+		{eleza + "eleza(jozi(5))", "moja 5"},
+		{eleza + "eleza([3, 1])", "kubwa kwanza"},
+		{eleza + "eleza([1, 3])", "mwanzo 1, baki 0"},
+		{eleza + "eleza([1, 2, 3, 4])", "mwanzo 1, baki 2"},
+		{eleza + "eleza([1, 2, 3])", "mwanzo 1, baki 1"},
+		{eleza + `eleza({"aina": "mtu", "jina": "Asha", "umri": 3})`, "mtu Asha"},
+		{eleza + `eleza({"aina": "mnyama", "jina": "Simba"})`, "kingine"},
+		{eleza + `eleza({"x": 0, "y": 4})`, "mhimili 8"},
+		{eleza + "eleza(tupu)", "hakuna"},
+		{eleza + "eleza(1.5)", "kingine"},
+		// a literal fits what is == to it, so "0" doesn't fit 0 but 0.0 does
+		{eleza + `eleza("0")`, "kingine"},
+		{eleza + "eleza(0.0)", "sifuri"},
+		{`linganisha ([1.0]) { [1] => "y", _ => "n" }`, "y"},
+		{`linganisha ([kweli]) { [1] => "y", _ => "n" }`, "n"},
+		{"linganisha (3) { 1 => 1 }", nil},
+		{"linganisha ([1, [2, 3]]) { [a, [b, c]] => a + b + c }", 6},
+		{"linganisha ({1: {\"k\": 2}}) { {1: {\"k\": n}} => n }", 2},
+		// the names of an arm hide those around it only while it runs
+		{"fanya a = 1; linganisha ([2]) { [a] => a }; a", 1},
+		{"fanya a = 1; linganisha ([2]) { [a] => a }", 2},
+		{"fanya a = 1; linganisha (2) { a kama (a > 5) => a, _ => a }", 1},
+		{"fanya a = 1; linganisha ([2, 3]) { [a] => a, _ => a }", 1},
+		{"linganisha ([1, 2]) { [a, b] => a + b }; a", "Neno Halifahamiki: a"},
+		{"linganisha (2) { n kama (n > 5) => n, _ => 0 }; n", "Neno Halifahamiki: n"},
+		{"linganisha (3) { n => { fanya t = n } }; t", "Neno Halifahamiki: t"},
+		{"fanya s = 0; linganisha (3) { n => { s = n * 2 } }; s", 6},
+		{"fanya a = 1; fanya f = unda() { linganisha (2) { a => a } + a }; f()", 3},
+		{"fanya s = 0; kwa x ktk [1, 2, 3] { linganisha (x) { 2 => { endelea }, n => { s += n } } }; s", 4},
+		{"fanya f = unda(x) { linganisha (x) { 1 => { rudisha \"moja\" } }; \"sio\" }; f(1) + f(2)", "mojasio"},
+		{"linganisha (1 / 0) { _ => 1 }", "Huwezi kugawanya kwa sifuri"},
+		{"linganisha (1) { n kama (n + kweli) => 1 }", "Aina Hazilingani: NAMBA + BOOLEAN"},
 	}
 
-	if str.Value != "Mambo Vipi?" {
-		t.Errorf("String has wrong value, got=%q", str.Value)
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
 	}
 }
 
-func TestBuiltinFunctions(t *testing.T) {
+func TestCollectionEquality(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected interface{}
 	}{
-		{`idadi("")`, 0},
-		{`idadi("four")`, 4},
-		{`idadi("hello world")`, 11},
-		{`idadi(1)`, "Samahani, hii function haitumiki na NAMBA"},
-		{`idadi("one", "two")`, "Hoja hazilingani, tunahitaji=1, tumepewa=2"},
-		{`jumla()`, "Hoja hazilingani, tunahitaji=1, tumepewa=0"},
-		{`jumla("")`, "Samahani, hii function haitumiki na NENO"},
-		{`jumla(1)`, "Samahani, hii function haitumiki na NAMBA"},
-		{`jumla([1,2,3])`, 6},
-		{`jumla([1,2,3.4])`, 6.4},
-		{`jumla([1.1,2.5,3.4])`, 7},
-		{`jumla([1.1,2.5,"q"])`, "Samahani namba tu zinahitajika"},
+		{"[1, [2, 3]] == [1, [2, 3]]", true},
+		{"[1, 2] == [2, 1]", false},
+		{"[1, 2] != [1, 2]", false},
+		{"[1, 2] != [1]", true},
+		{"[1] == 1", false},
+		{`{"a": [1], "b": 2} == {"b": 2, "a": [1]}`, true},
+		{`{"a": 1} == {"a": 1, "b": 2}`, false},
+		{`{"a": 1} != {"b": 1}`, true},
+		{"[1, 2.0] == [1.0, 2]", true},
+		{"fanya a = [1]; sukuma(a, 2) == [1, 2]", true},
+		{`fanya a = [1]; a[0] = a; fanya b = [1]; b[0] = b; a == b`, true},
+		{`fanya d = {}; d["d"] = d; d == {"d": d}`, true},
+		{"sawa_kabisa([1, {\"a\": jozi(1, 2)}], [1, {\"a\": jozi(1, 2)}])", true},
+		{"sawa_kabisa([1, 2.0], [1, 2])", false},
+		{"sawa_kabisa(1, 1.0)", false},
+		{"sawa_kabisa([1])", "Hoja hazilingani, tunahitaji=2, tumepewa=1"},
+		{"thibitisha_sawa([1, [2]], [1, [2]])", nil},
 	}
 
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
-        
-		switch expected := tt.expected.(type) {
-		case int:
-			testIntegerObject(t, evaluated, int64(expected))
-		case float64:
-             testFloatObject(t, evaluated, float64(expected))
-
-		case string:
-			errObj, ok := evaluated.(*object.Error)
-			if !ok {
-				t.Errorf("Object is not Error, got=%T(%+v)", evaluated, evaluated)
-				continue
-			}
-			if errObj.Message != fmt.Sprintf("\x1b[%dm%s\x1b[0m", 31, expected) {
-				t.Errorf("Wrong eror message, expected=%q, got=%q", expected, errObj.Message)
-			}
+		if expected, ok := tt.expected.(bool); ok {
+			testBooleanObject(t, evaluated, expected)
+			continue
 		}
+		testValue(t, tt.input, evaluated, tt.expected)
 	}
 }
 
-func TestArrayLiterals(t *testing.T) {
-	input := "[1, 2 * 2, 3 + 3]"
-
-	evaluated := testEval(input)
-	result, ok := evaluated.(*object.Array)
-	if !ok {
-		t.Fatalf("Object is not an Array, got=%T(%+v)", evaluated, evaluated)
+func TestDeepCopy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fanya a = [1, [2]]; fanya b = nakili(a); b[1][0] = 3; a[1][0]`, 2},
+		{`fanya a = {"k": {"n": 1}}; fanya b = nakili(a); b["k"]["n"] = 2; a["k"]["n"]`, 1},
+		{`fanya a = [1, {"k": [2]}]; nakili(a) == a`, true},
+		{`fanya a = [1]; a[0] = a; fanya b = nakili(a); b[0] == b`, true},
+		{`fanya a = [1]; a[0] = a; fanya b = nakili(a); b[0] = 2; idadi(a[0])`, 1},
+		{`fanya s = [1]; fanya a = nakili([s, s]); a[0][0] = 5; a[1][0]`, 5},
+		{`muundo Mtu { fanya vitu = [] }; fanya m = Mtu(); fanya n = nakili(m); n.vitu = sukuma(n.vitu, 1); idadi(m.vitu)`, 0},
+		{`nakili("neno")`, "neno"},
+		{`nakili()`, "Hoja hazilingani, tunahitaji=1, tumepewa=0"},
 	}
 
-	if len(result.Elements) != 3 {
-		t.Fatalf("Array has wrong number of elements, got=%d", len(result.Elements))
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if expected, ok := tt.expected.(bool); ok {
+			testBooleanObject(t, evaluated, expected)
+			continue
+		}
+		testValue(t, tt.input, evaluated, tt.expected)
 	}
-
-	testIntegerObject(t, result.Elements[0], 1)
-	testIntegerObject(t, result.Elements[1], 4)
-	testIntegerObject(t, result.Elements[2], 6)
 }
 
-func TestArrayIndexExpressions(t *testing.T) {
+func TestIntrospection(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected interface{}
 	}{
-		{
-			"[1, 2, 3][0]",
-			1,
-		},
-		{
-			"[1, 2, 3][1]",
-			2,
-		},
-		{
-			"[1, 2, 3][2]",
-			3,
-		},
-		{
-			"fanya i = 0; [1][i];",
-			1,
-		},
-		{
-			"fanya myArr = [1, 2, 3]; myArr[2];",
-			3,
-		},
-		{
-			"[1, 2, 3][3]",
-			nil,
-		},
-		{
-			"[1, 2, 3][-1]",
-			nil,
-		},
+		{`fanya b = 2; fanya a = 1; neno(orodha_ya_majina())`, "[a, b]"},
+		{`fanya x = 1; fanya f = unda(y) { fanya x = 2; orodha_ya_majina() }; neno(f(0))`, "[f, x, y]"},
+		{`fanya majina = orodha_ya_majina; fanya n = 1; neno(majina())`, "[majina, n]"},
+		{`neno(orodha_ya_majina({"b": 1, "a": 2}))`, "[a, b]"},
+		{`muundo Mtu { fanya jina = "Asha"; salamu() { "habari" } }; neno(orodha_ya_majina(Mtu()))`, "[jina, salamu]"},
+		{`muundo Mtu { fanya jina = "Asha"; salamu() { "habari" } }; neno(orodha_ya_majina(Mtu))`, "[jina, salamu]"},
+		{`tumia kumbukumbu; neno(orodha_ya_majina(kumbukumbu))`, "[elekeza, kiwango, kosa, onyo, taarifa, tatua]"},
+		{`orodha_ya_majina(1)`, "Samahani, orodha_ya_majina haitumiki na NAMBA"},
+		{`neno([ni_namba(1), ni_namba(1.5), ni_namba(desimali("0.1")), ni_namba("1")])`, "[kweli, kweli, kweli, sikweli]"},
+		{`neno([ni_neno("a"), ni_orodha([]), ni_kamusi({}), ni_jozi(jozi(1)), ni_seti(seti([]))])`, "[kweli, kweli, kweli, kweli, kweli]"},
+		{`neno([ni_boolean(sikweli), ni_tupu(tupu), ni_tupu(0), ni_orodha({})])`, "[kweli, kweli, sikweli, sikweli]"},
+		{`muundo M {}; neno([ni_unda(unda() {}), ni_unda(andika), ni_unda(M), ni_kitu(M()), ni_kitu(M)])`, "[kweli, kweli, sikweli, kweli, sikweli]"},
+		{`ni_orodha()`, "Hoja hazilingani, tunahitaji=1, tumepewa=0"},
 	}
 
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
-		integer, ok := tt.expected.(int)
-		if ok {
-			testIntegerObject(t, evaluated, int64(integer))
-		} else {
-			testNullObject(t, evaluated)
+		testValue(t, tt.input, evaluated, tt.expected)
+	}
+}
+
+func TestFormatString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`panga_neno("jina: %s, umri: %d", "Asha", 30)`, "jina: Asha, umri: 30"},
+		{`panga_neno("bei: %.2f", 3.14159)`, "bei: 3.14"},
+		{`panga_neno("%f", 2)`, "2.000000"},
+		{`panga_neno("%.2f", desimali("0.125"))`, "0.13"},
+		{`panga_neno("%d", 2 ** 70)`, "1180591620717411303424"},
+		{`panga_neno("%v na %v", [1, "a"], tupu)`, "[1, a] na null"},
+		{`panga_neno("[%5d|%-5s|%05.1f]", 42, "ab", 2.5)`, "[   42|ab   |002.5]"},
+		{`panga_neno("100%%")`, "100%"},
+		{`panga_neno("habari")`, "habari"},
+		{`panga_neno("%s", 1)`, "Samahani, %s inahitaji NENO, sio NAMBA"},
+		{`panga_neno("%d", 1.5)`, "Samahani, %d inahitaji NAMBA, sio DESIMALI"},
+		{`panga_neno("%x", 1)`, "Samahani, %x haijulikani, tumia %s, %d, %f au %v"},
+		{`panga_neno("%s %s", "a")`, "Samahani, \"%s %s\" inahitaji hoja zaidi ya 1 ulizoweka"},
+		{`panga_neno("%s", "a", "b")`, "Samahani, \"%s\" inatumia hoja 1 tu, wewe umeweka 2"},
+		{`panga_neno("50%")`, "Samahani, \"50%\" inaishia na % bila herufi ya aina"},
+		{`panga_neno(1)`, "Samahani, panga_neno inahitaji NENO, sio NAMBA"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testValue(t, tt.input, evaluated, tt.expected)
+	}
+}
+
+func TestConstants(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"thabiti PI = 3; PI * 2", 6},
+		{"thabiti PI = 3; PI = 4", "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		{"thabiti PI = 3; PI += 1", "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		{"thabiti PI = 3; PI++", "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		{"thabiti PI = 3; fanya PI = 4", "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		{"thabiti PI = 3; thabiti PI = 4", "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		{"thabiti PI = 3; fanya [PI] = [4]", "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		{"thabiti PI = 3; kwa PI ktk [4] {}", "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		{"thabiti PI = 3; fanya f = unda() { PI = 4 }; f()", "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		{"thabiti PI = 3; jaribu { PI = 4 } shika (e) {}; PI", 3},
+		{`thabiti PI = 3; jaribu { tupa "x" } shika (PI) { PI }`, "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		{"thabiti PI = 3; linganisha (5) { PI => PI * 2 }", "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		{"thabiti PI = 3; linganisha ([5]) { [PI] => PI * 2 }; PI", "PI ni thabiti, haiwezi kupewa thamani nyingine"},
+		// a parameter or a name made inside a function hides the constant
+		{"thabiti PI = 3; fanya f = unda(PI) { PI = PI + 1; PI }; f(1) + PI", 5},
+		{"thabiti PI = 3; fanya f = unda() { fanya PI = 1; PI += 1; PI }; f() + PI", 5},
+		{"thabiti N = 3; fanya f = unda() { N }; f()", 3},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestIteratorMethods(t *testing.T) {
+	hesabu := `muundo Hesabu {
+		  fanya mwisho = 3
+		  fanya sasa = 0
+		  anzisha() { hii.sasa = 0 }
+		  ifuatayo() {
+		      kama (hii.sasa >= hii.mwisho) { rudisha tupu }
+		      hii.sasa += 1
+		      rudisha hii.sasa
+		  }
 		}
+		fanya h = Hesabu()
+		`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{hesabu + "neno([x kwa x ktk h])", "[1, 2, 3]"},
+		{hesabu + "fanya s = 0; kwa i, x ktk h { s += i * 10 + x }; s", 36},
+		// 'anzisha' starts every loop afresh
+		{hesabu + "neno([[x kwa x ktk h], [x kwa x ktk h]])", "[[1, 2, 3], [1, 2, 3]]"},
+		{hesabu + "fanya s = 0; kwa x ktk h { kama (x == 2) { vunja }; s += x }; s", 1},
+		{`fanya d = {"n": 0}
+		  d["ifuatayo"] = unda() { kama (d["n"] == 2) { rudisha tupu }; d["n"] += 1; d["n"] }
+		  neno([[x kwa x ktk d], [x kwa x ktk d]])`, "[[1, 2], []]"},
+		{`fanya d = {"n": 0, "anzisha": unda() { d["n"] = 0 }}
+		  d["ifuatayo"] = unda() { kama (d["n"] == 2) { rudisha tupu }; d["n"] += 1; d["n"] }
+		  neno([[x kwa x ktk d], [x kwa x ktk d]])`, "[[1, 2], [1, 2]]"},
+		{`muundo Moja { ifuatayo() { tupu } }; neno([x kwa x ktk Moja()])`, "[]"},
+		// a dict whose 'ifuatayo' isn't a function is looped over as usual
+		{`neno([k kwa k, v ktk {"ifuatayo": 1}])`, "[ifuatayo]"},
+		{`fanya d = {"ifuatayo": unda() { 1 / "a" }}; kwa x ktk d {}`, "Aina Hazilingani: NAMBA / NENO"},
+		{`fanya d = {"ifuatayo": unda() { 1 }, "anzisha": unda() { tupa "hapana" }}; jaribu { kwa x ktk d {} } shika (e) { e }`, "hapana"},
+		{"muundo M {}; kwa x ktk M() {}", "Huwezi kufanya operesheni hii na KITU"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
 	}
 }
 
-func TestDictLiterals(t *testing.T) {
-	input := `fanya two = "two";
-{
-	"one": 10 - 9,
-	two: 1 +1,
-	"thr" + "ee": 6 / 2,
-	4: 4,
-	kweli: 5,
-	sikweli: 6
-}`
+func TestVariadicFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fanya f = unda(...a) { idadi(a) }; f()", 0},
+		{"fanya f = unda(...a) { idadi(a) }; f(1, 2, 3)", 3},
+		{"fanya f = unda(a, ...b) { a + b[1] }; f(1, 2, 3)", 4},
+		{"fanya f = unda(a, ...b) { aina(b) }; f(1)", "ORODHA"},
+		{"unda(...a) { a[0] }(7)", 7},
+		{"fanya f = unda(a, ...b) { b }; neno(f)", "unda(a, ...b) {\nb\n}"},
+		{"muundo M { unda(...a) { hii.a = a } }; idadi(M(1, 2).a)", 2},
+		{"muundo M { jumla(...a) { a[0] + a[1] } }; M().jumla(2, 3)", 5},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestNamedArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fanya f = unda(a, b) { a - b }; f(b=1, a=5)", 4},
+		{"fanya f = unda(a, b, c) { a + b * c }; f(1, c=3, b=2)", 7},
+		{"fanya f = unda(a, ...b) { idadi(b) }; f(a=1)", 0},
+		{"fanya f = unda(a, b) { a }; f(a=1)", 1},
+		{"fanya f = unda(a, b) { b }; f(a=1)", "Neno Halifahamiki: b"},
+		{"muundo M { unda(x, y) { hii.x = x - y } }; M(y=1, x=3).x", 2},
+		{"muundo M { ondoa(x, y) { x - y } }; M().ondoa(y=1, x=3)", 2},
+		{"fanya a = sambamba unda(x, y) { x - y }(y=1, x=3); a.subiri()", 2},
+		{"fanya f = unda(a) { a }; f(b=1)", "Samahani, f haina hoja inayoitwa b"},
+		{"fanya f = unda(a, ...b) { a }; f(b=1)", "Samahani, f haina hoja inayoitwa b"},
+		{"muundo M {}; M(a=1)", "Samahani, M haina hoja inayoitwa a"},
+		{"fanya f = unda(a, b) { a }; f(1, a=2)", "Samahani, hoja a imepewa thamani zaidi ya mara moja"},
+		{"fanya x = 5; fanya f = unda(x, y) { x }; f(y=1)", "Samahani, f haikupewa hoja x"},
+		{"muundo M { unda(x, y, z) {} }; M(z=1)", "Samahani, M haikupewa hoja x"},
+		{"idadi(a=[1])", "Samahani, vitendakazi vya ndani havipokei hoja zenye majina"},
+		{"fanya x = 1; x(a=1)", "Hii sio function: NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fanya [a, b, c] = [1, 2, 3]; a + b * c", 7},
+		{`fanya [a, b] = jozi("x", 2); a * b`, "xx"},
+		{`fanya {jina, umri} = {"jina": "Asha", "umri": 20, "mji": "Dar"}; "${jina} ${umri}"`, "Asha 20"},
+		{"fanya f = unda(o) { fanya [x, y] = o; x - y }; f([5, 3])", 2},
+		{"fanya i = 0; fanya { i++ } wakati (i < 3); i", 3},
+		{"fanya [a, b] = [1]", "Samahani, [a, b] inahitaji vitu 2, lakini imepewa 1"},
+		{"fanya [a] = 5", "Samahani, [a] inahitaji ORODHA, sio NAMBA"},
+		{`fanya {a, b} = {"a": 1}`, `Samahani, kamusi haina ufunguo "b"`},
+		{"fanya {a} = [1]", "Samahani, {a} inahitaji KAMUSI, sio ORODHA"},
+		{"fanya f = unda() { rudisha 7, 2 }; fanya a, b = f(); a - b", 5},
+		{"fanya f = unda() { rudisha 7, 2 }; idadi(f())", 2},
+		{"fanya f = unda(x) { kama (x) { rudisha [1], tupu }; rudisha tupu, 2 }; fanya v, e = f(sikweli); e", 2},
+		{"fanya a, b = [1, 2, 3]", "Samahani, [a, b] inahitaji vitu 2, lakini imepewa 3"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fanya jumlisha = unda(...n) { fanya s = 0; kwa x ktk n { s += x }; s }; jumlisha(...[1, 2, 3])", 6},
+		{"fanya f = unda(a, b, c) { a * 100 + b * 10 + c }; f(...[1, 2], 3)", 123},
+		{"fanya f = unda(a, b, c) { a * 100 + b * 10 + c }; f(...[1], c=3, b=2)", 123},
+		{"fanya a = [1, 2]; idadi([0, ...a, 3, ...jozi(4, 5), ...[]])", 6},
+		{"fanya a = [1, 2]; fanya b = [...a]; b[0] = 9; a[0]", 1},
+		{"idadi(...[[3, 7, 5]])", 3},
+		{"[...5]", "Samahani, ... inahitaji ORODHA, sio NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestClosures(t *testing.T) {
+	input := `
+fanya newAdder = unda(x) {
+	unda(y) { x + y};
+};
+
+fanya addTwo = newAdder(2);
+addTwo(2);
+`
+	testIntegerObject(t, testEval(input), 4)
+}
+
+func TestStringLiteral(t *testing.T) {
+	input := `"Habari yako!"`
 
 	evaluated := testEval(input)
-	result, ok := evaluated.(*object.Dict)
+	str, ok := evaluated.(*object.String)
 	if !ok {
-		t.Fatalf("Eval didn't return a dict, got=%T(%+v)", evaluated, evaluated)
+		t.Fatalf("Object is not string, got=%T(%+v)", evaluated, evaluated)
 	}
 
-	expected := map[object.HashKey]int64{
-		(&object.String{Value: "one"}).HashKey():   1,
-		(&object.String{Value: "two"}).HashKey():   2,
-		(&object.String{Value: "three"}).HashKey(): 3,
-		(&object.Integer{Value: 4}).HashKey():      4,
-		TRUE.HashKey():                             5,
-		FALSE.HashKey():                            6,
+	if str.Value != "Habari yako!" {
+		t.Errorf("String has wrong value, got=%q", str.Value)
 	}
+}
 
-	if len(result.Pairs) != len(expected) {
-		t.Fatalf("Dict has wrong number of pairs, got=%d", len(result.Pairs))
+func TestClasses(t *testing.T) {
+	class := `
+muundo Mtu {
+	fanya jina = "bila jina"
+	fanya marafiki = []
+
+	unda(jina, umri) {
+		hii.jina = jina
+		hii.umri = umri
 	}
 
-	for expectedKey, expectedValue := range expected {
-		pair, ok := result.Pairs[expectedKey]
+	salamu() {
+		rudisha "Habari " + hii.jina
+	}
+
+	kua() { hii.umri += 1 }
+
+	ongeza(rafiki) {
+		hii.marafiki = sukuma(hii.marafiki, rafiki)
+		rudisha hii
+	}
+}
+muundo Tupu {}
+`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fanya m = Mtu("Juma", 20); m.jina`, "Juma"},
+		{`fanya m = Mtu("Juma", 20); m.salamu()`, "Habari Juma"},
+		{`fanya m = Mtu("Juma", 20); m.kua(); m.kua(); m.umri`, 22},
+		{`fanya a = Mtu("Juma", 20); fanya b = Mtu("Asha", 30); a.kua(); b.umri`, 30},
+		{`fanya a = Mtu("Juma", 20); fanya b = Mtu("Asha", 30); a.ongeza(1).ongeza(2); idadi(a.marafiki) + idadi(b.marafiki)`, 2},
+		{`fanya m = Mtu("Juma", 20); m.jina = "Ali"; m.salamu()`, "Habari Ali"},
+		{`fanya m = Mtu("Juma", 20); fanya s = m.salamu; s()`, "Habari Juma"},
+		{`aina(Mtu("Juma", 20))`, "KITU"},
+		{`Tupu().Inspect`, "Tupu haina Inspect"},
+		{`Tupu(1)`, "Muundo Tupu hauna 'unda', hauwezi kupewa hoja 1"},
+		{`Mtu("Juma", 20).hakuna()`, "Mtu haina hakuna"},
+		{`hii`, "'hii' inatumika ndani ya muundo tu"},
+		{`fanya x = 5; x.y = 1`, "Huwezi kubadilisha y ya NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(class+tt.input), tt.expected)
+	}
+
+	evaluated := testEval(class + `Mtu("Juma", 20)`)
+	if evaluated.Inspect() != "Mtu{jina: Juma, marafiki: [], umri: 20}" {
+		t.Errorf("wrong Inspect for instance. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestDoWhileExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"fanya i = 0; fanya { i++ } wakati (i < 5); i", 5},
+		{"fanya i = 10; fanya { i++ } wakati (i < 5); i", 11},
+		{"fanya i = 0; fanya s = 0; fanya { i++; kama (i == 2) { endelea }; s += i } wakati (i < 4); s", 8},
+		{"fanya i = 0; fanya { i++; kama (i == 3) { vunja } } wakati (kweli); i", 3},
+		{"fanya f = unda() { fanya i = 0; fanya { i++; kama (i == 4) { rudisha i } } wakati (kweli) }; f()", 4},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"fanya s = 0; kwa i ktk mpaka(5) { s += i }; s", 10},
+		{"fanya s = 0; kwa i ktk mpaka(2, 5) { s += i }; s", 9},
+		{"fanya s = 0; kwa i ktk mpaka(10, 0, -2) { s += i }; s", 30},
+		{"fanya s = 0; kwa i ktk mpaka(0, 10, 3) { s += i }; s", 18},
+		{"fanya s = 0; kwa i ktk mpaka(5, 0) { s += 1 }; s", 0},
+		{"fanya s = 0; kwa k, v ktk mpaka(5, 8) { s += k }; s", 3},
+		{"fanya s = 0; kwa i ktk mpaka(1000000) { kama (i == 3) { vunja }; s += 1 }; s", 3},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+
+	evaluated := testEval("mpaka(0, 10, 2)")
+	if evaluated.Inspect() != "mpaka(0, 10, 2)" {
+		t.Errorf("wrong Inspect for range. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestTemplateLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`fanya jina = "Juma"; "jina ni ${jina}"`, "jina ni Juma"},
+		{`fanya a = 2; "${a} + ${a} = ${a + a}"`, "2 + 2 = 4"},
+		{`"orodha ${[1, 2]}"`, "orodha [1, 2]"},
+		{`"${kweli}"`, "kweli"},
+		{`fanya x = "ndani"; "${"nje ${x}"}"`, "nje ndani"},
+		{`"bei ni \${bei}"`, "bei ni ${bei}"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
 		if !ok {
-			t.Errorf("No pair for give key")
+			t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if str.Value != tt.expected {
+			t.Errorf("String has wrong value. want=%q, got=%q", tt.expected, str.Value)
 		}
+	}
 
-		testIntegerObject(t, pair.Value, expectedValue)
+	evaluated := testEval(`"${haipo}"`)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if errorMessage(errObj) != "Neno Halifahamiki: haipo" {
+		t.Errorf("wrong error message. got=%q", errorMessage(errObj))
 	}
 }
 
-func TestDictIndexExpression(t *testing.T) {
+func TestStringconcatenation(t *testing.T) {
+	input := `"Mambo" + " " + "Vipi" + "?"`
+
+	evaluated := testEval(input)
+
+	str, ok := evaluated.(*object.String)
+	if !ok {
+		t.Fatalf("object is not a string, got=%T(%+v)", evaluated, evaluated)
+	}
+
+	if str.Value != "Mambo Vipi?" {
+		t.Errorf("String has wrong value, got=%q", str.Value)
+	}
+}
+
+func TestBuiltinFunctions(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected interface{}
 	}{
-		{
-			`{"foo": 5}["foo"]`,
-			5,
-		},
-		{
-			`{"foo": 5}["bar"]`,
-			nil,
-		},
-		{
-			`fanya key = "foo"; {"foo": 5}[key]`,
-			5,
-		},
-		{
-			`{}["foo"]`,
-			nil,
-		},
-		{
-			`{5: 5}[5]`,
-			5,
-		},
-		{
-			`{kweli: 5}[kweli]`,
-			5,
-		},
-		{
-			`{sikweli: 5}[sikweli]`,
-			5,
-		},
+		{`idadi("")`, 0},
+		{`idadi("four")`, 4},
+		{`idadi("hello world")`, 11},
+		{`idadi("ŋombe 🐄")`, 7},
+		{`idadi(1)`, "Samahani, hii function haitumiki na NAMBA"},
+		{`idadi("one", "two")`, "Hoja hazilingani, tunahitaji=1, tumepewa=2"},
+		{`jumla()`, "Hoja hazilingani, tunahitaji=1, tumepewa=0"},
+		{`jumla("")`, "Samahani, hii function haitumiki na NENO"},
+		{`jumla(1)`, "Samahani, hii function haitumiki na NAMBA"},
+		{`jumla([1,2,3])`, 6},
+		{`jumla([1,2,3.4])`, 6.4},
+		{`jumla([1.1,2.5,3.4])`, 7},
+		{`jumla([1.1,2.5,"q"])`, "Samahani namba tu zinahitajika"},
+		{`mpaka()`, "Samahani, hii function inapokea hoja 1 hadi 3, wewe umeweka 0"},
+		{`mpaka("a")`, "Samahani, mpaka inahitaji namba, sio NENO"},
+		{`mpaka(0, 10, 0)`, "Samahani, hatua ya mpaka haiwezi kuwa 0"},
 	}
 
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
-		integer, ok := tt.expected.(int)
-		if ok {
-			testIntegerObject(t, evaluated, int64(integer))
-		} else {
-			testNullObject(t, evaluated)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case float64:
+			testFloatObject(t, evaluated, float64(expected))
+
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("Object is not Error, got=%T(%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != fmt.Sprintf("\x1b[%dm%s\x1b[0m", 31, expected) {
+				t.Errorf("Wrong eror message, expected=%q, got=%q", expected, errObj.Message)
+			}
 		}
 	}
 }
+
+func TestArrayLiterals(t *testing.T) {
+	input := "[1, 2 * 2, 3 + 3]"
+
+	evaluated := testEval(input)
+	result, ok := evaluated.(*object.Array)
+	if !ok {
+		t.Fatalf("Object is not an Array, got=%T(%+v)", evaluated, evaluated)
+	}
+
+	if len(result.Elements) != 3 {
+		t.Fatalf("Array has wrong number of elements, got=%d", len(result.Elements))
+	}
+
+	testIntegerObject(t, result.Elements[0], 1)
+	testIntegerObject(t, result.Elements[1], 4)
+	testIntegerObject(t, result.Elements[2], 6)
+}
+
+func TestArrayIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			"[1, 2, 3][0]",
+			1,
+		},
+		{
+			"[1, 2, 3][1]",
+			2,
+		},
+		{
+			"[1, 2, 3][2]",
+			3,
+		},
+		{
+			"fanya i = 0; [1][i];",
+			1,
+		},
+		{
+			"fanya myArr = [1, 2, 3]; myArr[2];",
+			3,
+		},
+		{
+			"[1, 2, 3][3]",
+			nil,
+		},
+		{
+			"[1, 2, 3][-1]",
+			3,
+		},
+		{
+			"[1, 2, 3][-3]",
+			1,
+		},
+		{
+			"[1, 2, 3][-4]",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestStringIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"habari"[0]`, "h"},
+		{`"habari"[-2]`, "r"},
+		{`"habari"[-6]`, "h"},
+		{`"habari"[6]`, nil},
+		{`"habari"[-7]`, nil},
+		{`"ŋombe 🐄"[6]`, "🐄"},
+		{`"ŋombe 🐄"[-1]`, "🐄"},
+		{`"habari"["a"]`, "Tafadhali tumia number, sio: NENO"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[0, 1, 2, 3, 4, 5][1:4]", "[1, 2, 3]"},
+		{"[0, 1, 2, 3, 4, 5][:3]", "[0, 1, 2]"},
+		{"[0, 1, 2, 3, 4, 5][::2]", "[0, 2, 4]"},
+		{"[0, 1, 2, 3, 4, 5][::-1]", "[5, 4, 3, 2, 1, 0]"},
+		{"[0, 1, 2, 3, 4, 5][-2:]", "[4, 5]"},
+		{"[0, 1, 2, 3, 4, 5][:-2]", "[0, 1, 2, 3]"},
+		{"[0, 1, 2, 3, 4, 5][4:1:-1]", "[4, 3, 2]"},
+		{"[0, 1, 2, 3, 4, 5][5:0:-2]", "[5, 3, 1]"},
+		{"[0, 1, 2, 3, 4, 5][-10:2]", "[0, 1]"},
+		{"[0, 1, 2, 3, 4, 5][10:]", "[]"},
+		{"[0, 1, 2][tupu:tupu:tupu]", "[0, 1, 2]"},
+		{"fanya a = [1, 2]; fanya b = a[:]; b[0] = 9; a[0]", 1},
+		{`"habari"[:3]`, "hab"},
+		{`"habari"[::-1]`, "irabah"},
+		{`"habari"[1::2]`, "aai"},
+		{`"habari"[-3:]`, "ari"},
+		{"fanya t = tupu; t?[1:]", nil},
+		{"[1, 2][::0]", "Samahani, hatua ya kukata haiwezi kuwa sifuri"},
+		{`[1, 2]["a":]`, "Tafadhali tumia number, sio: NENO"},
+		{"5[1:]", "Operesheni hii haiwezekani kwa: NAMBA"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if arr, ok := evaluated.(*object.Array); ok {
+			if arr.Inspect() != tt.expected {
+				t.Errorf("wrong slice for %q. want=%v, got=%s", tt.input, tt.expected, arr.Inspect())
+			}
+			continue
+		}
+		testValue(t, tt.input, evaluated, tt.expected)
+	}
+}
+
+func TestComprehensions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[x * 2 kwa x ktk [-1, 2, 3] kama x > 0]", "[4, 6]"},
+		{"[i kwa i, x ktk [5, -1, 6, -2] kama x < 0]", "[1, 3]"},
+		{`[c + c kwa c ktk "ab"]`, "[aa, bb]"},
+		{"[x kwa x ktk []]", "[]"},
+		{"fanya o = [1, 2]; [[y kwa y ktk o] kwa x ktk o]", "[[1, 2], [1, 2]]"},
+		{"fanya f = unda(n) { rudisha [x + n kwa x ktk [1, 5] kama x > n] }; f(2)", "[7]"},
+		{"[x kwa x ktk [1]]; x", "Neno Halifahamiki: x"},
+		{"fanya x = 10; [x kwa x ktk [1]]; x", 10},
+		{"fanya d = {x: x * x kwa x ktk [1, 2, 3] kama x > 1}; [d[1], d[2], d[3]]", "[null, 4, 9]"},
+		{`fanya d = {k: v + 1 kwa k, v ktk {"a": 1}}; d["a"]`, 2},
+		{"[x kwa x ktk 5]", "Huwezi kufanya operesheni hii na NAMBA"},
+		{"[1 / x kwa x ktk [1, 0]]", "Huwezi kugawanya kwa sifuri"},
+		{"{[x]: 1 kwa x ktk [1]}", "Hashing imeshindikana: ORODHA"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if arr, ok := evaluated.(*object.Array); ok {
+			if arr.Inspect() != tt.expected {
+				t.Errorf("wrong array for %q. want=%v, got=%s", tt.input, tt.expected, arr.Inspect())
+			}
+			continue
+		}
+		testValue(t, tt.input, evaluated, tt.expected)
+	}
+}
+
+func TestIndexAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fanya a = [1, 2, 3]; a[-1] = 9; a[2]", 9},
+		{"fanya a = [1, 2, 3]; a[-3] += 5; a[0]", 6},
+		{"fanya a = [1, 2, 3]; a[3] = 9", "Index imezidi idadi ya elements"},
+		{"fanya a = [1, 2, 3]; a[-4] = 9", "Index imezidi idadi ya elements"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDictLiterals(t *testing.T) {
+	input := `fanya two = "two";
+{
+	"one": 10 - 9,
+	two: 1 +1,
+	"thr" + "ee": 6 / 2,
+	4: 4,
+	kweli: 5,
+	sikweli: 6
+}`
+
+	evaluated := testEval(input)
+	result, ok := evaluated.(*object.Dict)
+	if !ok {
+		t.Fatalf("Eval didn't return a dict, got=%T(%+v)", evaluated, evaluated)
+	}
+
+	expected := map[object.HashKey]int64{
+		(&object.String{Value: "one"}).HashKey():   1,
+		(&object.String{Value: "two"}).HashKey():   2,
+		(&object.String{Value: "three"}).HashKey(): 3,
+		(&object.Integer{Value: 4}).HashKey():      4,
+		TRUE.HashKey():                             5,
+		FALSE.HashKey():                            6,
+	}
+
+	if len(result.Pairs) != len(expected) {
+		t.Fatalf("Dict has wrong number of pairs, got=%d", len(result.Pairs))
+	}
+
+	for expectedKey, expectedValue := range expected {
+		pair, ok := result.Pairs[expectedKey]
+		if !ok {
+			t.Errorf("No pair for give key")
+		}
+
+		testIntegerObject(t, pair.Value, expectedValue)
+	}
+}
+
+func TestDictIndexExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`{"foo": 5}["foo"]`,
+			5,
+		},
+		{
+			`{"foo": 5}["bar"]`,
+			nil,
+		},
+		{
+			`fanya key = "foo"; {"foo": 5}[key]`,
+			5,
+		},
+		{
+			`{}["foo"]`,
+			nil,
+		},
+		{
+			`{5: 5}[5]`,
+			5,
+		},
+		{
+			`{kweli: 5}[kweli]`,
+			5,
+		},
+		{
+			`{sikweli: 5}[sikweli]`,
+			5,
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestSwitchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`badili (1) { ikiwa 1 { "moja" } ikiwa 2 { "mbili" } }`, "moja"},
+		{`badili (3) { ikiwa 1 { "moja" } ikiwa 2, 3 { "mbili au tatu" } }`, "mbili au tatu"},
+		{`badili ("b") { ikiwa "a" { 1 } kawaida { 2 } }`, 2},
+		{`badili (5) { kawaida { 2 } ikiwa 5 { 1 } }`, 1},
+		{`badili (5) { ikiwa 1 { 1 } }`, nil},
+		{`badili (1) { ikiwa "1" { 1 } kawaida { 2 } }`, 2},
+		{`fanya f = unda(x) { badili (x) { ikiwa 1 { rudisha "moja" } }; rudisha "nyingine" }; f(1)`, "moja"},
+		{`badili (x) { ikiwa 1 { 1 } }`, "Neno Halifahamiki: x"},
+		{`badili (1) { ikiwa y { 1 } }`, "Neno Halifahamiki: y"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestTailCalls(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fanya jumla = unda(n, acc) { kama (n == 0) { rudisha acc }; rudisha jumla(n - 1, acc + n) }; jumla(200000, 0)`, 20000100000},
+		{`fanya shuka = unda(n) { kama (n == 0) { "sawa" } sivyo { shuka(n - 1) } }; shuka(200000)`, "sawa"},
+		{`fanya a = unda(n) { kama (n == 0) { "a" } sivyo { b(n - 1) } }; fanya b = unda(n) { kama (n == 0) { "b" } sivyo { a(n - 1) } }; a(100001)`, "b"},
+		{`fanya kosa = unda() { tupa "hapa" }; fanya f = unda() { jaribu { rudisha kosa() } shika (e) { rudisha "nimeshika " + e } }; f()`, "nimeshika hapa"},
+		{`muundo Hesabu { shuka(n) { kama (n == 0) { rudisha "mwisho" }; rudisha hii.shuka(n - 1) } }; Hesabu().shuka(1000)`, "mwisho"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	input := `
+fanya fib = unda(n) {
+	kama (n < 2) { rudisha n }
+	rudisha fib(n - 1) + fib(n - 2)
+}
+muundo Hesabu { ongeza(x) { rudisha x + 1 } }
+fanya h = Hesabu()
+kwa i ktk mpaka(3) { h.ongeza(i) }
+fib(10)
+`
+	StartProfile()
+	start := time.Now()
+	testEval(input)
+	total := time.Since(start)
+	profiles := StopProfile()
+
+	calls := make(map[string]int)
+	for _, p := range profiles {
+		calls[p.Name] = p.Calls
+		// recursive calls are only timed once
+		if p.Time > total {
+			t.Errorf("%s took longer than the whole program: %s > %s", p.Name, p.Time, total)
+		}
+	}
+	if calls["fib"] != 177 || calls["Hesabu.ongeza"] != 3 || len(calls) != 2 {
+		t.Errorf("wrong call counts, got=%v", calls)
+	}
+	if profiles[0].Name != "fib" || profiles[0].Position.Line != 2 {
+		t.Errorf("expected fib at line 2 first, got=%s at line %d", profiles[0].Name, profiles[0].Position.Line)
+	}
+}
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`jaribu { 10 } shika { 20 }`, 10},
+		{`jaribu { 10 / 0 } shika { 20 }`, 20},
+		{`jaribu { 5 + kweli } shika (k) { k }`, "Aina Hazilingani: NAMBA + BOOLEAN"},
+		{`jaribu { tupa "hitilafu" } shika (k) { k }`, "hitilafu"},
+		{`jaribu { tupa 404 } shika (k) { k }`, "404"},
+		{`fanya f = unda() { tupa "ndani" }; jaribu { f() } shika (k) { k }`, "ndani"},
+		{`fanya f = unda() { jaribu { rudisha 1 } shika { rudisha 2 } }; f()`, 1},
+		{`jaribu { jaribu { tupa "a" } shika (k) { tupa k + "b" } } shika (k) { k }`, "ab"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok {
+				t.Errorf("object is not String, got=%T(%+v)", evaluated, evaluated)
+				continue
+			}
+			if str.Value != expected {
+				t.Errorf("String has wrong value, expected=%q, got=%q", expected, str.Value)
+			}
+		}
+	}
+}
+
+func TestThrowUncaught(t *testing.T) {
+	evaluated := testEval(`tupa "hitilafu"; 5`)
+
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object return, got=%T(%+v)", evaluated, evaluated)
+	}
+
+	if errObj.Message != fmt.Sprintf("\x1b[%dm%s\x1b[0m", 31, "hitilafu") {
+		t.Errorf("wrong error message, got=%q", errObj.Message)
+	}
+}
+
+func TestFileBuiltins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{fmt.Sprintf(`andika_faili(%q, "a\nb\n"); soma_faili(%q)`, path, path), "a\nb\n"},
+		{fmt.Sprintf(`ongeza_faili(%q, "c\n"); soma_faili(%q)`, path, path), "a\nb\nc\n"},
+		{fmt.Sprintf(`fanya s = ""; kwa i, l ktk fungua_faili(%q) { s += l }; s`, path), "abc"},
+		{fmt.Sprintf(`fanya n = 0; kwa i, l ktk fungua_faili(%q) { n = i }; n`, path), 2},
+		{fmt.Sprintf(`fanya f = fungua_faili(%q); kwa l ktk f { vunja }; fanya s = ""; kwa l ktk f { s += l }; s`, path), "abc"},
+		{fmt.Sprintf(`andika_faili(%q, 5)`, path), "Samahani, andika_faili inaandika NENO au BAITI tu, sio NAMBA"},
+		{fmt.Sprintf(`andika_faili(%q, baiti([0, 255, 10])); idadi(soma_baiti(%q))`, path, path), 3},
+		{fmt.Sprintf(`soma_baiti(%q)[1]`, path), 255},
+		{`soma_faili("/hakuna/faili.txt")`, "Nimeshindwa kusoma faili \"/hakuna/faili.txt\""},
+		{`fungua_faili("/hakuna/faili.txt")`, "Nimeshindwa kufungua faili \"/hakuna/faili.txt\""},
+		{`soma_faili(1)`, "Samahani, jina la faili linahitaji kuwa NENO, sio NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`json.tengua("5")`, 5},
+		{`json.tengua("1.5")`, 1.5},
+		{`json.tengua("[1, 2, 3]")[2]`, 3},
+		{`json.tengua("{\"a\": {\"b\": [10]}}")["a"]["b"][0]`, 10},
+		{`aina(json.tengua("null"))`, "TUPU"},
+		{`json.fungua({"b": [1, 2.5, kweli, tupu], "a": "x\"y"})`, `{"a":"x\"y","b":[1,2.5,true,null]}`},
+		{`json.fungua(json.tengua("{\"x\":[{},[]]}"))`, `{"x":[{},[]]}`},
+		{`tumia json; json.fungua("a")`, `"a"`},
+		{`json.tengua("{x}")`, "JSON si sahihi karibu na herufi 2"},
+		{`json.tengua("[1, 2")`, "JSON si sahihi: imeisha ghafla"},
+		{`json.tengua("1 2")`, "JSON si sahihi: kuna vitu zaidi baada ya thamani ya kwanza"},
+		{`json.tengua(1)`, "Samahani, json.tengua inahitaji NENO, sio NAMBA"},
+		{`json.fungua(mpaka(2))`, "Samahani, MPAKA haiwezi kubadilishwa kuwa JSON"},
+		{`json.hakuna`, "Moduli json haina hakuna"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestYAML(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`yaml.tengua("5")`, 5},
+		{`yaml.tengua("1.5")`, 1.5},
+		{`yaml.tengua("- 1\n- 2\n- 3")[2]`, 3},
+		{`yaml.tengua("a:\n  b: [10]")["a"]["b"][0]`, 10},
+		{`yaml.tengua("a: ndiyo\nb: true")["b"]`, true},
+		{`yaml.tengua("1: moja")[1]`, "moja"},
+		{`yaml.tengua("a: &x 7\nb: *x")["b"]`, 7},
+		{`aina(yaml.tengua("a:")["a"])`, "TUPU"},
+		{`aina(yaml.tengua("123456789012345678901234"))`, "NAMBA_KUBWA"},
+		{`yaml.tengua("2023-01-05").mwaka`, 2023},
+		{`aina(yaml.tengua(""))`, "TUPU"},
+		{`yaml.fungua({"b": [1, 2.5, kweli, tupu], "a": "x: y"})`, "a: 'x: y'\nb:\n  - 1\n  - 2.5\n  - true\n  - null\n"},
+		{`yaml.fungua(["1", "ndiyo"])`, "- \"1\"\n- ndiyo\n"},
+		{`yaml.fungua(yaml.tengua("x: [{a: 1}, []]"))`, "x:\n  - a: 1\n  - []\n"},
+		{`yaml.tengua("a: [1, 2")`, "YAML si sahihi: mstari 1: did not find expected ',' or ']'"},
+		{`yaml.tengua("a: 1\n---\nb: 2")`, "YAML si sahihi: kuna hati zaidi ya moja"},
+		{`yaml.tengua("? [1]\n: a")`, "Samahani, ORODHA haiwezi kuwa ufunguo wa kamusi"},
+		{`yaml.tengua(1)`, "Samahani, yaml.tengua inahitaji NENO, sio NAMBA"},
+		{`yaml.fungua(mpaka(2))`, "Samahani, MPAKA haiwezi kubadilishwa kuwa YAML"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestTOML(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`toml.tengua("jina = 'mradi'")["jina"]`, "mradi"},
+		{`toml.tengua("a = 5\nb = 1.5")["a"]`, 5},
+		{`toml.tengua("a = 5\nb = 1.5")["b"]`, 1.5},
+		{`toml.tengua("a = true")["a"]`, true},
+		{`toml.tengua("a = [1, [2, 3]]")["a"][1][0]`, 2},
+		{`toml.tengua("[seva]\nbandari = 8080")["seva"]["bandari"]`, 8080},
+		{`toml.tengua("a.b.c = 1")["a"]["b"]["c"]`, 1},
+		{`toml.tengua("[[kitu]]\nx = 1\n[[kitu]]\nx = 2")["kitu"][1]["x"]`, 2},
+		{`toml.tengua("lini = 2023-05-01")["lini"].mwaka`, 2023},
+		{`aina(toml.tengua(""))`, "KAMUSI"},
+		{`toml.tengua("a = 1\na = 2")`, "TOML si sahihi: mstari 2 (last key \"a\"): Key 'a' has already been defined."},
+		{`toml.tengua(1)`, "Samahani, toml.tengua inahitaji NENO, sio NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestXML(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`xml.tengua("<a x='1'>habari</a>")["tag"]`, "a"},
+		{`xml.tengua("<a x='1'>habari</a>")["attributes"]["x"]`, "1"},
+		{`xml.tengua("<a x='1'>habari</a>")["text"]`, "habari"},
+		{`xml.tengua("<?xml version='1.0'?>\n<a>\n  <b>1</b>\n  <b>2</b>\n</a>")["children"][1]["text"]`, "2"},
+		{`xml.tengua("<a><![CDATA[1 < 2]]> &amp; 3</a>")["text"]`, "1 < 2 & 3"},
+		{`idadi(xml.tafuta(xml.tengua("<a><b><c/></b><c/></a>"), "c"))`, 2},
+		{`idadi(xml.tafuta(xml.tengua("<a/>"), "c"))`, 0},
+		{`xml.fungua(xml.tengua("<a y='2' x='1'><b>1 &lt; 2</b></a>"))`, `<a x="1" y="2"><b>1 &lt; 2</b></a>`},
+		{`xml.fungua({"tag": "p", "text": "habari"})`, "<p>habari</p>"},
+		{`xml.tengua("<a><b></a>")`, "XML si sahihi: mstari 1: element <b> closed by </a>"},
+		{`xml.tengua("<a/><b/>")`, "XML si sahihi: kuna zaidi ya kipengele kimoja cha juu"},
+		{`xml.tengua("")`, "XML si sahihi: hakuna kipengele"},
+		{`xml.tengua(1)`, "Samahani, xml.tengua inahitaji NENO, sio NAMBA"},
+		{`xml.fungua({"text": "a"})`, "Samahani, kipengele cha XML kinahitaji tag"},
+		{`xml.fungua({"tag": "a", "children": [1]})`, "Samahani, kipengele cha XML kinahitaji kuwa KAMUSI, sio NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Njia", r.Method)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "%s %s %s %s", r.Method, r.Header.Get("X-Jina"), r.Header.Get("Content-Type"), body)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{fmt.Sprintf(`ombi.pata(%q)["status"]`, server.URL), 201},
+		{fmt.Sprintf(`ombi.pata(%q)["headers"]["X-Njia"]`, server.URL), "GET"},
+		{fmt.Sprintf(`ombi.pata(%q, {"X-Jina": "Juma"})["body"]`, server.URL), "GET Juma  "},
+		{fmt.Sprintf(`ombi.tuma(%q, "habari")["body"]`, server.URL), "POST   habari"},
+		{fmt.Sprintf(`ombi.tuma(%q, {"a": 1}, {"X-Jina": "Asha"})["body"]`, server.URL), `POST Asha application/json {"a":1}`},
+		{fmt.Sprintf(`ombi.weka(%q, "x")["body"]`, server.URL), "PUT   x"},
+		{fmt.Sprintf(`ombi.futa(%q)["body"]`, server.URL), "DELETE   "},
+		{`ombi.pata(1)`, "Samahani, url inahitaji kuwa NENO, sio NAMBA"},
+		{`ombi.pata()`, "Samahani, ombi.pata inapokea hoja 1 hadi 2, wewe umeweka 0"},
+		{fmt.Sprintf(`ombi.tuma(%q, 5)`, server.URL), "Samahani, ombi.tuma inatuma NENO, BAITI, KAMUSI au ORODHA tu, sio NAMBA"},
+		{fmt.Sprintf(`ombi.pata(%q, 5)`, server.URL), "Samahani, headers zinahitaji kuwa KAMUSI, sio NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestHTTPServerHandler(t *testing.T) {
+	handler := testEval(`
+unda(ombi) {
+	badili (ombi["path"]) {
+		ikiwa "/salamu" {
+			rudisha "Habari ${ombi["query"]["jina"]}"
+		}
+		ikiwa "/json" {
+			rudisha {"status": 201, "body": {"njia": ombi["method"], "idadi": idadi(ombi["body"])}}
+		}
+		ikiwa "/rudia" {
+			rudisha {"headers": {"X-Rudia": ombi["headers"]["X-Jina"]}, "body": ombi["body"]}
+		}
+		ikiwa "/kosa" {
+			tupa "imeharibika"
+		}
+		ikiwa "/status" {
+			rudisha {"status": namba(ombi["query"]["n"]), "body": "sawa"}
+		}
+	}
+	rudisha {"status": 404, "body": "Haipatikani"}
+}`)
+
+	server := httptest.NewServer(newHTTPHandler(handler))
+	defer server.Close()
+
+	tests := []struct {
+		method      string
+		path        string
+		body        string
+		status      int
+		contentType string
+		expected    string
+	}{
+		{"GET", "/salamu?jina=Juma", "", 200, "text/plain; charset=utf-8", "Habari Juma"},
+		{"POST", "/json", "abc", 201, "application/json", `{"idadi":3,"njia":"POST"}`},
+		{"PUT", "/rudia", "data", 200, "text/plain; charset=utf-8", "data"},
+		{"GET", "/kosa", "", 500, "text/plain; charset=utf-8", "imeharibika\n"},
+		{"GET", "/status?n=99", "", 500, "text/plain; charset=utf-8", "Samahani, status 99 si sahihi, inahitaji kuwa kati ya 100 na 999\n"},
+		{"GET", "/status?n=1000", "", 500, "text/plain; charset=utf-8", "Samahani, status 1000 si sahihi, inahitaji kuwa kati ya 100 na 999\n"},
+		{"GET", "/status?n=-5", "", 500, "text/plain; charset=utf-8", "Samahani, status -5 si sahihi, inahitaji kuwa kati ya 100 na 999\n"},
+		{"GET", "/status?n=299", "", 299, "text/plain; charset=utf-8", "sawa"},
+		{"GET", "/hakuna", "", 404, "text/plain; charset=utf-8", "Haipatikani"},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+		req.Header.Set("X-Jina", "Asha")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request to %s failed: %s", tt.path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.status {
+			t.Errorf("wrong status for %s. want=%d, got=%d", tt.path, tt.status, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != tt.contentType {
+			t.Errorf("wrong content type for %s. want=%q, got=%q", tt.path, tt.contentType, ct)
+		}
+		if string(body) != tt.expected {
+			t.Errorf("wrong body for %s. want=%q, got=%q", tt.path, tt.expected, body)
+		}
+		if tt.path == "/rudia" && resp.Header.Get("X-Rudia") != "Asha" {
+			t.Errorf("header not set. got=%q", resp.Header.Get("X-Rudia"))
+		}
+	}
+}
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fanya t = muda.changanua("2023-03-14 15:09:26"); t.mwaka`, 2023},
+		{`fanya t = muda.changanua("2023-03-14 15:09:26"); t.mwezi`, 3},
+		{`fanya t = muda.changanua("2023-03-14 15:09:26"); t.siku`, 14},
+		{`fanya t = muda.changanua("2023-03-14 15:09:26"); t.saa`, 15},
+		{`fanya t = muda.changanua("2023-03-14 15:09:26"); t.dakika`, 9},
+		{`fanya t = muda.changanua("2023-03-14 15:09:26"); t.sekunde`, 26},
+		{`muda.changanua("2023-03-14").saa`, 0},
+		{`muda.changanua("14/03/2023", "02/01/2006").mwezi`, 3},
+		{`muda.panga(muda.changanua("2023-03-14 15:09:26"), "02/01/2006 15:04")`, "14/03/2023 15:09"},
+		{`muda.panga(muda.ongeza(muda.changanua("2023-03-14"), 90), "15:04:05")`, "00:01:30"},
+		{`muda.tofauti(muda.changanua("2023-03-15"), muda.changanua("2023-03-14"))`, 86400},
+		{`muda.tofauti(muda.ongeza(muda.changanua("2023-03-14"), 0.5), muda.changanua("2023-03-14"))`, 0.5},
+		{`aina(muda.sasa())`, "MUDA"},
+		{`muda.sasa().mwaka > 2000`, true},
+		{`muda.changanua("2023-03-14") < muda.changanua("2023-03-15")`, true},
+		{`muda.changanua("2023-03-14") == muda.changanua("2023-03-14 00:00:00")`, true},
+		{`muda.changanua("jana")`, "Samahani, sijaweza kuelewa muda \"jana\""},
+		{`muda.sasa().wiki`, "MUDA haina wiki"},
+		{`muda.panga("2023", "2006")`, "Samahani, muda.panga inahitaji MUDA, sio NENO"},
+		{`muda.sasa() + 1`, "Aina Hazilingani: MUDA + NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+
+	evaluated := testEval(`muda.changanua("2023-03-14 15:09:26")`)
+	if evaluated.Inspect() != "2023-03-14 15:09:26" {
+		t.Errorf("wrong Inspect for time. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestRegexBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`kagua("^[a-z]+$", "habari")`, true},
+		{`kagua("^[a-z]+$", "Habari")`, false},
+		{`kagua(regex("\\d+"), "namba 42")`, true},
+		{`idadi(tafuta_zote("\\d+", "1 na 22 na 333"))`, 3},
+		{`tafuta_zote("\\d+", "1 na 22 na 333")[2]`, "333"},
+		{"tafuta_zote(`\\d+\\.\\d+`, \"3.14 na 2.5\")[1]", "2.5"},
+		{"idadi(`a\\n\nb`)", 5},
+		{"fanya n = 2\nfanya s = \"\"\"\n    idadi: ${n}\n      \"mwisho\"\n    \"\"\"\ns", "idadi: 2\n  \"mwisho\""},
+		{`idadi(tafuta_zote("x", "abc"))`, 0},
+		{`badilisha_regex("\\s+", "habari   yako  leo", " ")`, "habari yako leo"},
+		{`badilisha_regex("(\\w+)@(\\w+)", "juma@nuru", "$2:$1")`, "nuru:juma"},
+		{`aina(regex("a"))`, "REGEX"},
+		{`kagua("(", "a")`, "Samahani, regex \"(\" si sahihi"},
+		{`kagua(1, "a")`, "Samahani, regex inahitaji kuwa NENO, sio NAMBA"},
+		{`kagua("a", 1)`, "Samahani, kagua inahitaji NENO, sio NAMBA"},
+		{`badilisha_regex("a", "a", 1)`, "Samahani, badilisha_regex inahitaji NENO la kubadilisha, sio NAMBA"},
+		{`kagua("a")`, "Hoja hazilingani, tunahitaji=2, tumepewa=1"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSets(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`idadi(seti([1, 2, 2, 3, 1]))`, 3},
+		{`idadi(seti())`, 0},
+		{`idadi(seti("nuru"))`, 3},
+		{`idadi(seti(mpaka(5)))`, 5},
+		{`2 ktk seti([1, 2])`, true},
+		{`"2" ktk seti([1, 2])`, false},
+		{`seti([1, 2]) | seti([2, 3]) == seti([1, 2, 3])`, true},
+		{`seti([1, 2]) & seti([2, 3]) == seti([2])`, true},
+		{`seti([1, 2]) - seti([2, 3]) == seti([1])`, true},
+		{`seti([1, 2]) != seti([2, 1])`, false},
+		{`fanya s = seti(); s.ongeza(1, "a"); idadi(s)`, 2},
+		{`fanya s = seti([1, 2]); s.ondoa(1); s.ina(1)`, false},
+		{`seti([1]).ina(1)`, true},
+		{`aina(seti())`, "SETI"},
+		{`seti([[1]])`, "Samahani, ORODHA haiwezi kuwekwa kwenye seti"},
+		{`seti(1)`, "Samahani, seti haiwezi kutengenezwa kutoka NAMBA"},
+		{`seti([1]) * seti([1])`, "Operesheni Haielweki: SETI * SETI"},
+		{`seti().panga`, "SETI haina panga"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`thibitisha(1 < 2)`, nil},
+		{`thibitisha(1 > 2)`, "Uthibitisho umeshindwa (sharti: 1 > 2)"},
+		{`fanya x = [1]; thibitisha(idadi(x) == 2 && x[0] > 0)`, "Uthibitisho umeshindwa (sharti: idadi(x) == 2 && x[0] > 0)"},
+		{`thibitisha(tupu, "hakuna kitu")`, "Uthibitisho umeshindwa: hakuna kitu (sharti: tupu)"},
+		{`fanya a = [sikweli]; thibitisha(...a)`, "Uthibitisho umeshindwa"},
+		{`thibitisha()`, "Samahani, hii function inapokea hoja 1 au 2, wewe umeweka 0"},
+		{`thibitisha_sawa(1 + 1, 2)`, nil},
+		{`thibitisha_sawa("a", "a", "herufi")`, nil},
+		{`thibitisha_sawa(1 + 1, 3)`, "Uthibitisho umeshindwa: tumepata=2, tulitarajia=3"},
+		{`thibitisha_sawa("1", 1, "aina")`, "Uthibitisho umeshindwa: aina: tumepata=1, tulitarajia=1"},
+		{`thibitisha_sawa(1)`, "Samahani, hii function inapokea hoja 2 au 3, wewe umeweka 1"},
+		{`jaribu { thibitisha(sikweli) } shika (e) { e }`, "Uthibitisho umeshindwa (sharti: sikweli)"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+
+	// the error points at the condition, not at the call
+	err := testEval("fanya x = 1\nthibitisha(x > 2)").(*object.Error)
+	if err.Position.Line != 2 || err.Position.Column != 14 {
+		t.Errorf("wrong position for a failed assertion. got=%+v", err.Position)
+	}
+}
+
+func TestTuples(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`jozi(1, "a")[1]`, "a"},
+		{`jozi(1, 2)[5]`, nil},
+		{`idadi(jozi(1, 2, 3))`, 3},
+		{`idadi(jozi())`, 0},
+		{`2 ktk jozi(1, 2)`, true},
+		{`jozi(1) ktk jozi(jozi(1), 2)`, true},
+		{`jozi(1, 2) == jozi(1, 2)`, true},
+		{`jozi(1, 2) == jozi(2, 1)`, false},
+		{`jozi(1, "1") != jozi(1, 1)`, true},
+		{`idadi(jozi(1) + jozi(2, 3))`, 3},
+		{`fanya d = {jozi(0, 1): "juu"}; d[jozi(0, 1)]`, "juu"},
+		{`fanya d = {jozi(0, 1): "juu"}; jozi(0, 1) ktk d`, true},
+		{`idadi(seti([jozi(1, 2), jozi(1, 2), jozi(2, 1)]))`, 2},
+		{`aina(jozi(1))`, "JOZI"},
+		{`jozi([1])`, "Samahani, ORODHA haiwezi kuwekwa kwenye jozi"},
+		{`fanya t = jozi(1); t[0] = 2`, "JOZI haiwezi kubadilishwa"},
+		{`jozi(1)["a"]`, "Tafadhali tumia number, sio: NENO"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`idadi(baiti("habari"))`, 6},
+		{`idadi(baiti(baiti([241]).neno("latin-1")))`, 2},
+		{`idadi(baiti(baiti([241]).neno("latin-1"), "latin-1"))`, 1},
+		{`baiti("a")[0]`, 97},
+		{`baiti("a")[3]`, nil},
+		{`baiti([104, 105]).neno()`, "hi"},
+		{`baiti([241], "latin-1")`, "Samahani, usimbaji unatumika na NENO tu"},
+		{`baiti([241]).neno("latin-1")`, "ñ"},
+		{`baiti(baiti([241]).neno("latin-1"), "ascii")`, "Samahani, herufi 'ñ' haiwezi kusimbwa kwa ascii"},
+		{`baiti("habari").kata(1, 3).neno()`, "ab"},
+		{`baiti("habari").kata(4).neno()`, "ri"},
+		{`baiti("habari").kata(-5, 100).neno()`, "habari"},
+		{`baiti("ab") + baiti("c") == baiti("abc")`, true},
+		{`97 ktk baiti("abc")`, true},
+		{`baiti("bc") ktk baiti("abc")`, true},
+		{`fanya d = {baiti("a"): 1}; d[baiti("a")]`, 1},
+		{`fanya s = 0; kwa b ktk baiti([1, 2, 3]) { s += b }; s`, 6},
+		{`aina(baiti(""))`, "BAITI"},
+		{`baiti([256])`, "Samahani, baiti inahitaji namba kati ya 0 na 255, sio 256"},
+		{`baiti("a", "utf-16")`, "Samahani, usimbaji \"utf-16\" haujulikani"},
+		{`baiti([255]).neno()`, "Samahani, baiti hizi si utf-8 sahihi"},
+		{`baiti(1)`, "Samahani, baiti haziwezi kutengenezwa kutoka NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "-9223372036854775809"},
+		{"4611686018427387904 * 4", "18446744073709551616"},
+		{"2 ** 100", "1267650600228229401496703205376"},
+		{"3 ** 39", "4052555153018976267"},
+		{"123456789012345678901234567890 + 1", "123456789012345678901234567891"},
+		{"123456789012345678901234567890 % 1000", "890"},
+		{"(2 ** 100) / (2 ** 98)", "4"},
+		{"9223372036854775807 / 1", "9223372036854775807"},
+		{"9007199254740993 / 1", "9007199254740993"},
+		{"-9223372036854775808 / -1", "9223372036854775808"},
+		{"-9223372036854775808 % -1", "0"},
+		{"-(2 ** 64)", "-18446744073709551616"},
+		{`json.fungua(2 ** 64)`, "18446744073709551616"},
+		{`json.tengua("18446744073709551616") + 1`, "18446744073709551617"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+
+	values := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"aina(2 ** 64)", "NAMBA_KUBWA"},
+		{"aina(2 ** 64 - 2 ** 64)", "NAMBA"},
+		{"2 ** 64 - (2 ** 64 - 1)", 1},
+		{"-9223372036854775808 == -9223372036854775807 - 1", true},
+		{"2 ** 65 > 2 ** 64", true},
+		{"2 ** 64 == 2 ** 64", true},
+		{"2 ** 64 < 1.5", false},
+		{"fanya d = {2 ** 64: 1}; d[2 ** 64]", 1},
+		{"2 ** 64 % 0", "Huwezi kugawanya kwa sifuri"},
+	}
+
+	for _, tt := range values {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`desimali("0.1") + desimali("0.2")`, "0.3"},
+		{`desimali("10.25") * 3`, "30.75"},
+		{`desimali("10") / 4`, "2.5"},
+		{`desimali(1) / 3`, "0.3333333333333333333333333333"},
+		{`desimali("10.5") % 3`, "1.5"},
+		{`desimali("-10.5") % 3`, "-1.5"},
+		{`desimali("1.5") ** 2`, "2.25"},
+		{`desimali(2) ** -2`, "0.25"},
+		{`-desimali("2.50")`, "-2.5"},
+		{`desimali("1e3")`, "1000"},
+		{`desimali(0.1) + 0.2`, "0.3"},
+		{`desimali(2 ** 70) + 1`, "1180591620717411303425"},
+		{`json.fungua([desimali("19.99")])`, "[19.99]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+
+	values := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`aina(desimali(1))`, "DESIMALI_KAMILI"},
+		{`desimali("0.1") * 3 == desimali("0.3")`, true},
+		{`desimali("1.10") == desimali("1.1")`, true},
+		{`desimali("1.5") > 1`, true},
+		{`2.5 <= desimali("2.5")`, true},
+		{`fanya d = {desimali("1.50"): "a"}; d[desimali("1.5")]`, "a"},
+		{`desimali("1/3")`, "Samahani, \"1/3\" si desimali sahihi"},
+		{`desimali("abc")`, "Samahani, \"abc\" si desimali sahihi"},
+		{`desimali(kweli)`, "Samahani, desimali haiwezi kutengenezwa kutoka BOOLEAN"},
+		{`desimali(1) / 0`, "Huwezi kugawanya kwa sifuri"},
+		{`desimali(2) ** desimali("0.5")`, "Desimali inaweza kupandishwa kwa namba kamili tu"},
+		{`desimali(1) + "a"`, "Aina Hazilingani: DESIMALI_KAMILI + NENO"},
+	}
+
+	for _, tt := range values {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestImportStatement(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"hesabu.nr":   `fanya jumlisha = unda(a, b) { rudisha a + b }; fanya PI = 3.14`,
+		"tegemezi.nr": `tumia "hesabu.nr"; fanya mara2 = unda(x) { rudisha hesabu.jumlisha(x, x) }`,
+		"kosa.nr":     `fanya x = 5 + kweli`,
+		"zunguka.nr":  `tumia "zunguka.nr"`,
+
+		"vifurushi/takwimu/takwimu.nr": `tumia "takwimu/wastani"; fanya w = wastani.wastani`,
+		"vifurushi/takwimu/wastani.nr": `fanya wastani = unda(a, b) { rudisha (a + b) / 2 }`,
+	}
+	for name, contents := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldPaths := ModulePaths
+	ModulePaths = []string{dir}
+	defer func() { ModulePaths = oldPaths }()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`tumia "hesabu.nr"; hesabu.jumlisha(2, 3)`, 5},
+		{`tumia hesabu; hesabu.PI`, 3.14},
+		{`tumia tegemezi; tegemezi.mara2(4)`, 8},
+		{`tumia "hesabu.nr"; hesabu.hakuna`, "Moduli hesabu haina hakuna"},
+		{`tumia "hakuna.nr"`, `Moduli "hakuna.nr" haipatikani`},
+		{`tumia kosa`, "Aina Hazilingani: NAMBA + BOOLEAN"},
+		{`tumia zunguka`, `Moduli "zunguka.nr" inajiita yenyewe (mzunguko wa 'tumia')`},
+		// packages fetched with 'nuru pakua'
+		{`tumia takwimu; takwimu.w(2, 4)`, 3},
+		{`tumia "takwimu/wastani"; wastani.wastani(1, 3)`, 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case float64:
+			testFloatObject(t, evaluated, expected)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("object is not Error, got=%T(%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != fmt.Sprintf("\x1b[%dm%s\x1b[0m", 31, expected) {
+				t.Errorf("wrong error message, expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+
+	// an error inside a module points at the module, not the importer
+	errObj := testEval(`tumia kosa`).(*object.Error)
+	if filepath.Base(errObj.Position.File) != "kosa.nr" || errObj.Position.Line != 1 {
+		t.Errorf("wrong position for module error. got=%+v", errObj.Position)
+	}
+}
+
+func TestOutputCanBeCaptured(t *testing.T) {
+	var out bytes.Buffer
+	Stdout, Stdin = &out, strings.NewReader("Juma\n")
+	defer func() { Stdout, Stdin = os.Stdout, os.Stdin }()
+
+	testEval(`fanya jina = jaza("Jina: "); andika("Habari", jina); andika()`)
+
+	if expected := "Jina: Habari Juma\n\n"; out.String() != expected {
+		t.Errorf("wrong output. want=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestHelp(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fanya f = unda(a, b) {\n  \"Inajumlisha a na b.\n  Kisha inarudisha jumla.\"\n  a + b\n}; msaada(f)", "unda f(a, b)\n    Inajumlisha a na b.\n    Kisha inarudisha jumla.\n"},
+		// a string alone is what the function gives back, not its docs
+		{`msaada(unda(...x) { "x" })`, "unda(...x)\n    Hakuna maelezo.\n"},
+		{`muundo M { salimu() { "Inasalimia"; 1 } }; msaada(M().salimu)`, "unda M.salimu()\n    Inasalimia\n"},
+		{`msaada(yamwisho)`, "yamwisho(orodha)\n    Inarudisha kitu cha mwisho cha orodha.\n"},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		Stdout = &out
+		result := testEval(tt.input)
+		Stdout = os.Stdout
+		if isError(result) {
+			t.Fatalf("%s: %s", tt.input, result.Inspect())
+		}
+		if out.String() != tt.expected {
+			t.Errorf("wrong output for %q. want=%q, got=%q", tt.input, tt.expected, out.String())
+		}
+	}
+
+	testValue(t, "msaada(1)", testEval("msaada(1)"), "msaada inahitaji unda, sio NAMBA")
+
+	for _, name := range BuiltinNames() {
+		if _, ok := builtinDocs[name]; !ok {
+			t.Errorf("builtin %s has no docs", name)
+		}
+	}
+}
+
+func TestPrettyPrint(t *testing.T) {
+	var out bytes.Buffer
+	Stdout = &out
+	defer func() { Stdout = os.Stdout }()
+
+	testEval(`fanya d = {"b": [1, 2], "a": kweli}; d["c"] = d; chapisha_vizuri(d)`)
+	if expected := "{a: kweli, b: [1, 2], c: {...}}\n"; out.String() != expected {
+		t.Errorf("wrong output. want=%q, got=%q", expected, out.String())
+	}
+	testValue(t, "chapisha_vizuri()", testEval("chapisha_vizuri()"), "Hoja hazilingani, tunahitaji=1, tumepewa=0")
+}
+
+func TestImportFromModuleFS(t *testing.T) {
+	ModuleFS = fstest.MapFS{
+		"jumla.nr":                   {Data: []byte(`tumia "lib/mara"; fanya jumla = unda(a, b) { rudisha mara.mara(a, 1) + b }`)},
+		"lib/mara.nr":                {Data: []byte(`fanya mara = unda(a, b) { rudisha a * b }`)},
+		"vifurushi/orodha/orodha.nr": {Data: []byte(`fanya kwanza = 1`)},
+	}
+	defer func() { ModuleFS = nil }()
+
+	testIntegerObject(t, testEval(`tumia jumla; jumla.jumla(2, 3)`), 5)
+	testIntegerObject(t, testEval(`tumia orodha; orodha.kwanza`), 1)
+
+	errObj, ok := testEval(`tumia hesabu`).(*object.Error)
+	if !ok || errObj.Message != fmt.Sprintf("\x1b[%dm%s\x1b[0m", 31, `Moduli "hesabu" haipatikani`) {
+		t.Errorf("expected a missing module, got=%v", errObj)
+	}
+}
+
+func TestImportIsCached(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hesabu.nr")
+	if err := os.WriteFile(path, []byte(`fanya x = 1`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldPaths := ModulePaths
+	ModulePaths = []string{dir}
+	defer func() { ModulePaths = oldPaths }()
+
+	testEval(`tumia hesabu`)
+
+	if err := os.WriteFile(path, []byte(`fanya x = 2`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testIntegerObject(t, testEval(`tumia hesabu; hesabu.x`), 1)
+}
+
+func TestRegisterBuiltin(t *testing.T) {
+	double := func(args ...object.Object) object.Object {
+		return &object.Integer{Value: args[0].(*object.Integer).Value * 2}
+	}
+	if err := RegisterBuiltin("mara_mbili", 1, double); err != nil {
+		t.Fatalf("RegisterBuiltin failed: %s", err)
+	}
+	defer delete(builtins, "mara_mbili")
+
+	testIntegerObject(t, testEval(`mara_mbili(21)`), 42)
+
+	errObj, ok := testEval(`mara_mbili(1, 2)`).(*object.Error)
+	if !ok || errorMessage(errObj) != "Hoja hazilingani, tunahitaji=1, tumepewa=2" {
+		t.Errorf("expected an arity error, got=%v", errObj)
+	}
+
+	if err := RegisterBuiltin("ongeza_zote", Variadic(1), double); err != nil {
+		t.Fatalf("RegisterBuiltin failed: %s", err)
+	}
+	defer delete(builtins, "ongeza_zote")
+
+	testIntegerObject(t, testEval(`ongeza_zote(2, 3, 4)`), 4)
+
+	errObj, ok = testEval(`ongeza_zote()`).(*object.Error)
+	if !ok || errorMessage(errObj) != "Hoja hazilingani, tunahitaji angalau=1, tumepewa=0" {
+		t.Errorf("expected an arity error, got=%v", errObj)
+	}
+
+	tests := []struct {
+		name     string
+		arity    int
+		expected string
+	}{
+		{"mara_mbili", 1, "Tayari kuna kitendakazi cha ndani kinachoitwa mara_mbili"},
+		{"andika", AnyArity, "Tayari kuna kitendakazi cha ndani kinachoitwa andika"},
+		{"fanya", 1, `"fanya" haliwezi kuwa jina la kitendakazi`},
+		{"2x", 1, `"2x" haliwezi kuwa jina la kitendakazi`},
+		{"jina-refu", 1, `"jina-refu" haliwezi kuwa jina la kitendakazi`},
+		{"", 1, `"" haliwezi kuwa jina la kitendakazi`},
+	}
+	for _, tt := range tests {
+		err := RegisterBuiltin(tt.name, tt.arity, double)
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("RegisterBuiltin(%q): wrong error. want=%q, got=%v", tt.name, tt.expected, err)
+		}
+	}
+}
+
+func TestSpawnExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fanya a = sambamba unda(x) { x * 2 }(21); a.subiri()`, 42},
+		{`fanya f = unda() { 1 }; aina(sambamba f())`, "AHADI"},
+		{`fanya f = unda() {}; fanya a = sambamba f(); a.subiri(); a.subiri()`, nil},
+		{`fanya o = [0]; fanya a = sambamba unda() { o[0] = 5 }(); a.subiri(); o[0]`, 5},
+		{`fanya a = sambamba unda() { tupa "hitilafu" }(); jaribu { a.subiri() } shika (e) { e }`, "hitilafu"},
+		{`fanya ahadi = []
+		  kwa i ktk mpaka(10) {
+		      ahadi = sukuma(ahadi, sambamba unda(n) { n * n }(i))
+		  }
+		  fanya jumla = 0
+		  kwa a ktk ahadi { jumla += a.subiri() }
+		  jumla`, 285},
+		{`fanya orodha = [1, 2, 3]
+		  fanya kazi = unda() {
+		      fanya s = 0
+		      kwa x ktk orodha { kwa y ktk orodha { s += x * y } }
+		      rudisha s
+		  }
+		  fanya ahadi = [sambamba kazi(), sambamba kazi(), sambamba kazi()]
+		  ahadi[0].subiri() + ahadi[1].subiri() + ahadi[2].subiri()`, 108},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok || str.Value != expected {
+				t.Errorf("expected %q, got=%v", expected, evaluated)
+			}
+		case nil:
+			testNullObject(t, evaluated)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{`fanya a = sambamba unda() { x }(); a.subiri()`, "Neno Halifahamiki: x"},
+		{`sambamba x()`, "Neno Halifahamiki: x"},
+		{`fanya f = unda() {}; fanya a = sambamba f(); a.subiri(1)`, "Hoja hazilingani, tunahitaji=0, tumepewa=1"},
+		{`fanya f = unda() {}; fanya a = sambamba f(); a.ngoja()`, "AHADI haina ngoja"},
+	}
+
+	for _, tt := range errors {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok || errorMessage(errObj) != tt.expected {
+			t.Errorf("expected error %q, got=%v", tt.expected, errObj)
+		}
+	}
+}
+
+func TestChannel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fanya m = mfereji(1); m.tuma(5); m.pokea()`, 5},
+		{`aina(mfereji())`, "MFEREJI"},
+		{`fanya m = mfereji(); m.funga(); m.pokea()`, nil},
+		{`fanya m = mfereji()
+		  sambamba unda() {
+		      kwa i ktk mpaka(5) { m.tuma(i) }
+		      m.funga()
+		  }()
+		  fanya jumla = 0
+		  kwa x ktk m { jumla += x }
+		  jumla`, 10},
+		{`fanya m = mfereji(3); m.tuma("a"); m.tuma("b"); m.funga()
+		  fanya funguo = 0
+		  kwa i, x ktk m { funguo += i }
+		  funguo`, 1},
+		{`fanya kazi = mfereji(10)
+		  fanya matokeo = mfereji(10)
+		  fanya mfanyakazi = unda() {
+		      kwa x ktk kazi { matokeo.tuma(x * 2) }
+		  }
+		  fanya ahadi = [sambamba mfanyakazi(), sambamba mfanyakazi(), sambamba mfanyakazi()]
+		  kwa i ktk mpaka(10) { kazi.tuma(i) }
+		  kazi.funga()
+		  kwa a ktk ahadi { a.subiri() }
+		  matokeo.funga()
+		  fanya jumla = 0
+		  kwa x ktk matokeo { jumla += x }
+		  jumla`, 90},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok || str.Value != expected {
+				t.Errorf("expected %q, got=%v", expected, evaluated)
+			}
+		case nil:
+			testNullObject(t, evaluated)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{`fanya m = mfereji(); m.funga(); m.tuma(1)`, "Huwezi kutuma kwenye mfereji uliofungwa"},
+		{`fanya m = mfereji(); m.funga(); m.funga()`, "Mfereji umeshafungwa"},
+		{`mfereji("a")`, "Samahani, ukubwa wa mfereji unahitaji kuwa NAMBA, sio NENO"},
+		{`mfereji(-1)`, "Samahani, ukubwa wa mfereji hauwezi kuwa -1"},
+		{`mfereji(1, 2)`, "Samahani, mfereji inapokea hoja 0 au 1, wewe umeweka 2"},
+		{`mfereji().ondoa()`, "MFEREJI haina ondoa"},
+	}
+
+	for _, tt := range errors {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok || errorMessage(errObj) != tt.expected {
+			t.Errorf("expected error %q, got=%v", tt.expected, errObj)
+		}
+	}
+}
+
+func TestLockAndWaitGroup(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fanya hesabu = {"n": 0}
+		  fanya k = kufuli()
+		  fanya kk = kikundi()
+		  fanya kazi = unda() {
+		      kwa i ktk mpaka(50) {
+		          k.linda(unda() { hesabu["n"] += 1 })
+		      }
+		      kk.maliza()
+		  }
+		  kwa i ktk mpaka(4) {
+		      kk.ongeza()
+		      sambamba kazi()
+		  }
+		  kk.subiri()
+		  hesabu["n"]`, 200},
+		{`fanya orodha = [0]
+		  fanya k = kufuli()
+		  fanya kk = kikundi()
+		  kk.ongeza(3)
+		  kwa i ktk mpaka(3) {
+		      sambamba unda() {
+		          k.funga()
+		          orodha[0] += 1
+		          k.fungua()
+		          kk.maliza()
+		      }()
+		  }
+		  kk.subiri()
+		  orodha[0]`, 3},
+		{`fanya k = kufuli(); k.linda(unda() { 7 })`, 7},
+		{`fanya k = kufuli(); jaribu { k.linda(unda() { tupa "x" }) } shika { }; k.funga(); k.fungua(); aina(k)`, "KUFULI"},
+		{`fanya kk = kikundi(); kk.subiri(); aina(kk)`, "KIKUNDI"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok || str.Value != expected {
+				t.Errorf("expected %q, got=%v", expected, evaluated)
+			}
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{`kufuli().fungua()`, "Kufuli haijafungwa"},
+		{`kufuli().linda()`, "Hoja hazilingani, tunahitaji=1, tumepewa=0"},
+		{`kikundi().maliza()`, "Kikundi kimemaliza zaidi ya kilivyoongeza"},
+		{`kikundi().ongeza("a")`, "Samahani, ongeza inahitaji NAMBA, sio NENO"},
+		{`kikundi().ongeza(-1)`, "Kikundi kimemaliza zaidi ya kilivyoongeza"},
+		{`kufuli(1)`, "Hoja hazilingani, tunahitaji=0, tumepewa=1"},
+		{`kikundi().fungua()`, "KIKUNDI haina fungua"},
+	}
+
+	for _, tt := range errors {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok || errorMessage(errObj) != tt.expected {
+			t.Errorf("expected error %q, got=%v", tt.expected, errObj)
+		}
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`ramani_sambamba([1, 2, 3, 4, 5], unda(x) { x * x })`, "[1, 4, 9, 16, 25]"},
+		{`ramani_sambamba([], unda(x) { x })`, "[]"},
+		{`ramani_sambamba(["a", 1], aina)`, "[NENO, NAMBA]"},
+		{`ramani_sambamba([1, 2, 3], unda(x) { x + 1 }, 2)`, "[2, 3, 4]"},
+		{`ramani_sambamba([1, 2], unda(x) {})`, "[null, null]"},
+		{`fanya k = 10; ramani_sambamba([1, 2, 3], unda(x) { x * k })`, "[10, 20, 30]"},
+		{`muundo Mtu { unda(jina) { hii.jina = jina } }
+		  fanya watu = ramani_sambamba(["Asha", "Juma"], Mtu)
+		  watu[1].jina`, "Juma"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil || evaluated.Inspect() != tt.expected {
+			t.Errorf("expected %q, got=%v", tt.expected, evaluated)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{`ramani_sambamba([1, "a", 3, "b"], unda(x) { x + 1 })`, "Aina Hazilingani: NENO + NAMBA"},
+		{`ramani_sambamba([1])`, "Samahani, ramani_sambamba inapokea hoja 2 au 3, wewe umeweka 1"},
+		{`ramani_sambamba("abc", aina)`, "Samahani, ramani_sambamba inahitaji ORODHA, sio NENO"},
+		{`ramani_sambamba([1], 5)`, "Hii sio function: NAMBA"},
+		{`ramani_sambamba([1], aina, 0)`, "Samahani, idadi ya wafanyakazi inahitaji kuwa NAMBA kubwa kuliko 0, sio 0"},
+	}
+
+	for _, tt := range errors {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok || errorMessage(errObj) != tt.expected {
+			t.Errorf("expected error %q, got=%v", tt.expected, errObj)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fanya n = bahati.namba(1, 6); n >= 1 && n <= 6`, true},
+		{`bahati.namba(3, 3)`, 3},
+		{`fanya d = bahati.desimali(); d >= 0 && d < 1`, true},
+		{`bahati.chagua(["a", "b"]) ktk ["a", "b"]`, true},
+		{`jumla(bahati.changanya([1, 2, 3, 4]))`, 10},
+		{`fanya o = [1, 2, 3]; bahati.changanya(o); o[0] == 1 && o[2] == 3`, true},
+		{`bahati.mbegu(7); fanya a = [bahati.namba(0, 1000), bahati.desimali(), bahati.chagua([1, 2, 3, 4, 5])]
+		  bahati.mbegu(7); fanya b = [bahati.namba(0, 1000), bahati.desimali(), bahati.chagua([1, 2, 3, 4, 5])]
+		  a[0] == b[0] && a[1] == b[1] && a[2] == b[2]`, true},
+		{`bahati.namba(6, 1)`, "Samahani, 6 ni kubwa kuliko 1"},
+		{`bahati.namba(1.5, 2)`, "Samahani, bahati.namba inahitaji NAMBA, sio DESIMALI"},
+		{`bahati.chagua([])`, "Samahani, huwezi kuchagua kutoka kwenye orodha tupu"},
+		{`bahati.changanya("abc")`, "Samahani, bahati.changanya inahitaji ORODHA, sio NENO"},
+		{`bahati.mbegu()`, "Hoja hazilingani, tunahitaji=1, tumepewa=0"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestMath(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`hisabati.pi`, math.Pi},
+		{`hisabati.e`, math.E},
+		{`hisabati.sqrt(16)`, 4.0},
+		{`hisabati.sin(0)`, 0.0},
+		{`hisabati.cos(0)`, 1.0},
+		{`hisabati.tan(0)`, 0.0},
+		{`hisabati.exp(0)`, 1.0},
+		{`hisabati.log(hisabati.e)`, 1.0},
+		{`hisabati.log(8, 2)`, 3.0},
+		{`hisabati.pow(2, 10)`, 1024},
+		{`hisabati.pow(2, 0.5)`, math.Sqrt2},
+		{`hisabati.floor(2.7)`, 2},
+		{`hisabati.floor(-2.5)`, -3},
+		{`hisabati.floor(7 / 2)`, 3},
+		{`hisabati.floor(-7 / 2)`, -4},
+		{`hisabati.floor(7 / 0)`, "Huwezi kugawanya kwa sifuri"},
+		{`hisabati.ceil(2.1)`, 3},
+		{`hisabati.ceil(5)`, 5},
+		{`tumia hisabati; hisabati.floor(1.5)`, 1},
+		{`hisabati.sqrt(-1)`, "Samahani, namba hasi haina sqrt: -1"},
+		{`hisabati.log(0)`, "Samahani, log inahitaji namba kubwa kuliko 0, sio 0"},
+		{`hisabati.log(8, 1)`, "Samahani, 1 haiwezi kuwa msingi wa log"},
+		{`hisabati.sin("0")`, "Samahani, hisabati.sin inahitaji namba, sio NENO"},
+		{`hisabati.pow(2)`, "Hoja hazilingani, tunahitaji=2, tumepewa=1"},
+		{`hisabati.tau`, "Moduli hisabati haina tau"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestStringMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"a,b,c".gawa(",")[1]`, "b"},
+		{`idadi("  moja   mbili ".gawa())`, 2},
+		{`"-".unga(["a", 1, kweli])`, "a-1-kweli"},
+		{`"".unga([])`, ""},
+		{`"  habari  ".punguza()`, "habari"},
+		{`"--habari--".punguza("-")`, "habari"},
+		{`"Habari".herufikubwa()`, "HABARI"},
+		{`"Habari".herufindogo()`, "habari"},
+		{`"a-b-c".badilisha("-", "+")`, "a+b+c"},
+		{`"habari".tafuta("bar")`, 2},
+		{`"habari".tafuta("z")`, -1},
+		{`"ŋŋ habari".tafuta("bar")`, 5},
+		{`fanya s = ""; kwa i, c ktk "ŋa🐄" { s += neno(i) + c }; s`, "0ŋ1a2🐄"},
+		{`neno([c kwa c ktk "ñé"])`, "[ñ, é]"},
+		{`fanya s = "Asha"; s.herufikubwa(); s`, "Asha"},
+		{`"a".gawa(1)`, "Samahani, gawa inahitaji NENO, sio NAMBA"},
+		{`",".unga("ab")`, "Samahani, unga inahitaji ORODHA, sio NENO"},
+		{`"a".badilisha("a")`, "Hoja hazilingani, tunahitaji=2, tumepewa=1"},
+		{`"a".herufikubwa(1)`, "Hoja hazilingani, tunahitaji=0, tumepewa=1"},
+		{`"a".geuza()`, "NENO haina geuza"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestArrayMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 2, 3].ramani(unda(x) { x * 2 })[2]`, 6},
+		{`idadi([].ramani(unda(x) { x }))`, 0},
+		{`[1, "a"].ramani(aina)[1]`, "NENO"},
+		{`idadi([1, 2, 3, 4].chuja(unda(x) { x % 2 == 0 }))`, 2},
+		{`[1, 2, 3, 4].chuja(unda(x) { x > 2 })[0]`, 3},
+		{`[1, 2, 3, 4].punguza(unda(a, b) { a + b })`, 10},
+		{`[1, 2, 3].punguza(unda(a, b) { a + b }, 10)`, 16},
+		{`[].punguza(unda(a, b) { a + b }, 0)`, 0},
+		{`["a", "b"].punguza(unda(a, b) { a + b }, "")`, "ab"},
+		{`fanya o = [1, 2]; o.ramani(unda(x) { x * 10 }); o[0]`, 1},
+		{`[1, 2, 3].ramani(unda(x) { x * 2 }).chuja(unda(x) { x > 2 }).punguza(unda(a, b) { a + b })`, 10},
+		{`[].punguza(unda(a, b) { a })`, "Samahani, huwezi kupunguza orodha tupu bila thamani ya kuanzia"},
+		{`[1].ramani(5)`, "Samahani, ramani inahitaji unda, sio NAMBA"},
+		{`[1, "a"].ramani(unda(x) { x + 1 })`, "Aina Hazilingani: NENO + NAMBA"},
+		{`[1].chuja()`, "Hoja hazilingani, tunahitaji=1, tumepewa=0"},
+		{`[1].geuza()`, "ORODHA haina geuza"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDictBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`funguo({"b": 2, "a": 1})[0]`, "a"},
+		{`idadi(funguo({}))`, 0},
+		{`thamani({"b": 2, "a": 1})[1]`, 2},
+		{`vipengele({"b": 2, "a": 1})[1][0]`, "b"},
+		{`vipengele({"b": 2, "a": 1})[1][1]`, 2},
+		{`unganisha({"a": 1}, {"b": 2})["b"]`, 2},
+		{`unganisha({"a": 1}, {"a": 2}, {"a": 3})["a"]`, 3},
+		{`fanya d = {"a": 1}; unganisha(d, {"a": 2}); d["a"]`, 1},
+		{`funguo([1])`, "Samahani, funguo inahitaji KAMUSI, sio ORODHA"},
+		{`thamani()`, "Hoja hazilingani, tunahitaji=1, tumepewa=0"},
+		{`unganisha({})`, "Samahani, unganisha inahitaji kamusi 2 au zaidi, wewe umeweka 1"},
+		{`unganisha({}, 1)`, "Samahani, unganisha inahitaji KAMUSI, sio NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`panga([3, 1.5, 2, -1])[0]`, -1},
+		{`panga([3, 1.5, 2, -1])[1]`, 1.5},
+		{`panga(["ndizi", "embe", "papai"])[0]`, "embe"},
+		{`idadi(panga([]))`, 0},
+		{`panga([3, 1, 2], unda(a, b) { a > b })[0]`, 3},
+		{`panga([[2, "a"], [1, "b"], [2, "c"], [1, "d"]], unda(a, b) { a[0] - b[0] })[1][1]`, "d"},
+		{`panga([[2, "a"], [1, "b"], [2, "c"], [1, "d"]], unda(a, b) { a[0] - b[0] })[2][1]`, "a"},
+		{`fanya o = [2, 1]; panga(o); o[0]`, 2},
+		{`panga([1, "a"])`, "Samahani, siwezi kulinganisha NENO na NAMBA"},
+		{`panga([kweli, sikweli])`, "Samahani, siwezi kulinganisha BOOLEAN na BOOLEAN"},
+		{`panga([1, 2], unda(a, b) { "ndio" })`, "Samahani, unda ya panga inahitaji kurudisha BOOLEAN au namba, sio NENO"},
+		{`panga([1, "a"], unda(a, b) { a + b })`, "Aina Hazilingani: NENO + NAMBA"},
+		{`panga("abc")`, "Samahani, panga inahitaji ORODHA, sio NENO"},
+		{`panga([1], 2)`, "Samahani, panga inahitaji unda, sio NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`namba("42")`, 42},
+		{`namba(" -7 ")`, -7},
+		{`namba("2.5")`, 2.5},
+		{`aina(namba("123456789012345678901234"))`, "NAMBA_KUBWA"},
+		{`namba(3.5)`, 3.5},
+		{`namba(kweli)`, 1},
+		{`namba(sikweli)`, 0},
+		{`namba("abc")`, `Samahani, "abc" haiwezi kugeuzwa kuwa namba`},
+		{`namba("NaN")`, `Samahani, "NaN" haiwezi kugeuzwa kuwa namba`},
+		{`namba("")`, `Samahani, "" haiwezi kugeuzwa kuwa namba`},
+		{`namba([1])`, "Samahani, ORODHA haiwezi kugeuzwa kuwa namba"},
+		{`namba()`, "Hoja hazilingani, tunahitaji=1, tumepewa=0"},
+		{`neno(12) + "!"`, "12!"},
+		{`neno(2.5)`, "2.5"},
+		{`neno("a")`, "a"},
+		{`neno([1, "a"])`, "[1, a]"},
+		{`neno(kweli)`, "kweli"},
+		{`boolean("kweli")`, true},
+		{`boolean("sikweli")`, false},
+		{`boolean(0)`, false},
+		{`boolean(0.5)`, true},
+		{`boolean(tupu)`, false},
+		{`boolean(kweli)`, true},
+		{`boolean("ndio")`, `Samahani, "ndio" haiwezi kugeuzwa kuwa BOOLEAN`},
+		{`boolean({})`, "Samahani, KAMUSI haiwezi kugeuzwa kuwa BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSoma(t *testing.T) {
+	var out bytes.Buffer
+	Stdout, Stdin = &out, strings.NewReader("moja\n\r\ntatu")
+	defer func() { Stdout, Stdin = os.Stdout, os.Stdin }()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`soma("> ")`, "moja"},
+		{`soma()`, ""},
+		{`soma()`, "tatu"},
+		{`soma()`, nil},
+		{`jaza()`, ""},
+		{`soma(1)`, "Samahani, swali la soma linahitaji kuwa NENO, sio NAMBA"},
+		{`soma("a", "b")`, "Samahani, hii function inapokea hoja 0 au 1, wewe umeweka 2"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+
+	if out.String() != "> " {
+		t.Errorf("wrong output. want=%q, got=%q", "> ", out.String())
+	}
+}
+
+func TestArgs(t *testing.T) {
+	testValue(t, "idadi(HOJA)", testEval("idadi(HOJA)"), 0)
+
+	SetArgs("programu.nr", []string{"-n", "5"})
+	defer func() { globals["HOJA"] = &object.Array{} }()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`HOJA[0]`, "programu.nr"},
+		{`HOJA[2]`, "5"},
+		{`idadi(HOJA)`, 3},
+		{`fanya f = unda() { rudisha HOJA[1] }; f()`, "-n"},
+		{`fanya HOJA = [1]; idadi(HOJA)`, 1},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestEnvironmentVariables(t *testing.T) {
+	t.Setenv("NURU_JINA", "Asha")
+	t.Setenv("NURU_TUPU", "")
+	os.Unsetenv("NURU_HAIPO")
+	t.Setenv("NURU_MPYA", "")
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`mazingira.pata("NURU_JINA")`, "Asha"},
+		{`mazingira.pata("NURU_TUPU")`, ""},
+		{`mazingira.pata("NURU_HAIPO")`, nil},
+		{`mazingira.weka("NURU_MPYA", "ndio"); mazingira.pata("NURU_MPYA")`, "ndio"},
+		{`mazingira.pata(1)`, "Samahani, mazingira.pata inahitaji NENO, sio NAMBA"},
+		{`mazingira.weka("NURU_MPYA", 1)`, "Samahani, mazingira.weka inahitaji NENO, sio NAMBA"},
+		{`mazingira.weka("NURU_MPYA")`, "Hoja hazilingani, tunahitaji=2, tumepewa=1"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh haipo")
+	}
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`endesha("sh", "-c", "echo habari")["stdout"]`, "habari\n"},
+		{`endesha("sh", "-c", "echo kosa >&2; exit 3")["stderr"]`, "kosa\n"},
+		{`endesha("sh", "-c", "exit 3")["code"]`, 3},
+		{`endesha("sh", "-c", "exit 0")["code"]`, 0},
+		{`fanya p = endesha_mkondo("sh", "-c", "echo a; echo b")
+		  fanya s = ""
+		  kwa mstari ktk p { s += mstari }
+		  s + neno(p.subiri())`, "ab0"},
+		{`fanya p = endesha_mkondo("sh", "-c", "echo a; echo b; exit 2"); p.subiri()`, 2},
+		{`fanya p = endesha_mkondo("sh", "-c", "exit 4"); p.subiri(); p.subiri()`, 4},
+		{`fanya p = endesha_mkondo("sleep", "10"); p.simamisha(); p.subiri()`, -1},
+		{`endesha()`, "Samahani, endesha inahitaji amri ya kuendesha"},
+		{`endesha("echo", 1)`, "Samahani, endesha inahitaji NENO, sio NAMBA"},
+		{`endesha_mkondo("sh", "-c", "exit 0").ngoja`, "MCHAKATO haina ngoja"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+
+	errObj, ok := testEval(`endesha("hakuna_amri_hii")`).(*object.Error)
+	if !ok || !strings.Contains(errObj.Message, `Nimeshindwa kuendesha "hakuna_amri_hii"`) {
+		t.Errorf("expected an error for a missing command. got=%v", errObj)
+	}
+}
+
+func TestFilesystem(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("habari"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{fmt.Sprintf(`os.hamia(%q); os.saraka() == %q`, dir, dir), true},
+		{`os.ipo("a.txt")`, true},
+		{`os.ipo("b.txt")`, false},
+		{`os.taarifa("a.txt")["ukubwa"]`, 6},
+		{`os.taarifa("a.txt")["ni_saraka"]`, false},
+		{`aina(os.taarifa("a.txt")["imebadilishwa"])`, "MUDA"},
+		{`os.tengeneza_saraka("x/y"); os.taarifa("x")["ni_saraka"]`, true},
+		{`os.tengeneza_saraka("x/y")`, nil},
+		{`os.badilisha_jina("a.txt", "x/b.txt"); os.ipo("a.txt")`, false},
+		{`neno(os.orodhesha())`, "[x]"},
+		{`neno(os.orodhesha("x"))`, "[b.txt, y]"},
+		{`os.futa("x")`, `Samahani, "x" ni saraka, tumia os.futa_saraka`},
+		{`os.futa("x/b.txt"); os.ipo("x/b.txt")`, false},
+		{`os.futa_saraka("x"); os.ipo("x")`, false},
+		{`os.futa_saraka("x")`, `Samahani, "x" sio saraka`},
+		{`os.taarifa("x")`, `Nimeshindwa kupata taarifa za "x"`},
+		{`os.orodhesha("x")`, `Nimeshindwa kusoma saraka "x"`},
+		{`os.ipo(1)`, "Samahani, os.ipo inahitaji NENO, sio NAMBA"},
+		{`os.badilisha_jina("a")`, "Hoja hazilingani, tunahitaji=2, tumepewa=1"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+	files := map[string]string{
+		"watu.csv":  "jina,umri\nAsha,30\n\"Juma, Jr\",25\n",
+		"nusu.csv":  "a;b\n1;2\n",
+		"mbaya.csv": "a,b\n1,2,3\n",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(path(name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{fmt.Sprintf(`neno(csv.soma(%q))`, path("watu.csv")), "[[jina, umri], [Asha, 30], [Juma, Jr, 25]]"},
+		{fmt.Sprintf(`csv.soma(%q)[2][0]`, path("watu.csv")), "Juma, Jr"},
+		{fmt.Sprintf(`csv.soma(%q, {"kichwa": kweli})[1]["umri"]`, path("watu.csv")), "25"},
+		{fmt.Sprintf(`csv.soma(%q, {"kitenganishi": ";"})[1][1]`, path("nusu.csv")), "2"},
+		{fmt.Sprintf(`csv.andika(%q, [["a", "b, c"], [1, tupu]]); soma_faili(%q)`, path("1.csv"), path("1.csv")), "a,\"b, c\"\n1,\n"},
+		{fmt.Sprintf(`csv.andika(%q, [{"jina": "Asha", "umri": 30}, {"jina": "Juma"}]); soma_faili(%q)`, path("2.csv"), path("2.csv")), "jina,umri\nAsha,30\nJuma,\n"},
+		{fmt.Sprintf(`csv.andika(%q, [{"a": 1, "b": 2}], {"vichwa": ["b", "a"], "kitenganishi": ";"}); soma_faili(%q)`, path("3.csv"), path("3.csv")), "b;a\n2;1\n"},
+		{fmt.Sprintf(`csv.andika(%q, [["x", "y"]]); csv.soma(%q)[0][1]`, path("4.csv"), path("4.csv")), "y"},
+		{fmt.Sprintf(`csv.soma(%q, {"kichwa": kweli})`, path("mbaya.csv")), fmt.Sprintf("Samahani, %q si CSV sahihi, kosa liko mstari 2", path("mbaya.csv"))},
+		{fmt.Sprintf(`csv.soma(%q)`, path("hakuna.csv")), fmt.Sprintf("Nimeshindwa kufungua faili %q", path("hakuna.csv"))},
+		{`csv.soma("a.csv", {"kitenganishi": ";;"})`, "Samahani, kitenganishi kinahitaji kuwa herufi moja"},
+		{`csv.soma("a.csv", {"vichwa": []})`, `Samahani, csv.soma haina chaguo "vichwa"`},
+		{`csv.andika("a.csv", [[1], {}])`, "Samahani, safu za csv zinahitaji kuwa ORODHA zote au KAMUSI zote, sio KAMUSI"},
+		{`csv.andika("a.csv", "a")`, "Samahani, csv.andika inahitaji ORODHA ya safu, sio NENO"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`base64.fungua("Habari")`, "SGFiYXJp"},
+		{`base64.fungua(baiti([0, 255]))`, "AP8="},
+		{`base64.tengua("SGFiYXJp").neno()`, "Habari"},
+		{`base64.tengua("AP8=") == baiti([0, 255])`, true},
+		{`base64.fungua_url(baiti([251, 255]))`, "-_8="},
+		{`base64.tengua_url("-_8=")[0]`, 251},
+		{`base64.tengua("SGF!")`, "Base64 si sahihi karibu na herufi 3"},
+		{`base64.fungua(1)`, "Samahani, base64.fungua inahitaji NENO au BAITI, sio NAMBA"},
+		{`base64.tengua(baiti("a"))`, "Samahani, base64.tengua inahitaji NENO, sio BAITI"},
+		{`hex.fungua("Nuru")`, "4e757275"},
+		{`hex.fungua(baiti([0, 16, 255]))`, "0010ff"},
+		{`hex.tengua("4E757275").neno()`, "Nuru"},
+		{`hex.tengua("4g")`, `Hex si sahihi: 'g' si herufi ya hex`},
+		{`hex.tengua("abc")`, "Hex si sahihi: idadi ya herufi zake ni witiri"},
+		{`hex.fungua()`, "Hoja hazilingani, tunahitaji=1, tumepewa=0"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`hash.md5("")`, "d41d8cd98f00b204e9800998ecf8427e"},
+		{`hash.sha1("abc")`, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{`hash.sha256("abc")`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{`hash.sha256(baiti("abc")) == hash.sha256("abc")`, true},
+		{`idadi(hash.sha512("abc"))`, 128},
+		{`hash.hmac("key", "The quick brown fox jumps over the lazy dog")`, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"},
+		{`hash.hmac("key", "The quick brown fox jumps over the lazy dog", "MD5")`, "80070713463e7749b90c2dc24911e275"},
+		{`hash.hmac("key", "data", "sha3")`, `Samahani, hash haina "sha3", tumia md5, sha1, sha256, sha512`},
+		{`hash.sha256(1)`, "Samahani, hash.sha256 inahitaji NENO au BAITI, sio NAMBA"},
+		{`hash.hmac("key")`, "Samahani, hash.hmac inapokea hoja 2 au 3, wewe umeweka 1"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "duka.db")
+	setup := fmt.Sprintf(`fanya db = hifadhidata.fungua(%q)
+		db.tekeleza("CREATE TABLE IF NOT EXISTS bidhaa (id INTEGER PRIMARY KEY, jina TEXT, bei REAL, picha BLOB)")
+	`, path)
+	testEval(setup + `db.tekeleza("INSERT INTO bidhaa (jina, bei) VALUES (?, ?)", "sukari", 2.5)`)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`db.tekeleza("INSERT INTO bidhaa (jina, bei) VALUES (?, ?)", "chumvi", 1)["id_ya_mwisho"]`, 2},
+		{`db.uliza("SELECT jina FROM bidhaa WHERE id = ?", 1)[0]["jina"]`, "sukari"},
+		{`db.uliza("SELECT bei FROM bidhaa WHERE jina = ?", "sukari")[0]["bei"]`, 2.5},
+		{`idadi(db.uliza("SELECT * FROM bidhaa WHERE jina = ?", "x' OR '1'='1"))`, 0},
+		{`db.uliza("SELECT picha FROM bidhaa")[0]["picha"]`, nil},
+		{`db.tekeleza("UPDATE bidhaa SET picha = ? WHERE id = 1", baiti([1, 2])); db.uliza("SELECT picha FROM bidhaa WHERE id = 1")[0]["picha"][1]`, 2},
+		{`db.tekeleza("UPDATE bidhaa SET bei = bei * 2")["zilizobadilika"]`, 2},
+		{`idadi(db.uliza("SELECT * FROM bidhaa WHERE bei > 100"))`, 0},
+		{`db.uliza("SELECT * FROM hakuna")`, "Hifadhidata imeshindwa: SQL logic error: no such table: hakuna (1)"},
+		{`db.tekeleza("INSERT INTO bidhaa (jina) VALUES (?)", [1])`, "Samahani, ORODHA haiwezi kuhifadhiwa kwenye hifadhidata"},
+		{`db.uliza()`, "Samahani, uliza inahitaji SQL"},
+		{`db.funga(); db.uliza("SELECT 1")`, "Hifadhidata imeshindwa: sql: database is closed"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(setup+tt.input), tt.expected)
+	}
+
+	testValue(t, "memory", testEval(`fanya db = hifadhidata.fungua(":memory:")
+		db.tekeleza("CREATE TABLE t (x)"); db.tekeleza("INSERT INTO t VALUES (?)", 7)
+		db.uliza("SELECT x FROM t")[0]["x"]`), 7)
+}
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`url.tengua("https://example.com:8080/njia/ya?q=nuru+lugha&p=2#juu")["scheme"]`, "https"},
+		{`url.tengua("https://example.com:8080/njia/ya?q=nuru")["host"]`, "example.com"},
+		{`url.tengua("https://example.com:8080/njia/ya?q=nuru")["port"]`, 8080},
+		{`url.tengua("https://example.com/njia%20yangu")["path"]`, "/njia yangu"},
+		{`url.tengua("https://example.com")["port"]`, nil},
+		{`url.tengua("https://example.com/?q=nuru+lugha")["query"]["q"]`, "nuru lugha"},
+		{`url.tengua("https://example.com/?a=1&a=2")["query"]["a"][1]`, "2"},
+		{`url.tengua("https://example.com/#juu")["fragment"]`, "juu"},
+		{`url.tengua("%")`, `Samahani, url "%" si sahihi`},
+		{`url.jenga({"scheme": "https", "host": "example.com", "path": "/njia yangu", "query": {"q": "a b", "p": 2}})`, "https://example.com/njia%20yangu?p=2&q=a+b"},
+		{`url.jenga({"scheme": "http", "host": "localhost", "port": 8080, "path": "/"})`, "http://localhost:8080/"},
+		{`url.jenga(url.tengua("https://example.com:81/a?b=c#d"))`, "https://example.com:81/a?b=c#d"},
+		{`url.jenga({"mtumiaji": "a"})`, `Samahani, url haina sehemu "mtumiaji"`},
+		{`url.fungua_hoja({"jina": "Asha Juma", "v": [1, 2]})`, "jina=Asha+Juma&v=1&v=2"},
+		{`url.tengua_hoja("jina=Asha+Juma&x=%26")["x"]`, "&"},
+		{`url.tengua_hoja("a=%zz")`, `Samahani, hoja "a=%zz" si sahihi`},
+		{`url.fungua_hoja("a")`, "Samahani, url.fungua_hoja inahitaji KAMUSI, sio NENO"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestNetwork(t *testing.T) {
+	echo := `fanya seva = mtandao.sikiliza("127.0.0.1", 0)
+		fanya kazi = sambamba unda() {
+			fanya m = seva.kubali()
+			kwa mstari ktk m {
+				m.andika("mwangwi: " + mstari + "\n")
+			}
+			m.funga()
+		}()
+		fanya c = mtandao.unganisha("127.0.0.1", seva.bandari())
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`c.andika("habari\n")`, 7},
+		{`c.andika("habari\n"); c.soma_mstari()`, "mwangwi: habari"},
+		{`c.andika(baiti("a\n")); c.soma(5).neno()`, "mwang"},
+		{`c.andika("a\n"); c.funga(); kazi.subiri(); seva.funga()`, nil},
+		{`c.andika("a\n"); c.soma_mstari(); c.andika("b\n"); c.soma_mstari()`, "mwangwi: b"},
+		{`fanya s2 = mtandao.sikiliza("127.0.0.1", 0)
+		  fanya k = sambamba unda() { s2.kubali().funga() }()
+		  fanya c2 = mtandao.unganisha("127.0.0.1", s2.bandari())
+		  k.subiri()
+		  c2.soma() == tupu && c2.soma_mstari() == tupu`, true},
+		{`c.andika(1)`, "Samahani, andika inahitaji NENO au BAITI, sio NAMBA"},
+		{`c.soma(0)`, "Samahani, soma inahitaji idadi ya baiti iliyo zaidi ya 0, sio 0"},
+		{`mtandao.unganisha("127.0.0.1", 70000)`, "Samahani, mtandao.unganisha inahitaji bandari kati ya 0 na 65535, sio 70000"},
+		{`mtandao.unganisha("127.0.0.1", 1, "sctp")`, `Samahani, mtandao.unganisha inatumia tcp au udp tu, sio "sctp"`},
+		{`seva.ngoja`, "MSIKILIZAJI haina ngoja"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(echo+tt.input), tt.expected)
+	}
+}
+
+func TestWebSocket(t *testing.T) {
+	// the server sends back every message, until it is sent "funga"
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte(r.Header.Get("X-Jina")))
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil || string(data) == "funga" {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			conn.WriteMessage(kind, data)
+		}
+	}))
+	defer server.Close()
+
+	dial := fmt.Sprintf(`fanya ws = mtandao.websocket(%q, {"X-Jina": "Asha"}); `, "ws"+strings.TrimPrefix(server.URL, "http"))
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`ws.pokea()`, "Asha"},
+		{`ws.pokea(); ws.tuma("habari"); ws.pokea()`, "habari"},
+		{`ws.pokea(); ws.tuma(baiti([1, 2])); ws.pokea()[1]`, 2},
+		{`ws.pokea(); ws.tuma("funga"); ws.pokea()`, nil},
+		{`fanya o = []; ws.tuma("a"); ws.tuma("b"); ws.tuma("funga")
+		  kwa ujumbe ktk ws { o = o + [ujumbe] }
+		  neno(o)`, "[Asha, a, b]"},
+		{`fanya o = [""]
+		  fanya a = ws.sikiliza(unda(u) { o[0] = o[0] + u + "," })
+		  ws.tuma("a"); ws.tuma("funga")
+		  a.subiri()
+		  o[0]`, "Asha,a,"},
+		{`fanya a = ws.sikiliza(unda(u) { tupa "kosa" })
+		  a.subiri()`, "kosa"},
+		{`ws.funga(); ws.funga()`, nil},
+		{`ws.tuma(1)`, "Samahani, tuma inahitaji NENO au BAITI, sio NAMBA"},
+		{`ws.sikiliza(1)`, "Samahani, sikiliza inahitaji unda, sio NAMBA"},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(dial+tt.input), tt.expected)
+	}
+
+	errObj, ok := testEval(`mtandao.websocket("ws://127.0.0.1:1")`).(*object.Error)
+	if !ok || !strings.Contains(errObj.Message, `Nimeshindwa kuunganisha na "ws://127.0.0.1:1"`) {
+		t.Errorf("expected an error for a failed connection. got=%v", errObj)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var out bytes.Buffer
+	Stdout = &out
+	logger.now = func() time.Time { return time.Date(2024, 3, 1, 9, 30, 0, 0, time.Local) }
+	defer func() {
+		Stdout = os.Stdout
+		logger.level, logger.path, logger.now = 1, "", time.Now
+	}()
+
+	testEval(`kumbukumbu.tatua("haionekani"); kumbukumbu.taarifa("imeanza", 1, [2]); kumbukumbu.kiwango("onyo"); kumbukumbu.taarifa("haionekani"); kumbukumbu.onyo("angalia"); kumbukumbu.kosa("imeshindwa")`)
+	expected := "2024-03-01 09:30:00 [TAARIFA] imeanza 1 [2]\n2024-03-01 09:30:00 [ONYO] angalia\n2024-03-01 09:30:00 [KOSA] imeshindwa\n"
+	if out.String() != expected {
+		t.Errorf("wrong log. want=%q, got=%q", expected, out.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "kumbukumbu.log")
+	out.Reset()
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{fmt.Sprintf(`kumbukumbu.elekeza(%q); kumbukumbu.kosa("moja"); kumbukumbu.elekeza(fungua_faili(%q)); kumbukumbu.kosa("mbili"); soma_faili(%q)`, path, path, path),
+			"2024-03-01 09:30:00 [KOSA] moja\n2024-03-01 09:30:00 [KOSA] mbili\n"},
+		{`kumbukumbu.kiwango("tatua")`, "tatua"},
+		{`kumbukumbu.kiwango()`, "tatua"},
+		{`kumbukumbu.kiwango("sana")`, `Kiwango "sana" hakijulikani, tumia kimoja kati ya tatua, taarifa, onyo, kosa`},
+		{`kumbukumbu.kiwango(1)`, "Samahani, kumbukumbu.kiwango inahitaji NENO, sio NAMBA"},
+		{`kumbukumbu.elekeza(1)`, "Samahani, kumbukumbu.elekeza inahitaji NENO, FAILI au tupu, sio NAMBA"},
+		{`kumbukumbu.elekeza("/hakuna/faili.log"); kumbukumbu.kosa("x")`, `Nimeshindwa kufungua faili "/hakuna/faili.log"`},
+	}
+
+	for _, tt := range tests {
+		testValue(t, tt.input, testEval(tt.input), tt.expected)
+	}
+	if out.Len() != 0 {
+		t.Errorf("logs sent to a file were written out: %q", out.String())
+	}
+}
@@ -0,0 +1,179 @@
+package evaluator
+
+import (
+	"os"
+	"sort"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+func init() {
+	registerModule("os", map[string]object.BuiltinFunction{
+		"saraka":           currentDir,
+		"hamia":            changeDir,
+		"orodhesha":        listDir,
+		"tengeneza_saraka": makeDir,
+		"futa_saraka":      removeDir,
+		"futa":             removeFile,
+		"badilisha_jina":   renamePath,
+		"ipo":              pathExists,
+		"taarifa":          statPath,
+	})
+}
+
+// pathArgs checks that the os function name was given want paths
+func pathArgs(name string, want int, args []object.Object) ([]string, *object.Error) {
+	if len(args) != want {
+		return nil, newError("Hoja hazilingani, tunahitaji=%d, tumepewa=%d", want, len(args))
+	}
+
+	paths := make([]string, want)
+	for i := range args {
+		path, err := stringArg("os."+name, args, i)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = path
+	}
+	return paths, nil
+}
+
+func currentDir(args ...object.Object) object.Object {
+	if _, err := pathArgs("saraka", 0, args); err != nil {
+		return err
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return newError("Nimeshindwa kupata saraka ya sasa")
+	}
+	return &object.String{Value: dir}
+}
+
+func changeDir(args ...object.Object) object.Object {
+	paths, errObj := pathArgs("hamia", 1, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	if err := os.Chdir(paths[0]); err != nil {
+		return newError("Nimeshindwa kuhamia saraka %q", paths[0])
+	}
+	return NULL
+}
+
+// listDir gives the names of what is in a directory, the current one if none
+// is given, in order
+func listDir(args ...object.Object) object.Object {
+	if len(args) == 0 {
+		args = []object.Object{&object.String{Value: "."}}
+	}
+	paths, errObj := pathArgs("orodhesha", 1, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	entries, err := os.ReadDir(paths[0])
+	if err != nil {
+		return newError("Nimeshindwa kusoma saraka %q", paths[0])
+	}
+
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	sort.Strings(names)
+
+	elements := make([]object.Object, len(names))
+	for i, name := range names {
+		elements[i] = &object.String{Value: name}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// makeDir makes a directory along with any of its parents that are missing.
+// A directory that is already there is left as it is.
+func makeDir(args ...object.Object) object.Object {
+	paths, errObj := pathArgs("tengeneza_saraka", 1, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	if err := os.MkdirAll(paths[0], 0755); err != nil {
+		return newError("Nimeshindwa kutengeneza saraka %q", paths[0])
+	}
+	return NULL
+}
+
+// removeDir removes a directory and everything in it
+func removeDir(args ...object.Object) object.Object {
+	paths, errObj := pathArgs("futa_saraka", 1, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	if info, err := os.Stat(paths[0]); err != nil || !info.IsDir() {
+		return newError("Samahani, %q sio saraka", paths[0])
+	}
+	if err := os.RemoveAll(paths[0]); err != nil {
+		return newError("Nimeshindwa kufuta saraka %q", paths[0])
+	}
+	return NULL
+}
+
+func removeFile(args ...object.Object) object.Object {
+	paths, errObj := pathArgs("futa", 1, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	if info, err := os.Stat(paths[0]); err == nil && info.IsDir() {
+		return newError("Samahani, %q ni saraka, tumia os.futa_saraka", paths[0])
+	}
+	if err := os.Remove(paths[0]); err != nil {
+		return newError("Nimeshindwa kufuta faili %q", paths[0])
+	}
+	return NULL
+}
+
+func renamePath(args ...object.Object) object.Object {
+	paths, errObj := pathArgs("badilisha_jina", 2, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	if err := os.Rename(paths[0], paths[1]); err != nil {
+		return newError("Nimeshindwa kubadilisha jina la %q kuwa %q", paths[0], paths[1])
+	}
+	return NULL
+}
+
+func pathExists(args ...object.Object) object.Object {
+	paths, errObj := pathArgs("ipo", 1, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	_, err := os.Stat(paths[0])
+	return nativeBoolToBooleanObject(err == nil)
+}
+
+// statPath gives the name, size in bytes and time of the last change of a file
+// or directory, and whether it is a directory
+func statPath(args ...object.Object) object.Object {
+	paths, errObj := pathArgs("taarifa", 1, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	info, err := os.Stat(paths[0])
+	if err != nil {
+		return newError("Nimeshindwa kupata taarifa za %q", paths[0])
+	}
+	return newDict(map[string]object.Object{
+		"jina":          &object.String{Value: info.Name()},
+		"ukubwa":        &object.Integer{Value: info.Size()},
+		"imebadilishwa": &object.Time{Value: info.ModTime()},
+		"ni_saraka":     nativeBoolToBooleanObject(info.IsDir()),
+	})
+}
@@ -0,0 +1,68 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// evalFloorDivision is the '\' operator: it divides and rounds the answer
+// down, so that it is always a whole number, even for FLOAT and DESIMALI
+// operands. 7 \ 2 is 3 and -7 \ 2 is -4.
+func evalFloorDivision(left, right object.Object) object.Object {
+	switch {
+	case isInteger(left) && isInteger(right):
+		leftVal := toBigInt(left)
+		rightVal := toBigInt(right)
+		if rightVal.Sign() == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
+		// QuoRem rounds towards zero, which is one too much when the
+		// answer is negative and not whole
+		quo, rem := new(big.Int).QuoRem(leftVal, rightVal, new(big.Int))
+		if rem.Sign() != 0 && rem.Sign() != rightVal.Sign() {
+			quo.Sub(quo, big.NewInt(1))
+		}
+		return normalizeBigInt(quo)
+
+	case left.Type() == object.DECIMAL_OBJ || right.Type() == object.DECIMAL_OBJ:
+		leftVal := toDecimal(left)
+		rightVal := toDecimal(right)
+		if leftVal == nil || rightVal == nil {
+			return newError("Samahani, %s \\ %s haina jibu la namba kamili", left.Inspect(), right.Inspect())
+		}
+		if rightVal.Sign() == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
+		quo := new(big.Rat).Quo(leftVal, rightVal)
+		// the denominator is always positive, so Div rounds down
+		return normalizeBigInt(new(big.Int).Div(quo.Num(), quo.Denom()))
+
+	default:
+		leftVal := numberToFloat(left)
+		rightVal := numberToFloat(right)
+		if rightVal == 0 {
+			return newError("Huwezi kugawanya kwa sifuri")
+		}
+		result := math.Floor(leftVal / rightVal)
+		if math.IsNaN(result) || math.IsInf(result, 0) {
+			return newError("Samahani, %s \\ %s haina jibu la namba kamili", left.Inspect(), right.Inspect())
+		}
+		whole, _ := big.NewFloat(result).Int(nil)
+		return normalizeBigInt(whole)
+	}
+}
+
+// numberToFloat gives an INTEGER, BIGINT or FLOAT as a float64
+func numberToFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.BigInt:
+		return bigToFloat(obj.Value)
+	case *object.Float:
+		return obj.Value
+	}
+	return 0
+}
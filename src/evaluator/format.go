@@ -0,0 +1,132 @@
+package evaluator
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// formatString is the 'panga_neno' builtin. It puts its arguments in place
+// of the verbs of the format, the way printf does:
+//
+//	%s  a string
+//	%d  a whole number
+//	%f  any number, with 6 decimal places unless given like %.2f
+//	%v  anything, as andika shows it
+//	%%  a %
+//
+// A width like %5d or %-10s pads the value with spaces.
+func formatString(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newError("Samahani, panga_neno inahitaji angalau hoja 1")
+	}
+	format, ok := args[0].(*object.String)
+	if !ok {
+		return newError("Samahani, panga_neno inahitaji NENO, sio %s", args[0].Type())
+	}
+	values := args[1:]
+
+	var out strings.Builder
+	used := 0
+	s := format.Value
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			out.WriteByte(s[i])
+			continue
+		}
+
+		// the flags, width and precision come between the % and the verb
+		j := i + 1
+		for j < len(s) && strings.IndexByte("-+ 0", s[j]) >= 0 {
+			j++
+		}
+		for j < len(s) && isDigitByte(s[j]) {
+			j++
+		}
+		width := j
+		precision := -1
+		if j < len(s) && s[j] == '.' {
+			j++
+			start := j
+			for j < len(s) && isDigitByte(s[j]) {
+				j++
+			}
+			precision, _ = strconv.Atoi(s[start:j])
+		}
+		if j == len(s) {
+			return newError("Samahani, %q inaishia na %% bila herufi ya aina", s)
+		}
+
+		verb := s[j]
+		spec := s[i:j]
+		flags := s[i:width]
+		i = j
+		if verb == '%' {
+			out.WriteByte('%')
+			continue
+		}
+		if used == len(values) {
+			return newError("Samahani, %q inahitaji hoja zaidi ya %d ulizoweka", s, len(values))
+		}
+
+		text, err := formatValue(values[used], verb, spec, flags, precision)
+		if err != nil {
+			return err
+		}
+		out.WriteString(text)
+		used++
+	}
+
+	if used < len(values) {
+		return newError("Samahani, %q inatumia hoja %d tu, wewe umeweka %d", s, used, len(values))
+	}
+	return &object.String{Value: out.String()}
+}
+
+// formatValue formats value for one verb of panga_neno. spec is the verb as
+// written without its letter, and flags is spec without the precision.
+func formatValue(value object.Object, verb byte, spec, flags string, precision int) (string, *object.Error) {
+	switch verb {
+	case 's':
+		str, ok := value.(*object.String)
+		if !ok {
+			return "", newError("Samahani, %%s inahitaji NENO, sio %s", value.Type())
+		}
+		return fmt.Sprintf(spec+"s", str.Value), nil
+	case 'v':
+		return fmt.Sprintf(spec+"s", value.Inspect()), nil
+	case 'd':
+		switch value := value.(type) {
+		case *object.Integer:
+			return fmt.Sprintf(spec+"d", value.Value), nil
+		case *object.BigInt:
+			return fmt.Sprintf(spec+"d", value.Value), nil
+		}
+		return "", newError("Samahani, %%d inahitaji NAMBA, sio %s", value.Type())
+	case 'f':
+		switch value := value.(type) {
+		case *object.Integer:
+			return fmt.Sprintf(spec+"f", float64(value.Value)), nil
+		case *object.Float:
+			return fmt.Sprintf(spec+"f", value.Value), nil
+		case *object.BigInt:
+			return fmt.Sprintf(spec+"f", new(big.Float).SetInt(value.Value)), nil
+		case *object.Decimal:
+			// big.Rat can't be given to Sprintf, but it rounds itself exactly
+			if precision < 0 {
+				precision = 6
+			}
+			return fmt.Sprintf(flags+"s", value.Value.FloatString(precision)), nil
+		}
+		return "", newError("Samahani, %%f inahitaji namba, sio %s", value.Type())
+	default:
+		return "", newError("Samahani, %%%c haijulikani, tumia %%s, %%d, %%f au %%v", verb)
+	}
+}
+
+func isDigitByte(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
@@ -0,0 +1,20 @@
+package evaluator
+
+import "github.com/AvicennaJr/Nuru/object"
+
+// globals are values, rather than functions, that every program can read
+// without defining them
+var globals = map[string]object.Object{
+	// HOJA holds the path of the script being run followed by the arguments
+	// given after it on the command line
+	"HOJA": &object.Array{},
+}
+
+// SetArgs makes HOJA hold script followed by args
+func SetArgs(script string, args []string) {
+	elements := []object.Object{&object.String{Value: script}}
+	for _, arg := range args {
+		elements = append(elements, &object.String{Value: arg})
+	}
+	globals["HOJA"] = &object.Array{Elements: elements}
+}
@@ -0,0 +1,76 @@
+package evaluator
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"sort"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// hashes are the algorithms of the hash module, by name
+var hashes = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+func init() {
+	functions := map[string]object.BuiltinFunction{"hmac": hmacHash}
+	for name, newHash := range hashes {
+		name, newHash := name, newHash
+		functions[name] = func(args ...object.Object) object.Object {
+			data, err := encodingInput("hash."+name, args)
+			if err != nil {
+				return err
+			}
+			h := newHash()
+			h.Write(data)
+			return &object.String{Value: hex.EncodeToString(h.Sum(nil))}
+		}
+	}
+	registerModule("hash", functions)
+}
+
+// hmacHash signs data with a key, using sha256 unless another algorithm is
+// named
+func hmacHash(args ...object.Object) object.Object {
+	if len(args) < 2 || len(args) > 3 {
+		return newError("Samahani, hash.hmac inapokea hoja 2 au 3, wewe umeweka %d", len(args))
+	}
+
+	key, errObj := encodingInput("hash.hmac", args[:1])
+	if errObj != nil {
+		return errObj
+	}
+	data, errObj := encodingInput("hash.hmac", args[1:2])
+	if errObj != nil {
+		return errObj
+	}
+	algorithm := "sha256"
+	if len(args) == 3 {
+		if algorithm, errObj = stringArg("hash.hmac", args, 2); errObj != nil {
+			return errObj
+		}
+	}
+	newHash, ok := hashes[strings.ToLower(algorithm)]
+	if !ok {
+		names := make([]string, 0, len(hashes))
+		for name := range hashes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return newError("Samahani, hash haina %q, tumia %s", algorithm, strings.Join(names, ", "))
+	}
+
+	mac := hmac.New(newHash, key)
+	mac.Write(data)
+	return &object.String{Value: hex.EncodeToString(mac.Sum(nil))}
+}
@@ -0,0 +1,125 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// builtinDocs is what 'msaada' shows for each builtin. The first line is how
+// it is called and the rest says what it does.
+var builtinDocs = map[string]string{
+	"aina":             "aina(kitu)\nInarudisha aina ya kitu kama neno, mfano NAMBA au NENO.",
+	"andika":           "andika(vitu...)\nInaandika vitu kwenye skrini, vikitenganishwa na nafasi.",
+	"andika_faili":     "andika_faili(njia, maandishi)\nInaandika neno au baiti kwenye faili, ikifuta kilichokuwemo.",
+	"badilisha_regex":  "badilisha_regex(pattern, neno, badala)\nInabadilisha kila kinacholingana na pattern. $1, $2 ni vikundi vyake.",
+	"baiti":            "baiti(neno au orodha)\nInatengeneza baiti kutoka kwa neno au orodha ya namba 0 hadi 255.",
+	"boolean":          "boolean(kitu)\nInageuza namba, tupu au \"kweli\"/\"sikweli\" kuwa kweli au sikweli.",
+	"chapisha_vizuri":  "chapisha_vizuri(kitu)\nInaandika orodha na kamusi zilizo ndani ya nyingine kwa mistari na nafasi, funguo zikiwa kwa mpangilio.",
+	"desimali":         "desimali(namba au neno)\nInatengeneza desimali kamili, isiyopoteza usahihi.",
+	"endesha":          "endesha(amri, hoja...)\nInaendesha amri, inasubiri imalize na kurudisha kamusi ya stdout, stderr na code.",
+	"endesha_mkondo":   "endesha_mkondo(amri, hoja...)\nInaanzisha amri bila kusubiri. Kitanzi cha kwa juu yake kinapata kila mstari inaoandika.",
+	"fungua_faili":     "fungua_faili(njia)\nInafungua faili ili kitanzi cha kwa kisome mstari mmoja mmoja.",
+	"funguo":           "funguo(kamusi)\nInarudisha funguo za kamusi kama orodha.",
+	"idadi":            "idadi(kitu)\nInarudisha idadi ya vitu vya neno, orodha, seti, jozi au baiti.",
+	"jaza":             "jaza(ujumbe)\nInaonyesha ujumbe na kusoma mstari kutoka kwa mtumiaji.",
+	"jozi":             "jozi(vitu...)\nInatengeneza jozi isiyoweza kubadilishwa kutoka kwa vitu.",
+	"jumla":            "jumla(orodha)\nInarudisha jumla ya namba zilizo kwenye orodha.",
+	"kagua":            "kagua(pattern, neno)\nInarudisha kweli kama pattern inalingana na sehemu yoyote ya neno.",
+	"kikundi":          "kikundi()\nInatengeneza kikundi cha kusubiri kazi za sambamba zimalize.",
+	"kufuli":           "kufuli()\nInatengeneza kufuli ya kulinda kitu kinachotumiwa na kazi za sambamba.",
+	"mfereji":          "mfereji(ukubwa)\nInatengeneza mfereji wa kupitisha vitu kati ya kazi za sambamba.",
+	"mpaka":            "mpaka(mwanzo, mwisho, hatua)\nInatoa namba kuanzia mwanzo hadi kabla ya mwisho. Kwa hoja moja inaanzia 0.",
+	"msaada":           "msaada(unda)\nInaonyesha maelezo ya unda au builtin. Bila hoja inaorodhesha builtins zote.",
+	"nakili":           "nakili(kitu)\nInarudisha nakala ya orodha, kamusi au kitu cha muundo, pamoja na vyote vilivyomo ndani yake.",
+	"namba":            "namba(kitu)\nInageuza neno au boolean kuwa namba au desimali.",
+	"neno":             "neno(kitu)\nInageuza kitu kuwa neno, kama andika inavyokionyesha.",
+	"ni_boolean":       "ni_boolean(kitu)\nInarudisha kweli kama kitu ni kweli au sikweli.",
+	"ni_jozi":          "ni_jozi(kitu)\nInarudisha kweli kama kitu ni jozi.",
+	"ni_kamusi":        "ni_kamusi(kitu)\nInarudisha kweli kama kitu ni kamusi.",
+	"ni_kitu":          "ni_kitu(kitu)\nInarudisha kweli kama kitu kimetengenezwa na muundo.",
+	"ni_namba":         "ni_namba(kitu)\nInarudisha kweli kama kitu ni namba, desimali, namba kubwa au desimali kamili.",
+	"ni_neno":          "ni_neno(kitu)\nInarudisha kweli kama kitu ni neno.",
+	"ni_orodha":        "ni_orodha(kitu)\nInarudisha kweli kama kitu ni orodha.",
+	"ni_seti":          "ni_seti(kitu)\nInarudisha kweli kama kitu ni seti.",
+	"ni_tupu":          "ni_tupu(kitu)\nInarudisha kweli kama kitu ni tupu.",
+	"ni_unda":          "ni_unda(kitu)\nInarudisha kweli kama kitu ni unda au builtin inayoweza kuitwa.",
+	"ongeza_faili":     "ongeza_faili(njia, maandishi)\nInaongeza neno au baiti mwisho wa faili.",
+	"orodha_ya_majina": "orodha_ya_majina(kitu)\nBila hoja inarudisha majina yote yanayoonekana pale ilipoitwa. Ikipewa kamusi, kitu, muundo au moduli inarudisha funguo au majina yaliyomo.",
+	"panga_neno":       "panga_neno(kiolezo, vitu...)\nInaweka vitu mahali pa %s, %d, %f na %v kwenye kiolezo, kama printf.",
+	"panga":            "panga(orodha)\nInarudisha orodha mpya ya namba au maneno yaliyopangwa.",
+	"ramani_sambamba":  "ramani_sambamba(orodha, unda, idadi)\nInaita unda kwa kila kitu cha orodha, kadhaa kwa wakati mmoja.",
+	"regex":            "regex(pattern)\nInakagua pattern mara moja na kurudisha regex ya kutumia badala yake.",
+	"sawa_kabisa":      "sawa_kabisa(a, b)\nInarudisha kweli kama a na b zina thamani sawa za aina ileile, hata ndani ya orodha na kamusi.",
+	"seti":             "seti(vitu)\nInatengeneza seti kutoka kwa orodha, neno, kamusi au mpaka, bila marudio.",
+	"soma":             "soma(ujumbe)\nInasoma mstari mmoja. Inarudisha tupu hakuna cha kusoma tena.",
+	"soma_baiti":       "soma_baiti(njia)\nInasoma faili lote kama baiti.",
+	"soma_faili":       "soma_faili(njia)\nInasoma faili lote kama neno.",
+	"sukuma":           "sukuma(orodha, vitu...)\nInarudisha orodha ikiwa na vitu vimeongezwa mwishoni.",
+	"tafuta_zote":      "tafuta_zote(pattern, neno)\nInarudisha orodha ya kila sehemu ya neno inayolingana na pattern.",
+	"thamani":          "thamani(kamusi)\nInarudisha thamani za kamusi kama orodha.",
+	"thibitisha":       "thibitisha(sharti, ujumbe)\nInaleta kosa linaloonyesha sharti kama si kweli. Ujumbe si lazima.",
+	"thibitisha_sawa":  "thibitisha_sawa(tumepata, tulitarajia, ujumbe)\nInaleta kosa linaloonyesha vyote viwili kama havilingani.",
+	"unganisha":        "unganisha(kamusi...)\nInatengeneza kamusi mpya kutoka kwa kamusi kadhaa. Ya mwisho inashinda.",
+	"vipengele":        "vipengele(kamusi)\nInarudisha kila jozi ya kamusi kama orodha [ufunguo, thamani].",
+	"yamwisho":         "yamwisho(orodha)\nInarudisha kitu cha mwisho cha orodha.",
+}
+
+func init() {
+	builtins["msaada"] = &object.Builtin{Fn: help}
+}
+
+// help is the 'msaada' builtin. It prints the docs of a function, or lists the
+// builtins when it is given nothing.
+func help(args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newError("Samahani, hii function inapokea hoja 0 au 1, wewe umeweka %d", len(args))
+	}
+
+	if len(args) == 0 {
+		for _, name := range BuiltinNames() {
+			usage, _ := builtinDoc(name)
+			fmt.Fprintln(Stdout, usage)
+		}
+		return nil
+	}
+
+	switch fn := args[0].(type) {
+	case *object.Function:
+		printDoc(fn.Signature(), fn.Doc)
+	case *object.BoundMethod:
+		printDoc(fn.Method.Signature(), fn.Method.Doc)
+	case *object.Builtin:
+		for name, builtin := range builtins {
+			if builtin == fn {
+				printDoc(builtinDoc(name))
+				return nil
+			}
+		}
+		printDoc("builtin function", "")
+	default:
+		return newError("msaada inahitaji unda, sio %s", args[0].Type())
+	}
+	return nil
+}
+
+// builtinDoc splits the docs of the builtin name into how it is called and
+// what it does. Builtins added by plugins have only their name.
+func builtinDoc(name string) (usage, doc string) {
+	usage, doc, _ = strings.Cut(builtinDocs[name], "\n")
+	if usage == "" {
+		usage = name + "()"
+	}
+	return usage, doc
+}
+
+func printDoc(usage, doc string) {
+	fmt.Fprintln(Stdout, usage)
+	if doc == "" {
+		doc = "Hakuna maelezo."
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintln(Stdout, "    "+strings.TrimSpace(line))
+	}
+}
@@ -0,0 +1,117 @@
+package evaluator
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+func init() {
+	registerModule("ombi", map[string]object.BuiltinFunction{
+		"pata": func(args ...object.Object) object.Object {
+			return httpRequest("pata", http.MethodGet, false, args)
+		},
+		"tuma": func(args ...object.Object) object.Object {
+			return httpRequest("tuma", http.MethodPost, true, args)
+		},
+		"weka": func(args ...object.Object) object.Object {
+			return httpRequest("weka", http.MethodPut, true, args)
+		},
+		"futa": func(args ...object.Object) object.Object {
+			return httpRequest("futa", http.MethodDelete, false, args)
+		},
+	})
+}
+
+// httpRequest backs the ombi functions. Their arguments are the url, then
+// the body if the method takes one, then an optional dict of headers.
+func httpRequest(name, method string, hasBody bool, args []object.Object) object.Object {
+	max := 2
+	if hasBody {
+		max = 3
+	}
+	if len(args) < 1 || len(args) > max {
+		return newError("Samahani, ombi.%s inapokea hoja 1 hadi %d, wewe umeweka %d", name, max, len(args))
+	}
+
+	url, ok := args[0].(*object.String)
+	if !ok {
+		return newError("Samahani, url inahitaji kuwa NENO, sio %s", args[0].Type())
+	}
+	args = args[1:]
+
+	var body io.Reader
+	isJSON := false
+	if hasBody && len(args) > 0 {
+		switch data := args[0].(type) {
+		case *object.String:
+			body = bytes.NewBufferString(data.Value)
+		case *object.Bytes:
+			body = bytes.NewReader(data.Value)
+		case *object.Dict, *object.Array:
+			var out bytes.Buffer
+			if err := writeJSON(&out, data); err != nil {
+				return err
+			}
+			body = &out
+			isJSON = true
+		default:
+			return newError("Samahani, ombi.%s inatuma NENO, BAITI, KAMUSI au ORODHA tu, sio %s", name, data.Type())
+		}
+		args = args[1:]
+	}
+
+	req, err := http.NewRequest(method, url.Value, body)
+	if err != nil {
+		return newError("Samahani, url %q si sahihi", url.Value)
+	}
+	if isJSON {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if len(args) > 0 {
+		headers, ok := args[0].(*object.Dict)
+		if !ok {
+			return newError("Samahani, headers zinahitaji kuwa KAMUSI, sio %s", args[0].Type())
+		}
+		for _, pair := range headers.Pairs {
+			req.Header.Set(plainString(pair.Key), plainString(pair.Value))
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return newError("Ombi kwa %q limeshindikana: %s", url.Value, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return newError("Nimeshindwa kusoma jibu kutoka %q", url.Value)
+	}
+
+	respHeaders := make(map[string]object.Object)
+	for k := range resp.Header {
+		respHeaders[k] = &object.String{Value: resp.Header.Get(k)}
+	}
+
+	return newDict(map[string]object.Object{
+		"status":  &object.Integer{Value: int64(resp.StatusCode)},
+		"headers": newDict(respHeaders),
+		"body":    &object.String{Value: string(respBody)},
+		"baiti":   &object.Bytes{Value: respBody},
+	})
+}
+
+// plainString is the value of a String, or how any other object prints
+func plainString(obj object.Object) string {
+	if str, ok := obj.(*object.String); ok {
+		return str.Value
+	}
+	return obj.Inspect()
+}
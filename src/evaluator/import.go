@@ -0,0 +1,218 @@
+package evaluator
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/packages"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+// ModulePaths are the directories searched when a module is imported with
+// 'tumia'. The directory of the module doing the import is always searched first.
+var ModulePaths = []string{"."}
+
+// ModuleFS, when set, holds the modules instead of the disk, as it does for a
+// program built with 'nuru jenga'. Paths in it are relative to the folder of
+// the program.
+var ModuleFS fs.FS
+
+var (
+	importMu    sync.Mutex // guards moduleCache and loading
+	moduleCache = make(map[string]*object.Module)
+	// loading holds the modules being loaded, by the environment their code
+	// runs in. Functions started with 'sambamba' may import at the same
+	// time, so who is importing is found from the environment rather than
+	// kept in one list.
+	loading = make(map[*object.Environment]*loadingModule)
+)
+
+// loadingModule is a file being loaded, and the one that imported it
+type loadingModule struct {
+	path     string
+	importer *loadingModule
+}
+
+// stdModules are the modules that come with Nuru, like json. They can be
+// used without 'tumia', but importing them by name works too.
+var stdModules = make(map[string]*object.Module)
+
+func registerModule(name string, fns map[string]object.BuiltinFunction) {
+	env := object.NewEnvironment()
+	for fnName, fn := range fns {
+		env.Set(fnName, &object.Builtin{Fn: fn})
+	}
+	stdModules[name] = &object.Module{Name: name, Env: env}
+}
+
+func evalImportStatement(node *ast.ImportStatement, env *object.Environment) object.Object {
+	if mod, ok := stdModules[node.Path]; ok {
+		env.Set(node.Name.Value, mod)
+		return nil
+	}
+
+	importer := importingModule(env)
+	path, ok := findModule(node.Path, importer)
+	if !ok {
+		return newError("Moduli %q haipatikani", node.Path)
+	}
+
+	importMu.Lock()
+	mod, ok := moduleCache[path]
+	importMu.Unlock()
+	if ok {
+		env.Set(node.Name.Value, mod)
+		return nil
+	}
+
+	for m := importer; m != nil; m = m.importer {
+		if m.path == path {
+			return newError("Moduli %q inajiita yenyewe (mzunguko wa 'tumia')", node.Path)
+		}
+	}
+
+	// two functions running at the same time may both load a module that
+	// isn't cached yet, in which case the one loaded last is kept
+	mod, err := loadModule(node.Name.Value, path, importer)
+	if err != nil {
+		return err
+	}
+
+	importMu.Lock()
+	moduleCache[path] = mod
+	importMu.Unlock()
+	env.Set(node.Name.Value, mod)
+
+	return nil
+}
+
+// importingModule returns the module whose code env belongs to, if it is
+// still being loaded
+func importingModule(env *object.Environment) *loadingModule {
+	for env.Outer() != nil {
+		env = env.Outer()
+	}
+
+	importMu.Lock()
+	defer importMu.Unlock()
+	return loading[env]
+}
+
+func loadModule(name, path string, importer *loadingModule) (*object.Module, *object.Error) {
+	var contents []byte
+	var err error
+	if ModuleFS != nil {
+		contents, err = fs.ReadFile(ModuleFS, path)
+	} else {
+		contents, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, newError("Nimeshindwa kusoma moduli %q", path)
+	}
+
+	l := lexer.NewFile(path, string(contents))
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		return nil, newError("Moduli %q ina makosa:\n\t%s", path, strings.Join(p.Errors(), "\n\t"))
+	}
+
+	env := object.NewEnvironment()
+
+	importMu.Lock()
+	loading[env] = &loadingModule{path: path, importer: importer}
+	importMu.Unlock()
+
+	evaluated := Eval(program, env)
+
+	importMu.Lock()
+	delete(loading, env)
+	importMu.Unlock()
+
+	if err, ok := evaluated.(*object.Error); ok {
+		return nil, err
+	}
+
+	return &object.Module{Name: name, Env: env}, nil
+}
+
+// findModule resolves an import path to the absolute path of a file. The
+// folder of importer, if it is a module, is searched first.
+func findModule(name string, importer *loadingModule) (string, bool) {
+	if filepath.Ext(name) == "" {
+		name += ".nr"
+	}
+
+	if ModuleFS != nil {
+		return findEmbeddedModule(filepath.ToSlash(name), importer)
+	}
+
+	if filepath.IsAbs(name) {
+		return name, fileExists(name)
+	}
+
+	dirs := []string{}
+	if importer != nil {
+		dirs = append(dirs, filepath.Dir(importer.path))
+	}
+	dirs = append(dirs, ModulePaths...)
+
+	for _, dir := range dirs {
+		candidates := []string{
+			filepath.Join(dir, name),
+			// packages fetched with 'nuru pakua', where 'tumia hesabu'
+			// loads vifurushi/hesabu/hesabu.nr
+			filepath.Join(dir, packages.Dir, name),
+			filepath.Join(dir, packages.Dir, strings.TrimSuffix(name, ".nr"), filepath.Base(name)),
+		}
+		for _, candidate := range candidates {
+			path, err := filepath.Abs(candidate)
+			if err != nil {
+				continue
+			}
+			if fileExists(path) {
+				return path, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// findEmbeddedModule is findModule for modules held in ModuleFS, which are
+// looked up next to the module doing the import and then from the top
+func findEmbeddedModule(name string, importer *loadingModule) (string, bool) {
+	dirs := []string{}
+	if importer != nil {
+		dirs = append(dirs, path.Dir(importer.path))
+	}
+	dirs = append(dirs, ".")
+
+	for _, dir := range dirs {
+		candidates := []string{
+			path.Join(dir, name),
+			path.Join(dir, packages.Dir, name),
+			path.Join(dir, packages.Dir, strings.TrimSuffix(name, ".nr"), path.Base(name)),
+		}
+		for _, candidate := range candidates {
+			if info, err := fs.Stat(ModuleFS, candidate); err == nil && !info.IsDir() {
+				return candidate, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,103 @@
+package evaluator
+
+import (
+	"sort"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// typeChecks are the 'ni_' builtins, each with the types it is true for
+var typeChecks = map[string][]object.ObjectType{
+	"ni_namba":   {object.INTEGER_OBJ, object.FLOAT_OBJ, object.BIGINT_OBJ, object.DECIMAL_OBJ},
+	"ni_neno":    {object.STRING_OBJ},
+	"ni_boolean": {object.BOOLEAN_OBJ},
+	"ni_tupu":    {object.NULL_OBJ},
+	"ni_orodha":  {object.ARRAY_OBJ},
+	"ni_kamusi":  {object.DICT_OBJ},
+	"ni_jozi":    {object.TUPLE_OBJ},
+	"ni_seti":    {object.SET_OBJ},
+	"ni_unda":    {object.FUNCTION_OBJ, object.BUILTIN_OBJ, object.COMPILED_FUNCTION_OBJ},
+	"ni_kitu":    {object.INSTANCE_OBJ},
+}
+
+func init() {
+	builtins["orodha_ya_majina"] = &object.Builtin{Fn: listNames}
+	for name, types := range typeChecks {
+		builtins[name] = &object.Builtin{Fn: isType(types)}
+	}
+}
+
+// isType makes the builtin that tells whether its argument is one of types
+func isType(types []object.ObjectType) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+		}
+		for _, t := range types {
+			if args[0].Type() == t {
+				return TRUE
+			}
+		}
+		return FALSE
+	}
+}
+
+// envNames is orodha_ya_majina() called without an argument, which the
+// evaluator answers with the names that can be used where it was called
+func envNames(env *object.Environment) object.Object {
+	return nameList(env.Names())
+}
+
+// listNames is the 'orodha_ya_majina' builtin given something to look into:
+// the keys of a dict, the fields and methods of an instance or class, or
+// what a module has
+func listNames(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Samahani, orodha_ya_majina inahitaji kuitwa moja kwa moja bila hoja, au na hoja 1")
+	}
+
+	var names []string
+	switch obj := args[0].(type) {
+	case *object.Dict:
+		pairs := sortedPairs(obj)
+		keys := make([]object.Object, len(pairs))
+		for i, pair := range pairs {
+			keys[i] = pair.Key
+		}
+		return &object.Array{Elements: keys}
+	case *object.Instance:
+		for name := range obj.Fields {
+			names = append(names, name)
+		}
+		for name := range obj.Class.Methods {
+			names = append(names, name)
+		}
+	case *object.Class:
+		for _, field := range obj.Fields {
+			names = append(names, field.Name.Value)
+		}
+		for name := range obj.Methods {
+			names = append(names, name)
+		}
+	case *object.Module:
+		for name := range obj.Env.Locals() {
+			names = append(names, name)
+		}
+	default:
+		return newError("Samahani, orodha_ya_majina haitumiki na %s", args[0].Type())
+	}
+	return nameList(names)
+}
+
+// nameList gives names as a sorted array of strings, each of them once
+func nameList(names []string) *object.Array {
+	sort.Strings(names)
+	elements := []object.Object{}
+	for i, name := range names {
+		if i > 0 && name == names[i-1] {
+			continue
+		}
+		elements = append(elements, &object.String{Value: name})
+	}
+	return &object.Array{Elements: elements}
+}
@@ -0,0 +1,175 @@
+package evaluator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+func init() {
+	registerModule("json", map[string]object.BuiltinFunction{
+		"tengua": jsonDecode,
+		"fungua": jsonEncode,
+	})
+}
+
+// jsonDecode turns a JSON string into Nuru objects
+func jsonDecode(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("Samahani, json.tengua inahitaji NENO, sio %s", args[0].Type())
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(str.Value)))
+	dec.UseNumber()
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return jsonError(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return newError("JSON si sahihi: kuna vitu zaidi baada ya thamani ya kwanza")
+	}
+
+	return fromJSON(value)
+}
+
+func jsonError(err error) *object.Error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return newError("JSON si sahihi karibu na herufi %d", syntaxErr.Offset)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return newError("JSON si sahihi: imeisha ghafla")
+	}
+	return newError("JSON si sahihi")
+}
+
+func fromJSON(value interface{}) object.Object {
+	switch value := value.(type) {
+	case nil:
+		return NULL
+	case bool:
+		return nativeBoolToBooleanObject(value)
+	case string:
+		return &object.String{Value: value}
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return &object.Integer{Value: i}
+		}
+		if i, ok := new(big.Int).SetString(value.String(), 10); ok {
+			return &object.BigInt{Value: i}
+		}
+		f, _ := value.Float64()
+		return &object.Float{Value: f}
+	case []interface{}:
+		elements := make([]object.Object, len(value))
+		for i, el := range value {
+			elements[i] = fromJSON(el)
+		}
+		return &object.Array{Elements: elements}
+	case map[string]interface{}:
+		dict := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+		for k, v := range value {
+			key := &object.String{Value: k}
+			dict.Pairs[key.HashKey()] = object.DictPair{Key: key, Value: fromJSON(v)}
+		}
+		return dict
+	}
+	return NULL
+}
+
+// jsonEncode turns Nuru objects into a JSON string
+func jsonEncode(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	var out bytes.Buffer
+	if err := writeJSON(&out, args[0]); err != nil {
+		return err
+	}
+
+	return &object.String{Value: out.String()}
+}
+
+func writeJSON(out *bytes.Buffer, obj object.Object) *object.Error {
+	switch obj := obj.(type) {
+	case *object.Null:
+		out.WriteString("null")
+	case *object.Boolean:
+		if obj.Value {
+			out.WriteString("true")
+		} else {
+			out.WriteString("false")
+		}
+	case *object.Integer, *object.BigInt, *object.Decimal:
+		out.WriteString(obj.Inspect())
+	case *object.Float:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return newError("Samahani, %s haiwezi kubadilishwa kuwa JSON", obj.Inspect())
+		}
+		out.WriteString(obj.Inspect())
+	case *object.String:
+		encoded, _ := json.Marshal(obj.Value)
+		out.Write(encoded)
+	case *object.Array:
+		return writeJSONArray(out, obj.Elements)
+	case *object.Tuple:
+		return writeJSONArray(out, obj.Elements)
+	case *object.Dict:
+		// keys are sorted so the same dict always gives the same JSON
+		pairs := make(map[string]object.Object)
+		keys := []string{}
+		for _, pair := range obj.Pairs {
+			key := pair.Key.Inspect()
+			if str, ok := pair.Key.(*object.String); ok {
+				key = str.Value
+			}
+			pairs[key] = pair.Value
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		out.WriteString("{")
+		for i, key := range keys {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			encoded, _ := json.Marshal(key)
+			out.Write(encoded)
+			out.WriteString(":")
+			if err := writeJSON(out, pairs[key]); err != nil {
+				return err
+			}
+		}
+		out.WriteString("}")
+	default:
+		return newError("Samahani, %s haiwezi kubadilishwa kuwa JSON", obj.Type())
+	}
+	return nil
+}
+
+func writeJSONArray(out *bytes.Buffer, elements []object.Object) *object.Error {
+	out.WriteString("[")
+	for i, el := range elements {
+		if i > 0 {
+			out.WriteString(",")
+		}
+		if err := writeJSON(out, el); err != nil {
+			return err
+		}
+	}
+	out.WriteString("]")
+	return nil
+}
@@ -0,0 +1,119 @@
+package evaluator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// logLevels are the levels of kumbukumbu, from the least to the most serious
+var logLevels = []string{"tatua", "taarifa", "onyo", "kosa"}
+
+// logger is where kumbukumbu writes and what it leaves out. Functions started
+// with 'sambamba' log too, so it is locked.
+var logger = struct {
+	sync.Mutex
+	level int    // messages below this level are left out
+	path  string // the file to add messages to, or Stdout when empty
+	now   func() time.Time
+}{level: 1, now: time.Now}
+
+func init() {
+	functions := map[string]object.BuiltinFunction{
+		"kiwango": logLevel,
+		"elekeza": logOutput,
+	}
+	for level, name := range logLevels {
+		functions[name] = logAt(level)
+	}
+	registerModule("kumbukumbu", functions)
+}
+
+// logAt makes the function that logs at level, writing its arguments the way
+// andika does after the time and the level
+func logAt(level int) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		logger.Lock()
+		defer logger.Unlock()
+		if level < logger.level {
+			return nil
+		}
+
+		parts := make([]string, len(args))
+		for i, arg := range args {
+			parts[i] = arg.Inspect()
+		}
+		line := fmt.Sprintf("%s [%s] %s\n", logger.now().Format(object.TimeFormat), strings.ToUpper(logLevels[level]), strings.Join(parts, " "))
+
+		if logger.path == "" {
+			fmt.Fprint(Stdout, line)
+			return nil
+		}
+		file, err := os.OpenFile(logger.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return newError("Nimeshindwa kufungua faili %q", logger.path)
+		}
+		defer file.Close()
+		if _, err := file.WriteString(line); err != nil {
+			return newError("Nimeshindwa kuandika faili %q", logger.path)
+		}
+		return nil
+	}
+}
+
+// logLevel is kumbukumbu.kiwango. Given a level it leaves out the messages
+// below it, and it always gives back the level in use.
+func logLevel(args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newError("Samahani, kumbukumbu.kiwango inapokea hoja 0 au 1, wewe umeweka %d", len(args))
+	}
+
+	logger.Lock()
+	defer logger.Unlock()
+	if len(args) == 1 {
+		name, ok := args[0].(*object.String)
+		if !ok {
+			return newError("Samahani, kumbukumbu.kiwango inahitaji NENO, sio %s", args[0].Type())
+		}
+		level := -1
+		for i, l := range logLevels {
+			if l == name.Value {
+				level = i
+			}
+		}
+		if level < 0 {
+			return newError("Kiwango %q hakijulikani, tumia kimoja kati ya %s", name.Value, strings.Join(logLevels, ", "))
+		}
+		logger.level = level
+	}
+	return &object.String{Value: logLevels[logger.level]}
+}
+
+// logOutput is kumbukumbu.elekeza. It sends the messages to the end of a file,
+// given by its name or as opened with fungua_faili, or back to the screen when
+// given tupu.
+func logOutput(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	var path string
+	switch arg := args[0].(type) {
+	case *object.String:
+		path = arg.Value
+	case *object.File:
+		path = arg.Path
+	case *object.Null:
+	default:
+		return newError("Samahani, kumbukumbu.elekeza inahitaji NENO, FAILI au tupu, sio %s", args[0].Type())
+	}
+
+	logger.Lock()
+	logger.path = path
+	logger.Unlock()
+	return nil
+}
@@ -0,0 +1,123 @@
+package evaluator
+
+import (
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// evalMatchExpression runs the first arm whose pattern fits the value. The
+// names a pattern takes are set in an environment of the arm's own, so they
+// are gone once it is over, but the arm can still change the variables
+// around it. When no arm fits the result is tupu.
+func evalMatchExpression(node *ast.MatchExpression, env *object.Environment) object.Object {
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	for _, arm := range node.Arms {
+		taken := object.NewEnvironment()
+		fits, err := match(arm.Pattern, val, taken)
+		if err != nil {
+			return err
+		}
+		if !fits {
+			continue
+		}
+
+		scope := object.NewBlockEnvironment(env)
+		for name, val := range taken.Locals() {
+			if err, ok := scope.Define(name, val).(*object.Error); ok {
+				return err
+			}
+		}
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, scope)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+		return evalBlockStatement(arm.Body, scope)
+	}
+	return NULL
+}
+
+// match reports whether val fits pattern, setting the names the pattern
+// takes in env as it goes
+func match(pattern ast.Expression, val object.Object, env *object.Environment) (bool, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != "_" {
+			if err, ok := env.Set(pattern.Value, val).(*object.Error); ok {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case *ast.ArrayLiteral:
+		var elements []object.Object
+		switch val := val.(type) {
+		case *object.Array:
+			elements = val.Elements
+		case *object.Tuple:
+			elements = val.Elements
+		default:
+			return false, nil
+		}
+
+		patterns := pattern.Elements
+		var rest *ast.SpreadExpression
+		if len(patterns) > 0 {
+			rest, _ = patterns[len(patterns)-1].(*ast.SpreadExpression)
+		}
+		if rest != nil {
+			patterns = patterns[:len(patterns)-1]
+			if len(elements) < len(patterns) {
+				return false, nil
+			}
+		} else if len(elements) != len(patterns) {
+			return false, nil
+		}
+
+		for i, p := range patterns {
+			if fits, err := match(p, elements[i], env); !fits || err != nil {
+				return false, err
+			}
+		}
+		if rest != nil {
+			left := append([]object.Object{}, elements[len(patterns):]...)
+			return match(rest.Value, &object.Array{Elements: left}, env)
+		}
+		return true, nil
+
+	case *ast.DictLiteral:
+		dict, ok := val.(*object.Dict)
+		if !ok {
+			return false, nil
+		}
+		for _, key := range pattern.Keys {
+			hashKey, ok := Eval(key, env).(object.Hashable)
+			if !ok {
+				return false, newError("Hashing imeshindikana: %s", key.String())
+			}
+			pair, ok := dict.Pairs[hashKey.HashKey()]
+			if !ok {
+				return false, nil
+			}
+			if fits, err := match(pattern.Pairs[key], pair.Value, env); !fits || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	// anything else is a literal, which fits a value == to it
+	literal := Eval(pattern, env)
+	if err, ok := literal.(*object.Error); ok {
+		return false, err
+	}
+	return evalInfixExpression("==", literal, val) == TRUE, nil
+}
@@ -0,0 +1,127 @@
+package evaluator
+
+import (
+	"math"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+func init() {
+	registerModule("hisabati", map[string]object.BuiltinFunction{
+		"sin":   mathFunc("sin", math.Sin),
+		"cos":   mathFunc("cos", math.Cos),
+		"tan":   mathFunc("tan", math.Tan),
+		"exp":   mathFunc("exp", math.Exp),
+		"sqrt":  mathSqrt,
+		"log":   mathLog,
+		"pow":   mathPow,
+		"floor": mathRound("floor", math.Floor),
+		"ceil":  mathRound("ceil", math.Ceil),
+	})
+	hisabati := stdModules["hisabati"].Env
+	hisabati.Set("pi", &object.Float{Value: math.Pi})
+	hisabati.Set("e", &object.Float{Value: math.E})
+}
+
+// toFloat reads a number given to a hisabati function
+func toFloat(name string, obj object.Object) (float64, *object.Error) {
+	switch n := obj.(type) {
+	case *object.Integer:
+		return float64(n.Value), nil
+	case *object.Float:
+		return n.Value, nil
+	case *object.BigInt:
+		return bigToFloat(n.Value), nil
+	default:
+		return 0, newError("Samahani, hisabati.%s inahitaji namba, sio %s", name, obj.Type())
+	}
+}
+
+// mathFunc makes a hisabati function out of one from Go's math package that
+// takes and returns one number
+func mathFunc(name string, fn func(float64) float64) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+		}
+		x, err := toFloat(name, args[0])
+		if err != nil {
+			return err
+		}
+		return &object.Float{Value: fn(x)}
+	}
+}
+
+// mathRound is like mathFunc, but gives a whole number when it fits in one
+func mathRound(name string, fn func(float64) float64) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+		}
+		if n, ok := args[0].(*object.Integer); ok {
+			return n
+		}
+		x, err := toFloat(name, args[0])
+		if err != nil {
+			return err
+		}
+		x = fn(x)
+		if x >= math.MinInt64 && x < math.MaxInt64 {
+			return &object.Integer{Value: int64(x)}
+		}
+		return &object.Float{Value: x}
+	}
+}
+
+func mathSqrt(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	x, err := toFloat("sqrt", args[0])
+	if err != nil {
+		return err
+	}
+	if x < 0 {
+		return newError("Samahani, namba hasi haina sqrt: %s", args[0].Inspect())
+	}
+	return &object.Float{Value: math.Sqrt(x)}
+}
+
+// mathLog is the natural logarithm, or the logarithm to the base given second
+func mathLog(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("Samahani, hisabati.log inapokea hoja 1 au 2, wewe umeweka %d", len(args))
+	}
+	x, err := toFloat("log", args[0])
+	if err != nil {
+		return err
+	}
+	if x <= 0 {
+		return newError("Samahani, log inahitaji namba kubwa kuliko 0, sio %s", args[0].Inspect())
+	}
+	if len(args) == 1 {
+		return &object.Float{Value: math.Log(x)}
+	}
+
+	base, err := toFloat("log", args[1])
+	if err != nil {
+		return err
+	}
+	if base <= 0 || base == 1 {
+		return newError("Samahani, %s haiwezi kuwa msingi wa log", args[1].Inspect())
+	}
+	return &object.Float{Value: math.Log(x) / math.Log(base)}
+}
+
+// mathPow gives the same result as the ** operator
+func mathPow(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+	}
+	for _, arg := range args {
+		if _, err := toFloat("pow", arg); err != nil {
+			return err
+		}
+	}
+	return evalInfixExpression("**", args[0], args[1])
+}
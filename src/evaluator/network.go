@@ -0,0 +1,200 @@
+package evaluator
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// dialTimeout is how long mtandao.unganisha waits for the other side
+const dialTimeout = 30 * time.Second
+
+func init() {
+	registerModule("mtandao", map[string]object.BuiltinFunction{
+		"unganisha": dial,
+		"sikiliza":  listen,
+		"websocket": dialWebSocket,
+	})
+}
+
+// portArg reads a port number, which must fit in 16 bits
+func portArg(name string, obj object.Object) (string, *object.Error) {
+	port, ok := obj.(*object.Integer)
+	if !ok || port.Value < 0 || port.Value > 65535 {
+		return "", newError("Samahani, %s inahitaji bandari kati ya 0 na 65535, sio %s", name, obj.Inspect())
+	}
+	return strconv.FormatInt(port.Value, 10), nil
+}
+
+// dial connects to a host and port, over tcp unless "udp" is given
+func dial(args ...object.Object) object.Object {
+	if len(args) < 2 || len(args) > 3 {
+		return newError("Samahani, mtandao.unganisha inapokea hoja 2 au 3, wewe umeweka %d", len(args))
+	}
+	host, errObj := stringArg("mtandao.unganisha", args, 0)
+	if errObj != nil {
+		return errObj
+	}
+	port, errObj := portArg("mtandao.unganisha", args[1])
+	if errObj != nil {
+		return errObj
+	}
+	network := "tcp"
+	if len(args) == 3 {
+		if network, errObj = stringArg("mtandao.unganisha", args, 2); errObj != nil {
+			return errObj
+		}
+		network = strings.ToLower(network)
+		if network != "tcp" && network != "udp" {
+			return newError("Samahani, mtandao.unganisha inatumia tcp au udp tu, sio %q", network)
+		}
+	}
+
+	addr := net.JoinHostPort(host, port)
+	conn, err := net.DialTimeout(network, addr, dialTimeout)
+	if err != nil {
+		return newError("Nimeshindwa kuunganisha na %s: %s", addr, err)
+	}
+	return object.NewConnection(conn)
+}
+
+// listen waits for tcp connections on a port, on every address of the
+// computer unless a host is given first
+func listen(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("Samahani, mtandao.sikiliza inapokea hoja 1 au 2, wewe umeweka %d", len(args))
+	}
+	host := ""
+	if len(args) == 2 {
+		var errObj *object.Error
+		if host, errObj = stringArg("mtandao.sikiliza", args, 0); errObj != nil {
+			return errObj
+		}
+	}
+	port, errObj := portArg("mtandao.sikiliza", args[len(args)-1])
+	if errObj != nil {
+		return errObj
+	}
+
+	addr := net.JoinHostPort(host, port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return newError("Nimeshindwa kusikiliza %s: %s", addr, err)
+	}
+	return &object.Listener{Value: listener}
+}
+
+func connectionMethod(c *object.Connection, name string) (object.Object, bool) {
+	switch name {
+	case "andika":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			data, errObj := encodingInput("andika", args)
+			if errObj != nil {
+				return errObj
+			}
+			n, err := c.Conn.Write(data)
+			if err != nil {
+				return newError("Nimeshindwa kuandika kwenye muungano: %s", err)
+			}
+			return &object.Integer{Value: int64(n)}
+		}}, true
+	case "soma":
+		// soma gives what has arrived, up to a number of bytes, waiting if
+		// nothing has. It gives tupu once the other side has closed.
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newError("Samahani, hii function inapokea hoja 0 au 1, wewe umeweka %d", len(args))
+			}
+			size := int64(4096)
+			if len(args) == 1 {
+				n, ok := args[0].(*object.Integer)
+				if !ok || n.Value < 1 {
+					return newError("Samahani, soma inahitaji idadi ya baiti iliyo zaidi ya 0, sio %s", args[0].Inspect())
+				}
+				size = n.Value
+			}
+			buf := make([]byte, size)
+			n, err := c.Reader.Read(buf)
+			if n == 0 && errors.Is(err, io.EOF) {
+				return NULL
+			}
+			if n == 0 && err != nil {
+				return newError("Nimeshindwa kusoma kutoka kwenye muungano: %s", err)
+			}
+			return &object.Bytes{Value: buf[:n]}
+		}}, true
+	case "soma_mstari":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			line, err := c.Reader.ReadString('\n')
+			if line == "" && errors.Is(err, io.EOF) {
+				return NULL
+			}
+			if err != nil && !errors.Is(err, io.EOF) {
+				return newError("Nimeshindwa kusoma kutoka kwenye muungano: %s", err)
+			}
+			return &object.String{Value: strings.TrimRight(line, "\r\n")}
+		}}, true
+	case "anwani":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			return &object.String{Value: c.Conn.RemoteAddr().String()}
+		}}, true
+	case "funga":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			if err := c.Conn.Close(); err != nil {
+				return newError("Nimeshindwa kufunga muungano: %s", err)
+			}
+			return NULL
+		}}, true
+	}
+	return nil, false
+}
+
+func listenerMethod(l *object.Listener, name string) (object.Object, bool) {
+	switch name {
+	case "kubali":
+		// kubali waits for the next connection
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			conn, err := l.Value.Accept()
+			if err != nil {
+				return newError("Nimeshindwa kukubali muungano: %s", err)
+			}
+			return object.NewConnection(conn)
+		}}, true
+	case "bandari":
+		// bandari is the port, which is chosen by the system when 0 is given
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			return &object.Integer{Value: int64(l.Value.Addr().(*net.TCPAddr).Port)}
+		}}, true
+	case "funga":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			if err := l.Value.Close(); err != nil {
+				return newError("Nimeshindwa kufunga msikilizaji: %s", err)
+			}
+			return NULL
+		}}, true
+	}
+	return nil, false
+}
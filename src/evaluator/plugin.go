@@ -0,0 +1,79 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+// AnyArity is the arity of a builtin that takes any number of arguments
+const AnyArity = -1
+
+// Variadic is the arity of a builtin that takes min arguments or more, like
+// a function whose last parameter is '...hoja'. Variadic(0) is AnyArity.
+func Variadic(min int) int {
+	return -min - 1
+}
+
+// RegisterBuiltin adds fn to the builtins every program can call, under name.
+// A call with a different number of arguments than arity, or fewer than a
+// Variadic arity allows, fails before fn is called. Builtins should be
+// registered before any code runs.
+func RegisterBuiltin(name string, arity int, fn object.BuiltinFunction) error {
+	builtin, err := NewBuiltin(name, arity, fn)
+	if err != nil {
+		return err
+	}
+	if _, ok := builtins[name]; ok {
+		return fmt.Errorf("Tayari kuna kitendakazi cha ndani kinachoitwa %s", name)
+	}
+	builtins[name] = builtin
+	return nil
+}
+
+// NewBuiltin makes a builtin of fn that checks it is called with arity
+// arguments, for programs that add it to an environment of their own.
+// name must be something Nuru code can call.
+func NewBuiltin(name string, arity int, fn object.BuiltinFunction) (*object.Builtin, error) {
+	if !validName(name) {
+		return nil, fmt.Errorf("%q haliwezi kuwa jina la kitendakazi", name)
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("Kitendakazi %s hakina unda", name)
+	}
+	if arity == AnyArity {
+		return &object.Builtin{Fn: fn}, nil
+	}
+	if arity < AnyArity {
+		min := -arity - 1
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) < min {
+				return newError("Hoja hazilingani, tunahitaji angalau=%d, tumepewa=%d", min, len(args))
+			}
+			return fn(args...)
+		}}, nil
+	}
+
+	return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+		if len(args) != arity {
+			return newError("Hoja hazilingani, tunahitaji=%d, tumepewa=%d", arity, len(args))
+		}
+		return fn(args...)
+	}}, nil
+}
+
+// validName reports whether name would be read as a name by the lexer
+func validName(name string) bool {
+	if name == "" || token.LookupIdent(name) != token.IDENT {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		ch := name[i]
+		letter := 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+		if !letter && (i == 0 || ch < '0' || ch > '9') {
+			return false
+		}
+	}
+	return true
+}
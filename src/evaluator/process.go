@@ -0,0 +1,115 @@
+package evaluator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// command builds the command named by the first argument, with the rest as
+// its arguments
+func command(name string, args []object.Object) (*exec.Cmd, *object.Error) {
+	if len(args) == 0 {
+		return nil, newError("Samahani, %s inahitaji amri ya kuendesha", name)
+	}
+
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		str, ok := arg.(*object.String)
+		if !ok {
+			return nil, newError("Samahani, %s inahitaji NENO, sio %s", name, arg.Type())
+		}
+		strs[i] = str.Value
+	}
+	return exec.Command(strs[0], strs[1:]...), nil
+}
+
+// exitCode is the code a command that ran exited with. A command that
+// exits with a code other than 0 isn't an error, only one that couldn't run.
+func exitCode(err error) (int64, error) {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return int64(exitErr.ExitCode()), nil
+	}
+	return 0, err
+}
+
+// runCommand runs a command until it is done and gives what it printed
+func runCommand(args ...object.Object) object.Object {
+	cmd, errObj := command("endesha", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	code, err := exitCode(cmd.Run())
+	if err != nil {
+		return newError("Nimeshindwa kuendesha %q: %s", cmd.Args[0], err)
+	}
+
+	return newDict(map[string]object.Object{
+		"stdout": &object.String{Value: stdout.String()},
+		"stderr": &object.String{Value: stderr.String()},
+		"code":   &object.Integer{Value: code},
+	})
+}
+
+// startCommand starts a command without waiting for it, so that what it
+// prints can be read while it runs. What it prints to stderr goes straight
+// to the program's stderr.
+func startCommand(args ...object.Object) object.Object {
+	cmd, errObj := command("endesha_mkondo", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	cmd.Stderr = os.Stderr
+	output, err := cmd.StdoutPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		return newError("Nimeshindwa kuendesha %q: %s", cmd.Args[0], err)
+	}
+	return object.NewProcess(cmd, output)
+}
+
+func processMethod(p *object.Process, name string) (object.Object, bool) {
+	switch name {
+	case "subiri":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			if p.Cmd.ProcessState != nil {
+				return &object.Integer{Value: int64(p.Cmd.ProcessState.ExitCode())}
+			}
+
+			// the command may block until what it printed is read, and
+			// Wait can't be called before that, so the lines no loop read
+			// are skipped
+			io.Copy(io.Discard, p.Output)
+			code, err := exitCode(p.Cmd.Wait())
+			if err != nil {
+				return newError("Nimeshindwa kusubiri %q: %s", p.Cmd.Args[0], err)
+			}
+			return &object.Integer{Value: code}
+		}}, true
+	case "simamisha":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			if err := p.Cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+				return newError("Nimeshindwa kusimamisha %q: %s", p.Cmd.Args[0], err)
+			}
+			return NULL
+		}}, true
+	}
+	return nil, false
+}
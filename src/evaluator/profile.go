@@ -0,0 +1,89 @@
+package evaluator
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+// FunctionProfile is how often a function was called and how long it ran in
+// total, including the functions it called
+type FunctionProfile struct {
+	Name     string
+	Position token.Position // where the function's body starts
+	Calls    int
+	Time     time.Duration
+
+	active int // calls still running, so recursion isn't timed twice
+	start  time.Time
+}
+
+// profile is nil unless profiling. Functions are told apart by their body,
+// so every closure made from the same function literal counts as one.
+var (
+	profile   map[*ast.BlockStatement]*FunctionProfile
+	profileMu sync.Mutex // functions started with 'sambamba' are recorded too
+)
+
+// StartProfile starts recording every call of a Nuru function
+func StartProfile() {
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	profile = make(map[*ast.BlockStatement]*FunctionProfile)
+}
+
+// StopProfile stops recording and returns what was recorded, the functions
+// that took longest first
+func StopProfile() []FunctionProfile {
+	profileMu.Lock()
+	var profiles []FunctionProfile
+	for _, p := range profile {
+		profiles = append(profiles, *p)
+	}
+	profile = nil
+	profileMu.Unlock()
+
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Time != profiles[j].Time {
+			return profiles[i].Time > profiles[j].Time
+		}
+		return profiles[i].Calls > profiles[j].Calls
+	})
+	return profiles
+}
+
+func enterProfile(name string, body *ast.BlockStatement) {
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	if profile == nil {
+		return
+	}
+
+	p, ok := profile[body]
+	if !ok {
+		p = &FunctionProfile{Name: name, Position: body.Pos()}
+		profile[body] = p
+	}
+	p.Calls++
+	p.active++
+	if p.active == 1 {
+		p.start = time.Now()
+	}
+}
+
+func leaveProfile(body *ast.BlockStatement) {
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	p, ok := profile[body]
+	if !ok {
+		return
+	}
+
+	p.active--
+	if p.active == 0 {
+		p.Time += time.Since(p.start)
+	}
+}
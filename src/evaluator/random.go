@@ -0,0 +1,129 @@
+package evaluator
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// random is shared by every bahati function. It starts from the time, unless
+// bahati.mbegu gives it a seed so a program makes the same numbers every run.
+var (
+	random   = rand.New(rand.NewSource(time.Now().UnixNano()))
+	randomMu sync.Mutex // rand.Rand isn't safe to use from several goroutines
+)
+
+func init() {
+	registerModule("bahati", map[string]object.BuiltinFunction{
+		"namba":     randomInt,
+		"desimali":  randomFloat,
+		"chagua":    randomChoice,
+		"changanya": randomShuffle,
+		"mbegu":     randomSeed,
+	})
+}
+
+// randomInt gives a whole number from the first number up to and including
+// the second
+func randomInt(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+	}
+
+	low, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("Samahani, bahati.namba inahitaji NAMBA, sio %s", args[0].Type())
+	}
+	high, ok := args[1].(*object.Integer)
+	if !ok {
+		return newError("Samahani, bahati.namba inahitaji NAMBA, sio %s", args[1].Type())
+	}
+	if low.Value > high.Value {
+		return newError("Samahani, %d ni kubwa kuliko %d", low.Value, high.Value)
+	}
+
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	// the span is worked out unsigned, so it can't overflow even when it
+	// covers every int64
+	span := uint64(high.Value-low.Value) + 1
+	if span != 0 && span <= math.MaxInt64 {
+		return &object.Integer{Value: low.Value + random.Int63n(int64(span))}
+	}
+	for {
+		if n := random.Uint64(); span == 0 || n < span {
+			return &object.Integer{Value: low.Value + int64(n)}
+		}
+	}
+}
+
+// randomFloat gives a number from 0 up to but not including 1
+func randomFloat(args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+	}
+
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	return &object.Float{Value: random.Float64()}
+}
+
+func randomChoice(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("Samahani, bahati.chagua inahitaji ORODHA, sio %s", args[0].Type())
+	}
+	if len(arr.Elements) == 0 {
+		return newError("Samahani, huwezi kuchagua kutoka kwenye orodha tupu")
+	}
+
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	return arr.Elements[random.Intn(len(arr.Elements))]
+}
+
+// randomShuffle returns the elements of an array in a random order, leaving
+// the array itself as it was
+func randomShuffle(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("Samahani, bahati.changanya inahitaji ORODHA, sio %s", args[0].Type())
+	}
+
+	elements := make([]object.Object, len(arr.Elements))
+	copy(elements, arr.Elements)
+
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	random.Shuffle(len(elements), func(i, j int) {
+		elements[i], elements[j] = elements[j], elements[i]
+	})
+	return &object.Array{Elements: elements}
+}
+
+func randomSeed(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	seed, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("Samahani, bahati.mbegu inahitaji NAMBA, sio %s", args[0].Type())
+	}
+
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	random.Seed(seed.Value)
+	return nil
+}
@@ -0,0 +1,158 @@
+package evaluator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+func init() {
+	registerModule("seva", map[string]object.BuiltinFunction{
+		"sikiliza": serverListen,
+	})
+}
+
+// serverListen starts an HTTP server on a port and answers every request
+// by calling a Nuru function. It only returns if the server fails.
+func serverListen(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+	}
+
+	var addr string
+	switch port := args[0].(type) {
+	case *object.Integer:
+		addr = fmt.Sprintf(":%d", port.Value)
+	case *object.String:
+		addr = port.Value
+	default:
+		return newError("Samahani, port inahitaji kuwa NAMBA au NENO, sio %s", args[0].Type())
+	}
+
+	if !isCallable(args[1]) {
+		return newError("Samahani, seva.sikiliza inahitaji function, sio %s", args[1].Type())
+	}
+
+	if err := http.ListenAndServe(addr, newHTTPHandler(args[1])); err != nil {
+		return newError("Seva imeshindwa kuanza kwenye %s: %s", addr, err)
+	}
+	return nil
+}
+
+func isCallable(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Function, *object.Builtin, *object.BoundMethod:
+		return true
+	}
+	return false
+}
+
+// newHTTPHandler wraps a Nuru function as an http.Handler. net/http runs
+// every request on its own goroutine but dicts and objects aren't safe to
+// change from several goroutines, so only one request is handled by Nuru at
+// a time.
+func newHTTPHandler(handler object.Object) http.Handler {
+	var mu sync.Mutex
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Nimeshindwa kusoma ombi", http.StatusBadRequest)
+			return
+		}
+
+		headers := make(map[string]object.Object)
+		for k := range r.Header {
+			headers[k] = &object.String{Value: r.Header.Get(k)}
+		}
+
+		query := make(map[string]object.Object)
+		for k := range r.URL.Query() {
+			query[k] = &object.String{Value: r.URL.Query().Get(k)}
+		}
+
+		request := newDict(map[string]object.Object{
+			"method":  &object.String{Value: r.Method},
+			"path":    &object.String{Value: r.URL.Path},
+			"query":   newDict(query),
+			"headers": newDict(headers),
+			"body":    &object.String{Value: string(body)},
+		})
+
+		mu.Lock()
+		response := applyFunction(handler, []object.Object{request})
+		mu.Unlock()
+
+		writeHTTPResponse(w, response)
+	})
+}
+
+// writeHTTPResponse sends what a handler returned. A string is sent as the
+// body; a dict can set status, headers and body, and a dict or list body is
+// sent as JSON.
+func writeHTTPResponse(w http.ResponseWriter, response object.Object) {
+	status := http.StatusOK
+	var body object.Object = &object.String{}
+
+	switch resp := response.(type) {
+	case *object.Error:
+		http.Error(w, errorMessage(resp), http.StatusInternalServerError)
+		return
+	case *object.String:
+		body = resp
+	case *object.Dict:
+		for _, pair := range resp.Pairs {
+			switch plainString(pair.Key) {
+			case "status":
+				code, ok := pair.Value.(*object.Integer)
+				if !ok {
+					http.Error(w, "status inahitaji kuwa NAMBA", http.StatusInternalServerError)
+					return
+				}
+				// net/http panics when asked to write a status outside these
+				if code.Value < 100 || code.Value > 999 {
+					writeHTTPResponse(w, newError("Samahani, status %d si sahihi, inahitaji kuwa kati ya 100 na 999", code.Value))
+					return
+				}
+				status = int(code.Value)
+			case "headers":
+				headers, ok := pair.Value.(*object.Dict)
+				if !ok {
+					http.Error(w, "headers zinahitaji kuwa KAMUSI", http.StatusInternalServerError)
+					return
+				}
+				for _, h := range headers.Pairs {
+					w.Header().Set(plainString(h.Key), plainString(h.Value))
+				}
+			case "body":
+				body = pair.Value
+			}
+		}
+	case *object.Null:
+	default:
+		body = &object.String{Value: response.Inspect()}
+	}
+
+	switch body := body.(type) {
+	case *object.Dict, *object.Array:
+		var out bytes.Buffer
+		if err := writeJSON(&out, body); err != nil {
+			http.Error(w, errorMessage(err), http.StatusInternalServerError)
+			return
+		}
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(status)
+		w.Write(out.Bytes())
+	case *object.Null:
+		w.WriteHeader(status)
+	default:
+		w.WriteHeader(status)
+		io.WriteString(w, plainString(body))
+	}
+}
@@ -0,0 +1,147 @@
+package evaluator
+
+import (
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// newSet is the 'seti' builtin. It takes nothing, or anything that can be
+// looped over, and keeps each element once.
+func newSet(args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newError("Samahani, hii function inapokea hoja 0 au 1, wewe umeweka %d", len(args))
+	}
+
+	set := &object.Set{Elements: make(map[object.HashKey]object.Object)}
+	if len(args) == 0 {
+		return set
+	}
+
+	var elements []object.Object
+	switch arg := args[0].(type) {
+	case *object.Array:
+		elements = arg.Elements
+	case *object.Set:
+		elements = arg.Sorted()
+	case *object.String:
+		for _, ch := range arg.Value {
+			elements = append(elements, &object.String{Value: string(ch)})
+		}
+	case *object.Dict:
+		for _, pair := range arg.Pairs {
+			elements = append(elements, pair.Key)
+		}
+	case *object.Range:
+		for i := arg.Start; (arg.Step > 0 && i < arg.End) || (arg.Step < 0 && i > arg.End); i += arg.Step {
+			elements = append(elements, &object.Integer{Value: i})
+		}
+	default:
+		return newError("Samahani, seti haiwezi kutengenezwa kutoka %s", args[0].Type())
+	}
+
+	for _, el := range elements {
+		if err := setAdd(set, el); err != nil {
+			return err
+		}
+	}
+	return set
+}
+
+func setAdd(set *object.Set, el object.Object) *object.Error {
+	hashable, ok := el.(object.Hashable)
+	if !ok {
+		return newError("Samahani, %s haiwezi kuwekwa kwenye seti", el.Type())
+	}
+	set.Elements[hashable.HashKey()] = el
+	return nil
+}
+
+func setContains(set *object.Set, el object.Object) bool {
+	hashable, ok := el.(object.Hashable)
+	if !ok {
+		return false
+	}
+	_, ok = set.Elements[hashable.HashKey()]
+	return ok
+}
+
+func evalSetInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.Set)
+	rightVal := right.(*object.Set)
+	result := &object.Set{Elements: make(map[object.HashKey]object.Object)}
+
+	switch operator {
+	case "|":
+		for k, v := range leftVal.Elements {
+			result.Elements[k] = v
+		}
+		for k, v := range rightVal.Elements {
+			result.Elements[k] = v
+		}
+		return result
+	case "&":
+		for k, v := range leftVal.Elements {
+			if _, ok := rightVal.Elements[k]; ok {
+				result.Elements[k] = v
+			}
+		}
+		return result
+	case "-":
+		for k, v := range leftVal.Elements {
+			if _, ok := rightVal.Elements[k]; !ok {
+				result.Elements[k] = v
+			}
+		}
+		return result
+	case "==":
+		return nativeBoolToBooleanObject(setsEqual(leftVal, rightVal))
+	case "!=":
+		return nativeBoolToBooleanObject(!setsEqual(leftVal, rightVal))
+	default:
+		return newError("Operesheni Haielweki: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
+
+func setsEqual(a, b *object.Set) bool {
+	if len(a.Elements) != len(b.Elements) {
+		return false
+	}
+	for k := range a.Elements {
+		if _, ok := b.Elements[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// setMethod returns the method called name bound to set
+func setMethod(set *object.Set, name string) (object.Object, bool) {
+	switch name {
+	case "ongeza":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			for _, el := range args {
+				if err := setAdd(set, el); err != nil {
+					return err
+				}
+			}
+			return set
+		}}, true
+	case "ondoa":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			for _, el := range args {
+				if hashable, ok := el.(object.Hashable); ok {
+					delete(set.Elements, hashable.HashKey())
+				}
+			}
+			return set
+		}}, true
+	case "ina":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+			return nativeBoolToBooleanObject(setContains(set, args[0]))
+		}}, true
+	}
+	return nil, false
+}
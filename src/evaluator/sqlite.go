@@ -0,0 +1,6 @@
+//go:build !js
+
+package evaluator
+
+// the SQLite driver is pure Go, but can't be built for the browser
+import _ "modernc.org/sqlite"
@@ -0,0 +1,122 @@
+package evaluator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// stringArg checks that the argument at i of a string method is a string
+func stringArg(method string, args []object.Object, i int) (string, *object.Error) {
+	str, ok := args[i].(*object.String)
+	if !ok {
+		return "", newError("Samahani, %s inahitaji NENO, sio %s", method, args[i].Type())
+	}
+	return str.Value, nil
+}
+
+func stringMethod(str *object.String, name string) (object.Object, bool) {
+	switch name {
+	case "gawa":
+		// with no separator, gawa splits on any run of spaces
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newError("Samahani, hii function inapokea hoja 0 au 1, wewe umeweka %d", len(args))
+			}
+			var parts []string
+			if len(args) == 0 {
+				parts = strings.Fields(str.Value)
+			} else {
+				sep, err := stringArg("gawa", args, 0)
+				if err != nil {
+					return err
+				}
+				parts = strings.Split(str.Value, sep)
+			}
+			elements := make([]object.Object, len(parts))
+			for i, part := range parts {
+				elements[i] = &object.String{Value: part}
+			}
+			return &object.Array{Elements: elements}
+		}}, true
+	case "unga":
+		// unga joins the elements of an array, with the string between them
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return newError("Samahani, unga inahitaji ORODHA, sio %s", args[0].Type())
+			}
+			parts := make([]string, len(arr.Elements))
+			for i, el := range arr.Elements {
+				parts[i] = plainString(el)
+			}
+			return &object.String{Value: strings.Join(parts, str.Value)}
+		}}, true
+	case "punguza":
+		// with no argument, punguza removes spaces from both ends, otherwise
+		// the characters given
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newError("Samahani, hii function inapokea hoja 0 au 1, wewe umeweka %d", len(args))
+			}
+			if len(args) == 0 {
+				return &object.String{Value: strings.TrimSpace(str.Value)}
+			}
+			chars, err := stringArg("punguza", args, 0)
+			if err != nil {
+				return err
+			}
+			return &object.String{Value: strings.Trim(str.Value, chars)}
+		}}, true
+	case "herufikubwa":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			return &object.String{Value: strings.ToUpper(str.Value)}
+		}}, true
+	case "herufindogo":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			return &object.String{Value: strings.ToLower(str.Value)}
+		}}, true
+	case "badilisha":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+			}
+			old, err := stringArg("badilisha", args, 0)
+			if err != nil {
+				return err
+			}
+			replacement, err := stringArg("badilisha", args, 1)
+			if err != nil {
+				return err
+			}
+			return &object.String{Value: strings.ReplaceAll(str.Value, old, replacement)}
+		}}, true
+	case "tafuta":
+		// tafuta gives where the text is first found, or -1 if it isn't
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+			sub, err := stringArg("tafuta", args, 0)
+			if err != nil {
+				return err
+			}
+			idx := strings.Index(str.Value, sub)
+			if idx < 0 {
+				return &object.Integer{Value: -1}
+			}
+			return &object.Integer{Value: int64(utf8.RuneCountInString(str.Value[:idx]))}
+		}}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,172 @@
+package evaluator
+
+import (
+	"time"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// timeLayouts are tried in order by muda.changanua when no format is given
+var timeLayouts = []string{
+	object.TimeFormat,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func init() {
+	registerModule("muda", map[string]object.BuiltinFunction{
+		"sasa":      timeNow,
+		"changanua": timeParse,
+		"panga":     timeFormat,
+		"ongeza":    timeAdd,
+		"tofauti":   timeDiff,
+	})
+}
+
+func timeNow(args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+	}
+	return &object.Time{Value: time.Now()}
+}
+
+// timeParse reads a time from a string, with an optional Go layout such as "02/01/2006"
+func timeParse(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("Samahani, muda.changanua inapokea hoja 1 au 2, wewe umeweka %d", len(args))
+	}
+
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("Samahani, muda.changanua inahitaji NENO, sio %s", args[0].Type())
+	}
+
+	layouts := timeLayouts
+	if len(args) == 2 {
+		layout, ok := args[1].(*object.String)
+		if !ok {
+			return newError("Samahani, muundo wa muda unahitaji kuwa NENO, sio %s", args[1].Type())
+		}
+		layouts = []string{layout.Value}
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, str.Value, time.Local); err == nil {
+			return &object.Time{Value: t}
+		}
+	}
+
+	return newError("Samahani, sijaweza kuelewa muda %q", str.Value)
+}
+
+func timeFormat(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+	}
+
+	t, ok := args[0].(*object.Time)
+	if !ok {
+		return newError("Samahani, muda.panga inahitaji MUDA, sio %s", args[0].Type())
+	}
+
+	layout, ok := args[1].(*object.String)
+	if !ok {
+		return newError("Samahani, muundo wa muda unahitaji kuwa NENO, sio %s", args[1].Type())
+	}
+
+	return &object.String{Value: t.Value.Format(layout.Value)}
+}
+
+// timeAdd moves a time forward, or back for negative numbers, by a number of seconds
+func timeAdd(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+	}
+
+	t, ok := args[0].(*object.Time)
+	if !ok {
+		return newError("Samahani, muda.ongeza inahitaji MUDA, sio %s", args[0].Type())
+	}
+
+	var seconds float64
+	switch n := args[1].(type) {
+	case *object.Integer:
+		seconds = float64(n.Value)
+	case *object.Float:
+		seconds = n.Value
+	default:
+		return newError("Samahani, muda.ongeza inahitaji sekunde kwa namba, sio %s", args[1].Type())
+	}
+
+	return &object.Time{Value: t.Value.Add(time.Duration(seconds * float64(time.Second)))}
+}
+
+// timeDiff gives the number of seconds from the second time to the first
+func timeDiff(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+	}
+
+	a, ok := args[0].(*object.Time)
+	if !ok {
+		return newError("Samahani, muda.tofauti inahitaji MUDA, sio %s", args[0].Type())
+	}
+	b, ok := args[1].(*object.Time)
+	if !ok {
+		return newError("Samahani, muda.tofauti inahitaji MUDA, sio %s", args[1].Type())
+	}
+
+	diff := a.Value.Sub(b.Value)
+	if diff%time.Second == 0 {
+		return &object.Integer{Value: int64(diff / time.Second)}
+	}
+	return &object.Float{Value: diff.Seconds()}
+}
+
+func evalTimeInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.Time).Value
+	rightVal := right.(*object.Time).Value
+
+	switch operator {
+	case "<":
+		return nativeBoolToBooleanObject(leftVal.Before(rightVal))
+	case ">":
+		return nativeBoolToBooleanObject(leftVal.After(rightVal))
+	case "<=":
+		return nativeBoolToBooleanObject(!leftVal.After(rightVal))
+	case ">=":
+		return nativeBoolToBooleanObject(!leftVal.Before(rightVal))
+	case "==":
+		return nativeBoolToBooleanObject(leftVal.Equal(rightVal))
+	case "!=":
+		return nativeBoolToBooleanObject(!leftVal.Equal(rightVal))
+	default:
+		return newError("Operesheni Haielweki: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// timeField looks up the parts of a time, as in t.mwaka or t.saa
+func timeField(t *object.Time, name string) (object.Object, bool) {
+	var value int
+	switch name {
+	case "mwaka":
+		value = t.Value.Year()
+	case "mwezi":
+		value = int(t.Value.Month())
+	case "siku":
+		value = t.Value.Day()
+	case "saa":
+		value = t.Value.Hour()
+	case "dakika":
+		value = t.Value.Minute()
+	case "sekunde":
+		value = t.Value.Second()
+	case "unix":
+		return &object.Integer{Value: t.Value.Unix()}, true
+	default:
+		return nil, false
+	}
+	return &object.Integer{Value: int64(value)}, true
+}
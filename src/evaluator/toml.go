@@ -0,0 +1,71 @@
+package evaluator
+
+import (
+	"strings"
+	"time"
+
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/BurntSushi/toml"
+)
+
+func init() {
+	registerModule("toml", map[string]object.BuiltinFunction{
+		"tengua": tomlDecode,
+	})
+}
+
+// tomlDecode turns a TOML document into a dict, like json.tengua does for
+// JSON
+func tomlDecode(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("Samahani, toml.tengua inahitaji NENO, sio %s", args[0].Type())
+	}
+
+	var doc map[string]interface{}
+	if _, err := toml.Decode(str.Value, &doc); err != nil {
+		msg := strings.TrimPrefix(err.Error(), "toml: ")
+		return newError("TOML si sahihi: %s", strings.Replace(msg, "line ", "mstari ", 1))
+	}
+
+	return fromTOML(doc)
+}
+
+func fromTOML(value interface{}) object.Object {
+	switch value := value.(type) {
+	case bool:
+		return nativeBoolToBooleanObject(value)
+	case string:
+		return &object.String{Value: value}
+	case int64:
+		return &object.Integer{Value: value}
+	case float64:
+		return &object.Float{Value: value}
+	case time.Time:
+		return &object.Time{Value: value}
+	case []interface{}:
+		elements := make([]object.Object, len(value))
+		for i, el := range value {
+			elements[i] = fromTOML(el)
+		}
+		return &object.Array{Elements: elements}
+	case []map[string]interface{}:
+		// arrays of tables, written with [[jina]]
+		elements := make([]object.Object, len(value))
+		for i, el := range value {
+			elements[i] = fromTOML(el)
+		}
+		return &object.Array{Elements: elements}
+	case map[string]interface{}:
+		dict := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+		for k, v := range value {
+			key := &object.String{Value: k}
+			dict.Pairs[key.HashKey()] = object.DictPair{Key: key, Value: fromTOML(v)}
+		}
+		return dict
+	}
+	return NULL
+}
@@ -0,0 +1,74 @@
+package evaluator
+
+import (
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// newTuple is the 'jozi' builtin. Every element has to be hashable, since
+// that is what lets a tuple be used as a dict key.
+func newTuple(args ...object.Object) object.Object {
+	elements := make([]object.Object, len(args))
+	for i, arg := range args {
+		if _, ok := arg.(object.Hashable); !ok {
+			return newError("Samahani, %s haiwezi kuwekwa kwenye jozi", arg.Type())
+		}
+		elements[i] = arg
+	}
+	return &object.Tuple{Elements: elements}
+}
+
+func evalTupleIndexExpression(tuple, index object.Object) object.Object {
+	elements := tuple.(*object.Tuple).Elements
+	idx := index.(*object.Integer).Value
+
+	if idx < 0 || idx > int64(len(elements)-1) {
+		return NULL
+	}
+	return elements[idx]
+}
+
+func evalTupleInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := left.(*object.Tuple)
+	rightVal := right.(*object.Tuple)
+
+	switch operator {
+	case "+":
+		elements := make([]object.Object, 0, len(leftVal.Elements)+len(rightVal.Elements))
+		elements = append(elements, leftVal.Elements...)
+		elements = append(elements, rightVal.Elements...)
+		return &object.Tuple{Elements: elements}
+	case "==":
+		return nativeBoolToBooleanObject(tuplesEqual(leftVal, rightVal))
+	case "!=":
+		return nativeBoolToBooleanObject(!tuplesEqual(leftVal, rightVal))
+	default:
+		return newError("Operesheni Haielweki: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
+
+func tuplesEqual(a, b *object.Tuple) bool {
+	if len(a.Elements) != len(b.Elements) {
+		return false
+	}
+	for i := range a.Elements {
+		if a.Elements[i].(object.Hashable).HashKey() != b.Elements[i].(object.Hashable).HashKey() {
+			return false
+		}
+	}
+	return true
+}
+
+func evalInTupleExpression(left object.Object, tuple *object.Tuple) object.Object {
+	hashable, ok := left.(object.Hashable)
+	if !ok {
+		return FALSE
+	}
+	key := hashable.HashKey()
+	for _, el := range tuple.Elements {
+		if el.(object.Hashable).HashKey() == key {
+			return TRUE
+		}
+	}
+	return FALSE
+}
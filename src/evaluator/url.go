@@ -0,0 +1,157 @@
+package evaluator
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// url.tengua takes a url apart, as json.tengua does JSON, and url.jenga puts
+// one back together
+func init() {
+	registerModule("url", map[string]object.BuiltinFunction{
+		"tengua":      urlParse,
+		"jenga":       urlBuild,
+		"tengua_hoja": queryDecode,
+		"fungua_hoja": queryEncode,
+	})
+}
+
+// urlParse gives the parts of a url as a dict
+func urlParse(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	str, errObj := stringArg("url.tengua", args, 0)
+	if errObj != nil {
+		return errObj
+	}
+
+	u, err := url.Parse(str)
+	if err != nil {
+		return newError("Samahani, url %q si sahihi", str)
+	}
+
+	var port object.Object = NULL
+	if p := u.Port(); p != "" {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			return newError("Samahani, url %q si sahihi", str)
+		}
+		port = &object.Integer{Value: n}
+	}
+	return newDict(map[string]object.Object{
+		"scheme":   &object.String{Value: u.Scheme},
+		"host":     &object.String{Value: u.Hostname()},
+		"port":     port,
+		"path":     &object.String{Value: u.Path},
+		"query":    queryDict(u.Query()),
+		"fragment": &object.String{Value: u.Fragment},
+	})
+}
+
+// urlBuild puts together a url from a dict with the parts urlParse gives.
+// Parts that are missing are left out.
+func urlBuild(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	parts, ok := args[0].(*object.Dict)
+	if !ok {
+		return newError("Samahani, url.jenga inahitaji KAMUSI, sio %s", args[0].Type())
+	}
+
+	u := &url.URL{}
+	for _, pair := range parts.Pairs {
+		key := plainString(pair.Key)
+		switch key {
+		case "scheme":
+			u.Scheme = plainString(pair.Value)
+		case "host":
+			u.Host = plainString(pair.Value)
+		case "path":
+			u.Path = plainString(pair.Value)
+		case "fragment":
+			u.Fragment = plainString(pair.Value)
+		case "port", "query":
+			// these need the others first
+		default:
+			return newError("Samahani, url haina sehemu %q", key)
+		}
+	}
+	if pair, ok := parts.Pairs[(&object.String{Value: "port"}).HashKey()]; ok && pair.Value != NULL {
+		u.Host = net.JoinHostPort(u.Host, plainString(pair.Value))
+	}
+	if pair, ok := parts.Pairs[(&object.String{Value: "query"}).HashKey()]; ok {
+		switch query := pair.Value.(type) {
+		case *object.String:
+			u.RawQuery = query.Value
+		case *object.Dict:
+			u.RawQuery = queryValues(query).Encode()
+		default:
+			return newError("Samahani, query inahitaji kuwa KAMUSI au NENO, sio %s", pair.Value.Type())
+		}
+	}
+	return &object.String{Value: u.String()}
+}
+
+// queryDict turns query values into a dict. A name given once has a string,
+// and one given more than once has an array of them.
+func queryDict(values url.Values) *object.Dict {
+	fields := make(map[string]object.Object, len(values))
+	for name, vals := range values {
+		if len(vals) == 1 {
+			fields[name] = &object.String{Value: vals[0]}
+		} else {
+			fields[name] = stringArray(vals)
+		}
+	}
+	return newDict(fields)
+}
+
+// queryValues is queryDict the other way round
+func queryValues(dict *object.Dict) url.Values {
+	values := url.Values{}
+	for _, pair := range dict.Pairs {
+		name := plainString(pair.Key)
+		if arr, ok := pair.Value.(*object.Array); ok {
+			for _, el := range arr.Elements {
+				values.Add(name, plainString(el))
+			}
+		} else {
+			values.Add(name, plainString(pair.Value))
+		}
+	}
+	return values
+}
+
+func queryDecode(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	str, errObj := stringArg("url.tengua_hoja", args, 0)
+	if errObj != nil {
+		return errObj
+	}
+
+	values, err := url.ParseQuery(str)
+	if err != nil {
+		return newError("Samahani, hoja %q si sahihi", str)
+	}
+	return queryDict(values)
+}
+
+// queryEncode gives a query string with the names in order, and the names
+// and values escaped
+func queryEncode(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	dict, ok := args[0].(*object.Dict)
+	if !ok {
+		return newError("Samahani, url.fungua_hoja inahitaji KAMUSI, sio %s", args[0].Type())
+	}
+	return &object.String{Value: queryValues(dict).Encode()}
+}
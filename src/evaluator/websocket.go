@@ -0,0 +1,119 @@
+package evaluator
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/gorilla/websocket"
+)
+
+// dialWebSocket connects to a ws:// or wss:// url, sending the headers in an
+// optional dict with the request that opens the connection
+func dialWebSocket(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 2 {
+		return newError("Samahani, mtandao.websocket inapokea hoja 1 au 2, wewe umeweka %d", len(args))
+	}
+	url, errObj := stringArg("mtandao.websocket", args, 0)
+	if errObj != nil {
+		return errObj
+	}
+
+	header := http.Header{}
+	if len(args) == 2 {
+		headers, ok := args[1].(*object.Dict)
+		if !ok {
+			return newError("Samahani, headers zinahitaji kuwa KAMUSI, sio %s", args[1].Type())
+		}
+		for _, pair := range headers.Pairs {
+			header.Set(plainString(pair.Key), plainString(pair.Value))
+		}
+	}
+
+	dialer := &websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: dialTimeout}
+	conn, _, err := dialer.Dial(url, header)
+	if err != nil {
+		return newError("Nimeshindwa kuunganisha na %q: %s", url, err)
+	}
+	return &object.WebSocket{Conn: conn}
+}
+
+// receive waits for the next message. Once the connection is closed, from
+// either side, it gives tupu.
+func receive(ws *object.WebSocket) object.Object {
+	msg, err := ws.Receive()
+	if err == nil {
+		return msg
+	}
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) ||
+		errors.Is(err, net.ErrClosed) {
+		return NULL
+	}
+	return newError("Nimeshindwa kupokea ujumbe: %s", err)
+}
+
+func websocketMethod(ws *object.WebSocket, name string) (object.Object, bool) {
+	switch name {
+	case "tuma":
+		// strings are sent as text messages and bytes as binary ones
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			data, errObj := encodingInput("tuma", args)
+			if errObj != nil {
+				return errObj
+			}
+			if err := ws.Send(data, args[0].Type() == object.STRING_OBJ); err != nil {
+				return newError("Nimeshindwa kutuma ujumbe: %s", err)
+			}
+			return NULL
+		}}, true
+	case "pokea":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			return receive(ws)
+		}}, true
+	case "sikiliza":
+		// sikiliza calls a function with every message that arrives, while
+		// the program goes on. It returns an ahadi that is done when the
+		// connection closes, or the function fails.
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+			}
+			fn, errObj := callback("sikiliza", args, 0)
+			if errObj != nil {
+				return errObj
+			}
+
+			future := &object.Future{Done: make(chan struct{})}
+			go func() {
+				defer close(future.Done)
+				for {
+					msg := receive(ws)
+					if msg == NULL || isError(msg) {
+						future.Result = msg
+						return
+					}
+					if result := applyFunction(fn, []object.Object{msg}); isError(result) {
+						future.Result = result
+						return
+					}
+				}
+			}()
+			return future
+		}}, true
+	case "funga":
+		return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newError("Hoja hazilingani, tunahitaji=0, tumepewa=%d", len(args))
+			}
+			if err := ws.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+				return newError("Nimeshindwa kufunga websocket: %s", err)
+			}
+			return NULL
+		}}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,207 @@
+package evaluator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// An element is a dict with its tag, a dict of its attributes, an array of
+// the elements inside it and the text directly inside it
+func init() {
+	registerModule("xml", map[string]object.BuiltinFunction{
+		"tengua": xmlDecode,
+		"fungua": xmlEncode,
+		"tafuta": xmlFind,
+	})
+}
+
+func xmlDecode(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	str, errObj := stringArg("xml.tengua", args, 0)
+	if errObj != nil {
+		return errObj
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(str))
+	var stack []*xmlElement
+	var root *xmlElement
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				return newError("XML si sahihi: mstari %d: %s", syntaxErr.Line, syntaxErr.Msg)
+			}
+			return newError("XML si sahihi: %s", err)
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			el := &xmlElement{tag: tok.Name.Local, attributes: make(map[string]object.Object)}
+			for _, attr := range tok.Attr {
+				el.attributes[attr.Name.Local] = &object.String{Value: attr.Value}
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, el)
+			} else if root == nil {
+				root = el
+			} else {
+				return newError("XML si sahihi: kuna zaidi ya kipengele kimoja cha juu")
+			}
+			stack = append(stack, el)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(tok)
+			}
+		}
+	}
+	if root == nil {
+		return newError("XML si sahihi: hakuna kipengele")
+	}
+	return root.dict()
+}
+
+type xmlElement struct {
+	tag        string
+	attributes map[string]object.Object
+	children   []*xmlElement
+	text       bytes.Buffer
+}
+
+func (el *xmlElement) dict() *object.Dict {
+	children := make([]object.Object, len(el.children))
+	for i, child := range el.children {
+		children[i] = child.dict()
+	}
+	return newDict(map[string]object.Object{
+		"tag":        &object.String{Value: el.tag},
+		"attributes": newDict(el.attributes),
+		"children":   &object.Array{Elements: children},
+		// the spaces that lay out the children aren't part of the text
+		"text": &object.String{Value: strings.TrimSpace(el.text.String())},
+	})
+}
+
+// xmlField gives the value of a key of an element dict, or nil if it isn't
+// there
+func xmlField(el *object.Dict, key string) object.Object {
+	if pair, ok := el.Pairs[(&object.String{Value: key}).HashKey()]; ok {
+		return pair.Value
+	}
+	return nil
+}
+
+func xmlEncode(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	var out bytes.Buffer
+	if err := writeXML(&out, args[0]); err != nil {
+		return err
+	}
+	return &object.String{Value: out.String()}
+}
+
+func writeXML(out *bytes.Buffer, obj object.Object) *object.Error {
+	el, ok := obj.(*object.Dict)
+	if !ok {
+		return newError("Samahani, kipengele cha XML kinahitaji kuwa KAMUSI, sio %s", obj.Type())
+	}
+	tag, ok := xmlField(el, "tag").(*object.String)
+	if !ok || tag.Value == "" {
+		return newError("Samahani, kipengele cha XML kinahitaji tag")
+	}
+
+	out.WriteString("<" + tag.Value)
+	if attrs := xmlField(el, "attributes"); attrs != nil {
+		dict, ok := attrs.(*object.Dict)
+		if !ok {
+			return newError("Samahani, attributes zinahitaji kuwa KAMUSI, sio %s", attrs.Type())
+		}
+		// attributes are sorted so the same element always gives the same XML
+		names := make([]string, 0, len(dict.Pairs))
+		values := make(map[string]string, len(dict.Pairs))
+		for _, pair := range dict.Pairs {
+			name := plainString(pair.Key)
+			names = append(names, name)
+			values[name] = plainString(pair.Value)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			out.WriteString(" " + name + `="`)
+			xml.EscapeText(out, []byte(values[name]))
+			out.WriteString(`"`)
+		}
+	}
+	out.WriteString(">")
+
+	if text := xmlField(el, "text"); text != nil && text != NULL {
+		xml.EscapeText(out, []byte(plainString(text)))
+	}
+	if children := xmlField(el, "children"); children != nil {
+		arr, ok := children.(*object.Array)
+		if !ok {
+			return newError("Samahani, children zinahitaji kuwa ORODHA, sio %s", children.Type())
+		}
+		for _, child := range arr.Elements {
+			if err := writeXML(out, child); err != nil {
+				return err
+			}
+		}
+	}
+
+	out.WriteString("</" + tag.Value + ">")
+	return nil
+}
+
+// xmlFind gives every element with a tag inside an element, at any depth,
+// in the order they appear
+func xmlFind(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("Hoja hazilingani, tunahitaji=2, tumepewa=%d", len(args))
+	}
+	el, ok := args[0].(*object.Dict)
+	if !ok {
+		return newError("Samahani, xml.tafuta inahitaji KAMUSI, sio %s", args[0].Type())
+	}
+	tag, errObj := stringArg("xml.tafuta", args, 1)
+	if errObj != nil {
+		return errObj
+	}
+
+	found := []object.Object{}
+	var search func(el *object.Dict)
+	search = func(el *object.Dict) {
+		children, ok := xmlField(el, "children").(*object.Array)
+		if !ok {
+			return
+		}
+		for _, child := range children.Elements {
+			child, ok := child.(*object.Dict)
+			if !ok {
+				continue
+			}
+			if name, ok := xmlField(child, "tag").(*object.String); ok && name.Value == tag {
+				found = append(found, child)
+			}
+			search(child)
+		}
+	}
+	search(el)
+	return &object.Array{Elements: found}
+}
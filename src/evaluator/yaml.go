@@ -0,0 +1,231 @@
+package evaluator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AvicennaJr/Nuru/object"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	registerModule("yaml", map[string]object.BuiltinFunction{
+		"tengua": yamlDecode,
+		"fungua": yamlEncode,
+	})
+}
+
+// yamlDecode turns a YAML string into Nuru objects, like json.tengua does
+// for JSON
+func yamlDecode(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("Samahani, yaml.tengua inahitaji NENO, sio %s", args[0].Type())
+	}
+
+	dec := yaml.NewDecoder(strings.NewReader(str.Value))
+	var doc yaml.Node
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return NULL
+		}
+		return yamlError(err)
+	}
+	var extra yaml.Node
+	if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+		return newError("YAML si sahihi: kuna hati zaidi ya moja")
+	}
+
+	return fromYAML(&doc)
+}
+
+func yamlError(err error) *object.Error {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	return newError("YAML si sahihi: %s", strings.Replace(msg, "line ", "mstari ", 1))
+}
+
+func fromYAML(node *yaml.Node) object.Object {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return NULL
+		}
+		return fromYAML(node.Content[0])
+	case yaml.AliasNode:
+		return fromYAML(node.Alias)
+	case yaml.SequenceNode:
+		elements := make([]object.Object, len(node.Content))
+		for i, el := range node.Content {
+			val := fromYAML(el)
+			if isError(val) {
+				return val
+			}
+			elements[i] = val
+		}
+		return &object.Array{Elements: elements}
+	case yaml.MappingNode:
+		dict := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := fromYAML(node.Content[i])
+			if isError(key) {
+				return key
+			}
+			hashable, ok := key.(object.Hashable)
+			if !ok {
+				return newError("Samahani, %s haiwezi kuwa ufunguo wa kamusi", key.Type())
+			}
+			val := fromYAML(node.Content[i+1])
+			if isError(val) {
+				return val
+			}
+			dict.Pairs[hashable.HashKey()] = object.DictPair{Key: key, Value: val}
+		}
+		return dict
+	}
+	return yamlScalar(node)
+}
+
+func yamlScalar(node *yaml.Node) object.Object {
+	switch node.ShortTag() {
+	case "!!null":
+		return NULL
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return yamlError(err)
+		}
+		return nativeBoolToBooleanObject(b)
+	case "!!int":
+		var i int64
+		if err := node.Decode(&i); err == nil {
+			return &object.Integer{Value: i}
+		}
+		if i, ok := new(big.Int).SetString(strings.ReplaceAll(node.Value, "_", ""), 0); ok {
+			return &object.BigInt{Value: i}
+		}
+		return newError("YAML si sahihi: %q si namba", node.Value)
+	case "!!float":
+		// whole numbers too big for an int are tagged as floats
+		if i, ok := new(big.Int).SetString(node.Value, 10); ok {
+			return &object.BigInt{Value: i}
+		}
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return yamlError(err)
+		}
+		return &object.Float{Value: f}
+	case "!!timestamp":
+		var t time.Time
+		if err := node.Decode(&t); err != nil {
+			return yamlError(err)
+		}
+		return &object.Time{Value: t}
+	}
+	return &object.String{Value: node.Value}
+}
+
+// yamlEncode turns Nuru objects into a YAML string
+func yamlEncode(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Hoja hazilingani, tunahitaji=1, tumepewa=%d", len(args))
+	}
+
+	node, errObj := toYAML(args[0])
+	if errObj != nil {
+		return errObj
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return newError("Samahani, %s haiwezi kubadilishwa kuwa YAML", args[0].Type())
+	}
+	enc.Close()
+	return &object.String{Value: out.String()}
+}
+
+func toYAML(obj object.Object) (*yaml.Node, *object.Error) {
+	scalar := func(tag, value string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+	}
+
+	switch obj := obj.(type) {
+	case *object.Null:
+		return scalar("!!null", "null"), nil
+	case *object.Boolean:
+		return scalar("!!bool", strconv.FormatBool(obj.Value)), nil
+	case *object.Integer:
+		return scalar("!!int", obj.Inspect()), nil
+	case *object.BigInt:
+		// YAML reads numbers this big as floats, so they aren't tagged as
+		// ints, which would have the tag written out
+		return scalar("", obj.Inspect()), nil
+	case *object.Decimal:
+		return scalar("!!float", obj.Inspect()), nil
+	case *object.Float:
+		switch {
+		case math.IsNaN(obj.Value):
+			return scalar("!!float", ".nan"), nil
+		case math.IsInf(obj.Value, 1):
+			return scalar("!!float", ".inf"), nil
+		case math.IsInf(obj.Value, -1):
+			return scalar("!!float", "-.inf"), nil
+		}
+		return scalar("!!float", strconv.FormatFloat(obj.Value, 'g', -1, 64)), nil
+	case *object.String:
+		return scalar("!!str", obj.Value), nil
+	case *object.Time:
+		return scalar("!!timestamp", obj.Value.Format(time.RFC3339Nano)), nil
+	case *object.Array:
+		return yamlSequence(obj.Elements)
+	case *object.Tuple:
+		return yamlSequence(obj.Elements)
+	case *object.Dict:
+		// keys are sorted so the same dict always gives the same YAML
+		pairs := make([]object.DictPair, 0, len(obj.Pairs))
+		for _, pair := range obj.Pairs {
+			pairs = append(pairs, pair)
+		}
+		sort.Slice(pairs, func(i, j int) bool {
+			return pairs[i].Key.Inspect() < pairs[j].Key.Inspect()
+		})
+
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, pair := range pairs {
+			key, err := toYAML(pair.Key)
+			if err != nil {
+				return nil, err
+			}
+			val, err := toYAML(pair.Value)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, key, val)
+		}
+		return node, nil
+	}
+	return nil, newError("Samahani, %s haiwezi kubadilishwa kuwa YAML", obj.Type())
+}
+
+func yamlSequence(elements []object.Object) (*yaml.Node, *object.Error) {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, el := range elements {
+		val, err := toYAML(el)
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, val)
+	}
+	return node, nil
+}
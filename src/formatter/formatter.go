@@ -0,0 +1,714 @@
+// Package formatter prints Nuru programs back out as source in one
+// consistent style, the way 'nuru fmt' shows them.
+package formatter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/parser"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+const indentation = "    "
+
+// primary is the precedence of anything that never needs brackets around it,
+// like names, literals and function literals
+const primary = parser.INDEX + 1
+
+// Format parses input and prints it back in the standard style. Comments are
+// kept, as are single blank lines between statements. If input doesn't parse,
+// the parser errors are returned instead.
+func Format(input string) (string, []string) {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return "", p.Errors()
+	}
+
+	pr := &printer{
+		input:      input,
+		sourceLine: strings.Split(input, "\n"),
+		lineStarts: lineStarts(input),
+		comments:   l.Comments(),
+		opened:     true,
+	}
+	pr.statements(program.Statements, token.Position{})
+	pr.flushComments(token.Position{Line: len(pr.sourceLine) + 1})
+	if pr.cur.Len() > 0 {
+		pr.newline()
+	}
+
+	if len(pr.lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(pr.lines, "\n") + "\n", nil
+}
+
+// Expression prints exp on its own, the way Format prints it in a program.
+// Without the source to copy them from, strings are given double quotes.
+func Expression(exp ast.Expression) string {
+	pr := &printer{opened: true}
+	pr.expression(exp, parser.LOWEST)
+	return strings.Join(append(pr.lines, pr.cur.String()), "\n")
+}
+
+type printer struct {
+	input      string
+	sourceLine []string
+	lineStarts []int
+
+	comments []lexer.Comment
+	next     int // the first comment not printed yet
+
+	lines  []string
+	cur    strings.Builder
+	indent int
+	opened bool // nothing has been printed since the last '{'
+}
+
+func lineStarts(input string) []int {
+	starts := []int{0}
+	for i := 0; i < len(input); i++ {
+		if input[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+func (p *printer) write(s string) {
+	if p.cur.Len() == 0 {
+		p.cur.WriteString(strings.Repeat(indentation, p.indent))
+	}
+	p.cur.WriteString(s)
+	p.opened = false
+}
+
+func (p *printer) newline() {
+	p.lines = append(p.lines, strings.TrimRight(p.cur.String(), " "))
+	p.cur.Reset()
+}
+
+// blankLine adds an empty line, unless one would be doubled or would come
+// straight after an opening brace
+func (p *printer) blankLine() {
+	if p.opened || len(p.lines) == 0 || p.lines[len(p.lines)-1] == "" {
+		return
+	}
+	p.lines = append(p.lines, "")
+}
+
+// blankBefore reports whether the line before line is empty in the source
+func (p *printer) blankBefore(line int) bool {
+	return line >= 2 && line-2 < len(p.sourceLine) && strings.TrimSpace(p.sourceLine[line-2]) == ""
+}
+
+// flushComments prints the comments that come before pos. A trailing comment
+// stays at the end of the line printed before it.
+func (p *printer) flushComments(pos token.Position) {
+	for p.next < len(p.comments) && before(p.comments[p.next].Position, pos) {
+		c := p.comments[p.next]
+		p.next++
+
+		if c.Trailing && p.cur.Len() == 0 && len(p.lines) > 0 && p.lines[len(p.lines)-1] != "" {
+			p.lines[len(p.lines)-1] += " " + c.Text
+			continue
+		}
+
+		if p.blankBefore(c.Position.Line) {
+			p.blankLine()
+		}
+		p.write(c.Text)
+		p.newline()
+	}
+}
+
+func before(a, b token.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+}
+
+// source returns the text of the token at pos exactly as it was written, or
+// nothing when printing an Expression
+func (p *printer) source(pos token.Position) string {
+	if p.input == "" {
+		return ""
+	}
+	start := p.lineStarts[pos.Line-1] + pos.Column - 1
+	l := lexer.New(p.input[start:])
+	l.NextToken()
+	return p.input[start : start+l.Offset()]
+}
+
+func (p *printer) statements(stmts []ast.Statement, end token.Position) {
+	for _, stmt := range stmts {
+		pos := stmt.Pos()
+		if es, ok := stmt.(*ast.ExpressionStatement); ok && es.Expression != nil {
+			pos = start(es.Expression)
+		}
+		p.flushComments(pos)
+		if pos.Line > 0 && p.blankBefore(pos.Line) {
+			p.blankLine()
+		}
+		p.statement(stmt)
+		p.newline()
+	}
+	if end.Line > 0 {
+		p.flushComments(end)
+	}
+}
+
+func (p *printer) statement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		if stmt.Constant {
+			p.write("thabiti ")
+		} else {
+			p.write("fanya ")
+		}
+		p.write(stmt.Name.Value + " = ")
+		p.expression(stmt.Value, parser.LOWEST)
+	case *ast.DestructuringStatement:
+		p.write("fanya " + stmt.Pattern() + " = ")
+		p.expression(stmt.Value, parser.LOWEST)
+	case *ast.ReturnStatement:
+		p.write("rudisha")
+		if array, ok := stmt.ReturnValue.(*ast.ArrayLiteral); ok && stmt.Multiple {
+			for i, el := range array.Elements {
+				if i > 0 {
+					p.write(",")
+				}
+				p.write(" ")
+				p.expression(el, parser.LOWEST)
+			}
+		} else if stmt.ReturnValue != nil {
+			p.write(" ")
+			p.expression(stmt.ReturnValue, parser.LOWEST)
+		}
+	case *ast.ThrowStatement:
+		p.write("tupa ")
+		p.expression(stmt.Value, parser.LOWEST)
+	case *ast.ImportStatement:
+		if stmt.Name.Token.Type == token.STRING {
+			p.write("tumia " + p.source(stmt.Name.Token.Position))
+		} else {
+			p.write("tumia " + stmt.Path)
+		}
+	case *ast.Break:
+		p.write(stmt.Token.Literal)
+	case *ast.Continue:
+		p.write(stmt.Token.Literal)
+	case *ast.ClassStatement:
+		p.class(stmt)
+	case *ast.ExpressionStatement:
+		p.expression(stmt.Expression, parser.LOWEST)
+	}
+}
+
+// class prints the fields, constructor and methods of a 'muundo' in the order
+// they were written
+func (p *printer) class(stmt *ast.ClassStatement) {
+	type member struct {
+		pos   token.Position
+		print func()
+	}
+	var members []member
+
+	for _, field := range stmt.Fields {
+		field := field
+		members = append(members, member{field.Pos(), func() { p.statement(field) }})
+	}
+	if stmt.Constructor != nil {
+		members = append(members, member{stmt.Constructor.Pos(), func() { p.function("unda", stmt.Constructor) }})
+	}
+	for _, method := range stmt.Methods {
+		method := method
+		members = append(members, member{method.Name.Pos(), func() { p.function(method.Name.Value, method.Function) }})
+	}
+	sort.Slice(members, func(i, j int) bool { return before(members[i].pos, members[j].pos) })
+
+	p.write("muundo " + stmt.Name.Value + " {")
+	p.newline()
+	p.indent++
+	p.opened = true
+	for _, m := range members {
+		p.flushComments(m.pos)
+		if p.blankBefore(m.pos.Line) {
+			p.blankLine()
+		}
+		m.print()
+		p.newline()
+	}
+	p.flushComments(stmt.End)
+	p.indent--
+	p.write("}")
+}
+
+// block prints a block in braces, with its statements indented
+func (p *printer) block(block *ast.BlockStatement) {
+	if len(block.Statements) == 0 && !p.hasCommentsBefore(block.End) {
+		p.write("{}")
+		return
+	}
+
+	p.write("{")
+	p.newline()
+	p.indent++
+	p.opened = true
+	p.statements(block.Statements, block.End)
+	p.indent--
+	p.write("}")
+}
+
+func (p *printer) hasCommentsBefore(pos token.Position) bool {
+	return pos.Line > 0 && p.next < len(p.comments) && before(p.comments[p.next].Position, pos)
+}
+
+func (p *printer) function(name string, fn *ast.FunctionLiteral) {
+	p.write(name + "(" + parameters(fn) + ") ")
+	p.block(fn.Body)
+}
+
+// arrowFunction prints '(x) => x * 2', keeping the block if one was written
+func (p *printer) arrowFunction(fn *ast.FunctionLiteral) {
+	p.write("(" + parameters(fn) + ") => ")
+	p.body(fn.Body)
+}
+
+// body prints what follows '=>': a block, or the expression standing for one
+func (p *printer) body(block *ast.BlockStatement) {
+	if block.Token.Type == token.LBRACE {
+		p.block(block)
+		return
+	}
+	body := block.Statements[0].(*ast.ExpressionStatement).Expression
+	if p.source(start(body)) == "{" {
+		// without brackets a dict would be read as a block
+		p.write("(")
+		defer p.write(")")
+	}
+	p.expression(body, parser.LOWEST)
+}
+
+func parameters(fn *ast.FunctionLiteral) string {
+	params := make([]string, len(fn.Parameters))
+	for i, param := range fn.Parameters {
+		params[i] = param.Value
+	}
+	if fn.Variadic {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
+	return strings.Join(params, ", ")
+}
+
+// start returns where exp begins in the source. Infix and call expressions
+// are positioned at their operator, so this looks at what comes first.
+func start(exp ast.Expression) token.Position {
+	switch exp := exp.(type) {
+	case *ast.InfixExpression:
+		return start(exp.Left)
+	case *ast.AssignmentExpression:
+		return start(exp.Left)
+	case *ast.ConditionalExpression:
+		return start(exp.Condition)
+	case *ast.ComparisonChain:
+		return start(exp.Operands[0])
+	case *ast.CallExpression:
+		return start(exp.Function)
+	case *ast.IndexExpression:
+		return start(exp.Left)
+	case *ast.SliceExpression:
+		return start(exp.Left)
+	case *ast.PropertyExpression:
+		return start(exp.Object)
+	}
+	return exp.Pos()
+}
+
+// precedence returns how tightly exp holds together, to know whether it
+// needs brackets where it is used
+func precedence(exp ast.Expression) int {
+	switch exp := exp.(type) {
+	case *ast.InfixExpression:
+		return parser.Precedence(exp.Token.Type)
+	case *ast.PrefixExpression, *ast.SpawnExpression:
+		return parser.PREFIX
+	case *ast.AssignmentExpression, *ast.YieldExpression:
+		return parser.LOWEST
+	case *ast.FunctionLiteral:
+		if exp.Arrow {
+			// its body takes everything after the '=>'
+			return parser.LOWEST
+		}
+	case *ast.ConditionalExpression:
+		return parser.TERNARY
+	case *ast.ComparisonChain:
+		return parser.LESSGREATER
+	case *ast.CallExpression:
+		return parser.CALL
+	case *ast.IndexExpression, *ast.SliceExpression, *ast.PropertyExpression:
+		return parser.INDEX
+	}
+	return primary
+}
+
+// expression prints exp, in brackets if it binds less tightly than min
+func (p *printer) expression(exp ast.Expression, min int) {
+	if precedence(exp) < min {
+		p.write("(")
+		defer p.write(")")
+	}
+
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		p.write(exp.Value)
+	case *ast.IntegerLiteral, *ast.BigIntegerLiteral, *ast.FloatLiteral, *ast.Boolean, *ast.Null, *ast.ThisExpression:
+		p.write(exp.TokenLiteral())
+	case *ast.StringLiteral, *ast.TemplateLiteral:
+		if p.input == "" {
+			p.write(strconv.Quote(exp.TokenLiteral()))
+		} else {
+			p.write(p.source(exp.Pos()))
+		}
+	case *ast.PrefixExpression:
+		p.write(exp.Operator)
+		p.expression(exp.Right, parser.PREFIX+1)
+	case *ast.SpawnExpression:
+		p.write("sambamba ")
+		p.expression(exp.Call, parser.PREFIX+1)
+	case *ast.YieldExpression:
+		p.write("toa ")
+		p.expression(exp.Value, parser.LOWEST)
+	case *ast.PostfixExpression:
+		p.write(exp.Token.Literal + exp.Operator)
+	case *ast.InfixExpression:
+		prec := parser.Precedence(exp.Token.Type)
+		left, right := prec, prec+1
+		if exp.Token.Type == token.POW || prec == parser.LESSGREATER {
+			// (a < b) < c would otherwise read as a chain
+			left = prec + 1
+		}
+		if exp.Token.Type == token.POW {
+			right = prec
+		}
+		p.expression(exp.Left, left)
+		p.write(" " + exp.Operator + " ")
+		p.expression(exp.Right, right)
+	case *ast.AssignmentExpression:
+		p.expression(exp.Left, parser.ASSIGN+1)
+		p.write(" " + exp.Token.Literal + " ")
+		p.expression(exp.Value, parser.LOWEST)
+	case *ast.CallExpression:
+		p.expression(exp.Function, parser.CALL)
+		p.list("(", exp.Arguments, ")", exp.Pos())
+	case *ast.ConditionalExpression:
+		p.expression(exp.Condition, parser.TERNARY+1)
+		p.write(" ? ")
+		p.expression(exp.Consequence, parser.LOWEST)
+		p.write(" : ")
+		p.expression(exp.Alternative, parser.TERNARY)
+	case *ast.ComparisonChain:
+		p.expression(exp.Operands[0], parser.LESSGREATER+1)
+		for i, operator := range exp.Operators {
+			p.write(" " + operator + " ")
+			p.expression(exp.Operands[i+1], parser.LESSGREATER+1)
+		}
+	case *ast.SpreadExpression:
+		p.write("...")
+		p.expression(exp.Value, parser.LOWEST)
+	case *ast.NamedArgument:
+		p.write(exp.Name.Value + "=")
+		p.expression(exp.Value, parser.LOWEST)
+	case *ast.IndexExpression:
+		p.expression(exp.Left, parser.INDEX)
+		if exp.Optional {
+			p.write("?")
+		}
+		p.write("[")
+		p.expression(exp.Index, parser.LOWEST)
+		p.write("]")
+	case *ast.Comprehension:
+		end := "]"
+		if exp.Key != nil {
+			p.write("{")
+			p.expression(exp.Key, parser.LOWEST)
+			p.write(": ")
+			end = "}"
+		} else {
+			p.write("[")
+		}
+		p.expression(exp.Value, parser.LOWEST)
+		p.write(" kwa ")
+		if exp.LoopKey != "" {
+			p.write(exp.LoopKey + ", ")
+		}
+		p.write(exp.LoopValue + " ktk ")
+		p.expression(exp.Iterable, parser.LOWEST)
+		if exp.Condition != nil {
+			p.write(" kama ")
+			p.expression(exp.Condition, parser.LOWEST)
+		}
+		p.write(end)
+	case *ast.SliceExpression:
+		p.expression(exp.Left, parser.INDEX)
+		if exp.Optional {
+			p.write("?")
+		}
+		p.write("[")
+		if exp.Start != nil {
+			p.expression(exp.Start, parser.LOWEST)
+		}
+		p.write(":")
+		if exp.Stop != nil {
+			p.expression(exp.Stop, parser.LOWEST)
+		}
+		if exp.Step != nil {
+			p.write(":")
+			p.expression(exp.Step, parser.LOWEST)
+		}
+		p.write("]")
+	case *ast.PropertyExpression:
+		p.expression(exp.Object, parser.INDEX)
+		p.write("." + exp.Property.Value)
+	case *ast.ArrayLiteral:
+		p.list("[", exp.Elements, "]", exp.Pos())
+	case *ast.DictLiteral:
+		p.dict(exp)
+	case *ast.FunctionLiteral:
+		if exp.Arrow {
+			p.arrowFunction(exp)
+		} else {
+			p.function("unda", exp)
+		}
+	case *ast.IfExpression:
+		p.ifExpression(exp)
+	case *ast.WhileExpression:
+		p.write("wakati (")
+		p.expression(exp.Condition, parser.LOWEST)
+		p.write(") ")
+		p.block(exp.Consequence)
+	case *ast.DoWhileExpression:
+		p.write("fanya ")
+		p.block(exp.Consequence)
+		p.write(" wakati (")
+		p.expression(exp.Condition, parser.LOWEST)
+		p.write(")")
+	case *ast.ForIn:
+		p.write("kwa ")
+		if exp.Key != "" {
+			p.write(exp.Key + ", ")
+		}
+		p.write(exp.Value + " ktk ")
+		p.expression(exp.Iterable, parser.LOWEST)
+		p.write(" ")
+		p.block(exp.Block)
+	case *ast.SwitchExpression:
+		p.switchExpression(exp)
+	case *ast.MatchExpression:
+		p.matchExpression(exp)
+	case *ast.TryExpression:
+		p.write("jaribu ")
+		p.block(exp.Block)
+		p.write(" shika ")
+		if exp.Identifier != nil {
+			p.write("(" + exp.Identifier.Value + ") ")
+		}
+		p.block(exp.Catch)
+	}
+}
+
+// list prints the items between open and close. Lists written over several
+// lines keep one item per line.
+func (p *printer) list(open string, items []ast.Expression, close string, opened token.Position) {
+	multiline := false
+	for _, item := range items {
+		if start(item).Line != opened.Line {
+			multiline = true
+		}
+	}
+
+	p.write(open)
+	if !multiline {
+		for i, item := range items {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.expression(item, parser.LOWEST)
+		}
+		p.write(close)
+		return
+	}
+
+	p.newline()
+	p.indent++
+	for i, item := range items {
+		p.flushComments(start(item))
+		p.expression(item, parser.LOWEST)
+		if i < len(items)-1 {
+			p.write(",")
+		}
+		p.newline()
+	}
+	p.indent--
+	p.write(close)
+}
+
+func (p *printer) dict(dict *ast.DictLiteral) {
+	multiline := false
+	for _, key := range dict.Keys {
+		if start(key).Line != dict.Token.Line {
+			multiline = true
+		}
+	}
+
+	p.write("{")
+	if !multiline {
+		for i, key := range dict.Keys {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.expression(key, parser.LOWEST)
+			p.write(": ")
+			p.expression(dict.Pairs[key], parser.LOWEST)
+		}
+		p.write("}")
+		return
+	}
+
+	p.newline()
+	p.indent++
+	for _, key := range dict.Keys {
+		p.flushComments(start(key))
+		p.expression(key, parser.LOWEST)
+		p.write(": ")
+		p.expression(dict.Pairs[key], parser.LOWEST)
+		p.write(",")
+		p.newline()
+	}
+	p.indent--
+	p.write("}")
+}
+
+func (p *printer) ifExpression(exp *ast.IfExpression) {
+	p.write("kama (")
+	p.expression(exp.Condition, parser.LOWEST)
+	p.write(") ")
+	p.block(exp.Consequence)
+
+	if exp.Alternative == nil {
+		return
+	}
+	p.write(" sivyo ")
+
+	// 'sivyo kama' is parsed into a block with no brace of its own
+	if exp.Alternative.Token.Type == "" && len(exp.Alternative.Statements) == 1 {
+		if stmt, ok := exp.Alternative.Statements[0].(*ast.ExpressionStatement); ok {
+			if elseIf, ok := stmt.Expression.(*ast.IfExpression); ok {
+				p.ifExpression(elseIf)
+				return
+			}
+		}
+	}
+	p.block(exp.Alternative)
+}
+
+func (p *printer) matchExpression(exp *ast.MatchExpression) {
+	p.write("linganisha (")
+	p.expression(exp.Value, parser.LOWEST)
+	p.write(") {")
+	p.newline()
+	p.indent++
+	p.opened = true
+	for _, arm := range exp.Arms {
+		p.flushComments(arm.Pattern.Pos())
+		if p.blankBefore(arm.Pattern.Pos().Line) {
+			p.blankLine()
+		}
+		p.pattern(arm.Pattern)
+		if arm.Guard != nil {
+			p.write(" kama (")
+			p.expression(arm.Guard, parser.LOWEST)
+			p.write(")")
+		}
+		p.write(" => ")
+		p.body(arm.Body)
+		p.write(",")
+		p.newline()
+	}
+	p.flushComments(exp.End)
+	p.indent--
+	p.write("}")
+}
+
+// pattern prints the pattern of a 'linganisha' arm, where {jina} stays short
+// for {"jina": jina}
+func (p *printer) pattern(pattern ast.Expression) {
+	switch pattern := pattern.(type) {
+	case *ast.ArrayLiteral:
+		p.write("[")
+		for i, element := range pattern.Elements {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.pattern(element)
+		}
+		p.write("]")
+	case *ast.DictLiteral:
+		p.write("{")
+		for i, key := range pattern.Keys {
+			if i > 0 {
+				p.write(", ")
+			}
+			value := pattern.Pairs[key]
+			if ident, ok := value.(*ast.Identifier); ok && ident.Pos() == key.Pos() {
+				p.write(ident.Value)
+				continue
+			}
+			p.expression(key, parser.LOWEST)
+			p.write(": ")
+			p.pattern(value)
+		}
+		p.write("}")
+	case *ast.SpreadExpression:
+		p.write("...")
+		p.pattern(pattern.Value)
+	default:
+		p.expression(pattern, parser.LOWEST)
+	}
+}
+
+func (p *printer) switchExpression(exp *ast.SwitchExpression) {
+	p.write("badili (")
+	p.expression(exp.Value, parser.LOWEST)
+	p.write(") {")
+	p.newline()
+	p.indent++
+	p.opened = true
+	for _, choice := range exp.Choices {
+		p.flushComments(choice.Pos())
+		if p.blankBefore(choice.Pos().Line) {
+			p.blankLine()
+		}
+		if choice.Default {
+			p.write("kawaida ")
+		} else {
+			p.write("ikiwa ")
+			for i, value := range choice.Expr {
+				if i > 0 {
+					p.write(", ")
+				}
+				p.expression(value, parser.LOWEST)
+			}
+			p.write(" ")
+		}
+		p.block(choice.Block)
+		p.newline()
+	}
+	p.flushComments(exp.End)
+	p.indent--
+	p.write("}")
+}
@@ -0,0 +1,183 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fanya  x=1+2*3 ;fanya y = x", "fanya x = 1 + 2 * 3\nfanya y = x\n"},
+		{"(1 + 2) * 3", "(1 + 2) * 3\n"},
+		{"fanya p=`\\d+\n`", "fanya p = `\\d+\n`\n"},
+		{"a - (b - c)", "a - (b - c)\n"},
+		{"(a - b) - c", "a - b - c\n"},
+		{"-(-x)", "-(-x)\n"},
+		{"!(x == y)", "!(x == y)\n"},
+		{"(f)(1)", "f(1)\n"},
+		{"(a + b)[0]", "(a + b)[0]\n"},
+		{"x+=1", "x += 1\n"},
+		{"(a**b)**c**d", "(a ** b) ** c ** d\n"},
+		{"(-a)**2", "(-a) ** 2\n"},
+		{"(a|b)&~c<<1", "(a | b) & ~c << 1\n"},
+		{"0<x<=10", "0 < x <= 10\n"},
+		{"(a<b)<c<(d>e)", "(a < b) < c < (d > e)\n"},
+		{"a[ 1 : n-1 ]", "a[1:n - 1]\n"},
+		{"a?[::-1][:2]", "a?[::-1][:2]\n"},
+		{"[x*2 kwa x ktk o kama x>0]", "[x * 2 kwa x ktk o kama x > 0]\n"},
+		{"{k:v kwa k,v ktk d}", "{k: v kwa k, v ktk d}\n"},
+		{"f(x=>x*2)", "f((x) => x * 2)\n"},
+		{"fanya f=(a,...b)=>{b}", "fanya f = (a, ...b) => {\n    b\n}\n"},
+		{"((x)=>x)(1)", "((x) => x)(1)\n"},
+		{"fanya d=(k)=>({k:1})", "fanya d = (k) => ({k: 1})\n"},
+		{"a??((x)=>x)", "a ?? ((x) => x)\n"},
+		{"fanya g=unda(n){toa n*2;toa (n)}", "fanya g = unda(n) {\n    toa n * 2\n    toa n\n}\n"},
+		{"linganisha(x){-1=>0,[a,...b] kama(a>1)=>{a},{\"k\":[_],jina}=>({k:1}),}", "linganisha (x) {\n    -1 => 0,\n    [a, ...b] kama (a > 1) => {\n        a\n    },\n    {\"k\": [_], jina} => ({k: 1}),\n}\n"},
+		{"a??(b??c)??d||e", "a ?? (b ?? c) ?? d || e\n"},
+		{`a?["b"]?[ 0 ]`, "a?[\"b\"]?[0]\n"},
+		{"f((a?b:c)?d:(e?g:h))", "f((a ? b : c) ? d : e ? g : h)\n"},
+		{"i++", "i++\n"},
+		{"fanya j=i--*2", "fanya j = i-- * 2\n"},
+		{"thabiti  PI=3.14", "thabiti PI = 3.14\n"},
+		{"andika('moja', \"mbili\\n\")", "andika('moja', \"mbili\\n\")\n"},
+		{`"jina ni ${ jina }"`, "\"jina ni ${ jina }\"\n"},
+		{"fanya f = unda(a,b){rudisha a+b}", "fanya f = unda(a, b) {\n    rudisha a + b\n}\n"},
+		{"unda() {}", "unda() {}\n"},
+		{"unda(a,...b){b}", "unda(a, ...b) {\n    b\n}\n"},
+		{"chora(1,upana = 2*3)", "chora(1, upana=2 * 3)\n"},
+		{"fanya [a,b]=x;fanya {c , d}=y", "fanya [a, b] = x\nfanya {c, d} = y\n"},
+		{"f(... a,[ ...b ,1])", "f(...a, [...b, 1])\n"},
+		{"fanya a,b=f()", "fanya a, b = f()\n"},
+		{"unda() { rudisha 1,[2] }", "unda() {\n    rudisha 1, [2]\n}\n"},
+		{"kama (x) {a} sivyo kama (y) {b} sivyo {c}", "kama (x) {\n    a\n} sivyo kama (y) {\n    b\n} sivyo {\n    c\n}\n"},
+		{"kama (x) {a} sivyo { kama (y) {b} }", "kama (x) {\n    a\n} sivyo {\n    kama (y) {\n        b\n    }\n}\n"},
+		{"wakati (x > 0) { x-- }", "wakati (x > 0) {\n    x--\n}\n"},
+		{"fanya { x -= 1 } wakati (x > 0)", "fanya {\n    x -= 1\n} wakati (x > 0)\n"},
+		{"kwa k,v ktk d { vunja }", "kwa k, v ktk d {\n    vunja\n}\n"},
+		{"badili (x) { ikiwa 1,2 { a } kawaida { b } }", "badili (x) {\n    ikiwa 1, 2 {\n        a\n    }\n    kawaida {\n        b\n    }\n}\n"},
+		{"jaribu { tupa \"kosa\" } shika (e) { e }", "jaribu {\n    tupa \"kosa\"\n} shika (e) {\n    e\n}\n"},
+		{"tumia \"lib/hesabu.nr\"; tumia json", "tumia \"lib/hesabu.nr\"\ntumia json\n"},
+		{"{\"b\": 2, \"a\": [1,2]}", "{\"b\": 2, \"a\": [1, 2]}\n"},
+		{"[1,\n2]", "[\n    1,\n    2\n]\n"},
+		{"{\"a\": 1,\n\"b\": 2}", "{\n    \"a\": 1,\n    \"b\": 2,\n}\n"},
+		{"muundo Mtu { salamu() { 1 } fanya jina = 1 }", "muundo Mtu {\n    salamu() {\n        1\n    }\n    fanya jina = 1\n}\n"},
+		{"a\n\n\n\nb", "a\n\nb\n"},
+		{"kama (x) {\n\n  a\n\n}", "kama (x) {\n    a\n}\n"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		got, errs := Format(tt.input)
+		if len(errs) != 0 {
+			t.Fatalf("%q: unexpected errors %v", tt.input, errs)
+		}
+		if got != tt.expected {
+			t.Errorf("%q: wrong output.\nwant=%q\ngot= %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestFormatKeepsComments(t *testing.T) {
+	input := `// kwanza
+fanya x = 1 // moja
+
+/* mengi
+   zaidi */
+fanya f = unda() { // kazi
+  // ndani
+  rudisha x
+  // mwisho
+}
+fanya d = {
+  "a": 1, // a
+  // b
+  "b": 2
+}
+// mwisho kabisa`
+
+	expected := `// kwanza
+fanya x = 1 // moja
+
+/* mengi
+   zaidi */
+fanya f = unda() { // kazi
+    // ndani
+    rudisha x
+    // mwisho
+}
+fanya d = {
+    "a": 1, // a
+    // b
+    "b": 2,
+}
+// mwisho kabisa
+`
+
+	got, errs := Format(input)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if got != expected {
+		t.Errorf("wrong output.\nwant=%q\ngot= %q", expected, got)
+	}
+}
+
+func TestFormatIsStable(t *testing.T) {
+	inputs := []string{
+		"fanya x = (1 + 2) * -y ** 2 % 3\nandika(x ktk [1, 2] && !kweli || x >= 2)",
+		"fanya f = unda(n) { kama (n < 2) { rudisha n }; rudisha f(n - 1) + f(n - 2) }",
+		"muundo A { unda(x) { hii.x = x } ongeza() { hii.x += 1; rudisha hii } }\nA(1).ongeza().x",
+		"fanya s = seti([1, 2]) | seti([3]) & seti([1])",
+	}
+
+	for _, input := range inputs {
+		once, errs := Format(input)
+		if len(errs) != 0 {
+			t.Fatalf("%q: unexpected errors %v", input, errs)
+		}
+		twice, _ := Format(once)
+		if once != twice {
+			t.Errorf("formatting is not stable.\nonce= %q\ntwice=%q", once, twice)
+		}
+
+		// the formatted code must mean exactly the same thing
+		before := parser.New(lexer.New(input)).ParseProgram().String()
+		after := parser.New(lexer.New(once)).ParseProgram().String()
+		if before != after {
+			t.Errorf("formatting changed the program.\nbefore=%q\nafter= %q", before, after)
+		}
+	}
+}
+
+func TestFormatParseErrors(t *testing.T) {
+	_, errs := Format("fanya = 1")
+	if len(errs) == 0 {
+		t.Errorf("expected parse errors")
+	}
+}
+
+func TestExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(a+b)*c==d", "(a + b) * c == d"},
+		{"jina=='Asha\\n'", "jina == \"Asha\\n\""},
+		{"x[0]>0&&f(1,2)", "x[0] > 0 && f(1, 2)"},
+		{"unda(x){\n\nrudisha x}", "unda(x) {\n    rudisha x\n}"},
+	}
+
+	for _, tt := range tests {
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+		exp := program.Statements[0].(*ast.ExpressionStatement).Expression
+		if got := Expression(exp); got != tt.expected {
+			t.Errorf("wrong expression for %q.\nwant=%q\ngot= %q", tt.input, tt.expected, got)
+		}
+	}
+}
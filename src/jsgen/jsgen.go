@@ -0,0 +1,645 @@
+// Package jsgen turns Nuru programs into JavaScript, the way 'nuru js' does,
+// so that they can run wherever JavaScript runs, like a web page or node,
+// without the interpreter. The program is joined by a small runtime that
+// makes operators, errors and printing behave as they do in Nuru.
+//
+// Only the core of the language is covered: values, functions, classes,
+// loops, 'badili', 'jaribu' and the simplest builtins. A program using
+// anything else, like 'tumia' or files, is refused with an error saying what
+// can't be turned into JavaScript.
+package jsgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+// builtins are the builtin functions the runtime has
+var builtins = []string{"andika", "aina", "idadi", "jumla", "yamwisho", "sukuma", "mpaka", "sawa_kabisa"}
+
+// reserved are the words JavaScript keeps for itself, which Nuru allows as
+// names. They get a '$' added when used as names.
+var reserved = map[string]bool{
+	"arguments": true, "await": true, "break": true, "case": true, "catch": true,
+	"class": true, "const": true, "continue": true, "debugger": true, "default": true,
+	"delete": true, "do": true, "else": true, "enum": true, "eval": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true,
+	"function": true, "if": true, "implements": true, "import": true, "in": true,
+	"instanceof": true, "interface": true, "let": true, "new": true, "null": true,
+	"package": true, "private": true, "protected": true, "public": true, "return": true,
+	"static": true, "super": true, "switch": true, "this": true, "throw": true,
+	"true": true, "try": true, "typeof": true, "undefined": true, "var": true,
+	"void": true, "while": true, "with": true, "yield": true, "console": true,
+}
+
+// Generate turns program into a JavaScript program that runs on its own
+func Generate(program *ast.Program) (string, error) {
+	g := &generator{out: &bytes.Buffer{}, declared: make(map[string]bool), used: make(map[string]token.Position)}
+	g.out.WriteString(runtime)
+	g.out.WriteString("\n$nuru.run(function () {\n")
+	g.indent++
+	g.line("var { %s } = $nuru.builtins;", strings.Join(builtins, ", "))
+	g.statements(program.Statements, true)
+	g.indent--
+	g.out.WriteString("});\n")
+
+	if g.err != nil {
+		return "", g.err
+	}
+	if err := g.checkUsed(); err != nil {
+		return "", err
+	}
+	return g.out.String(), nil
+}
+
+// maxSafeInteger is JavaScript's Number.MAX_SAFE_INTEGER, 2^53 - 1
+const maxSafeInteger = 1<<53 - 1
+
+type generator struct {
+	out    *bytes.Buffer
+	indent int
+	err    error
+	temps  int // how many temporary names have been made
+
+	declared map[string]bool           // every name the program declares
+	used     map[string]token.Position // builtins and modules the runtime lacks, where first used
+}
+
+// fail records that node can't be turned into JavaScript. Only the first
+// failure is kept.
+func (g *generator) fail(node ast.Node, what string) {
+	if g.err == nil {
+		g.err = fmt.Errorf("%s: %s haiwezi kugeuzwa kuwa JavaScript", node.Pos(), what)
+	}
+}
+
+// checkUsed fails if the program uses a builtin, module or global of the
+// interpreter that the runtime doesn't have, and doesn't declare one with its
+// name
+func (g *generator) checkUsed() error {
+	names := append(evaluator.BuiltinNames(), evaluator.ModuleNames()...)
+	for _, name := range append(names, evaluator.GlobalNames()...) {
+		if pos, ok := g.used[name]; ok && !g.declared[name] {
+			return fmt.Errorf("%s: %s haiwezi kugeuzwa kuwa JavaScript", pos, name)
+		}
+	}
+	return nil
+}
+
+func (g *generator) line(format string, a ...interface{}) {
+	g.out.WriteString(strings.Repeat("  ", g.indent))
+	fmt.Fprintf(g.out, format, a...)
+	g.out.WriteString("\n")
+}
+
+func (g *generator) temp(prefix string) string {
+	g.temps++
+	return fmt.Sprintf("$%s%d", prefix, g.temps)
+}
+
+func (g *generator) name(name string) string {
+	if reserved[name] {
+		return name + "$"
+	}
+	return name
+}
+
+func (g *generator) declare(name string) string {
+	g.declared[name] = true
+	return g.name(name)
+}
+
+// statements writes stmts. If result is set, the value of the last one is
+// returned, the way a function or the whole program gives the value of what
+// it ran last.
+func (g *generator) statements(stmts []ast.Statement, result bool) {
+	for i, stmt := range stmts {
+		g.statement(stmt, result && i == len(stmts)-1)
+	}
+}
+
+func (g *generator) block(block *ast.BlockStatement, result bool) {
+	if block != nil {
+		g.indent++
+		g.statements(block.Statements, result)
+		g.indent--
+	}
+}
+
+func (g *generator) statement(stmt ast.Statement, result bool) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		if stmt.Constant {
+			g.fail(stmt, "thabiti")
+		}
+		g.line("var %s = %s;", g.declare(stmt.Name.Value), g.expression(stmt.Value))
+	case *ast.DestructuringStatement:
+		value := g.expression(stmt.Value)
+		vars := make([]string, len(stmt.Names))
+		keys := make([]string, len(stmt.Names))
+		for i, name := range stmt.Names {
+			vars[i] = g.declare(name.Value)
+			keys[i] = quote(name.Value)
+		}
+		g.line("var [%s] = $nuru.unpack(%s, [%s], %t);", strings.Join(vars, ", "), value, strings.Join(keys, ", "), stmt.Dict)
+	case *ast.ReturnStatement:
+		g.line("return %s;", g.expression(stmt.ReturnValue))
+	case *ast.ThrowStatement:
+		g.line("throw $nuru.tupa(%s);", g.expression(stmt.Value))
+	case *ast.ClassStatement:
+		g.class(stmt)
+	case *ast.ExpressionStatement:
+		g.expressionStatement(stmt.Expression, result)
+	case *ast.BlockStatement:
+		g.statements(stmt.Statements, result)
+	case *ast.Break:
+		g.line("break;")
+	case *ast.Continue:
+		g.line("continue;")
+	case *ast.ImportStatement:
+		g.fail(stmt, "tumia")
+	default:
+		g.fail(stmt, fmt.Sprintf("%T", stmt))
+	}
+}
+
+func (g *generator) expressionStatement(expr ast.Expression, result bool) {
+	switch expr := expr.(type) {
+	case *ast.IfExpression:
+		g.line("if ($nuru.truthy(%s)) {", g.expression(expr.Condition))
+		g.block(expr.Consequence, result)
+		for expr.Alternative != nil {
+			// 'sivyo ikiwa' is an 'ikiwa' on its own in the 'sivyo' block
+			if len(expr.Alternative.Statements) == 1 {
+				if es, ok := expr.Alternative.Statements[0].(*ast.ExpressionStatement); ok {
+					if elseIf, ok := es.Expression.(*ast.IfExpression); ok {
+						expr = elseIf
+						g.line("} else if ($nuru.truthy(%s)) {", g.expression(expr.Condition))
+						g.block(expr.Consequence, result)
+						continue
+					}
+				}
+			}
+			g.line("} else {")
+			g.block(expr.Alternative, result)
+			break
+		}
+		g.line("}")
+	case *ast.WhileExpression:
+		g.line("while ($nuru.truthy(%s)) {", g.expression(expr.Condition))
+		g.block(expr.Consequence, false)
+		g.line("}")
+	case *ast.DoWhileExpression:
+		g.line("do {")
+		g.block(expr.Consequence, false)
+		g.line("} while ($nuru.truthy(%s));", g.expression(expr.Condition))
+	case *ast.ForIn:
+		g.forIn(expr)
+	case *ast.SwitchExpression:
+		g.switchStatement(expr, result)
+	case *ast.MatchExpression:
+		g.matchExpression(expr, result)
+	case *ast.TryExpression:
+		g.line("try {")
+		g.block(expr.Block, result)
+		caught := g.temp("kosa")
+		g.line("} catch (%s) {", caught)
+		if expr.Identifier != nil {
+			g.indent++
+			g.line("var %s = $nuru.message(%s);", g.declare(expr.Identifier.Value), caught)
+			g.indent--
+		}
+		g.block(expr.Catch, result)
+		g.line("}")
+	case *ast.Break:
+		g.line("break;")
+	case *ast.Continue:
+		g.line("continue;")
+	case *ast.AssignmentExpression:
+		g.line("%s;", g.expression(expr))
+	case *ast.PostfixExpression:
+		if result {
+			g.line("return %s;", g.expression(expr))
+			return
+		}
+		// the old value isn't needed when 'i++' is a statement of its own
+		name := g.name(expr.Token.Literal)
+		g.line("%s = $nuru.op(%s, %s, 1);", name, quote(expr.Operator[:1]), name)
+	default:
+		if result {
+			g.line("return %s;", g.expression(expr))
+		} else {
+			g.line("%s;", g.expression(expr))
+		}
+	}
+}
+
+func (g *generator) forIn(loop *ast.ForIn) {
+	target := g.declare(loop.Value)
+	if loop.Key != "" {
+		target = "[" + g.declare(loop.Key) + ", " + target + "]"
+	} else {
+		target = "[, " + target + "]"
+	}
+	g.line("for (var %s of $nuru.iterate(%s)) {", target, g.expression(loop.Iterable))
+	g.block(loop.Block, false)
+	g.line("}")
+}
+
+// comprehension writes '[x * 2 kwa x ktk orodha]' as a loop in a function
+// that is called at once
+func (g *generator) comprehension(comp *ast.Comprehension) string {
+	collected := g.temp("r")
+	target := "[, " + g.name(comp.LoopValue) + "]"
+	if comp.LoopKey != "" {
+		target = "[" + g.name(comp.LoopKey) + ", " + g.name(comp.LoopValue) + "]"
+	}
+	start, add := "[]", collected+".push("+g.expression(comp.Value)+")"
+	if comp.Key != nil {
+		start, add = "new Map()", collected+".set("+g.expression(comp.Key)+", "+g.expression(comp.Value)+")"
+	}
+	if comp.Condition != nil {
+		add = "if ($nuru.truthy(" + g.expression(comp.Condition) + ")) " + add
+	}
+	return fmt.Sprintf("(() => { const %s = %s; for (const %s of $nuru.iterate(%s)) { %s; } return %s; })()",
+		collected, start, target, g.expression(comp.Iterable), add, collected)
+}
+
+// matchExpression writes 'linganisha' as a chain of ifs. $nuru.match gives
+// the values for the names in a pattern, or null if the value doesn't fit.
+func (g *generator) matchExpression(m *ast.MatchExpression, result bool) {
+	value, found := g.temp("linganisha"), g.temp("m")
+	vars := []string{value + " = " + g.expression(m.Value), found}
+	for _, arm := range m.Arms {
+		for _, name := range arm.Names() {
+			vars = append(vars, g.declare(name.Value))
+		}
+	}
+	g.line("var %s;", strings.Join(vars, ", "))
+
+	keyword := "if"
+	for _, arm := range m.Arms {
+		condition := fmt.Sprintf("(%s = $nuru.match(%s, %s)) !== null", found, value, g.pattern(arm.Pattern))
+		if names := arm.Names(); len(names) > 0 {
+			targets := make([]string, len(names))
+			for i, name := range names {
+				targets[i] = g.name(name.Value)
+			}
+			condition += fmt.Sprintf(" && ([%s] = %s, true)", strings.Join(targets, ", "), found)
+		}
+		if arm.Guard != nil {
+			condition += fmt.Sprintf(" && $nuru.truthy(%s)", g.expression(arm.Guard))
+		}
+		g.line("%s (%s) {", keyword, condition)
+		g.block(arm.Body, result)
+		keyword = "} else if"
+	}
+	if keyword != "if" {
+		g.line("}")
+	}
+}
+
+// pattern describes a pattern of 'linganisha' for $nuru.match
+func (g *generator) pattern(pattern ast.Expression) string {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value == "_" {
+			return "{}"
+		}
+		return "{ bind: true }"
+	case *ast.ArrayLiteral:
+		var elements []string
+		rest := ""
+		for _, element := range pattern.Elements {
+			if spread, ok := element.(*ast.SpreadExpression); ok {
+				rest = ", rest: " + g.pattern(spread.Value)
+				continue
+			}
+			elements = append(elements, g.pattern(element))
+		}
+		return "{ array: [" + strings.Join(elements, ", ") + "]" + rest + " }"
+	case *ast.DictLiteral:
+		pairs := make([]string, len(pattern.Keys))
+		for i, key := range pattern.Keys {
+			pairs[i] = "[" + g.expression(key) + ", " + g.pattern(pattern.Pairs[key]) + "]"
+		}
+		return "{ dict: [" + strings.Join(pairs, ", ") + "] }"
+	}
+	return "{ value: " + g.expression(pattern) + " }"
+}
+
+// switchStatement writes 'badili' as a chain of ifs, since a 'vunja' inside
+// it leaves the loop around it, not the 'badili' as in a JavaScript switch
+func (g *generator) switchStatement(sw *ast.SwitchExpression, result bool) {
+	value := g.temp("badili")
+	g.line("var %s = %s;", value, g.expression(sw.Value))
+
+	keyword := "if"
+	var fallback *ast.CaseExpression
+	for _, choice := range sw.Choices {
+		if choice.Default {
+			fallback = choice
+			continue
+		}
+		conditions := make([]string, len(choice.Expr))
+		for i, expr := range choice.Expr {
+			conditions[i] = fmt.Sprintf("$nuru.same(%s, %s)", value, g.expression(expr))
+		}
+		g.line("%s (%s) {", keyword, strings.Join(conditions, " || "))
+		g.block(choice.Block, result)
+		g.line("}")
+		keyword = "else if"
+	}
+
+	if fallback != nil {
+		if keyword == "if" {
+			g.line("{")
+		} else {
+			g.line("else {")
+		}
+		g.block(fallback.Block, result)
+		g.line("}")
+	}
+}
+
+func (g *generator) class(class *ast.ClassStatement) {
+	g.line("var %s = $nuru.muundo(%s,", g.declare(class.Name.Value), quote(class.Name.Value))
+	g.indent++
+
+	g.line("function () {")
+	g.indent++
+	for _, field := range class.Fields {
+		g.line("this[%s] = %s;", quote(field.Name.Value), g.expression(field.Value))
+	}
+	g.indent--
+	g.line("},")
+
+	if class.Constructor != nil {
+		g.line("%s {", g.function("function", class.Constructor))
+		g.block(class.Constructor.Body, false)
+		g.line("},")
+	} else {
+		g.line("null,")
+	}
+
+	g.line("{")
+	g.indent++
+	for _, method := range class.Methods {
+		g.line("%s: %s {", quote(method.Name.Value), g.function("function", method.Function))
+		g.functionBody(method.Function)
+		g.line("},")
+	}
+	g.indent--
+	g.line("}")
+
+	g.indent--
+	g.line(");")
+}
+
+// function gives the head of a function with the parameters of fn
+func (g *generator) function(keyword string, fn *ast.FunctionLiteral) string {
+	params := make([]string, len(fn.Parameters))
+	for i, p := range fn.Parameters {
+		params[i] = g.declare(p.Value)
+	}
+	if fn.Variadic {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
+	if keyword == "=>" {
+		return "(" + strings.Join(params, ", ") + ") =>"
+	}
+	return keyword + " (" + strings.Join(params, ", ") + ")"
+}
+
+// expression gives the JavaScript for expr
+func (g *generator) expression(expr ast.Expression) string {
+	switch expr := expr.(type) {
+	case nil:
+		return "null"
+	case *ast.IntegerLiteral:
+		// a JavaScript number only holds whole numbers exactly up to 2^53
+		if expr.Value > maxSafeInteger || expr.Value < -maxSafeInteger {
+			g.fail(expr, "namba "+expr.Token.Literal)
+		}
+		return strconv.FormatInt(expr.Value, 10)
+	case *ast.BigIntegerLiteral:
+		g.fail(expr, "namba "+expr.Token.Literal)
+		return "null"
+	case *ast.FloatLiteral:
+		return strconv.FormatFloat(expr.Value, 'g', -1, 64)
+	case *ast.StringLiteral:
+		return quote(expr.Value)
+	case *ast.Boolean:
+		return strconv.FormatBool(expr.Value)
+	case *ast.Null:
+		return "null"
+	case *ast.Identifier:
+		if _, ok := evaluator.LookupBuiltin(expr.Value); ok && !isRuntimeBuiltin(expr.Value) {
+			g.use(expr)
+		}
+		for _, module := range evaluator.ModuleNames() {
+			if module == expr.Value {
+				g.use(expr)
+			}
+		}
+		if _, ok := evaluator.LookupGlobal(expr.Value); ok {
+			g.use(expr)
+		}
+		return g.name(expr.Value)
+	case *ast.ThisExpression:
+		return "this"
+	case *ast.TemplateLiteral:
+		parts := make([]string, len(expr.Parts))
+		for i, part := range expr.Parts {
+			parts[i] = "$nuru.str(" + g.expression(part) + ")"
+		}
+		if len(parts) == 0 {
+			return `""`
+		}
+		return "(" + strings.Join(parts, " + ") + ")"
+	case *ast.ArrayLiteral:
+		return "[" + strings.Join(g.expressions(expr.Elements), ", ") + "]"
+	case *ast.DictLiteral:
+		pairs := make([]string, len(expr.Keys))
+		for i, key := range expr.Keys {
+			pairs[i] = "[" + g.expression(key) + ", " + g.expression(expr.Pairs[key]) + "]"
+		}
+		return "new Map([" + strings.Join(pairs, ", ") + "])"
+	case *ast.Comprehension:
+		return g.comprehension(expr)
+	case *ast.PrefixExpression:
+		if expr.Operator == "!" {
+			return "$nuru.not(" + g.expression(expr.Right) + ")"
+		}
+		return fmt.Sprintf("$nuru.neg(%s, %s)", quote(expr.Operator), g.expression(expr.Right))
+	case *ast.InfixExpression:
+		if expr.Operator == "??" {
+			return fmt.Sprintf("(%s ?? %s)", g.expression(expr.Left), g.expression(expr.Right))
+		}
+		return fmt.Sprintf("$nuru.op(%s, %s, %s)", quote(expr.Operator), g.expression(expr.Left), g.expression(expr.Right))
+	case *ast.PostfixExpression:
+		name := g.name(expr.Token.Literal)
+		return fmt.Sprintf("((v) => (%s = $nuru.op(%s, v, 1), v))(%s)", name, quote(expr.Operator[:1]), name)
+	case *ast.IndexExpression:
+		if expr.Optional {
+			return fmt.Sprintf("((v) => v == null ? null : $nuru.index(v, %s))(%s)", g.expression(expr.Index), g.expression(expr.Left))
+		}
+		return fmt.Sprintf("$nuru.index(%s, %s)", g.expression(expr.Left), g.expression(expr.Index))
+	case *ast.SliceExpression:
+		bounds := []string{"null", "null", "null"}
+		for i, bound := range []ast.Expression{expr.Start, expr.Stop, expr.Step} {
+			if bound != nil {
+				bounds[i] = g.expression(bound)
+			}
+		}
+		if expr.Optional {
+			return fmt.Sprintf("((v) => v == null ? null : $nuru.slice(v, %s))(%s)", strings.Join(bounds, ", "), g.expression(expr.Left))
+		}
+		return fmt.Sprintf("$nuru.slice(%s, %s)", g.expression(expr.Left), strings.Join(bounds, ", "))
+	case *ast.PropertyExpression:
+		return fmt.Sprintf("$nuru.prop(%s, %s)", g.expression(expr.Object), quote(expr.Property.Value))
+	case *ast.CallExpression:
+		for _, arg := range expr.Arguments {
+			if _, ok := arg.(*ast.NamedArgument); ok {
+				g.fail(arg, "hoja yenye jina")
+			}
+		}
+		callee := g.expression(expr.Function)
+		if _, ok := expr.Function.(*ast.FunctionLiteral); ok {
+			callee = "(" + callee + ")"
+		}
+		return callee + "(" + strings.Join(g.expressions(expr.Arguments), ", ") + ")"
+	case *ast.ConditionalExpression:
+		return fmt.Sprintf("($nuru.truthy(%s) ? %s : %s)", g.expression(expr.Condition), g.expression(expr.Consequence), g.expression(expr.Alternative))
+	case *ast.ComparisonChain:
+		operands := g.expressions(expr.Operands)
+		operators := make([]string, len(expr.Operators))
+		for i, operator := range expr.Operators {
+			operators[i] = quote(operator)
+		}
+		for i := 1; i < len(operands); i++ {
+			operands[i] = "() => " + operands[i]
+		}
+		return "$nuru.chain([" + strings.Join(operators, ", ") + "], " + strings.Join(operands, ", ") + ")"
+	case *ast.SpreadExpression:
+		return "...$nuru.spread(" + g.expression(expr.Value) + ")"
+	case *ast.YieldExpression:
+		return "(yield " + g.expression(expr.Value) + ")"
+	case *ast.AssignmentExpression:
+		return g.assignment(expr)
+	case *ast.FunctionLiteral:
+		return g.functionLiteral(expr)
+	case *ast.IfExpression, *ast.MatchExpression:
+		// an 'ikiwa' or 'linganisha' giving a value becomes a function that is
+		// called at once
+		body := g.capture(func() {
+			g.indent++
+			g.expressionStatement(expr, true)
+			g.indent--
+		})
+		return "(() => {\n" + body + strings.Repeat("  ", g.indent) + "})()"
+	default:
+		g.fail(expr, strings.TrimPrefix(fmt.Sprintf("%T", expr), "*ast."))
+		return "null"
+	}
+}
+
+func (g *generator) expressions(exprs []ast.Expression) []string {
+	out := make([]string, len(exprs))
+	for i, expr := range exprs {
+		out[i] = g.expression(expr)
+	}
+	return out
+}
+
+// functionLiteral writes fn as an arrow function, which keeps the 'hii' of
+// the method it is made in, as Nuru does
+func (g *generator) functionLiteral(fn *ast.FunctionLiteral) string {
+	body := g.capture(func() { g.functionBody(fn) })
+	return g.function("=>", fn) + " {\n" + body + strings.Repeat("  ", g.indent) + "}"
+}
+
+// functionBody writes the statements of fn. Those of a generator go in a
+// JavaScript generator function, where every 'toa' becomes a yield.
+func (g *generator) functionBody(fn *ast.FunctionLiteral) {
+	if !fn.Generator {
+		g.block(fn.Body, true)
+		return
+	}
+	g.indent++
+	g.line("return $nuru.generator(function* () {")
+	g.block(fn.Body, false)
+	g.line("}.call(this));")
+	g.indent--
+}
+
+// capture gives what write writes, instead of adding it to the output
+func (g *generator) capture(write func()) string {
+	saved := g.out
+	g.out = &bytes.Buffer{}
+	write()
+	body := g.out.String()
+	g.out = saved
+	return body
+}
+
+func (g *generator) assignment(node *ast.AssignmentExpression) string {
+	value := g.expression(node.Value)
+	operator := ""
+	if op := node.Token.Literal; len(op) >= 2 {
+		operator = op[:len(op)-1]
+	}
+
+	switch left := node.Left.(type) {
+	case *ast.Identifier:
+		name := g.name(left.Value)
+		if operator != "" {
+			value = fmt.Sprintf("$nuru.op(%s, %s, %s)", quote(operator), name, value)
+		}
+		return name + " = " + value
+	case *ast.IndexExpression:
+		if operator != "" {
+			return fmt.Sprintf("$nuru.updateIndex(%s, %s, %s, %s)", g.expression(left.Left), g.expression(left.Index), quote(operator), value)
+		}
+		return fmt.Sprintf("$nuru.setIndex(%s, %s, %s)", g.expression(left.Left), g.expression(left.Index), value)
+	case *ast.PropertyExpression:
+		if operator != "" {
+			return fmt.Sprintf("$nuru.updateProp(%s, %s, %s, %s)", g.expression(left.Object), quote(left.Property.Value), quote(operator), value)
+		}
+		return fmt.Sprintf("$nuru.setProp(%s, %s, %s)", g.expression(left.Object), quote(left.Property.Value), value)
+	default:
+		g.fail(node, "kubadilisha "+node.Left.String())
+		return "null"
+	}
+}
+
+func (g *generator) use(ident *ast.Identifier) {
+	if _, ok := g.used[ident.Value]; !ok {
+		g.used[ident.Value] = ident.Pos()
+	}
+}
+
+func isRuntimeBuiltin(name string) bool {
+	for _, b := range builtins {
+		if b == name {
+			return true
+		}
+	}
+	return false
+}
+
+// quote writes s as a JavaScript string
+func quote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,169 @@
+package jsgen
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+func generate(t *testing.T, input string) (string, error) {
+	t.Helper()
+	p := parser.New(lexer.NewFile("test.nr", input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+	return Generate(program)
+}
+
+func TestGeneratedProgramsRun(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`andika("habari", 1, 2.5, kweli, tupu)`, "habari 1 2.5 kweli null"},
+		{`fanya fib = unda(n) { kama (n < 2) { n } sivyo { fib(n - 1) + fib(n - 2) } }; fib(15)`, "610"},
+		{`andika(7 / 2, 6 / 2, 2 ** 10, "a" * 3, [1, 2] + [3], !tupu, -3)`, "3.5 3 1024 aaa [1, 2, 3] kweli -3"},
+		{`fanya k = {"a": 1}; k["a"] += 10; k["b"] = 2; andika(k["a"], k["c"], "b" ktk k)`, "11 null kweli"},
+		{`kwa i, v ktk ["x", "y"] { andika(i, v) }`, "0 x\n1 y"},
+		{`kwa v ktk mpaka(0, 10, 3) { kama (v == 6) { endelea }; andika(v) }`, "0\n3\n9"},
+		{`fanya i = 0; wakati (i < 10) { i++; kama (i == 4) { vunja } }; i`, "4"},
+		{`fanya i = 0; fanya { i += 2 } wakati (i < 5); i`, "6"},
+		{`fanya i = 1; fanya j = i++; fanya f = unda(n) { n-- }; andika(i, j, f(4))`, "2 1 4"},
+		{`badili (3) { ikiwa 1, 2 { andika("ndogo") } ikiwa 3 { andika("tatu") } kawaida { andika("nyingi") } }`, "tatu"},
+		{`kwa x ktk [1, 2] { badili (x) { ikiwa 1 { vunja } kawaida { andika(x) } }; andika("baada", x) }; andika("mwisho")`, "mwisho"},
+		{`fanya x = kama (sikweli) { "ndio" } sivyo { "hapana" }; x`, "hapana"},
+		{`jaribu { 5 / 0 } shika (e) { andika("kosa:", e) }`, "kosa: Huwezi kugawanya kwa sifuri"},
+		{`jaribu { tupa "imeshindikana" } shika (e) { andika(e) }`, "imeshindikana"},
+		{`muundo Mtu {
+			fanya umri = 0
+			unda(jina) { hii.jina = jina }
+			salamu() {
+				fanya f = unda() { "Habari " + hii.jina }
+				f()
+			}
+		}
+		fanya m = Mtu("Asha")
+		m.umri += 2
+		andika(m.salamu(), m, aina(m), Mtu)
+		andika("${m.jina} ana miaka ${m.umri}")`, "Habari Asha Mtu{jina: Asha, umri: 2} KITU <muundo Mtu>\nAsha ana miaka 2"},
+		{`fanya new = 5; new`, "5"},
+		{`fanya s = "ŋombe 🐄"; andika(idadi(s), s[-1], s[1:3])`, "7 🐄 om"},
+		{`fanya a = [1, {"k": [2]}]; a[1]["s"] = a; fanya b = [1, {"k": [2]}]; b[1]["s"] = b; andika(a == b, [1] != [1], [1] == [2], {"a": 1} == {"a": 2}, sawa_kabisa({"a": [1]}, {"a": [1]}))`, "kweli sikweli sikweli sikweli kweli"},
+		{`fanya [a, b] = [1, 2]; fanya {c} = {"c": 3}; andika(a, b, c)`, "1 2 3"},
+		{`fanya [a, b] = [1]`, "Kosa: Samahani, [a, b] inahitaji vitu 2, lakini imepewa 1"},
+		{`fanya f = unda(a, ...b) { a + idadi(b) }; fanya x = [1, 2]; andika(f(...x, 3), [0, ...x])`, "3 [0, 1, 2]"},
+		{`andika([...5])`, "Kosa: Samahani, ... inahitaji ORODHA, sio NAMBA"},
+		{`andika(2 ** 3 ** 2, -2 ** 2, 2 ** -1)`, "512 -4 0.5"},
+		{`0 ** -1`, "Kosa: Huwezi kugawanya kwa sifuri"},
+		{`fanya x = 0; andika(x < 0 ? "hasi" : x == 0 ? "sifuri" : "chanya", tupu ? 1 : 2)`, "sifuri 2"},
+		{`fanya d = {"a": 1}; andika(d["a"] ?? 5, d["b"] ?? 5, sikweli ?? 1, 0 ?? 1)`, "1 5 sikweli 0"},
+		{`fanya d = {"a": {"b": 2}}; fanya t = tupu; andika(d?["a"]?["b"], d?["x"]?["b"], t?[1 / 0])`, "2 null null"},
+		{`andika(6 & 3, 6 | 3, 6 ^ 3, ~5, 1 << 40, -16 >> 2)`, "2 7 5 -6 1099511627776 -4"},
+		{`fanya x = 17; x \= 5; andika(7 \ 2, -7 \ 2, 7.5 \ 2, x)`, "3 -4 3 3"},
+		{`1 \ 0`, "Kosa: Huwezi kugawanya kwa sifuri"},
+		{`andika("a" \ 2)`, "Kosa: Aina Hazilingani: NENO \\ NAMBA"},
+		{`andika(9007199254740991 - 1, 2 ** 52, 1.5 ** 1000 > 1)`, "9007199254740990 4503599627370496 kweli"},
+		{`fanya i = 9007199254740991; i++`, "Kosa: Samahani, 9007199254740991 + 1 ni kubwa mno kwa namba za JavaScript"},
+		{`andika(2 ** 64)`, "Kosa: Samahani, 2 ** 64 ni kubwa mno kwa namba za JavaScript"},
+		{`andika(1 << 60)`, "Kosa: Samahani, 1 << 60 ni kubwa mno kwa namba za JavaScript"},
+		{`fanya x = 5; andika(0 < x < 10, 0 < x < 3, 1 > 2 < 1 / 0)`, "kweli sikweli sikweli"},
+		{`fanya a = [1, 2, 3]; a[-1] = 9; a[-3] += 5; andika(a, a[-4], "habari"[-2], "habari"[0])`, "[6, 2, 9] null r h"},
+		{`fanya a = [1]; a[1] = 0`, "Kosa: Index imezidi idadi ya elements"},
+		{`fanya o = [0, 1, 2, 3, 4, 5]; andika(o[1:4], o[::-2], o[-10:2], "habari"[:3], "habari"[::-1])`, "[1, 2, 3] [5, 3, 1] [0, 1] hab irabah"},
+		{`[1][::0]`, "Kosa: Samahani, hatua ya kukata haiwezi kuwa sifuri"},
+		{`fanya o = [-1, 2, 3]; fanya d = {x: x * x kwa x ktk o kama x > 1}; andika([x * 2 kwa x ktk o kama x > 0], [i kwa i, x ktk o kama x < 0], d[1], d[3])`, "[4, 6] [0] null 9"},
+		{`andika([x kwa x ktk 5])`, "Kosa: Huwezi kufanya operesheni hii na NAMBA"},
+		{`fanya f = (a, ...b) => a + idadi(b); fanya ongeza = x => y => x + y; andika(f(1, 2, 3), ongeza(1)(2), ((x) => x * 2)(4), (unda(x) { x })(5))`, "3 3 8 5"},
+		{`fanya asili = unda() { fanya n = 0; wakati (kweli) { toa n; n++ } }
+		  fanya mara2 = (g) => { kwa x ktk g { toa x * 2 } }
+		  fanya g = mara2(asili())
+		  kwa i, x ktk g { kama (i == 2) { vunja } }
+		  fanya y = tupu
+		  kwa x ktk g { y = x; vunja }
+		  muundo M { vyote() { toa 1; toa 2 } }
+		  andika(y, aina(g), g, [x kwa x ktk M().vyote()])`, "6 KIZALISHI <kizalishi> [1, 2]"},
+		{`muundo Hesabu {
+		      fanya sasa = 0
+		      anzisha() { hii.sasa = 0 }
+		      ifuatayo() { kama (hii.sasa == 3) { rudisha tupu }; hii.sasa += 1; hii.sasa }
+		  }
+		  fanya h = Hesabu()
+		  fanya d = {"n": 0}
+		  d["ifuatayo"] = unda() { kama (d["n"] == 2) { rudisha tupu }; d["n"] += 1; d["n"] }
+		  andika([x kwa x ktk h], [x kwa x ktk h], [x kwa i, x ktk d], [k kwa k, v ktk {"ifuatayo": 1}])`, "[1, 2, 3] [1, 2, 3] [1, 2] [ifuatayo]"},
+		{`fanya eleza = unda(x) {
+		      linganisha (x) {
+		          0 => "sifuri",
+		          [a, b] kama (a > b) => "kubwa kwanza",
+		          [a, ...baki] => "mwanzo ${a} ${baki}",
+		          {"aina": "mtu", jina} => "mtu ${jina}",
+		          _ => "kingine",
+		      }
+		  }
+		  fanya a = 1
+		  fanya pili = linganisha ([[1, 2]]) { [[_, n]] => n }
+		  andika(eleza(0), eleza([3, 1]), eleza([1, 3]), eleza({"aina": "mtu", "jina": "Asha"}), eleza("0"), pili, linganisha (3) { 1 => 1 })`,
+			"sifuri kubwa kwanza mwanzo 1 [3] mtu Asha kingine 2 null"},
+		{`andika(linganisha ([1.0, "a"]) { [1, "a"] => "y", _ => "n" }, linganisha ([kweli]) { [1] => "y", _ => "n" })`, "y n"},
+		{`1 + kweli`, "Kosa: Aina Hazilingani: NAMBA + BOOLEAN"},
+		{`andika(hakuna)`, "Kosa: Neno Halifahamiki: hakuna"},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		js, err := generate(t, tt.input)
+		if err != nil {
+			t.Errorf("%q: %s", tt.input, err)
+			continue
+		}
+		file := filepath.Join(dir, "programu.js")
+		if err := os.WriteFile(file, []byte(js), 0644); err != nil {
+			t.Fatal(err)
+		}
+		out, err := exec.Command(node, file).CombinedOutput()
+		if err != nil {
+			t.Errorf("test %d: node failed: %s\n%s", i, err, out)
+			continue
+		}
+		if got := strings.TrimSpace(string(out)); got != tt.expected {
+			t.Errorf("test %d: wrong output for %q.\nwant=%q\ngot=%q", i, tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`tumia hesabu`, "test.nr, Mstari 1, Safu 1: tumia haiwezi kugeuzwa kuwa JavaScript"},
+		{`andika(soma_faili("x"))`, "test.nr, Mstari 1, Safu 8: soma_faili haiwezi kugeuzwa kuwa JavaScript"},
+		{`json.andika({})`, "test.nr, Mstari 1, Safu 1: json haiwezi kugeuzwa kuwa JavaScript"},
+		{`thabiti PI = 3`, "test.nr, Mstari 1, Safu 1: thabiti haiwezi kugeuzwa kuwa JavaScript"},
+		{`andika(9007199254740993)`, "test.nr, Mstari 1, Safu 8: namba 9007199254740993 haiwezi kugeuzwa kuwa JavaScript"},
+		{`andika(18446744073709551616)`, "test.nr, Mstari 1, Safu 8: namba 18446744073709551616 haiwezi kugeuzwa kuwa JavaScript"},
+	}
+
+	for _, tt := range tests {
+		_, err := generate(t, tt.input)
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("%q: wrong error. want=%q, got=%v", tt.input, tt.expected, err)
+		}
+	}
+
+	// a program may declare its own function with the name of a builtin
+	if _, err := generate(t, `fanya soma_faili = unda(x) { x }; soma_faili(1)`); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,502 @@
+package jsgen
+
+// runtime is put at the top of every generated program. It holds what Nuru
+// does differently from JavaScript: how values are shown, which operators
+// work on which types and the errors they give, and the builtins that can
+// run outside the interpreter.
+const runtime = `var $nuru = (function () {
+  "use strict";
+
+  class Kosa extends Error {}
+
+  function kosa(message) {
+    return new Kosa(message);
+  }
+
+  class Range {
+    constructor(start, end, step) {
+      this.start = start;
+      this.end = end;
+      this.step = step;
+    }
+  }
+
+  // Generator is what calling a function that uses 'toa' gives. Its body is
+  // a JavaScript generator that is only ever moved on by iterate.
+  class Generator {
+    constructor(body) {
+      this.body = body;
+      this.index = 0;
+    }
+  }
+
+  function generator(body) {
+    return new Generator(body);
+  }
+
+  function aina(v) {
+    if (v === null || v === undefined) return "TUPU";
+    switch (typeof v) {
+      case "number": return Number.isInteger(v) ? "NAMBA" : "DESIMALI";
+      case "string": return "NENO";
+      case "boolean": return "BOOLEAN";
+      case "function":
+        if (v.$muundo) return "MUUNDO";
+        return v.$builtin ? "YA_NDANI" : "UNDO (FUNCTION)";
+    }
+    if (Array.isArray(v)) return "ORODHA";
+    if (v instanceof Map) return "KAMUSI";
+    if (v instanceof Range) return "MPAKA";
+    if (v instanceof Generator) return "KIZALISHI";
+    return "KITU";
+  }
+
+  function isNumber(t) {
+    return t === "NAMBA" || t === "DESIMALI";
+  }
+
+  function inspect(v) {
+    switch (aina(v)) {
+      case "TUPU": return "null";
+      case "BOOLEAN": return v ? "kweli" : "sikweli";
+      case "NENO": return v;
+      case "NAMBA": case "DESIMALI": return String(v);
+      case "ORODHA": return "[" + v.map(inspect).join(", ") + "]";
+      case "KAMUSI": {
+        const pairs = [];
+        v.forEach((value, key) => pairs.push(inspect(key) + ": " + inspect(value)));
+        return "{" + pairs.join(", ") + "}";
+      }
+      case "MPAKA":
+        if (v.step === 1) return "mpaka(" + v.start + ", " + v.end + ")";
+        return "mpaka(" + v.start + ", " + v.end + ", " + v.step + ")";
+      case "MUUNDO": return "<muundo " + v.$muundo + ">";
+      case "KIZALISHI": return "<kizalishi>";
+      case "YA_NDANI": return "builtin function";
+      case "UNDO (FUNCTION)": return "unda";
+      default: {
+        const fields = Object.keys(v).sort().map((name) => name + ": " + inspect(v[name]));
+        return v.$muundo + "{" + fields.join(", ") + "}";
+      }
+    }
+  }
+
+  // str is how a value is put into a template string
+  function str(v) {
+    return typeof v === "string" ? v : inspect(v);
+  }
+
+  function truthy(v) {
+    return v !== null && v !== undefined && v !== false;
+  }
+
+  function not(v) {
+    return !truthy(v);
+  }
+
+  function neg(operator, v) {
+    if (operator === "~") {
+      if (aina(v) !== "NAMBA") throw kosa("Operesheni haieleweki: ~" + aina(v));
+      return Number(~BigInt(v));
+    }
+    if (!isNumber(aina(v))) throw kosa("Operesheni Haielweki: -" + aina(v));
+    return operator === "-" ? -v : v;
+  }
+
+  // bits works on whole numbers as BigInts, since JavaScript's own bitwise
+  // operators cut them down to 32 bits
+  function bits(operator, a, b) {
+    const x = BigInt(a), y = BigInt(b);
+    switch (operator) {
+      case "&": return Number(x & y);
+      case "|": return Number(x | y);
+      case "^": return Number(x ^ y);
+    }
+    if (y < 0n) throw kosa("Samahani, huwezi kusogeza biti mara " + b);
+    return Number(operator === "<<" ? x << y : x >> y);
+  }
+
+  // exact refuses a number worked out from two whole ones that is too big
+  // for a JavaScript number to hold exactly, which the interpreter would
+  // have given as a NAMBA_KUBWA
+  function exact(v, operator, a, b) {
+    if (Number.isSafeInteger(a) && Number.isSafeInteger(b) && Math.abs(v) > Number.MAX_SAFE_INTEGER) {
+      throw kosa("Samahani, " + a + " " + operator + " " + b + " ni kubwa mno kwa namba za JavaScript");
+    }
+    return v;
+  }
+
+  function arithmetic(operator, a, b, ta, tb) {
+    switch (operator) {
+      case "+": return exact(a + b, operator, a, b);
+      case "-": return exact(a - b, operator, a, b);
+      case "*": return exact(a * b, operator, a, b);
+      case "**":
+        if (a === 0 && b < 0) throw kosa("Huwezi kugawanya kwa sifuri");
+        return exact(Math.pow(a, b), operator, a, b);
+      case "/":
+        if (b === 0) throw kosa("Huwezi kugawanya kwa sifuri");
+        return a / b;
+      case "%":
+        if (ta !== "NAMBA" || tb !== "NAMBA") break;
+        if (b === 0) throw kosa("Huwezi kugawanya kwa sifuri");
+        return a % b;
+      case "\\":
+        if (!isNumber(ta) || !isNumber(tb)) break;
+        if (b === 0) throw kosa("Huwezi kugawanya kwa sifuri");
+        return Math.floor(a / b);
+      case "&": case "|": case "^": case "<<": case ">>":
+        if (ta !== "NAMBA" || tb !== "NAMBA") break;
+        return exact(bits(operator, a, b), operator, a, b);
+      case "<": return a < b;
+      case "<=": return a <= b;
+      case ">": return a > b;
+      case ">=": return a >= b;
+      case "==": return a === b;
+      case "!=": return a !== b;
+    }
+    throw kosa("Operesheni Haielweki: " + ta + " " + operator + " " + tb);
+  }
+
+  function repeat(list, times) {
+    let out = list;
+    for (let i = times; i > 1; i--) out = out.concat(list);
+    return out;
+  }
+
+  function contains(container, v) {
+    switch (aina(container)) {
+      case "NENO": return typeof v === "string" && container.includes(v);
+      case "ORODHA": return container.some((e) => e === v || (e === null && v === undefined));
+      case "KAMUSI": return container.has(v);
+      default: return false;
+    }
+  }
+
+  // equal compares arrays and dicts by what they hold. A pair already being
+  // compared further up counts as equal, so ones that hold themselves end.
+  function equal(a, b, strict, comparing = []) {
+    if (a === b) return true;
+    const ta = aina(a), tb = aina(b);
+    if (strict && ta !== tb) return false;
+    if (ta === "ORODHA" && tb === "ORODHA") {
+      if (a.length !== b.length) return false;
+    } else if (ta === "KAMUSI" && tb === "KAMUSI") {
+      if (a.size !== b.size) return false;
+    } else {
+      return (a ?? null) === (b ?? null);
+    }
+    if (comparing.some(([x, y]) => x === a && y === b)) return true;
+    comparing.push([a, b]);
+    try {
+      if (ta === "ORODHA") return a.every((e, i) => equal(e, b[i], strict, comparing));
+      for (const [k, v] of a) {
+        if (!b.has(k) || !equal(v, b.get(k), strict, comparing)) return false;
+      }
+      return true;
+    } finally {
+      comparing.pop();
+    }
+  }
+
+  function op(operator, a, b) {
+    if (a === undefined) a = null;
+    if (b === undefined) b = null;
+    const ta = aina(a), tb = aina(b);
+    if (ta === "NENO" && tb === "NENO") {
+      switch (operator) {
+        case "+": return a + b;
+        case "==": return a === b;
+        case "!=": return a !== b;
+      }
+      throw kosa("Operesheni Haielweki: " + ta + " " + operator + " " + tb);
+    }
+    if (operator === "+" && ta === "KAMUSI" && tb === "KAMUSI") return new Map([...a, ...b]);
+    if (operator === "+" && ta === "ORODHA" && tb === "ORODHA") return a.concat(b);
+    if (operator === "*" && ta === "ORODHA" && tb === "NAMBA") return repeat(a, b);
+    if (operator === "*" && ta === "NAMBA" && tb === "ORODHA") return repeat(b, a);
+    if (operator === "*" && ta === "NENO" && tb === "NAMBA") return a.repeat(Math.max(b, 0));
+    if (operator === "*" && ta === "NAMBA" && tb === "NENO") return b.repeat(Math.max(a, 0));
+    if (isNumber(ta) && isNumber(tb)) return arithmetic(operator, a, b, ta, tb);
+    if (operator === "ktk") return contains(b, a);
+    if (operator === "==") return equal(a, b, false);
+    if (operator === "!=") return !equal(a, b, false);
+    if (ta === "BOOLEAN" && tb === "BOOLEAN") {
+      if (operator === "&&") return a && b;
+      if (operator === "||") return a || b;
+      throw kosa("Operesheni Haielweki: " + ta + " " + operator + " " + tb);
+    }
+    if (ta !== tb) throw kosa("Aina Hazilingani: " + ta + " " + operator + " " + tb);
+    throw kosa("Operesheni Haielweki: " + ta + " " + operator + " " + tb);
+  }
+
+  function index(v, i) {
+    switch (aina(v)) {
+      case "ORODHA":
+        if (aina(i) !== "NAMBA") throw kosa("Tafadhali tumia number, sio: " + aina(i));
+        if (i < 0) i += v.length;
+        return i >= 0 && i < v.length ? v[i] : null;
+      case "NENO": {
+        if (aina(i) !== "NAMBA") throw kosa("Tafadhali tumia number, sio: " + aina(i));
+        const chars = Array.from(v);
+        if (i < 0) i += chars.length;
+        return i >= 0 && i < chars.length ? chars[i] : null;
+      }
+      case "KAMUSI":
+        return v.has(i) ? v.get(i) : null;
+    }
+    throw kosa("Operesheni hii haiwezekani kwa: " + aina(v));
+  }
+
+  // slice takes 'v[start:stop:step]' the same way as the interpreter, with
+  // null for a bound that was left out
+  function slice(v, start, stop, step) {
+    let items;
+    switch (aina(v)) {
+      case "ORODHA": items = v; break;
+      case "NENO": items = Array.from(v); break;
+      default: throw kosa("Operesheni hii haiwezekani kwa: " + aina(v));
+    }
+    for (const b of [start, stop, step]) {
+      if (b !== null && aina(b) !== "NAMBA") throw kosa("Tafadhali tumia number, sio: " + aina(b));
+    }
+    if (step === null) step = 1;
+    if (step === 0) throw kosa("Samahani, hatua ya kukata haiwezi kuwa sifuri");
+    const n = items.length;
+    const lower = step < 0 ? -1 : 0, upper = step < 0 ? n - 1 : n;
+    const bound = (b, def) => {
+      if (b === null) return def;
+      if (b < 0) return Math.max(b + n, lower);
+      return Math.min(b, upper);
+    };
+    const from = bound(start, step < 0 ? upper : lower), to = bound(stop, step < 0 ? lower : upper);
+    const out = [];
+    for (let i = from; step > 0 ? i < to : i > to; i += step) out.push(items[i]);
+    return aina(v) === "NENO" ? out.join("") : out;
+  }
+
+  function setIndex(v, i, value) {
+    switch (aina(v)) {
+      case "ORODHA":
+        if (aina(i) !== "NAMBA") throw kosa("Hauwezi kufanya opereshen hii na " + inspect(i));
+        if (i < 0) i += v.length;
+        if (i < 0 || i >= v.length) throw kosa("Index imezidi idadi ya elements");
+        v[i] = value;
+        return;
+      case "KAMUSI":
+        v.set(i, value);
+        return;
+    }
+    throw kosa(aina(v) + " haifanyi operation hii");
+  }
+
+  function updateIndex(v, i, operator, value) {
+    setIndex(v, i, op(operator, index(v, i), value));
+  }
+
+  // unpack gives the values that 'fanya [a, b] = v', or 'fanya {a, b} = v'
+  // when dict is set, gives to names
+  function unpack(v, names, dict) {
+    if (dict) {
+      const pattern = "{" + names.join(", ") + "}";
+      if (aina(v) !== "KAMUSI") throw kosa("Samahani, " + pattern + " inahitaji KAMUSI, sio " + aina(v));
+      return names.map((name) => {
+        if (!v.has(name)) throw kosa("Samahani, kamusi haina ufunguo " + JSON.stringify(name));
+        return v.get(name);
+      });
+    }
+    const pattern = "[" + names.join(", ") + "]";
+    if (aina(v) !== "ORODHA") throw kosa("Samahani, " + pattern + " inahitaji ORODHA, sio " + aina(v));
+    if (v.length !== names.length) throw kosa("Samahani, " + pattern + " inahitaji vitu " + names.length + ", lakini imepewa " + v.length);
+    return v;
+  }
+
+  // chain works out 0 < x < 10, the operands after the first coming as
+  // functions so those after a comparison that fails are never worked out
+  function chain(operators, first, ...rest) {
+    let left = first;
+    for (let i = 0; i < operators.length; i++) {
+      const right = rest[i]();
+      if (!op(operators[i], left, right)) return false;
+      left = right;
+    }
+    return true;
+  }
+
+  // spread checks that what '...' is given can be spread
+  function spread(v) {
+    if (aina(v) !== "ORODHA") throw kosa("Samahani, ... inahitaji ORODHA, sio " + aina(v));
+    return v;
+  }
+
+  function isInstance(v) {
+    return aina(v) === "KITU" && typeof v.$muundo === "string";
+  }
+
+  function prop(v, name) {
+    if (isInstance(v)) {
+      if (Object.prototype.hasOwnProperty.call(v, name)) return v[name];
+      if (typeof v[name] === "function") return v[name].bind(v);
+      throw kosa(v.$muundo + " haina " + name);
+    }
+    throw kosa(aina(v) + " haina " + name);
+  }
+
+  function setProp(v, name, value) {
+    if (!isInstance(v)) throw kosa("Huwezi kubadilisha " + name + " ya " + aina(v));
+    v[name] = value;
+  }
+
+  function updateProp(v, name, operator, value) {
+    if (isInstance(v) && !Object.prototype.hasOwnProperty.call(v, name)) throw kosa(v.$muundo + " haina " + name);
+    setProp(v, name, op(operator, prop(v, name), value));
+  }
+
+  // iterate gives the key and value of everything 'kwa' loops over
+  function iterate(v) {
+    switch (aina(v)) {
+      case "ORODHA": return v.map((e, i) => [i, e]);
+      case "NENO": return Array.from(v, (c, i) => [i, c]);
+      case "KAMUSI":
+        if (typeof v.get("ifuatayo") === "function") return methods(v.get("ifuatayo"), v.get("anzisha"));
+        return [...v].sort((a, b) => inspect(a[0]) < inspect(b[0]) ? -1 : 1);
+      case "MPAKA": {
+        const out = [];
+        for (let x = v.start, i = 0; v.step > 0 ? x < v.end : x > v.end; x += v.step, i++) out.push([i, x]);
+        return out;
+      }
+      case "KIZALISHI":
+        // the body is moved on by hand, since leaving a for-of with 'vunja'
+        // would end it instead of leaving it for the next loop
+        return (function* () {
+          for (let r = v.body.next(); !r.done; r = v.body.next()) yield [v.index++, r.value];
+        })();
+    }
+    if (isInstance(v) && typeof v.ifuatayo === "function") {
+      return methods(() => v.ifuatayo(), typeof v.anzisha === "function" && (() => v.anzisha()));
+    }
+    throw kosa("Huwezi kufanya operesheni hii na " + aina(v));
+  }
+
+  // methods loops over a dict or instance that gives its own values: start,
+  // if it has one, is called first, then next until it gives tupu
+  function* methods(next, start) {
+    if (typeof start === "function") start();
+    for (let i = 0, e = next(); e !== null && e !== undefined; e = next(), i++) yield [i, e];
+  }
+
+  // same is how 'badili' compares a value with its cases
+  function same(a, b) {
+    return aina(a) === aina(b) && inspect(a) === inspect(b);
+  }
+
+  // match gives the values v has for the names in pattern, in order, or null
+  // if v doesn't fit it
+  function match(v, pattern, out = []) {
+    if ("value" in pattern) {
+      if (!equal(v, pattern.value, false)) return null;
+    } else if (pattern.array) {
+      const n = pattern.array.length;
+      if (aina(v) !== "ORODHA" || (pattern.rest ? v.length < n : v.length !== n)) return null;
+      for (let i = 0; i < n; i++) {
+        if (match(v[i], pattern.array[i], out) === null) return null;
+      }
+      if (pattern.rest) match(v.slice(n), pattern.rest, out);
+    } else if (pattern.dict) {
+      if (aina(v) !== "KAMUSI") return null;
+      for (const [key, p] of pattern.dict) {
+        if (!v.has(key) || match(v.get(key), p, out) === null) return null;
+      }
+    } else if (pattern.bind) {
+      out.push(v);
+    }
+    return out;
+  }
+
+  function tupa(v) {
+    return kosa(typeof v === "string" ? v : inspect(v));
+  }
+
+  function message(e) {
+    const match = e instanceof ReferenceError && /^(\S+) is not defined/.exec(e.message);
+    return match ? "Neno Halifahamiki: " + match[1].replace(/\$$/, "") : e.message;
+  }
+
+  function muundo(name, fields, constructor, methods) {
+    const proto = Object.assign(Object.create(null), methods);
+    Object.defineProperty(proto, "$muundo", { value: name });
+    const make = function (...args) {
+      const instance = Object.create(proto);
+      fields.call(instance);
+      if (constructor) {
+        constructor.apply(instance, args);
+      } else if (args.length !== 0) {
+        throw kosa("Muundo " + name + " hauna 'unda', hauwezi kupewa hoja " + args.length);
+      }
+      return instance;
+    };
+    make.$muundo = name;
+    return make;
+  }
+
+  function builtin(fn) {
+    fn.$builtin = true;
+    return fn;
+  }
+
+  function numbers(list) {
+    for (const n of list) {
+      if (!isNumber(aina(n))) throw kosa("Samahani namba tu zinahitajika");
+    }
+    return list;
+  }
+
+  const builtins = {
+    andika: builtin((...args) => {
+      console.log(args.map(inspect).join(" "));
+    }),
+    aina: builtin((v) => aina(v)),
+    idadi: builtin((v) => {
+      const t = aina(v);
+      if (t === "ORODHA") return v.length;
+      if (t === "NENO") return Array.from(v).length;
+      throw kosa("Samahani, hii function haitumiki na " + t);
+    }),
+    jumla: builtin((v) => {
+      if (aina(v) !== "ORODHA") throw kosa("Samahani, hii function haitumiki na " + aina(v));
+      return numbers(v).reduce((a, b) => a + b, 0);
+    }),
+    yamwisho: builtin((v) => {
+      if (aina(v) !== "ORODHA") throw kosa("Samahani, hii function haitumiki na " + aina(v));
+      return v.length > 0 ? v[v.length - 1] : null;
+    }),
+    sukuma: builtin((v, e) => {
+      if (aina(v) !== "ORODHA") throw kosa("Samahani, hii function haitumiki na " + aina(v));
+      return v.concat([e]);
+    }),
+    mpaka: builtin((...args) => {
+      if (args.length < 1 || args.length > 3) throw kosa("Samahani, hii function inapokea hoja 1 hadi 3, wewe umeweka " + args.length);
+      for (const n of args) {
+        if (aina(n) !== "NAMBA") throw kosa("Samahani, mpaka inahitaji namba, sio " + aina(n));
+      }
+      const [start, end, step] = args.length === 1 ? [0, args[0], 1] : [args[0], args[1], args.length === 3 ? args[2] : 1];
+      if (step === 0) throw kosa("Samahani, hatua ya mpaka haiwezi kuwa 0");
+      return new Range(start, end, step);
+    }),
+    sawa_kabisa: builtin((a, b) => equal(a, b, true)),
+  };
+
+  // run runs the program, showing what it ends with like nuru does, or the
+  // error that stopped it
+  function run(program) {
+    try {
+      const result = program();
+      if (result !== null && result !== undefined) console.log(inspect(result));
+    } catch (e) {
+      console.log("Kosa: " + message(e));
+    }
+  }
+
+  return { builtins, inspect, str, truthy, not, neg, op, index, setIndex, updateIndex, unpack, chain, slice, spread, prop, setProp, updateProp, iterate, generator, same, match, tupa, message, muundo, run };
+})();
+`
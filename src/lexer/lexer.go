@@ -1,6 +1,10 @@
 package lexer
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/AvicennaJr/Nuru/token"
 )
 
@@ -9,16 +13,64 @@ type Lexer struct {
 	position     int
 	readPosition int
 	ch           byte
+	file         string
 	line         int
+	lineStart    int // position of the first character of the current line
+	lastLine     int // line of the last token returned
+	comments     []Comment
+	errors       []string
+}
+
+// Comment is a comment skipped by the lexer. Trailing comments come after
+// code on the same line, like 'x = 1 // moja'.
+type Comment struct {
+	Text     string
+	Position token.Position
+	Trailing bool
 }
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
 
+// NewFile creates a lexer for the contents of a file, so that the position
+// of every token includes the file name.
+func NewFile(file, input string) *Lexer {
+	l := New(input)
+	l.file = file
+	return l
+}
+
+// NewAt creates a lexer whose tokens start counting lines from pos, used to
+// lex the expressions inside an interpolated string.
+func NewAt(input string, pos token.Position) *Lexer {
+	l := New(input)
+	l.file = pos.File
+	l.line = pos.Line
+	return l
+}
+
+func (l *Lexer) currentPosition() token.Position {
+	return token.Position{File: l.file, Line: l.line, Column: l.position - l.lineStart + 1}
+}
+
+// Offset is how many bytes of the input have been read. Right after
+// NextToken it is the end of the token that was returned.
+func (l *Lexer) Offset() int {
+	if l.position > len(l.input) {
+		return len(l.input)
+	}
+	return l.position
+}
+
 func (l *Lexer) readChar() {
+	if l.readPosition > 0 && l.position < len(l.input) && l.input[l.position] == '\n' {
+		l.line++
+		l.lineStart = l.readPosition
+	}
+
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -29,154 +81,242 @@ func (l *Lexer) readChar() {
 	l.readPosition += 1
 }
 
-func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
+// Clone returns a lexer that carries on from where l is without moving l, so
+// that the parser can look further ahead than the next token
+func (l *Lexer) Clone() *Lexer {
+	clone := *l
+	clone.comments = nil
+	clone.errors = nil
+	return &clone
+}
+
+// Errors returns the mistakes found in the input so far, which the parser
+// reports along with its own
+func (l *Lexer) Errors() []string {
+	return l.errors
+}
+
+// Comments returns the comments skipped so far, in the order they appear
+func (l *Lexer) Comments() []Comment {
+	return l.comments
+}
+
+func (l *Lexer) NextToken() (tok token.Token) {
 	l.skipWhitespace()
-	if l.ch == '/' && l.peekChar() == '/' {
-		l.skipSingleLineComment()
-		return l.NextToken()
-	}
-	if l.ch == '/' && l.peekChar() == '*' {
-		l.skipMultiLineComment()
+	if l.ch == '/' && (l.peekChar() == '/' || l.peekChar() == '*') {
+		pos := l.currentPosition()
+		start := l.position
+		if l.peekChar() == '/' {
+			l.skipSingleLineComment()
+		} else {
+			l.skipMultiLineComment()
+		}
+		l.comments = append(l.comments, Comment{
+			Text:     strings.TrimRight(l.input[start:l.Offset()], " \t\r\n"),
+			Position: pos,
+			Trailing: l.lastLine == pos.Line,
+		})
 		return l.NextToken()
 	}
 
+	// every token is positioned at its first character, even when reading it
+	// moves the lexer on to later lines
+	pos := l.currentPosition()
+	l.lastLine = pos.Line
+	defer func() { tok.Position = pos }()
+
 	switch l.ch {
 	case '=':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.EQ, Literal: string(ch) + string(l.ch), Line: l.line}
+			tok = token.Token{Type: token.EQ, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.Token{Type: token.ARROW, Literal: "=>"}
 		} else {
-			tok = newToken(token.ASSIGN, l.line, l.ch)
+			tok = newToken(token.ASSIGN, l.ch)
 		}
 	case ';':
-		tok = newToken(token.SEMICOLON, l.line, l.ch)
+		tok = newToken(token.SEMICOLON, l.ch)
 	case '(':
-		tok = newToken(token.LPAREN, l.line, l.ch)
+		tok = newToken(token.LPAREN, l.ch)
 	case ')':
-		tok = newToken(token.RPAREN, l.line, l.ch)
+		tok = newToken(token.RPAREN, l.ch)
 	case '{':
-		tok = newToken(token.LBRACE, l.line, l.ch)
+		tok = newToken(token.LBRACE, l.ch)
 	case '}':
-		tok = newToken(token.RBRACE, l.line, l.ch)
+		tok = newToken(token.RBRACE, l.ch)
 	case ',':
-		tok = newToken(token.COMMA, l.line, l.ch)
+		tok = newToken(token.COMMA, l.ch)
 	case '+':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.PLUS_ASSIGN, Line: l.line, Literal: string(ch) + string(l.ch)}
+			tok = token.Token{Type: token.PLUS_ASSIGN, Literal: string(ch) + string(l.ch)}
 		} else if l.peekChar() == '+' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.PLUS_PLUS, Literal: string(ch) + string(l.ch), Line: l.line}
+			tok = token.Token{Type: token.PLUS_PLUS, Literal: string(ch) + string(l.ch)}
 		} else {
-			tok = newToken(token.PLUS, l.line, l.ch)
+			tok = newToken(token.PLUS, l.ch)
 		}
 	case '-':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.MINUS_ASSIGN, Line: l.line, Literal: string(ch) + string(l.ch)}
+			tok = token.Token{Type: token.MINUS_ASSIGN, Literal: string(ch) + string(l.ch)}
 		} else if l.peekChar() == '-' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.MINUS_MINUS, Literal: string(ch) + string(l.ch), Line: l.line}
+			tok = token.Token{Type: token.MINUS_MINUS, Literal: string(ch) + string(l.ch)}
 		} else {
-			tok = newToken(token.MINUS, l.line, l.ch)
+			tok = newToken(token.MINUS, l.ch)
 		}
 	case '!':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.NOT_EQ, Literal: string(ch) + string(l.ch), Line: l.line}
+			tok = token.Token{Type: token.NOT_EQ, Literal: string(ch) + string(l.ch)}
 		} else {
-			tok = newToken(token.BANG, l.line, l.ch)
+			tok = newToken(token.BANG, l.ch)
 		}
 	case '/':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.SLASH_ASSIGN, Line: l.line, Literal: string(ch) + string(l.ch)}
+			tok = token.Token{Type: token.SLASH_ASSIGN, Literal: string(ch) + string(l.ch)}
 		} else {
-			tok = newToken(token.SLASH, l.line, l.ch)
+			tok = newToken(token.SLASH, l.ch)
 		}
 	case '*':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.ASTERISK_ASSIGN, Line: l.line, Literal: string(ch) + string(l.ch)}
+			tok = token.Token{Type: token.ASTERISK_ASSIGN, Literal: string(ch) + string(l.ch)}
 		} else if l.peekChar() == '*' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.POW, Literal: string(ch) + string(l.ch), Line: l.line}
+			tok = token.Token{Type: token.POW, Literal: string(ch) + string(l.ch)}
 		} else {
-			tok = newToken(token.ASTERISK, l.line, l.ch)
+			tok = newToken(token.ASTERISK, l.ch)
 		}
 	case '<':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.LTE, Literal: string(ch) + string(l.ch), Line: l.line}
+			tok = token.Token{Type: token.LTE, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '<' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.SHIFT_LEFT, Literal: string(ch) + string(l.ch)}
 		} else {
-			tok = newToken(token.LT, l.line, l.ch)
+			tok = newToken(token.LT, l.ch)
 		}
 	case '>':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.GTE, Literal: string(ch) + string(l.ch), Line: l.line}
+			tok = token.Token{Type: token.GTE, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.SHIFT_RIGHT, Literal: string(ch) + string(l.ch)}
 		} else {
-			tok = newToken(token.GT, l.line, l.ch)
+			tok = newToken(token.GT, l.ch)
 		}
 	case '"':
+		read := l.readString
+		if strings.HasPrefix(l.input[l.position:], `"""`) {
+			read = l.readMultilineString
+		}
+		literal, interpolated := read()
 		tok.Type = token.STRING
-		tok.Literal = l.readString()
-		tok.Line = l.line
+		if interpolated {
+			tok.Type = token.TEMPLATE
+		}
+		tok.Literal = literal
 	case '\'':
-		tok = token.Token{Type: token.STRING, Literal: l.readSingleQuoteString(), Line: l.line}
+		tok = token.Token{Type: token.STRING, Literal: l.readSingleQuoteString()}
+	case '`':
+		tok = token.Token{Type: token.STRING, Literal: l.readRawString()}
 	case '[':
-		tok = newToken(token.LBRACKET, l.line, l.ch)
+		tok = newToken(token.LBRACKET, l.ch)
 	case ']':
-		tok = newToken(token.RBRACKET, l.line, l.ch)
+		tok = newToken(token.RBRACKET, l.ch)
 	case ':':
-		tok = newToken(token.COLON, l.line, l.ch)
+		tok = newToken(token.COLON, l.ch)
+	case '.':
+		if l.peekChar() == '.' && l.readPosition+1 < len(l.input) && l.input[l.readPosition+1] == '.' {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		} else {
+			tok = newToken(token.DOT, l.ch)
+		}
 	case '&':
 		if l.peekChar() == '&' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.AND, Literal: string(ch) + string(l.ch), Line: l.line}
+			tok = token.Token{Type: token.AND, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.BIT_AND, l.ch)
 		}
 	case '|':
 		if l.peekChar() == '|' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.OR, Literal: string(ch) + string(l.ch), Line: l.line}
+			tok = token.Token{Type: token.OR, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.BIT_OR, l.ch)
 		}
+	case '?':
+		if l.peekChar() == '?' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.COALESCE, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '[' {
+			// only when written together, so that 'a ? [1] : [2]' is still a
+			// choice between two arrays
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.OPTIONAL, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.QUESTION, l.ch)
+		}
+	case '^':
+		tok = newToken(token.BIT_XOR, l.ch)
+	case '~':
+		tok = newToken(token.BIT_NOT, l.ch)
 	case '%':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
-			tok = token.Token{Type: token.MODULUS_ASSIGN, Line: l.line, Literal: string(ch) + string(l.ch)}
+			tok = token.Token{Type: token.MODULUS_ASSIGN, Literal: string(ch) + string(l.ch)}
 		} else {
-			tok = newToken(token.MODULUS, l.line, l.ch)
+			tok = newToken(token.MODULUS, l.ch)
+		}
+	case '\\':
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.BACKSLASH_ASSIGN, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.BACKSLASH, l.ch)
 		}
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
-		tok.Line = l.line
 	default:
 		if isLetter(l.ch) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
-			tok.Line = l.line
 			return tok
 		} else if isDigit(l.ch) {
 			tok = l.readDecimal()
 			return tok
 		} else {
-			tok = newToken(token.ILLEGAL, l.line, l.ch)
+			tok = newToken(token.ILLEGAL, l.ch)
 		}
 	}
 
@@ -184,8 +324,8 @@ func (l *Lexer) NextToken() token.Token {
 	return tok
 }
 
-func newToken(tokenType token.TokenType, line int, ch byte) token.Token {
-	return token.Token{Type: tokenType, Literal: string(ch), Line: line}
+func newToken(tokenType token.TokenType, ch byte) token.Token {
+	return token.Token{Type: tokenType, Literal: string(ch)}
 }
 
 func (l *Lexer) readIdentifier() string {
@@ -203,9 +343,6 @@ func isLetter(ch byte) bool {
 
 func (l *Lexer) skipWhitespace() {
 	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
-		if l.ch == '\n' {
-			l.line++
-		}
 		l.readChar()
 	}
 }
@@ -227,9 +364,9 @@ func (l *Lexer) readDecimal() token.Token {
 	if l.ch == '.' && isDigit(l.peekChar()) {
 		l.readChar()
 		fraction := l.readNumber()
-		return token.Token{Type: token.FLOAT, Literal: integer + "." + fraction, Line: l.line}
+		return token.Token{Type: token.FLOAT, Literal: integer + "." + fraction}
 	}
-	return token.Token{Type: token.INT, Literal: integer, Line: l.line}
+	return token.Token{Type: token.INT, Literal: integer}
 }
 
 func (l *Lexer) peekChar() byte {
@@ -247,81 +384,281 @@ func (l *Lexer) skipSingleLineComment() {
 	l.skipWhitespace()
 }
 
+// skipMultiLineComment skips a /* */ comment. Comments inside it must be
+// closed too, so that code holding a comment can be commented out whole.
 func (l *Lexer) skipMultiLineComment() {
-	endFound := false
-
-	for !endFound {
-		if l.ch == 0 {
-			endFound = true
+	line := l.line
+	depth := 0
+	for l.ch != 0 {
+		if l.ch == '/' && l.peekChar() == '*' {
+			depth++
+			l.readChar()
+		} else if l.ch == '*' && l.peekChar() == '/' {
+			depth--
+			l.readChar()
+			if depth == 0 {
+				l.readChar()
+				l.skipWhitespace()
+				return
+			}
 		}
+		l.readChar()
+	}
+	l.errors = append(l.errors, fmt.Sprintf("Mstari %d: Maoni yaliyoanza na /* hayajafungwa na */", line))
+}
 
-		if l.ch == '*' && l.peekChar() == '/' {
-			endFound = true
-			l.readChar()
+// readString reads a double quoted string. The second return value reports
+// whether the string contains ${...} interpolations, in which case the raw
+// source is returned so the parser can split it with SplitTemplate.
+func (l *Lexer) readString() (string, bool) {
+	start := l.position + 1
+	end := start
+	interpolated := false
+
+	for end < len(l.input) && l.input[end] != '"' {
+		switch {
+		case l.input[end] == '\\':
+			end += 2
+		case l.input[end] == '$' && end+1 < len(l.input) && l.input[end+1] == '{':
+			interpolated = true
+			end = matchBrace(l.input, end+2) + 1
+		default:
+			end++
 		}
+	}
+	if end > len(l.input) {
+		end = len(l.input)
+	}
 
+	for l.position < end {
 		l.readChar()
-		l.skipWhitespace()
 	}
 
+	raw := l.input[start:end]
+	if interpolated {
+		return raw, true
+	}
+	return unescape(raw), false
 }
 
-func (l *Lexer) readString() string {
-	var str string
-	for {
+// readMultilineString reads a string in triple quotes, which can hold
+// newlines and quotes and is otherwise like one in double quotes. When the
+// text starts on the line after the opening quotes, it is dedented.
+func (l *Lexer) readMultilineString() (string, bool) {
+	start := l.position + 3
+	end := start
+	interpolated := false
+
+	for end < len(l.input) && !strings.HasPrefix(l.input[end:], `"""`) {
+		switch {
+		case l.input[end] == '\\':
+			end += 2
+		case l.input[end] == '$' && end+1 < len(l.input) && l.input[end+1] == '{':
+			interpolated = true
+			end = matchBrace(l.input, end+2) + 1
+		default:
+			end++
+		}
+	}
+	if end > len(l.input) {
+		end = len(l.input)
+	}
+
+	// stop on the last of the closing quotes
+	last := end + 2
+	if last > len(l.input) {
+		last = len(l.input)
+	}
+	for l.position < last {
 		l.readChar()
-		if l.ch == '"' || l.ch == 0 {
-			break
-		} else if l.ch == '\\' {
-			switch l.peekChar() {
-			case 'n':
-				l.readChar()
-				l.ch = '\n'
-			case 'r':
-				l.readChar()
-				l.ch = '\r'
-			case 't':
-				l.readChar()
-				l.ch = '\t'
-			case '"':
-				l.readChar()
-				l.ch = '"'
-			case '\\':
-				l.readChar()
-				l.ch = '\\'
+	}
+
+	raw := dedent(l.input[start:end])
+	if interpolated {
+		return raw, true
+	}
+	return unescape(raw), false
+}
+
+// dedent takes the text of a triple quoted string that starts with a
+// newline, and drops that newline, the indentation all its lines share and
+// the last line when it is only the indentation of the closing quotes.
+// Text that starts on the same line as the quotes is left as it is.
+func dedent(text string) string {
+	if strings.HasPrefix(text, "\r\n") {
+		text = text[2:]
+	} else if strings.HasPrefix(text, "\n") {
+		text = text[1:]
+	} else {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	if last := lines[len(lines)-1]; strings.Trim(last, " \t") == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent, found := "", false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lead := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if !found {
+			indent, found = lead, true
+		}
+		for !strings.HasPrefix(lead, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, indent) {
+			lines[i] = line[len(indent):]
+		} else {
+			lines[i] = strings.TrimLeft(line, " \t")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SplitTemplate splits the raw source of an interpolated string into its text
+// parts and the source of its ${...} expressions. There is always one more
+// text part than there are expressions.
+func SplitTemplate(raw string) (texts []string, exprs []string) {
+	last := 0
+	for i := 0; i < len(raw); i++ {
+		switch {
+		case raw[i] == '\\':
+			i++
+		case raw[i] == '$' && i+1 < len(raw) && raw[i+1] == '{':
+			end := matchBrace(raw, i+2)
+			texts = append(texts, unescape(raw[last:i]))
+			if end < len(raw) {
+				exprs = append(exprs, raw[i+2:end])
+			} else {
+				exprs = append(exprs, raw[i+2:])
 			}
+			i = end
+			last = end + 1
 		}
-		str += string(l.ch)
 	}
-	return str
+	if last > len(raw) {
+		last = len(raw)
+	}
+	texts = append(texts, unescape(raw[last:]))
+	return texts, exprs
 }
 
-func (l *Lexer) readSingleQuoteString() string {
-	var str string
-	for {
-		l.readChar()
-		if l.ch == '\'' || l.ch == 0 {
-			break
-		} else if l.ch == '\\' {
-			switch l.peekChar() {
+// matchBrace returns the position of the '}' closing an interpolation whose
+// contents start at i, or len(s) if it is never closed.
+func matchBrace(s string, i int) int {
+	depth := 1
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '"', '\'':
+			i = skipQuoted(s, i)
+		}
+	}
+	return len(s)
+}
+
+// skipQuoted returns the position of the quote closing the string that starts at i
+func skipQuoted(s string, i int) int {
+	quote := s[i]
+	for i++; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		case quote == '"' && s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			i = matchBrace(s, i+2)
+		}
+	}
+	return len(s)
+}
+
+// unescape replaces the escapes in the text of a string with what they
+// stand for: \n, \r, \t, \", \', \\, \$ and \uXXXX for the character with
+// that hex code. A backslash before anything else is kept, so that a
+// pattern like "\d+" means what it says.
+func unescape(raw string) string {
+	// the input is read a byte at a time, and the bytes of a character
+	// like "ŋ" must go into the string as they are
+	var str strings.Builder
+	for i := 0; i < len(raw); i++ {
+		ch := raw[i]
+		if ch == '\\' && i+1 < len(raw) {
+			switch raw[i+1] {
 			case 'n':
-				l.readChar()
-				l.ch = '\n'
+				ch = '\n'
+				i++
 			case 'r':
-				l.readChar()
-				l.ch = '\r'
+				ch = '\r'
+				i++
 			case 't':
-				l.readChar()
-				l.ch = '\t'
-			case '"':
-				l.readChar()
-				l.ch = '"'
-			case '\\':
-				l.readChar()
-				l.ch = '\\'
+				ch = '\t'
+				i++
+			case '"', '\'', '\\', '$':
+				ch = raw[i+1]
+				i++
+			case 'u':
+				if i+6 <= len(raw) {
+					if code, err := strconv.ParseUint(raw[i+2:i+6], 16, 32); err == nil {
+						str.WriteRune(rune(code))
+						i += 5
+						continue
+					}
+				}
 			}
 		}
-		str += string(l.ch)
+		str.WriteByte(ch)
+	}
+	return str.String()
+}
+
+// readRawString reads a string in backticks, which is taken as it is
+// written: backslashes are left alone and it can go over several lines
+func (l *Lexer) readRawString() string {
+	start := l.position + 1
+	end := strings.IndexByte(l.input[start:], '`')
+	if end < 0 {
+		end = len(l.input)
+	} else {
+		end += start
+	}
+
+	for l.position < end {
+		l.readChar()
+	}
+	return l.input[start:end]
+}
+
+// readSingleQuoteString reads a string in single quotes, which has the
+// escapes of one in double quotes but no interpolation
+func (l *Lexer) readSingleQuoteString() string {
+	start := l.position + 1
+	end := start
+	for end < len(l.input) && l.input[end] != '\'' {
+		if l.input[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end > len(l.input) {
+		end = len(l.input)
+	}
+
+	for l.position < end {
+		l.readChar()
 	}
-	return str
+	return unescape(l.input[start:end])
 }
@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/AvicennaJr/Nuru/token"
@@ -40,7 +41,15 @@ func TestNextToken(t *testing.T) {
 	"bangi"
 	"ba ngi"
 	[1, 2];
-	{"mambo": "vipi"}`
+	{"mambo": "vipi"}
+	a & b | c ^ ~d << 1 >> 2 <= 3 >= 4
+	a \ 2 \= 3
+	a ? b ?? c
+	a?[b] ? [c]
+	(x) => x == 1
+	toa x
+	linganisha
+	thabiti`
 
 	tests := []struct {
 		expectedType    token.TokenType
@@ -132,6 +141,51 @@ func TestNextToken(t *testing.T) {
 		{token.COLON, ":"},
 		{token.STRING, "vipi"},
 		{token.RBRACE, "}"},
+		{token.IDENT, "a"},
+		{token.BIT_AND, "&"},
+		{token.IDENT, "b"},
+		{token.BIT_OR, "|"},
+		{token.IDENT, "c"},
+		{token.BIT_XOR, "^"},
+		{token.BIT_NOT, "~"},
+		{token.IDENT, "d"},
+		{token.SHIFT_LEFT, "<<"},
+		{token.INT, "1"},
+		{token.SHIFT_RIGHT, ">>"},
+		{token.INT, "2"},
+		{token.LTE, "<="},
+		{token.INT, "3"},
+		{token.GTE, ">="},
+		{token.INT, "4"},
+		{token.IDENT, "a"},
+		{token.BACKSLASH, "\\"},
+		{token.INT, "2"},
+		{token.BACKSLASH_ASSIGN, "\\="},
+		{token.INT, "3"},
+		{token.IDENT, "a"},
+		{token.QUESTION, "?"},
+		{token.IDENT, "b"},
+		{token.COALESCE, "??"},
+		{token.IDENT, "c"},
+		{token.IDENT, "a"},
+		{token.OPTIONAL, "?["},
+		{token.IDENT, "b"},
+		{token.RBRACKET, "]"},
+		{token.QUESTION, "?"},
+		{token.LBRACKET, "["},
+		{token.IDENT, "c"},
+		{token.RBRACKET, "]"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.ARROW, "=>"},
+		{token.IDENT, "x"},
+		{token.EQ, "=="},
+		{token.INT, "1"},
+		{token.YIELD, "toa"},
+		{token.IDENT, "x"},
+		{token.MATCH, "linganisha"},
+		{token.CONST, "thabiti"},
 		{token.EOF, ""},
 	}
 
@@ -151,3 +205,137 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestTemplateString(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{`"habari"`, token.STRING, "habari"},
+		{`"bei ni \${bei}"`, token.STRING, "bei ni ${bei}"},
+		{`"jina ni ${jina}"`, token.TEMPLATE, "jina ni ${jina}"},
+		{`"${ "}" } mwisho"`, token.TEMPLATE, `${ "}" } mwisho`},
+		{`"${ {"a": 1}["a"] }"`, token.TEMPLATE, `${ {"a": 1}["a"] }`},
+		{`"ŋombe\t🐄"`, token.STRING, "ŋombe\t🐄"},
+		{`'ñé\n'`, token.STRING, "ñé\n"},
+		{"`\\d+\\.${x}\n\"`", token.STRING, "\\d+\\.${x}\n\""},
+		{"`bila mwisho", token.STRING, "bila mwisho"},
+		{`"""sema "habari"\t"""`, token.STRING, "sema \"habari\"\t"},
+		{"\"\"\"\n    SELECT *\n      FROM watu\n\n    WHERE id = 1\n    \"\"\"", token.STRING, "SELECT *\n  FROM watu\n\nWHERE id = 1"},
+		{"\"\"\"\n\tjina: ${jina}\n\t\"\"\"", token.TEMPLATE, "jina: ${jina}"},
+		{`""""""`, token.STRING, ""},
+		{`"a\nb\tc\r\"d\" \\ e\'"`, token.STRING, "a\nb\tc\r\"d\" \\ e'"},
+		{`"\u014b\u00F1 \u2713"`, token.STRING, "ŋñ ✓"},
+		{`"\u12 \uzzzz \d+"`, token.STRING, `\u12 \uzzzz \d+`},
+		{`'it\'s\t\u014b'`, token.STRING, "it's\tŋ"},
+		{`'bei ni \${bei}'`, token.STRING, "bei ni ${bei}"},
+		{`"${x}\u014b"`, token.TEMPLATE, `${x}\u014b`},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if next := l.NextToken(); next.Type != token.EOF {
+			t.Fatalf("tests[%d] - string not read to its end. got=%q after it", i, next.Literal)
+		}
+	}
+}
+
+func TestSplitTemplate(t *testing.T) {
+	texts, exprs := SplitTemplate(`a ${x} b\t${ y + "}" }\${z}\u014b`)
+
+	expectedTexts := []string{"a ", " b\t", "${z}ŋ"}
+	expectedExprs := []string{"x", ` y + "}" `}
+
+	if len(texts) != len(expectedTexts) || len(exprs) != len(expectedExprs) {
+		t.Fatalf("wrong number of parts. texts=%q, exprs=%q", texts, exprs)
+	}
+
+	for i, want := range expectedTexts {
+		if texts[i] != want {
+			t.Errorf("texts[%d] wrong. expected=%q, got=%q", i, want, texts[i])
+		}
+	}
+
+	for i, want := range expectedExprs {
+		if exprs[i] != want {
+			t.Errorf("exprs[%d] wrong. expected=%q, got=%q", i, want, exprs[i])
+		}
+	}
+}
+
+func TestTokenPositions(t *testing.T) {
+	input := "fanya x = 5\n  andika(\"a\nb\", x) // maoni\n/* mstari\nmwingine */ x"
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"fanya", 1, 1},
+		{"x", 1, 7},
+		{"=", 1, 9},
+		{"5", 1, 11},
+		{"andika", 2, 3},
+		{"(", 2, 9},
+		{"a\nb", 2, 10},
+		{",", 3, 3},
+		{"x", 3, 5},
+		{")", 3, 6},
+		{"x", 5, 13},
+	}
+
+	l := NewFile("mfano.nr", input)
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position of %q wrong. expected=%d:%d, got=%d:%d",
+				i, tok.Literal, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+		if tok.File != "mfano.nr" {
+			t.Fatalf("tests[%d] - file wrong. got=%q", i, tok.File)
+		}
+	}
+}
+
+func TestBlockComments(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedTokens []string
+		expectedErrors []string
+	}{
+		{"a /* b */ c", []string{"a", "c"}, nil},
+		{"a /* b /* c */ d */ e", []string{"a", "e"}, nil},
+		{"a /**/ b /*/ c */ d", []string{"a", "b", "d"}, nil},
+		{"a\n/* b\n/* c */\nd", []string{"a"}, []string{"Mstari 2: Maoni yaliyoanza na /* hayajafungwa na */"}},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		var literals []string
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+			literals = append(literals, tok.Literal)
+		}
+
+		if strings.Join(literals, " ") != strings.Join(tt.expectedTokens, " ") {
+			t.Errorf("tests[%d] - tokens wrong. expected=%q, got=%q", i, tt.expectedTokens, literals)
+		}
+		if strings.Join(l.Errors(), "\n") != strings.Join(tt.expectedErrors, "\n") {
+			t.Errorf("tests[%d] - errors wrong. expected=%q, got=%q", i, tt.expectedErrors, l.Errors())
+		}
+	}
+}
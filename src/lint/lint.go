@@ -0,0 +1,341 @@
+// Package lint looks for likely mistakes in Nuru programs without running
+// them, the way 'nuru lint' reports them.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+// Problem is something in a program that is probably a mistake
+type Problem struct {
+	Position token.Position
+	Message  string
+}
+
+func (p Problem) String() string {
+	return p.Position.String() + ": " + p.Message
+}
+
+// Lint checks program for names that are never defined, code that can't be
+// reached, keys written twice in a dict and variables that hide one of the
+// same name outside their function. The problems are sorted by position.
+func Lint(program *ast.Program) []Problem {
+	l := &linter{predeclared: make(map[string]bool)}
+	for _, name := range evaluator.BuiltinNames() {
+		l.predeclared[name] = true
+	}
+	for _, name := range evaluator.ModuleNames() {
+		l.predeclared[name] = true
+	}
+	for _, name := range evaluator.GlobalNames() {
+		l.predeclared[name] = true
+	}
+
+	global := newScope(nil)
+	l.declare(global, program)
+	l.check(global, program)
+
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		a, b := l.problems[i].Position, l.problems[j].Position
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return l.problems
+}
+
+// scope holds the names declared in a function, or at the top of the file.
+// Blocks don't get a scope of their own, just like in the evaluator.
+type scope struct {
+	names map[string]token.Position
+	outer *scope
+}
+
+func newScope(outer *scope) *scope {
+	return &scope{names: make(map[string]token.Position), outer: outer}
+}
+
+func (s *scope) lookup(name string) (token.Position, bool) {
+	for ; s != nil; s = s.outer {
+		if pos, ok := s.names[name]; ok {
+			return pos, true
+		}
+	}
+	return token.Position{}, false
+}
+
+type linter struct {
+	predeclared map[string]bool // builtins and the standard modules
+	problems    []Problem
+}
+
+func (l *linter) report(pos token.Position, format string, a ...interface{}) {
+	l.problems = append(l.problems, Problem{Position: pos, Message: fmt.Sprintf(format, a...)})
+}
+
+// declare records every name node declares in s before anything is checked,
+// since a function may use a name that is only defined further down. It
+// doesn't look inside function literals, which get their own scope.
+func (l *linter) declare(s *scope, node ast.Node) {
+	switch node := node.(type) {
+	case *ast.FunctionLiteral:
+		return
+	case *ast.ClassStatement:
+		// the fields belong to the instances, not to the scope
+		l.define(s, node.Name.Value, node.Name.Pos())
+		return
+	case *ast.LetStatement:
+		if outer, ok := s.outer.lookup(node.Name.Value); ok {
+			l.report(node.Name.Pos(), "%s inaficha jina lililotangazwa nje ya unda hii (%s)", node.Name.Value, where(outer))
+		}
+		l.define(s, node.Name.Value, node.Name.Pos())
+	case *ast.DestructuringStatement:
+		for _, name := range node.Names {
+			if outer, ok := s.outer.lookup(name.Value); ok {
+				l.report(name.Pos(), "%s inaficha jina lililotangazwa nje ya unda hii (%s)", name.Value, where(outer))
+			}
+			l.define(s, name.Value, name.Pos())
+		}
+	case *ast.ForIn:
+		if node.Key != "" {
+			l.define(s, node.Key, node.Pos())
+		}
+		l.define(s, node.Value, node.Pos())
+	case *ast.Comprehension:
+		if node.LoopKey != "" {
+			l.define(s, node.LoopKey, node.Pos())
+		}
+		l.define(s, node.LoopValue, node.Pos())
+	case *ast.MatchExpression:
+		for _, arm := range node.Arms {
+			for _, name := range arm.Names() {
+				l.define(s, name.Value, name.Pos())
+			}
+		}
+	case *ast.TryExpression:
+		if node.Identifier != nil {
+			l.define(s, node.Identifier.Value, node.Identifier.Pos())
+		}
+	case *ast.ImportStatement:
+		l.define(s, node.Name.Value, node.Name.Pos())
+	}
+
+	for _, child := range children(node) {
+		l.declare(s, child)
+	}
+}
+
+// define keeps the first place a name is declared
+func (l *linter) define(s *scope, name string, pos token.Position) {
+	if _, ok := s.names[name]; !ok {
+		s.names[name] = pos
+	}
+}
+
+func (l *linter) check(s *scope, node ast.Node) {
+	switch node := node.(type) {
+	case *ast.Identifier:
+		if _, ok := s.lookup(node.Value); !ok && !l.predeclared[node.Value] {
+			l.report(node.Pos(), "Neno Halifahamiki: %s", node.Value)
+		}
+	case *ast.FunctionLiteral:
+		fs := newScope(s)
+		for _, param := range node.Parameters {
+			l.define(fs, param.Value, param.Pos())
+		}
+		l.declare(fs, node.Body)
+		l.check(fs, node.Body)
+		return
+	case *ast.Program:
+		l.unreachable(node.Statements)
+	case *ast.BlockStatement:
+		l.unreachable(node.Statements)
+	case *ast.DictLiteral:
+		l.duplicateKeys(node)
+	}
+
+	for _, child := range children(node) {
+		l.check(s, child)
+	}
+}
+
+// unreachable reports the first statement after a rudisha, tupa, vunja or
+// endelea, since it will never run
+func (l *linter) unreachable(stmts []ast.Statement) {
+	for i := 0; i < len(stmts)-1; i++ {
+		switch stmt := stmts[i]; stmt.(type) {
+		case *ast.ReturnStatement, *ast.ThrowStatement, *ast.Break, *ast.Continue:
+			l.report(stmts[i+1].Pos(), "Msimbo huu hautafikiwa kwa sababu ya '%s' iliyo kabla yake", stmt.TokenLiteral())
+			return
+		}
+	}
+}
+
+func (l *linter) duplicateKeys(dict *ast.DictLiteral) {
+	seen := make(map[string]token.Position)
+	for _, key := range dict.Keys {
+		id, ok := literalKey(key)
+		if !ok {
+			continue
+		}
+		if first, ok := seen[id]; ok {
+			l.report(key.Pos(), "Ufunguo %s umeshatumika kwenye kamusi hii (%s)", keyString(key), where(first))
+			continue
+		}
+		seen[id] = key.Pos()
+	}
+}
+
+// literalKey identifies a dict key whose value is known without running the
+// program. Keys of different types never match, just like when they are hashed.
+func literalKey(exp ast.Expression) (string, bool) {
+	switch exp := exp.(type) {
+	case *ast.StringLiteral:
+		return "neno " + exp.Value, true
+	case *ast.IntegerLiteral:
+		return "namba " + strconv.FormatInt(exp.Value, 10), true
+	case *ast.FloatLiteral:
+		return "desimali " + strconv.FormatFloat(exp.Value, 'g', -1, 64), true
+	case *ast.Boolean:
+		return "boolean " + strconv.FormatBool(exp.Value), true
+	}
+	return "", false
+}
+
+func keyString(key ast.Expression) string {
+	if str, ok := key.(*ast.StringLiteral); ok {
+		return strconv.Quote(str.Value)
+	}
+	return key.String()
+}
+
+// where describes a position in the file being linted, which doesn't need
+// the file's name again
+func where(pos token.Position) string {
+	pos.File = ""
+	return pos.String()
+}
+
+// children returns the nodes directly inside node. Names that are declared
+// rather than used, like the name in a 'fanya', are left out.
+func children(node ast.Node) []ast.Node {
+	var nodes []ast.Node
+	add := func(exps ...ast.Expression) {
+		for _, exp := range exps {
+			if exp != nil {
+				nodes = append(nodes, exp)
+			}
+		}
+	}
+	addBlock := func(block *ast.BlockStatement) {
+		if block != nil {
+			nodes = append(nodes, block)
+		}
+	}
+
+	switch node := node.(type) {
+	case *ast.Program:
+		for _, stmt := range node.Statements {
+			nodes = append(nodes, stmt)
+		}
+	case *ast.BlockStatement:
+		for _, stmt := range node.Statements {
+			nodes = append(nodes, stmt)
+		}
+	case *ast.LetStatement:
+		add(node.Value)
+	case *ast.DestructuringStatement:
+		add(node.Value)
+	case *ast.ReturnStatement:
+		add(node.ReturnValue)
+	case *ast.ExpressionStatement:
+		add(node.Expression)
+	case *ast.ThrowStatement:
+		add(node.Value)
+	case *ast.ClassStatement:
+		for _, field := range node.Fields {
+			nodes = append(nodes, field)
+		}
+		if node.Constructor != nil {
+			nodes = append(nodes, node.Constructor)
+		}
+		for _, method := range node.Methods {
+			nodes = append(nodes, method.Function)
+		}
+	case *ast.PrefixExpression:
+		add(node.Right)
+	case *ast.SpawnExpression:
+		add(node.Call)
+	case *ast.YieldExpression:
+		add(node.Value)
+	case *ast.InfixExpression:
+		add(node.Left, node.Right)
+	case *ast.AssignmentExpression:
+		add(node.Left, node.Value)
+	case *ast.ConditionalExpression:
+		add(node.Condition, node.Consequence, node.Alternative)
+	case *ast.ComparisonChain:
+		add(node.Operands...)
+	case *ast.IfExpression:
+		add(node.Condition)
+		addBlock(node.Consequence)
+		addBlock(node.Alternative)
+	case *ast.WhileExpression:
+		add(node.Condition)
+		addBlock(node.Consequence)
+	case *ast.DoWhileExpression:
+		addBlock(node.Consequence)
+		add(node.Condition)
+	case *ast.ForIn:
+		add(node.Iterable)
+		addBlock(node.Block)
+	case *ast.MatchExpression:
+		// the patterns only hold literals and the names they take
+		add(node.Value)
+		for _, arm := range node.Arms {
+			add(arm.Guard)
+			addBlock(arm.Body)
+		}
+	case *ast.SwitchExpression:
+		add(node.Value)
+		for _, choice := range node.Choices {
+			nodes = append(nodes, choice)
+		}
+	case *ast.CaseExpression:
+		add(node.Expr...)
+		addBlock(node.Block)
+	case *ast.TryExpression:
+		addBlock(node.Block)
+		addBlock(node.Catch)
+	case *ast.FunctionLiteral:
+		addBlock(node.Body)
+	case *ast.CallExpression:
+		add(node.Function)
+		add(node.Arguments...)
+	case *ast.SpreadExpression:
+		add(node.Value)
+	case *ast.NamedArgument:
+		add(node.Value)
+	case *ast.IndexExpression:
+		add(node.Left, node.Index)
+	case *ast.Comprehension:
+		add(node.Key, node.Value, node.Iterable, node.Condition)
+	case *ast.SliceExpression:
+		add(node.Left, node.Start, node.Stop, node.Step)
+	case *ast.PropertyExpression:
+		add(node.Object)
+	case *ast.ArrayLiteral:
+		add(node.Elements...)
+	case *ast.DictLiteral:
+		for _, key := range node.Keys {
+			add(key, node.Pairs[key])
+		}
+	case *ast.TemplateLiteral:
+		add(node.Parts...)
+	}
+	return nodes
+}
@@ -0,0 +1,60 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"fanya x = 1; andika(x + idadi([]))", nil},
+		{"andika(y)", []string{"Mstari 1, Safu 8: Neno Halifahamiki: y"}},
+		{"y = 2", []string{"Mstari 1, Safu 1: Neno Halifahamiki: y"}},
+		{"fanya f = unda(n) { rudisha n + m }", []string{"Mstari 1, Safu 33: Neno Halifahamiki: m"}},
+		// names may be used by functions before they are defined
+		{"fanya f = unda() { rudisha g() }\nfanya g = unda() { rudisha f() }", nil},
+		{"kama (kweli) { fanya z = 1 }\nandika(z)", nil},
+		{"kwa k, v ktk {} { andika(k, v) }", nil},
+		{"andika([i + x kwa i, x ktk [1] kama x > 0], {k: 1 kwa k ktk []})", nil},
+		{"jaribu { tupa 1 } shika (e) { andika(e) }", nil},
+		{"tumia \"hesabu.nr\"; hesabu.ongeza(json.dikodi(\"1\"))", nil},
+		{"muundo Mtu { fanya jina = \"\"; salamu() { rudisha hii.jina } }\nMtu().salamu()", nil},
+		{"muundo Mtu { salamu() { rudisha jina } }", []string{"Mstari 1, Safu 33: Neno Halifahamiki: jina"}},
+		{"fanya x = {\"a\": 1}; x.a", nil},
+		{"fanya f = unda() {\n  rudisha 1\n  andika(2)\n  andika(3)\n}", []string{"Mstari 3, Safu 3: Msimbo huu hautafikiwa kwa sababu ya 'rudisha' iliyo kabla yake"}},
+		{"wakati (kweli) {\n  vunja\n  andika(1)\n}", []string{"Mstari 3, Safu 3: Msimbo huu hautafikiwa kwa sababu ya 'vunja' iliyo kabla yake"}},
+		{"tupa \"kosa\"\nandika(1)", []string{"Mstari 2, Safu 1: Msimbo huu hautafikiwa kwa sababu ya 'tupa' iliyo kabla yake"}},
+		{"fanya d = {\"a\": 1, 2: 2, \"a\": 3, 2: 4}", []string{
+			"Mstari 1, Safu 26: Ufunguo \"a\" umeshatumika kwenye kamusi hii (Mstari 1, Safu 12)",
+			"Mstari 1, Safu 34: Ufunguo 2 umeshatumika kwenye kamusi hii (Mstari 1, Safu 20)",
+		}},
+		{"fanya d = {\"1\": 1, 1: 2, 1.5: 3, kweli: 4, sikweli: 5}", nil},
+		{"fanya x = 1\nfanya f = unda() {\n  fanya x = 2\n}", []string{"Mstari 3, Safu 9: x inaficha jina lililotangazwa nje ya unda hii (Mstari 1, Safu 7)"}},
+		{"fanya f = unda(x) { fanya x = 2 }", nil},
+		{"fanya x = 1; fanya x = 2", nil},
+		{"linganisha (1) { [a, ...b] kama (a > 0) => a + idadi(b), {\"k\": c, d} => c + d, _ => 0 }", nil},
+		{"linganisha (1) { [a] => b }", []string{"Mstari 1, Safu 25: Neno Halifahamiki: b"}},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%q: parser errors %v", tt.input, p.Errors())
+		}
+
+		var got []string
+		for _, problem := range Lint(program) {
+			got = append(got, problem.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("%q: wrong problems.\nwant=%q\ngot= %q", tt.input, tt.expected, got)
+		}
+	}
+}
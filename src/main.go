@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/AvicennaJr/Nuru/evaluator"
 	"github.com/AvicennaJr/Nuru/repl"
 )
 
@@ -19,11 +22,51 @@ const (
 `
 )
 
+// commands are the subcommands of nuru, like 'nuru fmt'. Each one gets the
+// arguments after its name and returns the exit code.
+var commands = map[string]func(args []string) int{
+	"ast":    runAst,
+	"debug":  runDebug,
+	"doc":    runDoc,
+	"fmt":    runFmt,
+	"jaribu": runJaribu,
+	"jenga":  runJenga,
+	"js":     runJs,
+	"lint":   runLint,
+	"pakua":  runPakua,
+	"tokens": runTokens,
+}
+
 func main() {
 
+	// an executable built with 'nuru jenga' only runs its program
+	if runBundle() {
+		os.Exit(0)
+	}
+
 	args := os.Args
 	coloredLogo := fmt.Sprintf("\x1b[%dm%s\x1b[0m", 36, LOGO)
 
+	if len(args) > 1 {
+		if run, ok := commands[args[1]]; ok {
+			os.Exit(run(args[2:]))
+		}
+	}
+
+	// --vm runs the script on the bytecode VM instead of the evaluator, and
+	// --profile reports where the script spent its time
+	useVM, useProfile := false, false
+	for len(args) > 1 {
+		if args[1] == "--vm" || args[1] == "-vm" {
+			useVM = true
+		} else if args[1] == "--profile" || args[1] == "-profile" {
+			useProfile = true
+		} else {
+			break
+		}
+		args = append(args[:1], args[2:]...)
+	}
+
 	if len(args) < 2 {
 
 		fmt.Println(coloredLogo)
@@ -33,11 +76,12 @@ func main() {
 		repl.Start(os.Stdin, os.Stdout)
 	}
 
-	if len(args) == 2 {
+	// arguments after the file name are left for the script, in HOJA
+	if len(args) >= 2 {
 
 		switch args[1] {
 		case "msaada", "-msaada", "--msaada", "help", "-help", "--help", "-h":
-			fmt.Printf("\x1b[%dm%s\x1b[0m\n", 32, "\nTumia 'nuru' kuanza program\n\nAU\n\nTumia 'nuru' ikifuatiwa na jina la file.\n\n\tMfano:\tnuru fileYangu.nr")
+			fmt.Printf("\x1b[%dm%s\x1b[0m\n", 32, "\nTumia 'nuru' kuanza program\n\nAU\n\nTumia 'nuru' ikifuatiwa na jina la file.\n\n\tMfano:\tnuru fileYangu.nr\n\nTumia 'nuru --vm' ikifuatiwa na jina la file kuendesha kwa VM.\n\n\tMfano:\tnuru --vm fileYangu.nr\n\nTumia 'nuru --profile' ikifuatiwa na jina la file kuona muda uliotumiwa na kila unda.\n\n\tMfano:\tnuru --profile fileYangu.nr\n\nTumia 'nuru fmt' ikifuatiwa na jina la file kulipanga vizuri.\n\n\tMfano:\tnuru fmt -w fileYangu.nr\n\nTumia 'nuru lint' ikifuatiwa na jina la file kutafuta makosa bila kuliendesha.\n\n\tMfano:\tnuru lint fileYangu.nr\n\nTumia 'nuru jaribu' kuendesha majaribio yaliyo kwenye mafaili ya '_jaribu.nr'.\n\n\tMfano:\tnuru jaribu\n\nTumia 'nuru debug' ikifuatiwa na jina la file kuliendesha hatua kwa hatua.\n\n\tMfano:\tnuru debug fileYangu.nr\n\nTumia 'nuru ast' ikifuatiwa na jina la file kuona jinsi lilivyosomwa.\n\n\tMfano:\tnuru ast -json fileYangu.nr\n\nTumia 'nuru tokens' ikifuatiwa na jina la file kuona tokeni zake.\n\n\tMfano:\tnuru tokens fileYangu.nr\n\nTumia 'nuru doc' ikifuatiwa na jina la file kutengeneza maelezo yake kutoka kwenye maoni.\n\n\tMfano:\tnuru doc -html -o maelezo fileYangu.nr\n\nTumia 'nuru pakua' ikifuatiwa na anwani ya git kupakua kifurushi.\n\n\tMfano:\tnuru pakua github.com/mtumiaji/hesabu@v1.0\n\nTumia 'nuru jenga' ikifuatiwa na jina la file kutengeneza programu inayojitegemea.\n\n\tMfano:\tnuru jenga -o programu fileYangu.nr\n\nTumia 'nuru js' ikifuatiwa na jina la file kuligeuza kuwa JavaScript.\n\n\tMfano:\tnuru js -o programu.js fileYangu.nr")
 			os.Exit(0)
 		case "version", "-version", "--version", "-v", "v":
 			fmt.Println(coloredLogo)
@@ -53,7 +97,28 @@ func main() {
 				os.Exit(0)
 			}
 
-			repl.Read(string(contents))
+			evaluator.SetArgs(file, args[2:])
+
+			if useVM {
+				if useProfile {
+					fmt.Printf("\x1b[%dm%s\x1b[0m\n", 31, "Error: --profile haitumiki pamoja na --vm")
+					os.Exit(1)
+				}
+				repl.ReadVM(file, string(contents))
+				os.Exit(0)
+			}
+
+			// modules imported by the script are looked up next to it first
+			evaluator.ModulePaths = append([]string{filepath.Dir(file)}, evaluator.ModulePaths...)
+
+			if useProfile {
+				start := time.Now()
+				evaluator.StartProfile()
+				repl.Read(file, string(contents))
+				printProfile(os.Stderr, evaluator.StopProfile(), time.Since(start))
+				os.Exit(0)
+			}
+			repl.Read(file, string(contents))
 		} else {
 			fmt.Printf("\x1b[%dm%s%s\x1b[0m", 31, file, " sii file sahihi. Tumia file la '.nr' au '.sw'\n")
 			os.Exit(0)
@@ -0,0 +1,234 @@
+// Package nuru lets Go programs run Nuru code, so that Nuru can be used as a
+// scripting language inside them:
+//
+//	interp := nuru.New()
+//	interp.Set("jina", "Asha")
+//	result, err := interp.Eval(`"Habari " + jina`)
+//
+// Each Interp keeps its own variables between calls to Eval. Modules loaded
+// with 'tumia', and where andika writes, are shared by every Interp.
+package nuru
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/parser"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+// AnyArity is the arity of a builtin that takes any number of arguments
+const AnyArity = evaluator.AnyArity
+
+// Variadic is the arity of a builtin that takes min arguments or more, like
+// a function whose last parameter is '...hoja'
+func Variadic(min int) int {
+	return evaluator.Variadic(min)
+}
+
+// RegisterBuiltin adds fn to the builtins that code run by every Interp can
+// call, under name. A call with a different number of arguments than arity,
+// or fewer than a Variadic arity allows, fails before fn is called. Builtins
+// should be registered before any code runs.
+func RegisterBuiltin(name string, arity int, fn object.BuiltinFunction) error {
+	return evaluator.RegisterBuiltin(name, arity, fn)
+}
+
+// Interp runs Nuru code
+type Interp struct {
+	env *object.Environment
+}
+
+// New makes an interpreter with no variables of its own
+func New() *Interp {
+	return &Interp{env: object.NewEnvironment()}
+}
+
+// SyntaxError is returned when code can't be parsed
+type SyntaxError struct {
+	Errors []string
+}
+
+func (e *SyntaxError) Error() string {
+	return "Kuna makosa:\n\t" + strings.Join(e.Errors, "\n\t")
+}
+
+// Error is returned when running code fails
+type Error struct {
+	Message  string
+	Position token.Position // where the error happened in the code
+}
+
+func (e *Error) Error() string {
+	if e.Position.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Position, e.Message)
+}
+
+// Eval runs src and returns the value of what it ran last. Variables made by
+// src can be read by later calls.
+func (i *Interp) Eval(src string) (object.Object, error) {
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, &SyntaxError{Errors: p.Errors()}
+	}
+	return result(evaluator.Eval(program, i.env))
+}
+
+// Set makes name hold value in the code that is run. value can be an
+// object.Object, or a Go value that ToObject turns into one.
+func (i *Interp) Set(name string, value interface{}) error {
+	obj, err := ToObject(value)
+	if err != nil {
+		return err
+	}
+	_, err = result(i.env.Set(name, obj))
+	return err
+}
+
+// RegisterBuiltin is like the RegisterBuiltin function, but fn can only be
+// called by code run by i, and name may replace one of the usual builtins
+func (i *Interp) RegisterBuiltin(name string, arity int, fn object.BuiltinFunction) error {
+	builtin, err := evaluator.NewBuiltin(name, arity, fn)
+	if err != nil {
+		return err
+	}
+	i.env.Set(name, builtin)
+	return nil
+}
+
+// Get returns the value of name, if the code has one with that name
+func (i *Interp) Get(name string) (object.Object, bool) {
+	return i.env.Get(name)
+}
+
+// Call calls the function name with args, which are turned into Nuru values
+// like Set does
+func (i *Interp) Call(name string, args ...interface{}) (object.Object, error) {
+	fn, ok := i.env.Get(name)
+	if !ok {
+		return nil, &Error{Message: "Neno Halifahamiki: " + name}
+	}
+	objects := make([]object.Object, len(args))
+	for n, arg := range args {
+		obj, err := ToObject(arg)
+		if err != nil {
+			return nil, err
+		}
+		objects[n] = obj
+	}
+	return result(evaluator.ApplyFunction(fn, objects))
+}
+
+func result(obj object.Object) (object.Object, error) {
+	if err, ok := obj.(*object.Error); ok {
+		return nil, &Error{Message: evaluator.ErrorMessage(err), Position: err.Position}
+	}
+	if obj == nil {
+		return evaluator.NULL, nil
+	}
+	return obj, nil
+}
+
+// ToObject turns a Go value into a Nuru one. It takes nil, booleans, numbers,
+// strings, slices, maps with string keys and functions taking and returning
+// object.Object, as well as values that are already an object.Object.
+func ToObject(value interface{}) (object.Object, error) {
+	switch v := value.(type) {
+	case nil:
+		return evaluator.NULL, nil
+	case object.Object:
+		return v, nil
+	case bool:
+		if v {
+			return evaluator.TRUE, nil
+		}
+		return evaluator.FALSE, nil
+	case string:
+		return &object.String{Value: v}, nil
+	case float32:
+		return &object.Float{Value: float64(v)}, nil
+	case float64:
+		return &object.Float{Value: v}, nil
+	case func(args ...object.Object) object.Object:
+		return &object.Builtin{Fn: v}, nil
+	case object.BuiltinFunction:
+		return &object.Builtin{Fn: v}, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &object.Integer{Value: rv.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("Namba %d ni kubwa mno", rv.Uint())
+		}
+		return &object.Integer{Value: int64(rv.Uint())}, nil
+	case reflect.Slice, reflect.Array:
+		elements := make([]object.Object, rv.Len())
+		for n := range elements {
+			el, err := ToObject(rv.Index(n).Interface())
+			if err != nil {
+				return nil, err
+			}
+			elements[n] = el
+		}
+		return &object.Array{Elements: elements}, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("Kamusi inahitaji funguo za maneno, sio %s", rv.Type().Key())
+		}
+		dict := &object.Dict{Pairs: make(map[object.HashKey]object.DictPair)}
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := &object.String{Value: iter.Key().String()}
+			val, err := ToObject(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			dict.Pairs[key.HashKey()] = object.DictPair{Key: key, Value: val}
+		}
+		return dict, nil
+	}
+	return nil, fmt.Errorf("Aina %T haiwezi kugeuzwa kuwa ya Nuru", value)
+}
+
+// ToGo turns a Nuru value into a plain Go one: nil, bool, int64, float64,
+// string, []interface{} or, for a dict, map[string]interface{} with the keys
+// as Nuru shows them. Other values are returned as they are.
+func ToGo(obj object.Object) interface{} {
+	switch obj := obj.(type) {
+	case nil, *object.Null:
+		return nil
+	case *object.Boolean:
+		return obj.Value
+	case *object.Integer:
+		return obj.Value
+	case *object.Float:
+		return obj.Value
+	case *object.String:
+		return obj.Value
+	case *object.Array:
+		out := make([]interface{}, len(obj.Elements))
+		for n, el := range obj.Elements {
+			out[n] = ToGo(el)
+		}
+		return out
+	case *object.Dict:
+		out := make(map[string]interface{}, len(obj.Pairs))
+		for _, pair := range obj.Pairs {
+			out[pair.Key.Inspect()] = ToGo(pair.Value)
+		}
+		return out
+	default:
+		return obj
+	}
+}
@@ -0,0 +1,146 @@
+package nuru
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+func TestEval(t *testing.T) {
+	interp := New()
+	if _, err := interp.Eval(`fanya x = 5; fanya ongeza = unda(a, b) { a + b }`); err != nil {
+		t.Fatalf("Eval failed: %s", err)
+	}
+
+	// variables stay between calls
+	result, err := interp.Eval(`ongeza(x, 2)`)
+	if err != nil {
+		t.Fatalf("Eval failed: %s", err)
+	}
+	if result.Inspect() != "7" {
+		t.Errorf("wrong result. got=%s", result.Inspect())
+	}
+
+	result, err = interp.Eval(`fanya y = 1`)
+	if err != nil || result.Type() != object.NULL_OBJ {
+		t.Errorf("expected null, got=%v, %v", result, err)
+	}
+
+	// another interpreter has its own variables
+	if _, ok := New().Get("x"); ok {
+		t.Errorf("x leaked into a new interpreter")
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	interp := New()
+
+	_, err := interp.Eval(`fanya = 5`)
+	if _, ok := err.(*SyntaxError); !ok {
+		t.Errorf("expected a SyntaxError, got=%T(%v)", err, err)
+	}
+
+	_, err = interp.Eval("fanya x = 1\nx + kweli")
+	nerr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected an Error, got=%T(%v)", err, err)
+	}
+	if nerr.Message != "Aina Hazilingani: NAMBA + BOOLEAN" || nerr.Position.Line != 2 {
+		t.Errorf("wrong error. got=%+v", nerr)
+	}
+	if nerr.Error() != "Mstari 2, Safu 3: Aina Hazilingani: NAMBA + BOOLEAN" {
+		t.Errorf("wrong error text. got=%q", nerr.Error())
+	}
+}
+
+func TestSetAndGet(t *testing.T) {
+	interp := New()
+	values := map[string]interface{}{
+		"jina":   "Asha",
+		"umri":   20,
+		"urefu":  1.65,
+		"hai":    true,
+		"hakuna": nil,
+		"alama":  []int{90, 85},
+		"mtu":    map[string]interface{}{"jina": "Juma"},
+		"mara2": func(args ...object.Object) object.Object {
+			n := args[0].(*object.Integer).Value
+			return &object.Integer{Value: n * 2}
+		},
+	}
+	for name, value := range values {
+		if err := interp.Set(name, value); err != nil {
+			t.Fatalf("Set(%q) failed: %s", name, err)
+		}
+	}
+
+	result, err := interp.Eval(`fanya jibu = [jina, umri + 1, urefu, hai, hakuna, alama[1], mtu["jina"], mara2(21)]`)
+	if err != nil {
+		t.Fatalf("Eval failed: %s, %v", err, result)
+	}
+	jibu, ok := interp.Get("jibu")
+	if !ok {
+		t.Fatalf("jibu was not set")
+	}
+	expected := []interface{}{"Asha", int64(21), 1.65, true, nil, int64(85), "Juma", int64(42)}
+	if got := ToGo(jibu); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values. want=%v, got=%v", expected, got)
+	}
+
+	// a boolean from Go works in conditions like one made in Nuru
+	interp.Set("hai", false)
+	result, _ = interp.Eval(`kama (hai) { "ndio" } sivyo { "hapana" }`)
+	if ToGo(result) != "hapana" {
+		t.Errorf("wrong branch taken. got=%v", result.Inspect())
+	}
+
+	if err := interp.Set("chaneli", make(chan int)); err == nil {
+		t.Errorf("expected an error for a channel")
+	}
+
+	interp.Eval(`thabiti PI = 3.14`)
+	if err := interp.Set("PI", 3); err == nil || !strings.Contains(err.Error(), "PI ni thabiti") {
+		t.Errorf("expected an error for a constant, got=%v", err)
+	}
+}
+
+func TestCall(t *testing.T) {
+	interp := New()
+	interp.Eval(`fanya salamu = unda(jina) { "Habari " + jina }`)
+
+	result, err := interp.Call("salamu", "Asha")
+	if err != nil || ToGo(result) != "Habari Asha" {
+		t.Errorf("wrong result. got=%v, %v", result, err)
+	}
+	if _, err := interp.Call("hakuna"); err == nil || err.Error() != "Neno Halifahamiki: hakuna" {
+		t.Errorf("wrong error. got=%v", err)
+	}
+	if _, err := interp.Call("salamu", 5); err == nil {
+		t.Errorf("expected an error from inside salamu")
+	}
+}
+
+func TestRegisterBuiltin(t *testing.T) {
+	shout := func(args ...object.Object) object.Object {
+		return &object.String{Value: strings.ToUpper(args[0].Inspect())}
+	}
+
+	interp := New()
+	if err := interp.RegisterBuiltin("piga_kelele", 1, shout); err != nil {
+		t.Fatalf("RegisterBuiltin failed: %s", err)
+	}
+	result, err := interp.Eval(`piga_kelele("habari")`)
+	if err != nil || ToGo(result) != "HABARI" {
+		t.Errorf("wrong result. got=%v, %v", result, err)
+	}
+	if _, err := interp.Eval(`piga_kelele()`); err == nil || err.Error() != "Mstari 1, Safu 12: Hoja hazilingani, tunahitaji=1, tumepewa=0" {
+		t.Errorf("wrong error. got=%v", err)
+	}
+
+	// only the interpreter it was registered with has it
+	if _, err := New().Eval(`piga_kelele("habari")`); err == nil {
+		t.Errorf("piga_kelele leaked into a new interpreter")
+	}
+}
@@ -1,23 +1,44 @@
 package object
 
+import (
+	"fmt"
+	"sync"
+)
+
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
 	return env
 }
 
+// NewBlockEnvironment makes an environment for the names of a block, like
+// those a 'linganisha' pattern takes. Unlike that of a function, assigning a
+// name it doesn't hold changes the one around it.
+func NewBlockEnvironment(outer *Environment) *Environment {
+	env := NewEnclosedEnvironment(outer)
+	env.block = true
+	return env
+}
+
 func NewEnvironment() *Environment {
 	s := make(map[string]Object)
-	return &Environment{store: s, outer: nil}
+	return &Environment{store: s, constants: make(map[string]bool), outer: nil}
 }
 
+// Environment holds the variables of a scope. Functions started with
+// 'sambamba' share the environments they close over, so they are locked.
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	mu        sync.RWMutex
+	store     map[string]Object
+	constants map[string]bool // names made with 'thabiti'
+	outer     *Environment
+	block     bool
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
+	e.mu.RLock()
 	obj, ok := e.store[name]
+	e.mu.RUnlock()
 
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.Get(name)
@@ -25,7 +46,129 @@ func (e *Environment) Get(name string) (Object, bool) {
 	return obj, ok
 }
 
+// Set gives name the value val in e. A constant of e keeps its value, and
+// an Error naming it is returned instead.
 func (e *Environment) Set(name string, val Object) Object {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.constants[name] {
+		return constantError(name)
+	}
 	e.store[name] = val
 	return val
 }
+
+// SetConst is Set for a name made with 'thabiti', which can't be given
+// another value afterwards
+func (e *Environment) SetConst(name string, val Object) Object {
+	if err, ok := e.Set(name, val).(*Error); ok {
+		return err
+	}
+	e.mu.Lock()
+	e.constants[name] = true
+	e.mu.Unlock()
+	return val
+}
+
+// Update is Set for an assignment like 'x = 1'. It also refuses when the
+// name is a constant of an enclosing environment that e doesn't hide, since
+// the assignment means to change that one.
+func (e *Environment) Update(name string, val Object) Object {
+	if err := e.checkConstant(name); err != nil {
+		return err
+	}
+	if e.block {
+		e.mu.RLock()
+		_, ok := e.store[name]
+		e.mu.RUnlock()
+		if !ok {
+			return e.outer.Update(name, val)
+		}
+	}
+	return e.Set(name, val)
+}
+
+// Define is Set for a name that hides any of the same name around e. Like
+// Update, it refuses to hide a constant.
+func (e *Environment) Define(name string, val Object) Object {
+	if err := e.checkConstant(name); err != nil {
+		return err
+	}
+	return e.Set(name, val)
+}
+
+// checkConstant returns the Error for changing name from e when name is a
+// constant there, or of an enclosing environment that e doesn't hide
+func (e *Environment) checkConstant(name string) *Error {
+	for env := e; env != nil; env = env.outer {
+		env.mu.RLock()
+		_, ok := env.store[name]
+		constant := env.constants[name]
+		env.mu.RUnlock()
+		if constant {
+			return constantError(name)
+		}
+		if ok {
+			break
+		}
+	}
+	return nil
+}
+
+func constantError(name string) *Error {
+	return &Error{Message: fmt.Sprintf("\x1b[%dm%s ni thabiti, haiwezi kupewa thamani nyingine\x1b[0m", 31, name)}
+}
+
+// Outer returns the environment e is enclosed in, or nil for the top one
+func (e *Environment) Outer() *Environment {
+	return e.outer
+}
+
+// Names returns the names of everything visible from e, including the
+// enclosing environments
+func (e *Environment) Names() []string {
+	var names []string
+	e.mu.RLock()
+	for name := range e.store {
+		names = append(names, name)
+	}
+	e.mu.RUnlock()
+	if e.outer != nil {
+		names = append(names, e.outer.Names()...)
+	}
+	return names
+}
+
+// Locals returns the bindings made in e itself, without those of the
+// enclosing environments
+func (e *Environment) Locals() map[string]Object {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	locals := make(map[string]Object, len(e.store))
+	for name, val := range e.store {
+		locals[name] = val
+	}
+	return locals
+}
+
+// Assign changes the value of name in the environment it was made in. Like
+// Set, it refuses to change a constant and returns an Error for it instead,
+// and it returns nil when name isn't found at all.
+func (e *Environment) Assign(name string, val Object) Object {
+	for env := e; env != nil; env = env.outer {
+		env.mu.Lock()
+		_, ok := env.store[name]
+		constant := env.constants[name]
+		if ok && !constant {
+			env.store[name] = val
+		}
+		env.mu.Unlock()
+		if constant {
+			return constantError(name)
+		}
+		if ok {
+			return val
+		}
+	}
+	return nil
+}
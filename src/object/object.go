@@ -1,14 +1,32 @@
 package object
 
 import (
+	"bufio"
 	"bytes"
+	"container/list"
+	"database/sql"
+	"encoding/binary"
 	"fmt"
 	"hash/fnv"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 
 	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/code"
+	"github.com/AvicennaJr/Nuru/token"
+	"github.com/gorilla/websocket"
 )
 
 type ObjectType string
@@ -19,6 +37,7 @@ const (
 	BOOLEAN_OBJ      = "BOOLEAN"
 	NULL_OBJ         = "TUPU"
 	RETURN_VALUE_OBJ = "RUDISHA"
+	TAIL_CALL_OBJ    = "MWITO"
 	ERROR_OBJ        = "KOSA"
 	FUNCTION_OBJ     = "UNDO (FUNCTION)"
 	STRING_OBJ       = "NENO"
@@ -27,6 +46,30 @@ const (
 	DICT_OBJ         = "KAMUSI"
 	CONTINUE_OBJ     = "ENDELEA"
 	BREAK_OBJ        = "VUNJA"
+	MODULE_OBJ       = "MODULI"
+	RANGE_OBJ        = "MPAKA"
+	CLASS_OBJ        = "MUUNDO"
+	INSTANCE_OBJ     = "KITU"
+	FILE_OBJ         = "FAILI"
+	TIME_OBJ         = "MUDA"
+	REGEX_OBJ        = "REGEX"
+	SET_OBJ          = "SETI"
+	TUPLE_OBJ        = "JOZI"
+	BYTES_OBJ        = "BAITI"
+	BIGINT_OBJ       = "NAMBA_KUBWA"
+	DECIMAL_OBJ      = "DESIMALI_KAMILI"
+	FUTURE_OBJ       = "AHADI"
+	CHANNEL_OBJ      = "MFEREJI"
+	LOCK_OBJ         = "KUFULI"
+	WAIT_GROUP_OBJ   = "KIKUNDI"
+	PROCESS_OBJ      = "MCHAKATO"
+	DATABASE_OBJ     = "HIFADHIDATA"
+	CONNECTION_OBJ   = "MUUNGANO"
+	LISTENER_OBJ     = "MSIKILIZAJI"
+	WEBSOCKET_OBJ    = "WEBSOCKET"
+	GENERATOR_OBJ    = "KIZALISHI"
+
+	COMPILED_FUNCTION_OBJ = "UNDO_ILIYOKUSANYWA"
 )
 
 type Object interface {
@@ -73,18 +116,61 @@ type ReturnValue struct {
 func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 func (rv *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
 
+// TailCall is a call in tail position that has been put off so the function
+// making it can return first. It never reaches a script.
+type TailCall struct {
+	Function  *Function
+	Arguments []Object
+	Position  token.Position
+}
+
+func (tc *TailCall) Inspect() string  { return "mwito" }
+func (tc *TailCall) Type() ObjectType { return TAIL_CALL_OBJ }
+
 type Error struct {
-	Message string
+	Message  string
+	Position token.Position // where the error happened, set as it is returned
+	Trace    []Frame        // the function calls the error came out of, innermost first
+	Skipped  int            // calls left out of Trace, like all but the last few tail calls
 }
 
+// Frame is one function call in the trace of an error
+type Frame struct {
+	Function string
+	Position token.Position // where the function was called from
+}
+
+// maxTraceFrames is how many calls of a trace are shown before the rest are
+// summarised, so deep recursion doesn't flood the screen
+const maxTraceFrames = 20
+
 func (e *Error) Inspect() string {
 	msg := fmt.Sprintf("\x1b[%dm%s\x1b[0m", 31, "Kosa: ")
-	return msg + e.Message
+	if e.Position.Line > 0 {
+		msg += fmt.Sprintf("\x1b[%dm%s: \x1b[0m", 31, e.Position)
+	}
+	msg += e.Message
+
+	shown := len(e.Trace)
+	if shown > maxTraceFrames {
+		shown = maxTraceFrames
+	}
+	for _, frame := range e.Trace[:shown] {
+		msg += fmt.Sprintf("\n    ndani ya %s, imeitwa %s", frame.Function, frame.Position)
+	}
+	if hidden := len(e.Trace) - shown + e.Skipped; hidden > 0 {
+		msg += fmt.Sprintf("\n    ... na miito mingine %d", hidden)
+	}
+	return msg
 }
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
 
 type Function struct {
+	Name       string // empty until the function is bound with 'fanya'
 	Parameters []*ast.Identifier
+	Variadic   bool   // the last parameter collects the extra arguments
+	Generator  bool   // the body uses 'toa', so calling it gives a Generator
+	Doc        string // the string the body starts with, shown by 'msaada'
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
@@ -93,38 +179,58 @@ func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
 func (f *Function) Inspect() string {
 	var out bytes.Buffer
 
-	params := []string{}
-	for _, p := range f.Parameters {
-		params = append(params, p.String())
-	}
-
 	out.WriteString("unda")
-	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(") {\n")
+	out.WriteString(f.parameters())
+	out.WriteString(" {\n")
 	out.WriteString(f.Body.String())
 	out.WriteString("\n}")
 
 	return out.String()
 }
 
+// Signature is how f is called, like 'unda jumla(a, b)'
+func (f *Function) Signature() string {
+	if f.Name == "" {
+		return "unda" + f.parameters()
+	}
+	return "unda " + f.Name + f.parameters()
+}
+
+func (f *Function) parameters() string {
+	params := []string{}
+	for _, p := range f.Parameters {
+		params = append(params, p.String())
+	}
+	if f.Variadic {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
+	return "(" + strings.Join(params, ", ") + ")"
+}
+
 type String struct {
 	Value  string
-	offset int
+	offset int // the byte Next reads the next character from
+	index  int // the number of characters Next has given
 }
 
 func (s *String) Inspect() string  { return s.Value }
 func (s *String) Type() ObjectType { return STRING_OBJ }
+
+// Next gives the characters of s one at a time with their index, counting
+// characters rather than bytes, so that letters like "ŋ" come out whole
 func (s *String) Next() (Object, Object) {
-	offset := s.offset
-	if len(s.Value) > offset {
-		s.offset = offset + 1
-		return &Integer{Value: int64(offset)}, &String{Value: string(s.Value[offset])}
+	if len(s.Value) > s.offset {
+		ch, size := utf8.DecodeRuneInString(s.Value[s.offset:])
+		idx := s.index
+		s.offset += size
+		s.index++
+		return &Integer{Value: int64(idx)}, &String{Value: string(ch)}
 	}
 	return nil, nil
 }
 func (s *String) Reset() {
 	s.offset = 0
+	s.index = 0
 }
 
 type BuiltinFunction func(args ...Object) Object
@@ -170,6 +276,135 @@ func (ao *Array) Reset() {
 	ao.offset = 0
 }
 
+// Tuple is a fixed list of hashable elements. It can't be changed once made,
+// so it can be used as a dict key or a set element.
+type Tuple struct {
+	Elements []Object
+	offset   int
+}
+
+func (t *Tuple) Type() ObjectType { return TUPLE_OBJ }
+func (t *Tuple) Inspect() string {
+	elements := []string{}
+	for _, el := range t.Elements {
+		elements = append(elements, el.Inspect())
+	}
+	return "jozi(" + strings.Join(elements, ", ") + ")"
+}
+
+func (t *Tuple) Next() (Object, Object) {
+	idx := t.offset
+	if len(t.Elements) > idx {
+		t.offset = idx + 1
+		return &Integer{Value: int64(idx)}, t.Elements[idx]
+	}
+	return nil, nil
+}
+
+func (t *Tuple) Reset() {
+	t.offset = 0
+}
+
+// HashKey combines the hash keys of the elements, so two tuples with equal
+// elements in the same order get the same key
+func (t *Tuple) HashKey() HashKey {
+	h := fnv.New64a()
+	buf := make([]byte, 8)
+	for _, el := range t.Elements {
+		key := el.(Hashable).HashKey()
+		h.Write([]byte(key.Type))
+		binary.BigEndian.PutUint64(buf, key.Value)
+		h.Write(buf)
+	}
+	return HashKey{Type: t.Type(), Value: h.Sum64()}
+}
+
+// Bytes holds raw binary data, like the contents of an image file, which
+// would be corrupted if it was forced into a String
+type Bytes struct {
+	Value  []byte
+	offset int
+}
+
+func (b *Bytes) Type() ObjectType { return BYTES_OBJ }
+func (b *Bytes) Inspect() string  { return "baiti(" + strconv.Quote(string(b.Value)) + ")" }
+
+func (b *Bytes) Next() (Object, Object) {
+	idx := b.offset
+	if len(b.Value) > idx {
+		b.offset = idx + 1
+		return &Integer{Value: int64(idx)}, &Integer{Value: int64(b.Value[idx])}
+	}
+	return nil, nil
+}
+
+func (b *Bytes) Reset() {
+	b.offset = 0
+}
+
+func (b *Bytes) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write(b.Value)
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
+
+// BigInt is an integer too big for an Integer. Arithmetic on integers
+// switches to a BigInt when the result overflows, and back to an Integer
+// when it fits again, so a BigInt is never a number an Integer could hold.
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b *BigInt) Type() ObjectType { return BIGINT_OBJ }
+func (b *BigInt) Inspect() string  { return b.Value.String() }
+
+func (b *BigInt) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write(b.Value.Bytes())
+	if b.Value.Sign() < 0 {
+		h.Write([]byte{'-'})
+	}
+	return HashKey{Type: b.Type(), Value: h.Sum64()}
+}
+
+// Decimal is an exact decimal number, for things like money where the
+// rounding of a Float gives wrong answers. The value is always a number
+// with a finite number of decimal places.
+type Decimal struct {
+	Value *big.Rat
+}
+
+func (d *Decimal) Type() ObjectType { return DECIMAL_OBJ }
+func (d *Decimal) Inspect() string {
+	// the denominator only has factors of 2 and 5, and the larger count
+	// of the two is the number of decimal places needed
+	denom := new(big.Int).Set(d.Value.Denom())
+	places := 0
+	for _, factor := range []int64{2, 5} {
+		count := 0
+		f := big.NewInt(factor)
+		mod := new(big.Int)
+		for {
+			quo, rem := new(big.Int).QuoRem(denom, f, mod)
+			if rem.Sign() != 0 {
+				break
+			}
+			denom = quo
+			count++
+		}
+		if count > places {
+			places = count
+		}
+	}
+	return d.Value.FloatString(places)
+}
+
+func (d *Decimal) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(d.Inspect()))
+	return HashKey{Type: d.Type(), Value: h.Sum64()}
+}
+
 type HashKey struct {
 	Type  ObjectType
 	Value uint64
@@ -256,6 +491,48 @@ func (d *Dict) Reset() {
 	d.offset = 0
 }
 
+// Set holds unique elements, stored by their HashKey like the keys of a Dict
+type Set struct {
+	Elements map[HashKey]Object
+	offset   int
+}
+
+func (s *Set) Type() ObjectType { return SET_OBJ }
+func (s *Set) Inspect() string {
+	elements := []string{}
+	for _, el := range s.Sorted() {
+		elements = append(elements, el.Inspect())
+	}
+	return "seti([" + strings.Join(elements, ", ") + "])"
+}
+
+// Sorted returns the elements ordered by how they print, so that sets
+// always print and loop in the same order
+func (s *Set) Sorted() []Object {
+	elements := make([]Object, 0, len(s.Elements))
+	for _, el := range s.Elements {
+		elements = append(elements, el)
+	}
+	sort.Slice(elements, func(i, j int) bool {
+		return elements[i].Inspect() < elements[j].Inspect()
+	})
+	return elements
+}
+
+func (s *Set) Next() (Object, Object) {
+	elements := s.Sorted()
+	if s.offset < len(elements) {
+		idx := s.offset
+		s.offset++
+		return &Integer{Value: int64(idx)}, elements[idx]
+	}
+	return nil, nil
+}
+
+func (s *Set) Reset() {
+	s.offset = 0
+}
+
 type Hashable interface {
 	HashKey() HashKey
 }
@@ -270,8 +547,487 @@ type Break struct{}
 func (b *Break) Type() ObjectType { return BREAK_OBJ }
 func (b *Break) Inspect() string  { return "break" }
 
-// Iterable interface for dicts, strings and arrays
+// Range is the lazy sequence of integers returned by mpaka(). The end is
+// never included, and nothing is allocated however large the range is.
+type Range struct {
+	Start  int64
+	End    int64
+	Step   int64
+	offset int64
+}
+
+func (r *Range) Type() ObjectType { return RANGE_OBJ }
+func (r *Range) Inspect() string {
+	if r.Step == 1 {
+		return fmt.Sprintf("mpaka(%d, %d)", r.Start, r.End)
+	}
+	return fmt.Sprintf("mpaka(%d, %d, %d)", r.Start, r.End, r.Step)
+}
+
+func (r *Range) Next() (Object, Object) {
+	value := r.Start + r.offset*r.Step
+	if (r.Step > 0 && value >= r.End) || (r.Step < 0 && value <= r.End) {
+		return nil, nil
+	}
+	idx := r.offset
+	r.offset++
+	return &Integer{Value: idx}, &Integer{Value: value}
+}
+
+func (r *Range) Reset() {
+	r.offset = 0
+}
+
+// File is returned by fungua_faili(). Looping over it with 'kwa' reads it
+// one line at a time instead of loading the whole file.
+type File struct {
+	Path    string
+	file    *os.File
+	scanner *bufio.Scanner
+	line    int64
+}
+
+func (f *File) Type() ObjectType { return FILE_OBJ }
+func (f *File) Inspect() string  { return "<faili " + f.Path + ">" }
+
+func (f *File) Next() (Object, Object) {
+	if f.scanner == nil {
+		file, err := os.Open(f.Path)
+		if err != nil {
+			return nil, nil
+		}
+		f.file = file
+		f.scanner = bufio.NewScanner(file)
+	}
+
+	if !f.scanner.Scan() {
+		f.Reset()
+		return nil, nil
+	}
+
+	idx := f.line
+	f.line++
+	return &Integer{Value: idx}, &String{Value: f.scanner.Text()}
+}
+
+func (f *File) Reset() {
+	if f.file != nil {
+		f.file.Close()
+	}
+	f.file = nil
+	f.scanner = nil
+	f.line = 0
+}
+
+// Process is a command started with endesha_mkondo(). Looping over it with
+// 'kwa' reads what the command prints one line at a time, while it runs.
+type Process struct {
+	Cmd     *exec.Cmd
+	Output  io.Reader // the command's stdout
+	scanner *bufio.Scanner
+	line    int64
+}
+
+func NewProcess(cmd *exec.Cmd, output io.Reader) *Process {
+	return &Process{Cmd: cmd, Output: output, scanner: bufio.NewScanner(output)}
+}
+
+func (p *Process) Type() ObjectType { return PROCESS_OBJ }
+func (p *Process) Inspect() string  { return "<mchakato " + p.Cmd.Args[0] + ">" }
+
+func (p *Process) Next() (Object, Object) {
+	if !p.scanner.Scan() {
+		return nil, nil
+	}
+	idx := p.line
+	p.line++
+	return &Integer{Value: idx}, &String{Value: p.scanner.Text()}
+}
+
+// Reset does nothing, since lines the command printed can't be read again
+func (p *Process) Reset() {}
+
+// Database is an SQLite database opened with hifadhidata.fungua()
+type Database struct {
+	Path  string
+	Value *sql.DB
+}
+
+func (d *Database) Type() ObjectType { return DATABASE_OBJ }
+func (d *Database) Inspect() string  { return "<hifadhidata " + d.Path + ">" }
+
+// Connection is a network connection made by mtandao.unganisha() or
+// accepted by a Listener. Looping over it with 'kwa' reads it one line at a
+// time until the other side closes it.
+type Connection struct {
+	Conn   net.Conn
+	Reader *bufio.Reader // reads from Conn, shared by every way of reading
+	line   int64
+}
+
+func NewConnection(conn net.Conn) *Connection {
+	return &Connection{Conn: conn, Reader: bufio.NewReader(conn)}
+}
+
+func (c *Connection) Type() ObjectType { return CONNECTION_OBJ }
+func (c *Connection) Inspect() string {
+	return "<muungano " + c.Conn.RemoteAddr().String() + ">"
+}
+
+func (c *Connection) Next() (Object, Object) {
+	line, err := c.Reader.ReadString('\n')
+	if line == "" && err != nil {
+		return nil, nil
+	}
+	idx := c.line
+	c.line++
+	return &Integer{Value: idx}, &String{Value: strings.TrimRight(line, "\r\n")}
+}
+
+// Reset does nothing, since what was read from a connection is gone
+func (c *Connection) Reset() {}
+
+// Listener waits for connections, for a server made with
+// mtandao.sikiliza()
+type Listener struct {
+	Value net.Listener
+}
+
+func (l *Listener) Type() ObjectType { return LISTENER_OBJ }
+func (l *Listener) Inspect() string  { return "<msikilizaji " + l.Value.Addr().String() + ">" }
+
+// WebSocket is a connection made with mtandao.websocket(). Looping over it
+// with 'kwa' receives messages until it is closed.
+type WebSocket struct {
+	Conn     *websocket.Conn
+	readMu   sync.Mutex // only one function may read a connection at a time
+	writeMu  sync.Mutex // and only one may write to it
+	received int64
+}
+
+func (w *WebSocket) Type() ObjectType { return WEBSOCKET_OBJ }
+func (w *WebSocket) Inspect() string  { return "<websocket " + w.Conn.RemoteAddr().String() + ">" }
+
+// Receive waits for the next message, which is a String if it was sent as
+// text and Bytes otherwise
+func (w *WebSocket) Receive() (Object, error) {
+	w.readMu.Lock()
+	defer w.readMu.Unlock()
+
+	kind, data, err := w.Conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	if kind == websocket.TextMessage {
+		return &String{Value: string(data)}, nil
+	}
+	return &Bytes{Value: data}, nil
+}
+
+// Send sends data as a text message if text is true, and a binary one if not
+func (w *WebSocket) Send(data []byte, text bool) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	kind := websocket.BinaryMessage
+	if text {
+		kind = websocket.TextMessage
+	}
+	return w.Conn.WriteMessage(kind, data)
+}
+
+// Close tells the other side the connection is over, then closes it
+func (w *WebSocket) Close() error {
+	w.writeMu.Lock()
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	w.Conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	w.writeMu.Unlock()
+	return w.Conn.Close()
+}
+
+func (w *WebSocket) Next() (Object, Object) {
+	msg, err := w.Receive()
+	if err != nil {
+		return nil, nil
+	}
+	idx := atomic.AddInt64(&w.received, 1) - 1
+	return &Integer{Value: idx}, msg
+}
+
+// Reset does nothing, since messages that were received are gone
+func (w *WebSocket) Reset() {}
+
+// TimeFormat is how a Time prints, and the first format muda.changanua tries
+const TimeFormat = "2006-01-02 15:04:05"
+
+type Time struct {
+	Value time.Time
+}
+
+func (t *Time) Type() ObjectType { return TIME_OBJ }
+func (t *Time) Inspect() string  { return t.Value.Format(TimeFormat) }
+
+type Regex struct {
+	Pattern string
+	Value   *regexp.Regexp
+}
+
+func (r *Regex) Type() ObjectType { return REGEX_OBJ }
+func (r *Regex) Inspect() string  { return "regex(\"" + r.Pattern + "\")" }
+
+// regexCacheSize is how many compiled patterns are kept. Past it the one
+// used longest ago is dropped, so that patterns built from input can't grow
+// the cache without end.
+const regexCacheSize = 256
+
+var (
+	regexCache   = make(map[string]*list.Element)
+	regexUsed    = list.New() // the *Regex values, the last used at the front
+	regexCacheMu sync.Mutex
+)
+
+// CompileRegex compiles a pattern, reusing the result if the same pattern
+// was compiled recently so that using a regex inside a loop stays cheap
+func CompileRegex(pattern string) (*Regex, error) {
+	regexCacheMu.Lock()
+	defer regexCacheMu.Unlock()
+
+	if el, ok := regexCache[pattern]; ok {
+		regexUsed.MoveToFront(el)
+		return el.Value.(*Regex), nil
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	re := &Regex{Pattern: pattern, Value: compiled}
+	regexCache[pattern] = regexUsed.PushFront(re)
+	if regexUsed.Len() > regexCacheSize {
+		oldest := regexUsed.Remove(regexUsed.Back()).(*Regex)
+		delete(regexCache, oldest.Pattern)
+	}
+	return re, nil
+}
+
+// Iterable interface for dicts, strings, arrays, ranges, files, channels,
+// processes, connections and websockets
 type Iterable interface {
 	Next() (Object, Object)
 	Reset()
 }
+
+// Module holds the top-level bindings of a file loaded with 'tumia'
+type Module struct {
+	Name string
+	Env  *Environment
+}
+
+func (m *Module) Type() ObjectType { return MODULE_OBJ }
+func (m *Module) Inspect() string  { return fmt.Sprintf("<moduli %s>", m.Name) }
+
+// Future is what a call made with 'sambamba' returns. Result is set once the
+// call is over, which is when Done is closed.
+type Future struct {
+	Done   chan struct{}
+	Result Object
+}
+
+func (f *Future) Type() ObjectType { return FUTURE_OBJ }
+func (f *Future) Inspect() string  { return "<ahadi>" }
+
+// Channel passes values between functions started with 'sambamba'. Looping
+// over it with 'kwa' receives values until it is closed.
+type Channel struct {
+	Value    chan Object
+	received int64 // how many values loops have taken, changed atomically
+}
+
+func (c *Channel) Type() ObjectType { return CHANNEL_OBJ }
+func (c *Channel) Inspect() string  { return "<mfereji>" }
+
+func (c *Channel) Next() (Object, Object) {
+	val, ok := <-c.Value
+	if !ok {
+		return nil, nil
+	}
+	idx := atomic.AddInt64(&c.received, 1) - 1
+	return &Integer{Value: idx}, val
+}
+
+// Reset does nothing, since values taken from a channel are gone
+func (c *Channel) Reset() {}
+
+// Generator is what calling a function that uses 'toa' gives. Run evaluates
+// the body on its own goroutine once the first value is wanted, and every
+// 'toa' there calls Yield, which waits until the next one is.
+type Generator struct {
+	Run     func() Object // gives an *Error if the body fails
+	values  chan Object
+	resume  chan struct{}
+	stop    chan struct{} // closed by Reset, once nobody wants more values
+	index   int64
+	done    bool
+	stopped bool
+}
+
+func (g *Generator) Type() ObjectType { return GENERATOR_OBJ }
+func (g *Generator) Inspect() string  { return "<kizalishi>" }
+
+func (g *Generator) Next() (Object, Object) {
+	if g.done {
+		return nil, nil
+	}
+	if g.values == nil {
+		g.values = make(chan Object)
+		g.resume = make(chan struct{})
+		g.stop = make(chan struct{})
+		go func() {
+			defer close(g.values)
+			if err, ok := g.Run().(*Error); ok {
+				select {
+				case g.values <- err:
+				case <-g.stop:
+				}
+			}
+		}()
+	} else {
+		g.resume <- struct{}{}
+	}
+
+	val, ok := <-g.values
+	if !ok {
+		g.done = true
+		return nil, nil
+	}
+	if _, ok := val.(*Error); ok {
+		g.done = true
+	}
+	idx := g.index
+	g.index++
+	return &Integer{Value: idx}, val
+}
+
+// Yield hands val to Next and waits until another value is wanted. It is
+// only called from the goroutine that Run is on, which it ends once the
+// generator is stopped. That is done with Goexit rather than an Error, which
+// a 'jaribu' in the body could catch and carry on from.
+func (g *Generator) Yield(val Object) {
+	select {
+	case g.values <- val:
+	case <-g.stop:
+		runtime.Goexit()
+	}
+	select {
+	case <-g.resume:
+	case <-g.stop:
+		runtime.Goexit()
+	}
+}
+
+// Reset stops a generator that has started, since the loop over it is over.
+// Its goroutine is let go, and looping over it again gives nothing.
+func (g *Generator) Reset() {
+	if g.values == nil || g.stopped {
+		return
+	}
+	g.stopped = true
+	g.done = true
+	close(g.stop)
+}
+
+// Lock lets functions started with 'sambamba' take turns changing a shared
+// value. It is held while Held has something in it, which unlike a
+// sync.Mutex lets unlocking a lock nobody holds be reported as an error.
+type Lock struct {
+	Held chan struct{}
+}
+
+func NewLock() *Lock {
+	return &Lock{Held: make(chan struct{}, 1)}
+}
+
+func (l *Lock) Type() ObjectType { return LOCK_OBJ }
+func (l *Lock) Inspect() string  { return "<kufuli>" }
+
+// WaitGroup waits for a number of functions to finish
+type WaitGroup struct {
+	Value sync.WaitGroup
+}
+
+func (wg *WaitGroup) Type() ObjectType { return WAIT_GROUP_OBJ }
+func (wg *WaitGroup) Inspect() string  { return "<kikundi>" }
+
+// CompiledFunction is a function lowered to bytecode by the compiler
+type CompiledFunction struct {
+	Instructions  code.Instructions
+	Positions     []token.Position // source position of every byte in Instructions
+	NumLocals     int
+	NumParameters int
+	Variadic      bool // the last parameter collects the extra arguments
+}
+
+func (cf *CompiledFunction) Type() ObjectType { return COMPILED_FUNCTION_OBJ }
+func (cf *CompiledFunction) Inspect() string {
+	return fmt.Sprintf("<undo iliyokusanywa %p>", cf)
+}
+
+// Closure is a CompiledFunction together with the free variables it captured.
+// To scripts it is just another function.
+type Closure struct {
+	Fn   *CompiledFunction
+	Free []Object
+}
+
+func (c *Closure) Type() ObjectType { return FUNCTION_OBJ }
+func (c *Closure) Inspect() string  { return fmt.Sprintf("<undo %p>", c) }
+
+// Class is what a 'muundo' declaration evaluates to. Calling it creates an Instance.
+type Class struct {
+	Name        string
+	Fields      []*ast.LetStatement // evaluated again for every new instance
+	Constructor *Function
+	Methods     map[string]*Function
+	Env         *Environment
+}
+
+func (c *Class) Type() ObjectType { return CLASS_OBJ }
+func (c *Class) Inspect() string  { return "<muundo " + c.Name + ">" }
+
+type Instance struct {
+	Class  *Class
+	Fields map[string]Object
+}
+
+func (i *Instance) Type() ObjectType { return INSTANCE_OBJ }
+func (i *Instance) Inspect() string {
+	var out bytes.Buffer
+
+	names := []string{}
+	for name := range i.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := []string{}
+	for _, name := range names {
+		fields = append(fields, fmt.Sprintf("%s: %s", name, i.Fields[name].Inspect()))
+	}
+
+	out.WriteString(i.Class.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// BoundMethod is a method looked up on an instance, which becomes 'hii' when it is called
+type BoundMethod struct {
+	Instance *Instance
+	Method   *Function
+}
+
+func (bm *BoundMethod) Type() ObjectType { return FUNCTION_OBJ }
+func (bm *BoundMethod) Inspect() string  { return bm.Method.Inspect() }
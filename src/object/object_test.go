@@ -1,6 +1,11 @@
 package object
 
-import "testing"
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestStringHashKey(t *testing.T) {
 	hello1 := &String{Value: "Hello World"}
@@ -20,3 +25,245 @@ func TestStringHashKey(t *testing.T) {
 		t.Errorf("Strings with different content have the same dict keys")
 	}
 }
+
+func TestRangeIteration(t *testing.T) {
+	r := &Range{Start: 1, End: 7, Step: 2}
+
+	expected := []int64{1, 3, 5}
+	for pass := 0; pass < 2; pass++ {
+		for i, want := range expected {
+			idx, val := r.Next()
+			if idx.(*Integer).Value != int64(i) || val.(*Integer).Value != want {
+				t.Fatalf("wrong element %d. want=(%d, %d), got=(%s, %s)", i, i, want, idx.Inspect(), val.Inspect())
+			}
+		}
+		if idx, val := r.Next(); idx != nil || val != nil {
+			t.Fatalf("range did not stop at its end")
+		}
+		r.Reset()
+	}
+}
+
+func TestStringIteration(t *testing.T) {
+	s := &String{Value: "ŋa🐄"}
+
+	expected := []string{"ŋ", "a", "🐄"}
+	for pass := 0; pass < 2; pass++ {
+		for i, want := range expected {
+			idx, val := s.Next()
+			if idx.(*Integer).Value != int64(i) || val.(*String).Value != want {
+				t.Fatalf("wrong character %d. want=(%d, %q), got=(%s, %q)", i, i, want, idx.Inspect(), val.Inspect())
+			}
+		}
+		if idx, val := s.Next(); idx != nil || val != nil {
+			t.Fatalf("string did not stop at its end")
+		}
+		s.Reset()
+	}
+}
+
+func TestSetInspectIsSorted(t *testing.T) {
+	set := &Set{Elements: make(map[HashKey]Object)}
+	for _, v := range []int64{3, 1, 2} {
+		el := &Integer{Value: v}
+		set.Elements[el.HashKey()] = el
+	}
+
+	if got := set.Inspect(); got != "seti([1, 2, 3])" {
+		t.Fatalf("wrong Inspect. got=%q", got)
+	}
+
+	_, first := set.Next()
+	if first.(*Integer).Value != 1 {
+		t.Fatalf("set did not iterate in order. got=%s", first.Inspect())
+	}
+}
+
+func TestEnvironmentConstants(t *testing.T) {
+	outer := NewEnvironment()
+	outer.SetConst("PI", &Float{Value: 3.14})
+	inner := NewEnclosedEnvironment(outer)
+
+	tests := []struct {
+		name string
+		set  func() Object
+		fail bool
+	}{
+		{"Set in the same environment", func() Object { return outer.Set("PI", &Integer{Value: 3}) }, true},
+		{"SetConst again", func() Object { return outer.SetConst("PI", &Integer{Value: 3}) }, true},
+		{"Update from an enclosed environment", func() Object { return inner.Update("PI", &Integer{Value: 3}) }, true},
+		{"Assign from an enclosed environment", func() Object { return inner.Assign("PI", &Integer{Value: 3}) }, true},
+		{"Set hiding it in an enclosed environment", func() Object { return inner.Set("PI", &Integer{Value: 3}) }, false},
+		{"Update of the name that hides it", func() Object { return inner.Update("PI", &Integer{Value: 4}) }, false},
+		{"Update of another name", func() Object { return outer.Update("e", &Float{Value: 2.71}) }, false},
+	}
+
+	for _, tt := range tests {
+		err, failed := tt.set().(*Error)
+		if failed != tt.fail {
+			t.Fatalf("%s: want error=%t, got=%t", tt.name, tt.fail, failed)
+		}
+		if failed && !strings.Contains(err.Message, "PI ni thabiti") {
+			t.Errorf("%s: error does not name the constant: %q", tt.name, err.Message)
+		}
+	}
+
+	if pi, _ := outer.Get("PI"); pi.Inspect() != "3.14" {
+		t.Errorf("the constant was changed to %s", pi.Inspect())
+	}
+
+	block := NewBlockEnvironment(outer)
+	if _, failed := block.Define("PI", &Integer{Value: 3}).(*Error); !failed {
+		t.Errorf("Define hid the constant in a block")
+	}
+}
+
+func TestBlockEnvironment(t *testing.T) {
+	outer := NewEnvironment()
+	outer.Set("x", &Integer{Value: 1})
+	block := NewBlockEnvironment(outer)
+	block.Define("y", &Integer{Value: 2})
+
+	block.Update("x", &Integer{Value: 3})
+	block.Update("y", &Integer{Value: 4})
+	if x, _ := outer.Get("x"); x.Inspect() != "3" {
+		t.Errorf("Update in a block did not change the name around it, x=%s", x.Inspect())
+	}
+	if _, ok := block.Locals()["x"]; ok {
+		t.Errorf("Update in a block made a name of its own")
+	}
+	if y, _ := block.Get("y"); y.Inspect() != "4" {
+		t.Errorf("Update in a block did not change its own name, y=%s", y.Inspect())
+	}
+	if _, ok := outer.Get("y"); ok {
+		t.Errorf("a name of the block is visible around it")
+	}
+}
+
+func TestGeneratorResetStopsBody(t *testing.T) {
+	finished := make(chan struct{})
+	g := &Generator{}
+	g.Run = func() Object {
+		defer close(finished)
+		for i := int64(0); ; i++ {
+			g.Yield(&Integer{Value: i})
+		}
+	}
+
+	g.Next()
+	if _, v := g.Next(); v.Inspect() != "1" {
+		t.Fatalf("wrong second value, got=%s", v.Inspect())
+	}
+	g.Reset()
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatalf("the body of the generator is still waiting after Reset")
+	}
+	if k, v := g.Next(); k != nil || v != nil {
+		t.Errorf("a stopped generator gave %v", v)
+	}
+}
+
+func TestTupleHashKey(t *testing.T) {
+	a := &Tuple{Elements: []Object{&Integer{Value: 1}, &String{Value: "x"}}}
+	b := &Tuple{Elements: []Object{&Integer{Value: 1}, &String{Value: "x"}}}
+	c := &Tuple{Elements: []Object{&String{Value: "x"}, &Integer{Value: 1}}}
+
+	if a.HashKey() != b.HashKey() {
+		t.Errorf("tuples with same content have different hash keys")
+	}
+	if a.HashKey() == c.HashKey() {
+		t.Errorf("tuples with different order have same hash keys")
+	}
+}
+
+func TestBytesInspect(t *testing.T) {
+	b := &Bytes{Value: []byte{'h', 'i', 0xff}}
+	if got := b.Inspect(); got != `baiti("hi\xff")` {
+		t.Fatalf("wrong Inspect. got=%s", got)
+	}
+}
+
+func TestCompileRegexCaches(t *testing.T) {
+	first, err := CompileRegex("[0-9]+")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	second, _ := CompileRegex("[0-9]+")
+	if first != second {
+		t.Errorf("the same pattern was compiled twice")
+	}
+
+	if _, err := CompileRegex("("); err == nil {
+		t.Errorf("expected an error for an invalid pattern")
+	}
+}
+
+func TestCompileRegexCacheIsLimited(t *testing.T) {
+	kept, _ := CompileRegex("kept")
+	for i := 0; i < 2*regexCacheSize; i++ {
+		CompileRegex(fmt.Sprintf("p%d", i))
+		// using a pattern keeps it from being the next one dropped
+		CompileRegex("kept")
+	}
+
+	regexCacheMu.Lock()
+	size := len(regexCache)
+	regexCacheMu.Unlock()
+	if size > regexCacheSize {
+		t.Errorf("the cache holds %d patterns, more than %d", size, regexCacheSize)
+	}
+
+	if again, _ := CompileRegex("kept"); again != kept {
+		t.Errorf("a pattern in use was dropped from the cache")
+	}
+	if first, _ := CompileRegex("p0"); first == nil || first.Pattern != "p0" {
+		t.Errorf("a dropped pattern was not compiled again")
+	}
+}
+
+func TestPretty(t *testing.T) {
+	dict := func(pairs ...Object) *Dict {
+		d := &Dict{Pairs: make(map[HashKey]DictPair)}
+		for i := 0; i < len(pairs); i += 2 {
+			d.Pairs[pairs[i].(Hashable).HashKey()] = DictPair{Key: pairs[i], Value: pairs[i+1]}
+		}
+		return d
+	}
+	str := func(s string) *String { return &String{Value: s} }
+	num := func(n int64) *Integer { return &Integer{Value: n} }
+
+	cycle := &Array{Elements: []Object{num(1)}}
+	cycle.Elements = append(cycle.Elements, cycle)
+
+	tests := []struct {
+		obj      Object
+		expected string
+	}{
+		{num(5), "5"},
+		{&Array{}, "[]"},
+		{dict(str("b"), num(2), str("a"), num(1)), "{a: 1, b: 2}"},
+		{&Tuple{Elements: []Object{num(1), &Array{Elements: []Object{num(2)}}}}, "jozi(1, [2])"},
+		{cycle, "[1, [...]]"},
+		{
+			dict(
+				str("majina"), &Array{Elements: []Object{str("Asha"), str("Juma"), str("Neema"), str("Baraka"), str("Zawadi")}},
+				str("anwani"), dict(str("mji"), str("Dodoma"), str("mtaa"), str("Uhuru"), str("namba"), num(12345)),
+			),
+			"{\n    anwani: {mji: Dodoma, mtaa: Uhuru, namba: 12345},\n    majina: [Asha, Juma, Neema, Baraka, Zawadi]\n}",
+		},
+		{
+			&Array{Elements: []Object{dict(str("jina"), str("Asha Juma Mwinyi"), str("umri"), num(30), str("kazi"), str("Mwalimu wa hesabu"))}},
+			"[\n    {\n        jina: Asha Juma Mwinyi,\n        kazi: Mwalimu wa hesabu,\n        umri: 30\n    }\n]",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := Pretty(tt.obj); got != tt.expected {
+			t.Errorf("wrong pretty form.\nwant=%q\ngot= %q", tt.expected, got)
+		}
+	}
+}
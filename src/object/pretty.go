@@ -0,0 +1,67 @@
+package object
+
+import (
+	"sort"
+	"strings"
+)
+
+// prettyWidth is how wide a collection can be on one line before Pretty gives
+// each of its elements a line of its own
+const prettyWidth = 60
+
+// Pretty shows obj the way Inspect does, but with the keys of dicts in order,
+// and arrays, dicts and tuples too wide for one line spread over several and
+// indented. A collection found inside itself is shown as [...] or {...}.
+func Pretty(obj Object) string {
+	return pretty(obj, "", make(map[Object]bool))
+}
+
+func pretty(obj Object, indent string, seen map[Object]bool) string {
+	inner := indent + "    "
+	var open, close string
+	var items []string
+
+	switch obj := obj.(type) {
+	case *Array:
+		if seen[obj] {
+			return "[...]"
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+
+		open, close = "[", "]"
+		for _, el := range obj.Elements {
+			items = append(items, pretty(el, inner, seen))
+		}
+	case *Tuple:
+		open, close = "jozi(", ")"
+		for _, el := range obj.Elements {
+			items = append(items, pretty(el, inner, seen))
+		}
+	case *Dict:
+		if seen[obj] {
+			return "{...}"
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+
+		pairs := make([]DictPair, 0, len(obj.Pairs))
+		for _, pair := range obj.Pairs {
+			pairs = append(pairs, pair)
+		}
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key.Inspect() < pairs[j].Key.Inspect() })
+
+		open, close = "{", "}"
+		for _, pair := range pairs {
+			items = append(items, pretty(pair.Key, inner, seen)+": "+pretty(pair.Value, inner, seen))
+		}
+	default:
+		return obj.Inspect()
+	}
+
+	line := open + strings.Join(items, ", ") + close
+	if len(indent)+len(line) <= prettyWidth && !strings.Contains(line, "\n") || len(items) == 0 {
+		return line
+	}
+	return open + "\n" + inner + strings.Join(items, ",\n"+inner) + "\n" + indent + close
+}
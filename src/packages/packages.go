@@ -0,0 +1,286 @@
+// Package packages fetches Nuru libraries for a project, the way 'nuru pakua'
+// does. A library is a git repository. It is stored in the project's
+// vifurushi folder, where 'tumia' finds it, and recorded in the project's
+// manifest, nuru.json, so that the whole project can be fetched again later.
+package packages
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Dir is the folder of a project that holds its packages. A package
+	// named hesabu is kept in vifurushi/hesabu, and 'tumia hesabu' loads
+	// vifurushi/hesabu/hesabu.nr.
+	Dir = "vifurushi"
+
+	// ManifestFile describes a project and the packages it depends on
+	ManifestFile = "nuru.json"
+
+	// RegistryVariable names the environment variable holding the address of
+	// the registry, a JSON object mapping package names to git addresses
+	RegistryVariable = "NURU_REGISTRY"
+)
+
+// Manifest is what is kept in nuru.json
+type Manifest struct {
+	Name         string            `json:"jina,omitempty"`
+	Version      string            `json:"toleo,omitempty"`
+	Dependencies map[string]string `json:"vitegemezi"` // name -> source, like "https://github.com/x/hesabu@v1.0"
+}
+
+// ReadManifest reads the manifest in dir. A project without one gets an
+// empty manifest.
+func ReadManifest(dir string) (*Manifest, error) {
+	m := &Manifest{Dependencies: make(map[string]string)}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Nimeshindwa kusoma %s", ManifestFile)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("%s ina makosa: %s", ManifestFile, err)
+	}
+	if m.Dependencies == nil {
+		m.Dependencies = make(map[string]string)
+	}
+	return m, nil
+}
+
+// Write saves m as the manifest in dir
+func (m *Manifest) Write(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("Nimeshindwa kuandika %s", ManifestFile)
+	}
+	return nil
+}
+
+// Source is where a package comes from: a git repository and, optionally,
+// the tag, branch or commit to use
+type Source struct {
+	Name    string
+	URL     string
+	Version string
+}
+
+func (s Source) String() string {
+	if s.Version == "" {
+		return s.URL
+	}
+	return s.URL + "@" + s.Version
+}
+
+// ParseSource reads a package as given to 'nuru pakua'. It is either a git
+// address, like github.com/mtumiaji/hesabu@v1.0, or the name of a package
+// in the registry, like hesabu@v1.0.
+func ParseSource(spec string) (Source, error) {
+	var src Source
+	location := spec
+	if i := strings.LastIndexByte(spec, '@'); i > strings.LastIndexByte(spec, '/') && i > 0 {
+		location, src.Version = spec[:i], spec[i+1:]
+	}
+
+	if !strings.ContainsAny(location, "/:") {
+		url, err := lookup(location)
+		if err != nil {
+			return Source{}, err
+		}
+		src.Name, src.URL = location, url
+		return src, checkName(src.Name)
+	}
+
+	src.URL = location
+	if !strings.Contains(location, "://") && !strings.HasPrefix(location, "git@") && !isLocal(location) {
+		src.URL = "https://" + location
+	}
+	src.Name = strings.TrimSuffix(filepath.Base(strings.TrimRight(location, "/")), ".git")
+	return src, checkName(src.Name)
+}
+
+// checkName makes sure a package name is a single folder name, since it is
+// joined to the packages folder and whatever is there is replaced. Names come
+// from the manifests of fetched packages too, so they can't be trusted.
+func checkName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("Jina la kifurushi %q haliruhusiwi", name)
+	}
+	return nil
+}
+
+func isLocal(path string) bool {
+	return filepath.IsAbs(path) || strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../")
+}
+
+// lookup finds the git address of the package name in the registry
+func lookup(name string) (string, error) {
+	registry := os.Getenv(RegistryVariable)
+	if registry == "" {
+		return "", fmt.Errorf("Kifurushi %q hakina anwani ya git, na %s haijawekwa", name, RegistryVariable)
+	}
+
+	data, err := readRegistry(registry)
+	if err != nil {
+		return "", fmt.Errorf("Nimeshindwa kusoma rejista %s", registry)
+	}
+	var index map[string]string
+	if err := json.Unmarshal(data, &index); err != nil {
+		return "", fmt.Errorf("Rejista %s ina makosa: %s", registry, err)
+	}
+	url, ok := index[name]
+	if !ok {
+		return "", fmt.Errorf("Kifurushi %q hakipo kwenye rejista", name)
+	}
+	return url, nil
+}
+
+func readRegistry(address string) ([]byte, error) {
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		return os.ReadFile(address)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(address)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Install fetches src into the packages folder of the project in root,
+// replacing any earlier copy, then fetches the packages it depends on that
+// the project doesn't have yet. It returns the sources of everything fetched.
+func Install(root string, src Source) ([]Source, error) {
+	installed := make(map[string]bool)
+	return install(root, src, installed)
+}
+
+// InstallAll fetches every package in the manifest of the project in root
+func InstallAll(root string, m *Manifest) ([]Source, error) {
+	names := make([]string, 0, len(m.Dependencies))
+	for name := range m.Dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fetched []Source
+	installed := make(map[string]bool)
+	for _, name := range names {
+		src, err := ParseSource(m.Dependencies[name])
+		if err != nil {
+			return fetched, err
+		}
+		src.Name = name
+		more, err := install(root, src, installed)
+		fetched = append(fetched, more...)
+		if err != nil {
+			return fetched, err
+		}
+	}
+	return fetched, nil
+}
+
+func install(root string, src Source, installed map[string]bool) ([]Source, error) {
+	if installed[src.Name] {
+		return nil, nil
+	}
+	installed[src.Name] = true
+	if err := checkName(src.Name); err != nil {
+		return nil, err
+	}
+
+	dest := filepath.Join(root, Dir, src.Name)
+	tmp := dest + ".inapakuliwa"
+	os.RemoveAll(tmp)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return nil, fmt.Errorf("Nimeshindwa kutengeneza folda %s", Dir)
+	}
+	if err := clone(src, tmp); err != nil {
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+	// the package is kept as plain files, not as a repository of its own
+	os.RemoveAll(filepath.Join(tmp, ".git"))
+	os.RemoveAll(dest)
+	if err := os.Rename(tmp, dest); err != nil {
+		return nil, fmt.Errorf("Nimeshindwa kuweka %s", dest)
+	}
+	fetched := []Source{src}
+
+	m, err := ReadManifest(dest)
+	if err != nil {
+		return fetched, fmt.Errorf("%s: %s", src.Name, err)
+	}
+	for name, spec := range m.Dependencies {
+		if installed[name] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, Dir, name)); err == nil {
+			continue
+		}
+		dep, err := ParseSource(spec)
+		if err != nil {
+			return fetched, err
+		}
+		dep.Name = name
+		more, err := install(root, dep, installed)
+		fetched = append(fetched, more...)
+		if err != nil {
+			return fetched, err
+		}
+	}
+	return fetched, nil
+}
+
+// clone copies the repository of src into dest with git, checking out its
+// version if it has one
+func clone(src Source, dest string) error {
+	// anything starting with - would be read by git as an option
+	if strings.HasPrefix(src.URL, "-") {
+		return fmt.Errorf("Anwani %q ya %s haiwezi kuanza na -", src.URL, src.Name)
+	}
+	if strings.HasPrefix(src.Version, "-") {
+		return fmt.Errorf("Toleo %q la %s haliwezi kuanza na -", src.Version, src.Name)
+	}
+
+	if err := git("", "clone", "--quiet", "--", src.URL, dest); err != nil {
+		return fmt.Errorf("Nimeshindwa kupakua %s: %s", src.URL, err)
+	}
+	if src.Version != "" {
+		if err := git(dest, "checkout", "--quiet", src.Version); err != nil {
+			return fmt.Errorf("Toleo %q la %s halipatikani: %s", src.Version, src.Name, err)
+		}
+	}
+	return nil
+}
+
+func git(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package packages
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSource(t *testing.T) {
+	registry := filepath.Join(t.TempDir(), "rejista.json")
+	if err := os.WriteFile(registry, []byte(`{"hesabu": "https://example.com/hesabu.git"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(RegistryVariable, registry)
+
+	tests := []struct {
+		spec     string
+		expected Source
+	}{
+		{"github.com/mtumiaji/hesabu", Source{"hesabu", "https://github.com/mtumiaji/hesabu", ""}},
+		{"github.com/mtumiaji/hesabu@v1.2", Source{"hesabu", "https://github.com/mtumiaji/hesabu", "v1.2"}},
+		{"https://example.com/nuru/takwimu.git@main", Source{"takwimu", "https://example.com/nuru/takwimu.git", "main"}},
+		{"git@github.com:mtumiaji/hesabu.git", Source{"hesabu", "git@github.com:mtumiaji/hesabu.git", ""}},
+		{"./hesabu@v1", Source{"hesabu", "./hesabu", "v1"}},
+		{"hesabu@v2", Source{"hesabu", "https://example.com/hesabu.git", "v2"}},
+	}
+
+	for _, tt := range tests {
+		src, err := ParseSource(tt.spec)
+		if err != nil {
+			t.Errorf("%q: unexpected error %s", tt.spec, err)
+			continue
+		}
+		if src != tt.expected {
+			t.Errorf("%q: wrong source. want=%+v, got=%+v", tt.spec, tt.expected, src)
+		}
+	}
+
+	for _, spec := range []string{"github.com/mtumiaji/..", "./..@v1", "..", "."} {
+		if _, err := ParseSource(spec); err == nil {
+			t.Errorf("%q: expected an error for a name that isn't a folder name", spec)
+		}
+	}
+
+	if _, err := ParseSource("hakuna"); err == nil || err.Error() != `Kifurushi "hakuna" hakipo kwenye rejista` {
+		t.Errorf("wrong error for a missing package, got=%v", err)
+	}
+}
+
+// makeRepo makes a git repository holding files, and tags its first commit
+func makeRepo(t *testing.T, tag string, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=nuru", "-c", "user.email=nuru@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %s", args, out)
+		}
+	}
+
+	run("init", "-q")
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("add", "-A")
+	run("commit", "-qm", "kwanza")
+	run("tag", tag)
+
+	// a later commit, which a tagged install must not see
+	if err := os.WriteFile(filepath.Join(dir, "mpya.nr"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "-A")
+	run("commit", "-qm", "pili")
+	return dir
+}
+
+func TestInstall(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	msaada := makeRepo(t, "v1", map[string]string{"msaada.nr": "fanya x = 1"})
+	hesabu := makeRepo(t, "v2", map[string]string{
+		"hesabu.nr":  "tumia msaada",
+		ManifestFile: `{"vitegemezi": {"msaada": "` + msaada + `@v1"}}`,
+	})
+
+	root := t.TempDir()
+	fetched, err := Install(root, Source{Name: "hesabu", URL: hesabu, Version: "v2"})
+	if err != nil {
+		t.Fatalf("Install failed: %s", err)
+	}
+	if len(fetched) != 2 || fetched[0].Name != "hesabu" || fetched[1].Name != "msaada" {
+		t.Errorf("wrong packages fetched, got=%+v", fetched)
+	}
+
+	for _, path := range []string{"vifurushi/hesabu/hesabu.nr", "vifurushi/msaada/msaada.nr"} {
+		if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+			t.Errorf("%s is missing", path)
+		}
+	}
+	for _, path := range []string{"vifurushi/hesabu/mpya.nr", "vifurushi/hesabu/.git"} {
+		if _, err := os.Stat(filepath.Join(root, path)); err == nil {
+			t.Errorf("%s shouldn't be there", path)
+		}
+	}
+
+	if _, err := Install(root, Source{Name: "hesabu", URL: hesabu, Version: "v9"}); err == nil {
+		t.Errorf("expected an error for a missing version")
+	}
+
+	for _, src := range []Source{
+		{Name: "hesabu", URL: "--upload-pack=touch /tmp/x"},
+		{Name: "hesabu", URL: hesabu, Version: "--orphan=x"},
+	} {
+		if _, err := Install(root, src); err == nil {
+			t.Errorf("%+v: expected an error for an option given as a source", src)
+		}
+	}
+}
+
+func TestInstallRejectsUnsafeNames(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// a package whose manifest names a dependency outside the packages folder
+	msaada := makeRepo(t, "v1", map[string]string{"msaada.nr": "fanya x = 1"})
+	hesabu := makeRepo(t, "v1", map[string]string{
+		"hesabu.nr":  "tumia msaada",
+		ManifestFile: `{"vitegemezi": {"../../nje": "` + msaada + `@v1"}}`,
+	})
+
+	root := filepath.Join(t.TempDir(), "mradi")
+	if _, err := Install(root, Source{Name: "hesabu", URL: hesabu, Version: "v1"}); err == nil {
+		t.Errorf("expected an error for the dependency ../../nje")
+	}
+	if _, err := os.Stat(filepath.Join(root, "..", "nje")); err == nil {
+		t.Errorf("a dependency was put outside the packages folder")
+	}
+
+	for _, name := range []string{"", ".", "..", "a/b"} {
+		if _, err := Install(root, Source{Name: name, URL: msaada}); err == nil {
+			t.Errorf("%q: expected an error for the name", name)
+		}
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Errorf("the project folder was removed")
+	}
+}
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	m, err := ReadManifest(dir)
+	if err != nil || len(m.Dependencies) != 0 {
+		t.Fatalf("expected an empty manifest, got=%+v, %v", m, err)
+	}
+
+	m.Name = "mradi"
+	m.Dependencies["hesabu"] = "https://github.com/mtumiaji/hesabu@v1"
+	if err := m.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = ReadManifest(dir)
+	if err != nil || m.Name != "mradi" || m.Dependencies["hesabu"] != "https://github.com/mtumiaji/hesabu@v1" {
+		t.Errorf("manifest not read back, got=%+v, %v", m, err)
+	}
+}
@@ -8,7 +8,18 @@ import (
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.curToken}
 
-	array.Elements = p.parseExpressionList(token.RBRACKET)
+	if p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		array.Elements = []ast.Expression{}
+		return array
+	}
+
+	p.nextToken()
+	first := p.parseListElement()
+	if p.peekTokenIs(token.FOR) {
+		return p.parseComprehension(&ast.Comprehension{Token: array.Token, Value: first}, token.RBRACKET)
+	}
+	array.Elements = p.parseRestOfList(first, token.RBRACKET)
 
 	return array
 }
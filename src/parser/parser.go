@@ -2,7 +2,10 @@ package parser
 
 import (
 	"fmt"
+	"math/big"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/AvicennaJr/Nuru/ast"
 	"github.com/AvicennaJr/Nuru/lexer"
@@ -13,20 +16,29 @@ const (
 	// Think of BODMAS
 	_ int = iota
 	LOWEST
+	TERNARY     // X ? Y : Z
+	COALESCE    // ??
 	COND        // OR or AND
 	ASSIGN      // =
 	EQUALS      // ==
 	LESSGREATER // > OR <
+	BIT_OR      // |
+	BIT_XOR     // ^
+	BIT_AND     // &
+	SHIFT       // << OR >>
 	SUM         // +
 	PRODUCT     // *
-	POWER       // ** we got the power XD
 	MODULUS     // %
 	PREFIX      //  -X OR !X
+	POWER       // ** we got the power XD, so -2 ** 2 is -(2 ** 2)
 	CALL        // myFunction(X)
 	INDEX       // Arrays
+	POSTFIX     // X++ OR X--
 )
 
 var precedences = map[token.TokenType]int{
+	token.QUESTION:        TERNARY,
+	token.COALESCE:        COALESCE,
 	token.AND:             COND,
 	token.OR:              COND,
 	token.IN:              COND,
@@ -37,6 +49,11 @@ var precedences = map[token.TokenType]int{
 	token.LTE:             LESSGREATER,
 	token.GT:              LESSGREATER,
 	token.GTE:             LESSGREATER,
+	token.BIT_OR:          BIT_OR,
+	token.BIT_XOR:         BIT_XOR,
+	token.BIT_AND:         BIT_AND,
+	token.SHIFT_LEFT:      SHIFT,
+	token.SHIFT_RIGHT:     SHIFT,
 	token.PLUS:            SUM,
 	token.PLUS_ASSIGN:     SUM,
 	token.MINUS:           SUM,
@@ -48,15 +65,24 @@ var precedences = map[token.TokenType]int{
 	token.POW:             POWER,
 	token.MODULUS:         MODULUS,
 	token.MODULUS_ASSIGN:  MODULUS,
+
+	token.BACKSLASH:        PRODUCT,
+	token.BACKSLASH_ASSIGN: PRODUCT,
+
 	// token.BANG:     PREFIX,
 	token.LPAREN:   CALL,
 	token.LBRACKET: INDEX, // Highest priority
+	token.OPTIONAL: INDEX,
+	token.DOT:      INDEX,
+	token.ARROW:    INDEX, // only ever follows a single name
+
+	token.PLUS_PLUS:   POSTFIX,
+	token.MINUS_MINUS: POSTFIX,
 }
 
 type (
-	prefixParseFn  func() ast.Expression
-	infixParseFn   func(ast.Expression) ast.Expression
-	postfixParseFn func() ast.Expression
+	prefixParseFn func() ast.Expression
+	infixParseFn  func(ast.Expression) ast.Expression
 )
 
 type Parser struct {
@@ -64,13 +90,13 @@ type Parser struct {
 
 	curToken  token.Token
 	peekToken token.Token
-	prevToken token.Token
 
 	errors []string
 
-	prefixParseFns  map[token.TokenType]prefixParseFn
-	infixParseFns   map[token.TokenType]infixParseFn
-	postfixParseFns map[token.TokenType]postfixParseFn
+	prefixParseFns map[token.TokenType]prefixParseFn
+	infixParseFns  map[token.TokenType]infixParseFn
+
+	functions []*ast.FunctionLiteral // the functions whose bodies are being parsed, innermost last
 }
 
 func New(l *lexer.Lexer) *Parser {
@@ -82,11 +108,13 @@ func New(l *lexer.Lexer) *Parser {
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.TEMPLATE, p.parseTemplateLiteral)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
 	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.BIT_NOT, p.parsePrefixExpression)
 	p.registerPrefix(token.PLUS, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
@@ -99,16 +127,29 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.NULL, p.parseNull)
 	p.registerPrefix(token.FOR, p.parseForExpression)
 	p.registerPrefix(token.SWITCH, p.parseSwitchStatement)
+	p.registerPrefix(token.LET, p.parseDoWhileExpression)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
+	p.registerPrefix(token.THIS, p.parseThis)
+	p.registerPrefix(token.SPAWN, p.parseSpawnExpression)
+	p.registerPrefix(token.YIELD, p.parseYieldExpression)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.AND, p.parseInfixExpression)
 	p.registerInfix(token.OR, p.parseInfixExpression)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
+	p.registerInfix(token.BIT_AND, p.parseInfixExpression)
+	p.registerInfix(token.BIT_OR, p.parseInfixExpression)
+	p.registerInfix(token.BIT_XOR, p.parseInfixExpression)
+	p.registerInfix(token.SHIFT_LEFT, p.parseInfixExpression)
+	p.registerInfix(token.SHIFT_RIGHT, p.parseInfixExpression)
 	p.registerInfix(token.PLUS_ASSIGN, p.parseAssignmentExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS_ASSIGN, p.parseAssignmentExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.SLASH_ASSIGN, p.parseAssignmentExpression)
+	p.registerInfix(token.BACKSLASH, p.parseInfixExpression)
+	p.registerInfix(token.BACKSLASH_ASSIGN, p.parseAssignmentExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK_ASSIGN, p.parseAssignmentExpression)
 	p.registerInfix(token.POW, p.parseInfixExpression)
@@ -122,18 +163,20 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.GTE, p.parseInfixExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.OPTIONAL, p.parseIndexExpression)
+	p.registerInfix(token.ARROW, p.parseShortArrowFunction)
 	p.registerInfix(token.ASSIGN, p.parseAssignmentExpression)
 	p.registerInfix(token.IN, p.parseInfixExpression)
-
-	p.postfixParseFns = make(map[token.TokenType]postfixParseFn)
-	p.registerPostfix(token.PLUS_PLUS, p.parsePostfixExpression)
-	p.registerPostfix(token.MINUS_MINUS, p.parsePostfixExpression)
+	p.registerInfix(token.DOT, p.parsePropertyExpression)
+	p.registerInfix(token.QUESTION, p.parseConditionalExpression)
+	p.registerInfix(token.COALESCE, p.parseInfixExpression)
+	p.registerInfix(token.PLUS_PLUS, p.parsePostfixExpression)
+	p.registerInfix(token.MINUS_MINUS, p.parsePostfixExpression)
 
 	return p
 }
 
 func (p *Parser) nextToken() {
-	p.prevToken = p.curToken
 	p.curToken = p.peekToken
 	p.peekToken = p.l.NextToken()
 }
@@ -155,13 +198,33 @@ func (p *Parser) parseStatement() ast.Statement {
 	// Remember to add switch statements to the language
 	switch p.curToken.Type {
 	case token.LET:
+		if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) && p.dictPatternAhead() ||
+			p.peekTokenIs(token.IDENT) && p.l.Clone().NextToken().Type == token.COMMA {
+			return p.parseDestructuringStatement()
+		}
+		if p.peekTokenIs(token.LBRACE) {
+			return p.parseExpressionStatement()
+		}
 		return p.parseLetStatment()
+	case token.CONST:
+		stmt := p.parseLetStatment()
+		if stmt == nil {
+			return nil
+		}
+		stmt.Constant = true
+		return stmt
 	case token.RETURN:
 		return p.parseReturnStatement()
 	case token.BREAK:
 		return p.parseBreak()
 	case token.CONTINUE:
 		return p.parseContinue()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.CLASS:
+		return p.parseClassStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -191,13 +254,93 @@ func (p *Parser) parseLetStatment() *ast.LetStatement {
 	return stmt
 }
 
+// dictPatternAhead reports whether the '{' after 'fanya' starts names like
+// '{jina, umri} =' rather than the block of a 'fanya {} wakati' loop
+func (p *Parser) dictPatternAhead() bool {
+	l := p.l.Clone()
+	for {
+		if l.NextToken().Type != token.IDENT {
+			return false
+		}
+		switch l.NextToken().Type {
+		case token.COMMA:
+		case token.RBRACE:
+			return l.NextToken().Type == token.ASSIGN
+		default:
+			return false
+		}
+	}
+}
+
+func (p *Parser) parseDestructuringStatement() *ast.DestructuringStatement {
+	stmt := &ast.DestructuringStatement{Token: p.curToken}
+	var end token.TokenType
+	switch {
+	case p.peekTokenIs(token.LBRACKET):
+		end = token.RBRACKET
+		p.nextToken()
+	case p.peekTokenIs(token.LBRACE):
+		stmt.Dict = true
+		end = token.RBRACE
+		p.nextToken()
+	default:
+		stmt.Bare = true
+	}
+
+	seen := make(map[string]bool)
+	for {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if seen[name.Value] {
+			msg := fmt.Sprintf("Mstari %d: Jina %s limetumika zaidi ya mara moja", p.curToken.Line, name.Value)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		seen[name.Value] = true
+		stmt.Names = append(stmt.Names, name)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if end != "" && !p.expectPeek(end) {
+		return nil
+	}
+	if !p.expectPeek(token.ASSIGN) {
+		return nil
+	}
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseNull() ast.Expression {
 	return &ast.Null{Token: p.curToken}
 }
 
 func (p *Parser) parseAssignmentExpression(exp ast.Expression) ast.Expression {
 	switch node := exp.(type) {
-	case *ast.Identifier, *ast.IndexExpression:
+	case *ast.Identifier, *ast.PropertyExpression:
+	case *ast.IndexExpression:
+		if node.Optional {
+			msg := fmt.Sprintf("Mstari %d: Huwezi kuweka thamani kwenye %s", p.curToken.Line, node.String())
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+	case *ast.SliceExpression:
+		msg := fmt.Sprintf("Mstari %d: Huwezi kuweka thamani kwenye %s", p.curToken.Line, node.String())
+		p.errors = append(p.errors, msg)
+		return nil
 	default:
 		if node != nil {
 			msg := fmt.Sprintf("Mstari %d:Tulitegemea kupata kitambulishi au array, badala yake tumepata: %s", p.curToken.Line, node.TokenLiteral())
@@ -237,7 +380,7 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 }
 
 func (p *Parser) Errors() []string {
-	return p.errors
+	return append(append([]string{}, p.l.Errors()...), p.errors...)
 }
 
 func (p *Parser) peekError(t token.TokenType) {
@@ -251,6 +394,57 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 
 	stmt.ReturnValue = p.parseExpression(LOWEST)
 
+	// several values are given back together in an array
+	if p.peekTokenIs(token.COMMA) {
+		values := &ast.ArrayLiteral{Token: p.peekToken, Elements: []ast.Expression{stmt.ReturnValue}}
+		for p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+			p.nextToken()
+			values.Elements = append(values.Elements, p.parseExpression(LOWEST))
+		}
+		stmt.ReturnValue = values
+		stmt.Multiple = true
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+	p.nextToken()
+
+	switch p.curToken.Type {
+	case token.STRING:
+		// tumia "lib/hesabu.nr" is bound to the name hesabu
+		stmt.Path = p.curToken.Literal
+		name := strings.TrimSuffix(filepath.Base(stmt.Path), filepath.Ext(stmt.Path))
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: name}
+	case token.IDENT:
+		stmt.Path = p.curToken.Literal
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	default:
+		msg := fmt.Sprintf("Mstari %d: Tulitegemea jina la moduli baada ya 'tumia', badala yake tumepata %s", p.curToken.Line, p.curToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
@@ -266,10 +460,6 @@ func (p *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
 	p.infixParseFns[tokenType] = fn
 }
 
-func (p *Parser) registerPostfix(tokenType token.TokenType, fn postfixParseFn) {
-	p.postfixParseFns[tokenType] = fn
-}
-
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
 
@@ -293,16 +483,16 @@ func (p *Parser) noInfixParseFnError(t token.TokenType) {
 }
 
 func (p *Parser) parseExpression(precedence int) ast.Expression {
-	postfix := p.postfixParseFns[p.curToken.Type]
-	if postfix != nil {
-		return (postfix())
-	}
 	prefix := p.prefixParseFns[p.curToken.Type]
 	if prefix == nil {
 		p.noPrefixParseFnError(p.curToken.Type)
 		return nil
 	}
 	leftExp := prefix()
+	if leftExp == nil {
+		// the error is already reported, and there is nothing to build on
+		return nil
+	}
 
 	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
 		infix := p.infixParseFns[p.peekToken.Type]
@@ -326,6 +516,11 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: p.curToken}
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		if big, ok := new(big.Int).SetString(p.curToken.Literal, 0); ok {
+			return &ast.BigIntegerLiteral{Token: p.curToken, Value: big}
+		}
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Mstari %d: Hatuwezi kuparse %q kama namba", p.curToken.Line, p.curToken.Literal)
 		p.errors = append(p.errors, msg)
@@ -361,6 +556,15 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 	return expression
 }
 
+// Precedence returns how tightly the infix operator t binds, from LOWEST up
+// to INDEX. Tools that print code use it to know where brackets are needed.
+func Precedence(t token.TokenType) int {
+	if p, ok := precedences[t]; ok {
+		return p
+	}
+	return LOWEST
+}
+
 func (p *Parser) peekPrecedence() int {
 	if p, ok := precedences[p.peekToken.Type]; ok {
 		return p
@@ -384,8 +588,48 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	}
 
 	precedence := p.curPrecedence()
+	if p.curTokenIs(token.POW) {
+		// 2 ** 3 ** 2 is 2 ** (3 ** 2)
+		precedence--
+	}
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
+
+	if precedence == LESSGREATER && p.peekPrecedence() == LESSGREATER {
+		return p.parseComparisonChain(expression)
+	}
+	return expression
+}
+
+// parseComparisonChain carries on from the first comparison of 0 < x < 10
+// to the end of the chain
+func (p *Parser) parseComparisonChain(first *ast.InfixExpression) ast.Expression {
+	chain := &ast.ComparisonChain{
+		Token:     first.Token,
+		Operands:  []ast.Expression{first.Left, first.Right},
+		Operators: []string{first.Operator},
+	}
+	for p.peekPrecedence() == LESSGREATER {
+		p.nextToken()
+		chain.Operators = append(chain.Operators, p.curToken.Literal)
+		p.nextToken()
+		chain.Operands = append(chain.Operands, p.parseExpression(LESSGREATER))
+	}
+	return chain
+}
+
+// parseConditionalExpression parses the rest of 'sharti ? thamani1 : thamani2'.
+// The last part takes in another '?', so a ? b : c ? d : e is a ? b : (c ? d : e).
+func (p *Parser) parseConditionalExpression(condition ast.Expression) ast.Expression {
+	expression := &ast.ConditionalExpression{Token: p.curToken, Condition: condition}
+
+	p.nextToken()
+	expression.Consequence = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.COLON) {
+		return nil
+	}
+	p.nextToken()
+	expression.Alternative = p.parseExpression(TERNARY - 1)
 	return expression
 }
 
@@ -393,24 +637,135 @@ func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
 }
 
+// parseGroupedExpression also parses the parameters of '(a, b) => a + b',
+// which are only known to be parameters once the '=>' is reached
 func (p *Parser) parseGroupedExpression() ast.Expression {
+	fn := &ast.FunctionLiteral{Token: p.curToken, Arrow: true}
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		if !p.expectPeek(token.ARROW) {
+			return nil
+		}
+		return p.parseArrowBody(fn)
+	}
+
 	p.nextToken()
+	if p.curTokenIs(token.ELLIPSIS) {
+		return p.parseArrowParameters(fn)
+	}
 
 	exp := p.parseExpression(LOWEST)
 
+	if ident, ok := exp.(*ast.Identifier); ok && p.peekTokenIs(token.COMMA) {
+		fn.Parameters = append(fn.Parameters, ident)
+		p.nextToken()
+		p.nextToken()
+		return p.parseArrowParameters(fn)
+	}
+
 	if !p.expectPeek(token.RPAREN) {
 		return nil
 	}
 
+	if p.peekTokenIs(token.ARROW) {
+		ident, ok := exp.(*ast.Identifier)
+		if !ok {
+			msg := fmt.Sprintf("Mstari %d: Hoja za => lazima ziwe majina, sio %s", p.curToken.Line, exp.String())
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		fn.Parameters = []*ast.Identifier{ident}
+		p.nextToken()
+		return p.parseArrowBody(fn)
+	}
+
 	return exp
 }
 
-func (p *Parser) parsePostfixExpression() ast.Expression {
-	expression := &ast.PostfixExpression{
-		Token:    p.prevToken,
-		Operator: p.curToken.Literal,
+// parseArrowParameters carries on the parameters of '(a, b) => a + b' from
+// the one at curToken
+func (p *Parser) parseArrowParameters(fn *ast.FunctionLiteral) ast.Expression {
+	for {
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			fn.Variadic = true
+		} else if !p.curTokenIs(token.IDENT) {
+			msg := fmt.Sprintf("Mstari %d: Hoja za => lazima ziwe majina, sio %s", p.curToken.Line, p.curToken.Literal)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		fn.Parameters = append(fn.Parameters, ident)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		if fn.Variadic {
+			msg := fmt.Sprintf("Mstari %d: ...%s lazima iwe hoja ya mwisho", p.curToken.Line, ident.Value)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		p.nextToken()
+		p.nextToken()
 	}
-	return expression
+
+	if !p.expectPeek(token.RPAREN) || !p.expectPeek(token.ARROW) {
+		return nil
+	}
+	return p.parseArrowBody(fn)
+}
+
+// parseShortArrowFunction parses 'x => x * 2', written without brackets
+// since it has a single parameter
+func (p *Parser) parseShortArrowFunction(left ast.Expression) ast.Expression {
+	ident, ok := left.(*ast.Identifier)
+	if !ok {
+		if left == nil {
+			return nil
+		}
+		msg := fmt.Sprintf("Mstari %d: Hoja za => lazima ziwe majina, sio %s", p.curToken.Line, left.String())
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	fn := &ast.FunctionLiteral{Token: ident.Token, Parameters: []*ast.Identifier{ident}, Arrow: true}
+	return p.parseArrowBody(fn)
+}
+
+// parseArrowBody parses what follows '=>': a block like that of 'unda', or
+// an expression that is the value of the function
+func (p *Parser) parseArrowBody(fn *ast.FunctionLiteral) ast.Expression {
+	p.nextToken()
+	p.functions = append(p.functions, fn)
+	fn.Body = p.parseBody()
+	p.functions = p.functions[:len(p.functions)-1]
+	if !fn.Generator {
+		markTailCalls(fn.Body, true)
+	}
+
+	return fn
+}
+
+// parseBody parses what follows '=>' from curToken: a block, or an
+// expression that stands for a block giving its value
+func (p *Parser) parseBody() *ast.BlockStatement {
+	if p.curTokenIs(token.LBRACE) {
+		return p.parseBlockStatement()
+	}
+	body := &ast.ExpressionStatement{Token: p.curToken, Expression: p.parseExpression(LOWEST)}
+	return &ast.BlockStatement{Token: body.Token, Statements: []ast.Statement{body}}
+}
+
+// parsePostfixExpression parses 'i++' and 'i--', which only work on a name
+func (p *Parser) parsePostfixExpression(left ast.Expression) ast.Expression {
+	ident, ok := left.(*ast.Identifier)
+	if !ok {
+		msg := fmt.Sprintf("Mstari %d: '%s' inatumika na jina tu, sio %s", p.curToken.Line, p.curToken.Literal, left.String())
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	return &ast.PostfixExpression{Token: ident.Token, Operator: p.curToken.Literal}
 }
 
 func (p *Parser) parseIfExpression() ast.Expression {
@@ -473,6 +828,7 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 		block.Statements = append(block.Statements, stmt)
 		p.nextToken()
 	}
+	block.End = p.curToken.Position
 
 	return block
 }
@@ -484,69 +840,190 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	lit.Parameters = p.parseFunctionParameters()
+	lit.Parameters, lit.Variadic = p.parseFunctionParameters()
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
 
+	p.functions = append(p.functions, lit)
 	lit.Body = p.parseBlockStatement()
+	p.functions = p.functions[:len(p.functions)-1]
+	// a generator is paused rather than left, so its frame can't be reused
+	if !lit.Generator {
+		markTailCalls(lit.Body, true)
+	}
 
 	return lit
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+// markTailCalls marks the calls in block that are the last thing a function
+// does: the value of a 'rudisha', or the final expression when last is true.
+// Calls inside 'jaribu' are left alone since their errors must be caught there.
+func markTailCalls(block *ast.BlockStatement, last bool) {
+	if block == nil {
+		return
+	}
+
+	for i, stmt := range block.Statements {
+		isLast := last && i == len(block.Statements)-1
+
+		switch stmt := stmt.(type) {
+		case *ast.ReturnStatement:
+			if call, ok := stmt.ReturnValue.(*ast.CallExpression); ok {
+				call.Tail = true
+			}
+		case *ast.ExpressionStatement:
+			switch exp := stmt.Expression.(type) {
+			case *ast.CallExpression:
+				exp.Tail = isLast
+			case *ast.IfExpression:
+				markTailCalls(exp.Consequence, isLast)
+				markTailCalls(exp.Alternative, isLast)
+			case *ast.SwitchExpression:
+				for _, choice := range exp.Choices {
+					markTailCalls(choice.Block, isLast)
+				}
+			case *ast.WhileExpression:
+				markTailCalls(exp.Consequence, false)
+			case *ast.DoWhileExpression:
+				markTailCalls(exp.Consequence, false)
+			case *ast.For:
+				markTailCalls(exp.Block, false)
+			case *ast.ForIn:
+				markTailCalls(exp.Block, false)
+			}
+		}
+	}
+}
+
+// parseFunctionParameters also reports whether the last parameter is written
+// '...hoja', collecting the arguments left over
+func (p *Parser) parseFunctionParameters() ([]*ast.Identifier, bool) {
 	identifiers := []*ast.Identifier{}
 
 	if p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
-		return identifiers
+		return identifiers, false
 	}
 
-	p.nextToken()
-
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers, ident)
-
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken()
+	variadic := false
+	for {
 		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return nil, false
+			}
+			variadic = true
+		}
 		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 		identifiers = append(identifiers, ident)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		if variadic {
+			msg := fmt.Sprintf("Mstari %d: ...%s lazima iwe hoja ya mwisho", p.curToken.Line, ident.Value)
+			p.errors = append(p.errors, msg)
+			return nil, false
+		}
+		p.nextToken()
 	}
 
 	if !p.expectPeek(token.RPAREN) {
-		return nil
+		return nil, false
 	}
 
-	return identifiers
+	return identifiers, variadic
 }
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
 	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	p.nameArguments(exp.Arguments)
 	return exp
 }
 
+// nameArguments turns the arguments written 'jina=thamani' into named
+// arguments. They have to come after the others, and each name can only be
+// given once.
+func (p *Parser) nameArguments(args []ast.Expression) {
+	names := make(map[string]bool)
+	for i, arg := range args {
+		assign, ok := arg.(*ast.AssignmentExpression)
+		if !ok || assign.Token.Type != token.ASSIGN {
+			if len(names) > 0 {
+				msg := fmt.Sprintf("Mstari %d: Hoja isiyo na jina haiwezi kufuata hoja yenye jina", p.curToken.Line)
+				p.errors = append(p.errors, msg)
+				return
+			}
+			continue
+		}
+		name, ok := assign.Left.(*ast.Identifier)
+		if !ok {
+			continue
+		}
+		if names[name.Value] {
+			msg := fmt.Sprintf("Mstari %d: Hoja %s imetolewa zaidi ya mara moja", name.Token.Line, name.Value)
+			p.errors = append(p.errors, msg)
+			return
+		}
+		names[name.Value] = true
+		args[i] = &ast.NamedArgument{Token: name.Token, Name: name, Value: assign.Value}
+	}
+}
+
 func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
 
-func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
-	list := []ast.Expression{}
+func (p *Parser) parseTemplateLiteral() ast.Expression {
+	template := &ast.TemplateLiteral{Token: p.curToken}
+
+	texts, exprs := lexer.SplitTemplate(p.curToken.Literal)
+	for i, text := range texts {
+		if text != "" {
+			template.Parts = append(template.Parts, &ast.StringLiteral{Token: p.curToken, Value: text})
+		}
+		if i == len(exprs) {
+			break
+		}
+
+		sub := New(lexer.NewAt(exprs[i], p.curToken.Position))
+		exp := sub.parseExpression(LOWEST)
+		if len(sub.Errors()) != 0 {
+			p.errors = append(p.errors, sub.Errors()...)
+			return nil
+		}
+		if exp == nil || !sub.peekTokenIs(token.EOF) {
+			msg := fmt.Sprintf("Mstari %d: Kuna kosa ndani ya ${%s}", p.curToken.Line, exprs[i])
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		template.Parts = append(template.Parts, exp)
+	}
 
+	return template
+}
+
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	if p.peekTokenIs(end) {
 		p.nextToken()
-		return list
+		return []ast.Expression{}
 	}
 
 	p.nextToken()
-	list = append(list, p.parseExpression(LOWEST))
+	return p.parseRestOfList(p.parseListElement(), end)
+}
+
+// parseRestOfList carries on from the first element of a list up to end
+func (p *Parser) parseRestOfList(first ast.Expression, end token.TokenType) []ast.Expression {
+	list := []ast.Expression{first}
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
-		list = append(list, p.parseExpression(LOWEST))
+		list = append(list, p.parseListElement())
 	}
 
 	if !p.expectPeek(end) {
@@ -555,15 +1032,69 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	return list
 }
 
+// parseListElement parses an element of an array literal or an argument of a
+// call, which may be spread with '...'
+func (p *Parser) parseListElement() ast.Expression {
+	if !p.curTokenIs(token.ELLIPSIS) {
+		return p.parseExpression(LOWEST)
+	}
+	spread := &ast.SpreadExpression{Token: p.curToken}
+	p.nextToken()
+	spread.Value = p.parseExpression(LOWEST)
+	return spread
+}
+
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	exp := &ast.IndexExpression{Token: p.curToken, Left: left, Optional: p.curTokenIs(token.OPTIONAL)}
 
 	p.nextToken()
+	if p.curTokenIs(token.COLON) {
+		return p.parseSliceExpression(exp)
+	}
 	exp.Index = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		return p.parseSliceExpression(exp)
+	}
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return exp
+}
+
+// parseSliceExpression carries on from the first ':' of 'a[start:stop:step]',
+// with whatever came before it as the index of exp
+func (p *Parser) parseSliceExpression(exp *ast.IndexExpression) ast.Expression {
+	slice := &ast.SliceExpression{Token: exp.Token, Left: exp.Left, Start: exp.Index, Optional: exp.Optional}
+
+	if !p.peekTokenIs(token.COLON) && !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		slice.Stop = p.parseExpression(LOWEST)
+	}
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		if !p.peekTokenIs(token.RBRACKET) {
+			p.nextToken()
+			slice.Step = p.parseExpression(LOWEST)
+		}
+	}
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
 	}
 
+	return slice
+}
+
+func (p *Parser) parsePropertyExpression(obj ast.Expression) ast.Expression {
+	exp := &ast.PropertyExpression{Token: p.curToken, Object: obj}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
 	return exp
 }
 
@@ -582,7 +1113,12 @@ func (p *Parser) parseDictLiteral() ast.Expression {
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
 
+		if len(dict.Keys) == 0 && p.peekTokenIs(token.FOR) {
+			return p.parseComprehension(&ast.Comprehension{Token: dict.Token, Key: key, Value: value}, token.RBRACE)
+		}
+
 		dict.Pairs[key] = value
+		dict.Keys = append(dict.Keys, key)
 
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
@@ -596,6 +1132,46 @@ func (p *Parser) parseDictLiteral() ast.Expression {
 	return dict
 }
 
+// parseComprehension carries on from the 'kwa' of a comprehension whose
+// element, or key and value, have been parsed
+func (p *Parser) parseComprehension(comp *ast.Comprehension, end token.TokenType) ast.Expression {
+	if _, ok := comp.Value.(*ast.SpreadExpression); ok {
+		msg := fmt.Sprintf("Mstari %d: Huwezi kutumia ... kabla ya kwa", p.curToken.Line)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	p.nextToken()
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	comp.LoopValue = p.curToken.Literal
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		comp.LoopKey = comp.LoopValue
+		comp.LoopValue = p.curToken.Literal
+	}
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	comp.Iterable = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		p.nextToken()
+		comp.Condition = p.parseExpression(LOWEST)
+	}
+	if !p.expectPeek(end) {
+		return nil
+	}
+
+	return comp
+}
+
 func (p *Parser) parseWhileExpression() ast.Expression {
 	expression := &ast.WhileExpression{Token: p.curToken}
 
@@ -619,6 +1195,33 @@ func (p *Parser) parseWhileExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseDoWhileExpression() ast.Expression {
+	expression := &ast.DoWhileExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Consequence = p.parseBlockStatement()
+
+	if !p.expectPeek(token.WHILE) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	expression.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return expression
+}
+
 func (p *Parser) parseBreak() *ast.Break {
 	stmt := &ast.Break{Token: p.curToken}
 	for p.curTokenIs(token.SEMICOLON) {
@@ -747,7 +1350,7 @@ func (p *Parser) parseSwitchStatement() ast.Expression {
 	for !p.curTokenIs(token.RBRACE) {
 
 		if p.curTokenIs(token.EOF) {
-			msg := fmt.Sprintf("Mstari %d: Haukufunga ENDAPO (SWITCH)", p.curToken.Line)
+			msg := fmt.Sprintf("Mstari %d: Haukufunga BADILI (SWITCH)", p.curToken.Line)
 			p.errors = append(p.errors, msg)
 			return nil
 		}
@@ -785,6 +1388,7 @@ func (p *Parser) parseSwitchStatement() ast.Expression {
 		p.nextToken()
 		expression.Choices = append(expression.Choices, tmp)
 	}
+	expression.End = p.curToken.Position
 
 	count := 0
 	for _, c := range expression.Choices {
@@ -793,7 +1397,7 @@ func (p *Parser) parseSwitchStatement() ast.Expression {
 		}
 	}
 	if count > 1 {
-		msg := fmt.Sprintf("Kauli ENDAPO (SWITCH) hua na kauli 'KAWAIDA' (DEFAULT) moja tu! Wewe umeweka %d", count)
+		msg := fmt.Sprintf("Mstari %d: Kauli BADILI (SWITCH) hua na kauli 'KAWAIDA' (DEFAULT) moja tu! Wewe umeweka %d", expression.Token.Line, count)
 		p.errors = append(p.errors, msg)
 		return nil
 
@@ -801,3 +1405,302 @@ func (p *Parser) parseSwitchStatement() ast.Expression {
 	return expression
 
 }
+
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+	expression.Value = p.parseExpression(LOWEST)
+	if expression.Value == nil || !p.expectPeek(token.RPAREN) || !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if p.peekTokenIs(token.EOF) {
+			msg := fmt.Sprintf("Mstari %d: Haukufunga LINGANISHA", p.peekToken.Line)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		p.nextToken()
+
+		arm := &ast.MatchArm{Pattern: p.parsePattern()}
+		if arm.Pattern == nil {
+			return nil
+		}
+		names := make(map[string]bool)
+		for _, name := range arm.Names() {
+			if names[name.Value] {
+				msg := fmt.Sprintf("Mstari %d: %s imetajwa mara mbili ndani ya pattern", name.Token.Line, name.Value)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			names[name.Value] = true
+		}
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			if !p.expectPeek(token.LPAREN) {
+				return nil
+			}
+			p.nextToken()
+			arm.Guard = p.parseExpression(LOWEST)
+			if !p.expectPeek(token.RPAREN) {
+				return nil
+			}
+		}
+		if !p.expectPeek(token.ARROW) {
+			return nil
+		}
+		p.nextToken()
+		arm.Body = p.parseBody()
+		if arm.Body == nil {
+			return nil
+		}
+		expression.Arms = append(expression.Arms, arm)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.peekTokenIs(token.EOF) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	p.nextToken()
+	expression.End = p.curToken.Position
+
+	return expression
+}
+
+// parsePattern parses the pattern of a 'linganisha' arm at curToken
+func (p *Parser) parsePattern() ast.Expression {
+	switch p.curToken.Type {
+	case token.IDENT:
+		return p.parseIdentifier()
+	case token.INT, token.FLOAT, token.STRING, token.TRUE, token.FALSE, token.NULL:
+		return p.prefixParseFns[p.curToken.Type]()
+	case token.MINUS:
+		if p.peekTokenIs(token.INT) || p.peekTokenIs(token.FLOAT) {
+			prefix := &ast.PrefixExpression{Token: p.curToken, Operator: p.curToken.Literal}
+			p.nextToken()
+			prefix.Right = p.prefixParseFns[p.curToken.Type]()
+			return prefix
+		}
+	case token.LBRACKET:
+		array := &ast.ArrayLiteral{Token: p.curToken}
+		for !p.peekTokenIs(token.RBRACKET) {
+			p.nextToken()
+			var element ast.Expression
+			if p.curTokenIs(token.ELLIPSIS) {
+				spread := &ast.SpreadExpression{Token: p.curToken}
+				if !p.expectPeek(token.IDENT) {
+					return nil
+				}
+				spread.Value = p.parseIdentifier()
+				element = spread
+				if !p.peekTokenIs(token.RBRACKET) {
+					msg := fmt.Sprintf("Mstari %d: ...%s lazima iwe mwisho wa pattern", p.curToken.Line, p.curToken.Literal)
+					p.errors = append(p.errors, msg)
+					return nil
+				}
+			} else if element = p.parsePattern(); element == nil {
+				return nil
+			}
+			array.Elements = append(array.Elements, element)
+			if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+		}
+		p.nextToken()
+		return array
+	case token.LBRACE:
+		dict := &ast.DictLiteral{Token: p.curToken, Pairs: make(map[ast.Expression]ast.Expression)}
+		for !p.peekTokenIs(token.RBRACE) {
+			p.nextToken()
+			var key, value ast.Expression
+			switch {
+			case p.curTokenIs(token.IDENT):
+				// {jina} is short for {"jina": jina}
+				key = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+				value = p.parseIdentifier()
+			case p.curTokenIs(token.STRING) || p.curTokenIs(token.INT):
+				key = p.prefixParseFns[p.curToken.Type]()
+				if !p.expectPeek(token.COLON) {
+					return nil
+				}
+				p.nextToken()
+				if value = p.parsePattern(); value == nil {
+					return nil
+				}
+			default:
+				msg := fmt.Sprintf("Mstari %d: Ufunguo wa pattern lazima uwe neno au namba, sio %s", p.curToken.Line, p.curToken.Literal)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			dict.Pairs[key] = value
+			dict.Keys = append(dict.Keys, key)
+			if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+		}
+		p.nextToken()
+		return dict
+	}
+
+	msg := fmt.Sprintf("Mstari %d: Huwezi kulinganisha na %s", p.curToken.Line, p.curToken.Literal)
+	p.errors = append(p.errors, msg)
+	return nil
+}
+
+func (p *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Block = p.parseBlockStatement()
+
+	if !p.expectPeek(token.CATCH) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.LPAREN) {
+		p.nextToken()
+
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		expression.Identifier = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Catch = p.parseBlockStatement()
+
+	return expression
+}
+
+func (p *Parser) parseClassStatement() *ast.ClassStatement {
+	stmt := &ast.ClassStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.nextToken()
+
+	methods := make(map[string]bool)
+
+	for !p.curTokenIs(token.RBRACE) {
+		switch {
+		case p.curTokenIs(token.EOF):
+			msg := fmt.Sprintf("Mstari %d: Haukufunga MUUNDO %s", p.curToken.Line, stmt.Name.Value)
+			p.errors = append(p.errors, msg)
+			return nil
+
+		case p.curTokenIs(token.SEMICOLON):
+
+		case p.curTokenIs(token.LET):
+			field := p.parseLetStatment()
+			if field == nil {
+				return nil
+			}
+			stmt.Fields = append(stmt.Fields, field)
+
+		case p.curTokenIs(token.FUNCTION):
+			if stmt.Constructor != nil {
+				msg := fmt.Sprintf("Mstari %d: MUUNDO %s una 'unda' zaidi ya moja", p.curToken.Line, stmt.Name.Value)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			fn, ok := p.parseFunctionLiteral().(*ast.FunctionLiteral)
+			if !ok {
+				return nil
+			}
+			if fn.Generator {
+				msg := fmt.Sprintf("Mstari %d: 'unda' ya MUUNDO %s haiwezi kutumia 'toa'", fn.Token.Line, stmt.Name.Value)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			stmt.Constructor = fn
+
+		case p.curTokenIs(token.IDENT):
+			name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			if methods[name.Value] {
+				msg := fmt.Sprintf("Mstari %d: Method %s imetajwa mara mbili ndani ya MUUNDO %s", p.curToken.Line, name.Value, stmt.Name.Value)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			methods[name.Value] = true
+
+			fn, ok := p.parseFunctionLiteral().(*ast.FunctionLiteral)
+			if !ok {
+				return nil
+			}
+			stmt.Methods = append(stmt.Methods, &ast.Method{Name: name, Function: fn})
+
+		default:
+			msg := fmt.Sprintf("Mstari %d: Ndani ya MUUNDO tulitegemea 'fanya', 'unda' au jina la method, badala yake tumepata %s", p.curToken.Line, p.curToken.Literal)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		p.nextToken()
+	}
+	stmt.End = p.curToken.Position
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseThis() ast.Expression {
+	return &ast.ThisExpression{Token: p.curToken}
+}
+
+func (p *Parser) parseSpawnExpression() ast.Expression {
+	expression := &ast.SpawnExpression{Token: p.curToken}
+
+	p.nextToken()
+	call, ok := p.parseExpression(PREFIX).(*ast.CallExpression)
+	if !ok {
+		msg := fmt.Sprintf("Mstari %d: Tulitegemea kuita unda baada ya 'sambamba'", expression.Token.Line)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	expression.Call = call
+
+	return expression
+}
+
+// parseYieldExpression parses 'toa x', which makes the function it is in a
+// generator
+func (p *Parser) parseYieldExpression() ast.Expression {
+	expression := &ast.YieldExpression{Token: p.curToken}
+
+	if len(p.functions) == 0 {
+		msg := fmt.Sprintf("Mstari %d: 'toa' inatumika ndani ya unda tu", p.curToken.Line)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	p.functions[len(p.functions)-1].Generator = true
+
+	p.nextToken()
+	expression.Value = p.parseExpression(LOWEST)
+	if expression.Value == nil {
+		return nil
+	}
+
+	return expression
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/AvicennaJr/Nuru/ast"
@@ -42,6 +43,93 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		constant bool
+	}{
+		{"thabiti PI = 3.14159", "thabiti PI = 3.14159;", true},
+		{"thabiti JINA = \"Nuru\";", "thabiti JINA = Nuru;", true},
+		{"fanya x = 1", "fanya x = 1;", false},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.LetStatement. got=%T", program.Statements[0])
+		}
+		if stmt.Constant != tt.constant {
+			t.Errorf("stmt.Constant wrong. want=%t, got=%t", tt.constant, stmt.Constant)
+		}
+		if stmt.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, stmt.String())
+		}
+	}
+}
+
+func TestDestructuringStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		dict     bool
+	}{
+		{"fanya [a, b, c] = orodha", "fanya [a, b, c] = orodha;", false},
+		{"fanya [a] = [1];", "fanya [a] = [1];", false},
+		{"fanya {jina, umri} = kamusi", "fanya {jina, umri} = kamusi;", true},
+		{"fanya x, y = f()", "fanya x, y = f();", false},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.DestructuringStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.DestructuringStatement, got=%T", program.Statements[0])
+		}
+		if stmt.String() != tt.expected {
+			t.Errorf("wrong statement. want=%q, got=%q", tt.expected, stmt.String())
+		}
+		if stmt.Dict != tt.dict {
+			t.Errorf("stmt.Dict wrong. want=%t, got=%t", tt.dict, stmt.Dict)
+		}
+	}
+
+	// a block after 'fanya' is still the start of a do-while loop
+	p := New(lexer.New("fanya { i } wakati (i < 3)"))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	if _, ok := stmt.Expression.(*ast.DoWhileExpression); !ok {
+		t.Errorf("expression is not *ast.DoWhileExpression, got=%T", stmt.Expression)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"fanya [a, a] = b", "Mstari 1: Jina a limetumika zaidi ya mara moja"},
+		{"fanya [a, 1] = b", "Mstari 1: Tulitegemea kupata KITAMBULISHI, badala yake tumepata NAMBA"},
+		{"fanya [a, b] b", "Mstari 1: Tulitegemea kupata =, badala yake tumepata KITAMBULISHI"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q. want first=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
 func testLetStatement(t *testing.T, s ast.Statement, name string) bool {
 	if s.TokenLiteral() != "fanya" {
 		t.Errorf("s.TokenLiteral not 'fanya', got = %q", s.TokenLiteral())
@@ -117,6 +205,41 @@ func TestReturnStatements(t *testing.T) {
 	}
 }
 
+func TestMultipleReturnValues(t *testing.T) {
+	program := New(lexer.New("rudisha a, b + 1, 3")).ParseProgram()
+	stmt := program.Statements[0].(*ast.ReturnStatement)
+	if !stmt.Multiple {
+		t.Errorf("stmt.Multiple is not true")
+	}
+	array, ok := stmt.ReturnValue.(*ast.ArrayLiteral)
+	if !ok || len(array.Elements) != 3 {
+		t.Fatalf("stmt.ReturnValue is not an array of 3, got=%T (%+v)", stmt.ReturnValue, stmt.ReturnValue)
+	}
+	if stmt.String() != "rudisha a, (b + 1), 3;" {
+		t.Errorf("stmt.String() wrong. got=%q", stmt.String())
+	}
+}
+
+func TestSpreadExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(...a)", "f(...a)"},
+		{"f(1, ...a + b, c=2)", "f(1, ...(a + b), c=2)"},
+		{"[0, ...a, ...[1, 2]]", "[0, ...a, ...[1, 2]]"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. want=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
 func TestIdentifierExpression(t *testing.T) {
 	input := "foobar;"
 
@@ -180,6 +303,25 @@ func TestIntergerLiteral(t *testing.T) {
 	}
 }
 
+func TestBigIntegerLiteralExpression(t *testing.T) {
+	input := "123456789012345678901234567890;"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.BigIntegerLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.BigIntegerLiteral, got=%T", stmt.Expression)
+	}
+
+	if literal.Value.String() != "123456789012345678901234567890" {
+		t.Errorf("literal.Value wrong, got=%s", literal.Value)
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -300,6 +442,70 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"a + b + c",
 			"((a + b) + c)",
 		},
+		{
+			"a ** b ** c",
+			"(a ** (b ** c))",
+		},
+		{
+			"a | b ^ c & d",
+			"(a | (b ^ (c & d)))",
+		},
+		{
+			"a & b << c + d",
+			"(a & (b << (c + d)))",
+		},
+		{
+			"a >> b < c | d",
+			"((a >> b) < (c | d))",
+		},
+		{
+			"a + b \\ c * d",
+			"(a + ((b \\ c) * d))",
+		},
+		{
+			"~a & b",
+			"((~a) & b)",
+		},
+		{
+			"a || b ? c + 1 : d",
+			"((a || b) ? (c + 1) : d)",
+		},
+		{
+			"a ?? b || c ?? d",
+			"((a ?? (b || c)) ?? d)",
+		},
+		{
+			"a ?? b ? c : d",
+			"((a ?? b) ? c : d)",
+		},
+		{
+			"0 < x < 10",
+			"(0 < x < 10)",
+		},
+		{
+			"a <= b + 1 > c == d",
+			"((a <= (b + 1) > c) == d)",
+		},
+		{
+			"(a < b) < c",
+			"((a < b) < c)",
+		},
+		{
+			"a ? b : c ? d : e",
+			"(a ? b : (c ? d : e))",
+		},
+		{
+			"a ? b ? c : d : e",
+			"(a ? (b ? c : d) : e)",
+		},
+		{
+			"-a ** b * c",
+			"((-(a ** b)) * c)",
+		},
+		{
+			"a ** -b % c",
+			"((a ** (-b)) % c)",
+		},
 		{
 			"a + b - c",
 			"((a + b) - c)",
@@ -328,6 +534,14 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"5 > 4 == 3 < 4",
 			"((5 > 4) == (3 < 4))",
 		},
+		{
+			"a | b & c - d",
+			"(a | (b & (c - d)))",
+		},
+		{
+			"a & b == c",
+			"((a & b) == c)",
+		},
 		{
 			"5 < 4 != 3 > 4",
 			"((5 < 4) != (3 > 4))",
@@ -673,14 +887,68 @@ func TestFunctionLiteralParsing(t *testing.T) {
 	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
 }
 
+func TestTailCallMarking(t *testing.T) {
+	input := `unda(n) {
+	f(n)
+	kama (n) {
+		rudisha g(n)
+	}
+	jaribu {
+		rudisha h(n)
+	} shika {}
+	kama (n) { i(n) } sivyo { j(n) }
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	body := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral).Body
+	callIn := func(stmt ast.Statement) *ast.CallExpression {
+		switch stmt := stmt.(type) {
+		case *ast.ReturnStatement:
+			return stmt.ReturnValue.(*ast.CallExpression)
+		case *ast.ExpressionStatement:
+			return stmt.Expression.(*ast.CallExpression)
+		}
+		t.Fatalf("no call in %T", stmt)
+		return nil
+	}
+
+	ifExp := body.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+	tryExp := body.Statements[2].(*ast.ExpressionStatement).Expression.(*ast.TryExpression)
+	lastIf := body.Statements[3].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+
+	tests := []struct {
+		call     *ast.CallExpression
+		expected bool
+	}{
+		{callIn(body.Statements[0]), false},
+		{callIn(ifExp.Consequence.Statements[0]), true},
+		{callIn(tryExp.Block.Statements[0]), false},
+		{callIn(lastIf.Consequence.Statements[0]), true},
+		{callIn(lastIf.Alternative.Statements[0]), true},
+	}
+
+	for i, tt := range tests {
+		if tt.call.Tail != tt.expected {
+			t.Errorf("tests[%d] - %s: Tail wrong. want=%t, got=%t", i, tt.call.String(), tt.expected, tt.call.Tail)
+		}
+	}
+}
+
 func TestFunctionParameterParsing(t *testing.T) {
 	tests := []struct {
 		input          string
 		expectedParams []string
+		variadic       bool
 	}{
 		{input: "unda() {};", expectedParams: []string{}},
 		{input: "unda(x) {};", expectedParams: []string{"x"}},
 		{input: "unda(x, y, z) {};", expectedParams: []string{"x", "y", "z"}},
+		{input: "unda(...x) {};", expectedParams: []string{"x"}, variadic: true},
+		{input: "unda(x, ...y) {};", expectedParams: []string{"x", "y"}, variadic: true},
 	}
 
 	for _, tt := range tests {
@@ -699,6 +967,66 @@ func TestFunctionParameterParsing(t *testing.T) {
 		for i, ident := range tt.expectedParams {
 			testLiteralExpression(t, function.Parameters[i], ident)
 		}
+
+		if function.Variadic != tt.variadic {
+			t.Errorf("function.Variadic wrong. want=%t, got=%t", tt.variadic, function.Variadic)
+		}
+	}
+}
+
+func TestNamedArguments(t *testing.T) {
+	program := New(lexer.New("chora(1, upana=10, urefu=a + 5)")).ParseProgram()
+	call := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	if len(call.Arguments) != 3 {
+		t.Fatalf("wrong number of arguments. want=3, got=%d", len(call.Arguments))
+	}
+	testIntegerLiteral(t, call.Arguments[0], 1)
+
+	expected := []string{"upana=10", "urefu=(a + 5)"}
+	for i, want := range expected {
+		arg, ok := call.Arguments[i+1].(*ast.NamedArgument)
+		if !ok {
+			t.Fatalf("argument %d is not *ast.NamedArgument, got=%T", i+1, call.Arguments[i+1])
+		}
+		if arg.String() != want {
+			t.Errorf("argument %d wrong. want=%q, got=%q", i+1, want, arg.String())
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(a=1, 2)", "Mstari 1: Hoja isiyo na jina haiwezi kufuata hoja yenye jina"},
+		{"f(a=1, a=2)", "Mstari 1: Hoja a imetolewa zaidi ya mara moja"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q. want first=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestVariadicParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"unda(...a, b) {}", "Mstari 1: ...a lazima iwe hoja ya mwisho"},
+		{"unda(a, ...) {}", "Mstari 1: Tulitegemea kupata KITAMBULISHI, badala yake tumepata )"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q. want first=%q, got=%q", tt.input, tt.expected, errors)
+		}
 	}
 }
 
@@ -1012,52 +1340,789 @@ func TestWhileLoop(t *testing.T) {
 	}
 }
 
-func TestShorthandAssignment(t *testing.T) {
-	input := []string{
-		"fanya x = 10; x *= 20;",
-		"fanya x = 5; x += 4;",
-		"fanya x = 7; x /= 2;",
-		"fanya x = 8; x -= 1;",
-		"fanya x = 5; x++;",
-		"fanya x = 3; x--;",
-		"fanya x = 40; fanya y = 13; x += y;"}
+func TestPostfixExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"i++", "(i++)"},
+		{"i--;", "(i--)"},
+		{"fanya j = i++", "fanya j = (i++);"},
+		{"i++ * 2 + i", "(((i++) * 2) + i)"},
+		{"-i++", "(-(i++))"},
+	}
 
-	for _, txt := range input {
-		l := lexer.New(txt)
-		p := New(l)
-		_ = p.ParseProgram()
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
 		checkParserErrors(t, p)
+		if len(program.Statements) != 1 {
+			t.Fatalf("wrong number of statements for %q. got=%d", tt.input, len(program.Statements))
+		}
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. want=%q, got=%q", tt.input, tt.expected, program.String())
+		}
 	}
 }
 
-func TestForExpression(t *testing.T) {
-	input := `kwa i, v ktk j {andika(i)}`
+func TestOptionalIndexExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`a?["b"]?[0]`, "((a?[b])?[0])"},
+		{`a?[1] + a[2]`, "((a?[1]) + (a[2]))"},
+		{"a ? [1] : [2]", "(a ? [1] : [2])"},
+	}
 
-	l := lexer.New(input)
-	p := New(l)
-	program := p.ParseProgram()
-	checkParserErrors(t, p)
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. want=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
 
-	if len(program.Statements) != 1 {
-		t.Fatalf("program.Body does not contain %d statements. got=%d", 1, len(program.Statements))
+	p := New(lexer.New(`a?["b"] = 1`))
+	p.ParseProgram()
+	expected := "Mstari 1: Huwezi kuweka thamani kwenye (a?[b])"
+	if len(p.Errors()) == 0 || p.Errors()[0] != expected {
+		t.Errorf("wrong errors. want=%q, got=%q", expected, p.Errors())
 	}
+}
 
-	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
-	if !ok {
-		t.Fatalf("program.Statements[0] is not ast.Expression, got=%T", program.Statements[0])
+func TestUnclosedCommentError(t *testing.T) {
+	p := New(lexer.New("fanya x = 1\n/* maoni\nyasiyofungwa"))
+	p.ParseProgram()
+	expected := "Mstari 2: Maoni yaliyoanza na /* hayajafungwa na */"
+	if len(p.Errors()) != 1 || p.Errors()[0] != expected {
+		t.Errorf("wrong errors. want=%q, got=%q", expected, p.Errors())
 	}
+}
 
-	exp, ok := stmt.Expression.(*ast.ForIn)
+func TestSliceExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a[1:4]", "(a[1:4])"},
+		{"a[:3]", "(a[:3])"},
+		{"a[1:]", "(a[1:])"},
+		{"a[:]", "(a[:])"},
+		{"a[::2]", "(a[::2])"},
+		{"a[::]", "(a[:])"},
+		{"a[-1:0:-1]", "(a[(-1):0:(-1)])"},
+		{"a?[i + 1:][0]", "((a?[(i + 1):])[0])"},
+		{"a[b ? 1 : 2]", "(a[(b ? 1 : 2)])"},
+	}
 
-	if !ok {
-		t.Fatalf("stmt.Expression is not ast.ForIn. got=%T", stmt.Expression)
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. want=%q, got=%q", tt.input, tt.expected, program.String())
+		}
 	}
 
-	if exp.Key != "i" {
-		t.Fatalf("Wrong Key Index, expected 'i' got %s", exp.Key)
+	p := New(lexer.New("a[1:2] = [5]"))
+	p.ParseProgram()
+	expected := "Mstari 1: Huwezi kuweka thamani kwenye (a[1:2])"
+	if len(p.Errors()) == 0 || p.Errors()[0] != expected {
+		t.Errorf("wrong errors. want=%q, got=%q", expected, p.Errors())
 	}
+}
 
-	if exp.Value != "v" {
-		t.Fatalf("Wrong Value Index, expected 'v' got %s", exp.Value)
+func TestArrowFunction(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		params   int
+	}{
+		{"(x) => x * 2", "(x) => (x * 2)", 1},
+		{"x => x * 2", "(x) => (x * 2)", 1},
+		{"() => 1", "() => 1", 0},
+		{"(a, b) => a + b", "(a, b) => (a + b)", 2},
+		{"(a, ...b) => b", "(a, ...b) => b", 2},
+		{"(...b) => b", "(...b) => b", 1},
+		{"(x) => { x }", "(x) => { x }", 1},
+		{"(x) => y => x + y", "(x) => (y) => (x + y)", 1},
+		{"(x) => x ? 1 : 2", "(x) => (x ? 1 : 2)", 1},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		fn, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+		if !ok || !fn.Arrow {
+			t.Fatalf("%q is not an arrow *ast.FunctionLiteral", tt.input)
+		}
+		if len(fn.Parameters) != tt.params {
+			t.Errorf("wrong number of parameters for %q. want=%d, got=%d", tt.input, tt.params, len(fn.Parameters))
+		}
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. want=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	others := []struct {
+		input    string
+		expected string
+	}{
+		{"f((x) => x, 2)", "f((x) => x, 2)"},
+		{"(x)", "x"},
+		{"(a + b) * c", "((a + b) * c)"},
+		{"((x) => x + 1)(2)", "(x) => (x + 1)(2)"},
+	}
+
+	for _, tt := range others {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. want=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"(a + b) => 1", "Mstari 1: Hoja za => lazima ziwe majina, sio (a + b)"},
+		{"(a, 1) => 1", "Mstari 1: Hoja za => lazima ziwe majina, sio 1"},
+		{"(...a, b) => 1", "Mstari 1: ...a lazima iwe hoja ya mwisho"},
+		{"(a, b)", "Mstari 1: Tulitegemea kupata =>, badala yake tumepata MWISHO"},
+		{"f(1) => 1", "Mstari 1: Hoja za => lazima ziwe majina, sio f(1)"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q. want first=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestComprehension(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[x * 2 kwa x ktk orodha kama x > 0]", "[(x * 2) kwa x ktk orodha kama (x > 0)]"},
+		{"[i kwa i, x ktk orodha]", "[i kwa i, x ktk orodha]"},
+		{"{k: v + 1 kwa k, v ktk kamusi}", "{k: (v + 1) kwa k, v ktk kamusi}"},
+		{"[[y kwa y ktk x] kwa x ktk orodha]", "[[y kwa y ktk x] kwa x ktk orodha]"},
+		{"[a ? b : c kwa a ktk d kama a ?? e]", "[(a ? b : c) kwa a ktk d kama (a ?? e)]"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if _, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.Comprehension); !ok {
+			t.Errorf("%q is not *ast.Comprehension", tt.input)
+		}
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. want=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"[...x kwa x ktk o]", "Mstari 1: Huwezi kutumia ... kabla ya kwa"},
+		{"[x kwa 1 ktk o]", "Mstari 1: Tulitegemea kupata KITAMBULISHI, badala yake tumepata NAMBA"},
+		{"[x kwa x o]", "Mstari 1: Tulitegemea kupata KTK, badala yake tumepata KITAMBULISHI"},
+		{"[1, x kwa x ktk o]", "Mstari 1: Tulitegemea kupata ], badala yake tumepata KWA"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q. want first=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestConditionalParseErrors(t *testing.T) {
+	p := New(lexer.New("a ? b"))
+	p.ParseProgram()
+	expected := "Mstari 1: Tulitegemea kupata :, badala yake tumepata MWISHO"
+	if len(p.Errors()) == 0 || p.Errors()[0] != expected {
+		t.Errorf("wrong errors. want=%q, got=%q", expected, p.Errors())
+	}
+}
+
+func TestPostfixParseErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a[0]++", "Mstari 1: '++' inatumika na jina tu, sio (a[0])"},
+		{"5--", "Mstari 1: '--' inatumika na jina tu, sio 5"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 || p.Errors()[0] != tt.expected {
+			t.Errorf("wrong errors for %q. want=%q, got=%q", tt.input, tt.expected, p.Errors())
+		}
+	}
+}
+
+func TestShorthandAssignment(t *testing.T) {
+	input := []string{
+		"fanya x = 10; x *= 20;",
+		"fanya x = 5; x += 4;",
+		"fanya x = 7; x /= 2;",
+		"fanya x = 8; x -= 1;",
+		"fanya x = 5; x++;",
+		"fanya x = 3; x--;",
+		"fanya x = 40; fanya y = 13; x += y;"}
+
+	for _, txt := range input {
+		l := lexer.New(txt)
+		p := New(l)
+		_ = p.ParseProgram()
+		checkParserErrors(t, p)
+	}
+}
+
+func TestForExpression(t *testing.T) {
+	input := `kwa i, v ktk j {andika(i)}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Body does not contain %d statements. got=%d", 1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.Expression, got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.ForIn)
+
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.ForIn. got=%T", stmt.Expression)
+	}
+
+	if exp.Key != "i" {
+		t.Fatalf("Wrong Key Index, expected 'i' got %s", exp.Key)
+	}
+
+	if exp.Value != "v" {
+		t.Fatalf("Wrong Value Index, expected 'v' got %s", exp.Value)
+	}
+}
+
+func TestTemplateLiteral(t *testing.T) {
+	input := `"jumla ni ${a + b}!"`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	template, ok := stmt.Expression.(*ast.TemplateLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.TemplateLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(template.Parts) != 3 {
+		t.Fatalf("template.Parts does not contain 3 parts. got=%d", len(template.Parts))
+	}
+
+	if str, ok := template.Parts[0].(*ast.StringLiteral); !ok || str.Value != "jumla ni " {
+		t.Errorf("first part wrong. got=%q", template.Parts[0].String())
+	}
+
+	testInfixExpression(t, template.Parts[1], "a", "+", "b")
+
+	if str, ok := template.Parts[2].(*ast.StringLiteral); !ok || str.Value != "!" {
+		t.Errorf("last part wrong. got=%q", template.Parts[2].String())
+	}
+}
+
+func TestTemplateLiteralErrors(t *testing.T) {
+	tests := []string{
+		`"${}"`,
+		`"${1 +}"`,
+		`"${1 2}"`,
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestDoWhileExpression(t *testing.T) {
+	input := `fanya { x += 1 } wakati (x < 10)`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Body does not contain %d statements. got=%d", 1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement, got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.DoWhileExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.DoWhileExpression. got=%T", stmt.Expression)
+	}
+
+	if len(exp.Consequence.Statements) != 1 {
+		t.Errorf("Consequence is not 1 statement. got=%d", len(exp.Consequence.Statements))
+	}
+
+	if !testInfixExpression(t, exp.Condition, "x", "<", 10) {
+		return
+	}
+}
+
+func TestClassStatement(t *testing.T) {
+	input := `muundo Mtu {
+	fanya jina = "bila jina"
+	fanya umri = 0
+
+	unda(jina) {
+		hii.jina = jina
+	}
+
+	salamu() {
+		rudisha "Habari " + hii.jina
+	}
+
+	kua(miaka) { hii.umri += miaka }
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Body does not contain %d statements. got=%d", 1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ClassStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ClassStatement, got=%T", program.Statements[0])
+	}
+
+	if !testIdentifier(t, stmt.Name, "Mtu") {
+		return
+	}
+
+	if len(stmt.Fields) != 2 || stmt.Fields[0].Name.Value != "jina" || stmt.Fields[1].Name.Value != "umri" {
+		t.Errorf("wrong fields. got=%d", len(stmt.Fields))
+	}
+
+	if stmt.Constructor == nil || len(stmt.Constructor.Parameters) != 1 {
+		t.Fatalf("constructor not parsed correctly")
+	}
+
+	first, ok := stmt.Constructor.Body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("constructor body is not ast.ExpressionStatement. got=%T", stmt.Constructor.Body.Statements[0])
+	}
+	assign, ok := first.Expression.(*ast.AssignmentExpression)
+	if !ok {
+		t.Fatalf("constructor body is not ast.AssignmentExpression. got=%T", first.Expression)
+	}
+	if assign.Left.String() != "(hii.jina)" {
+		t.Errorf("wrong assignment target. got=%q", assign.Left.String())
+	}
+
+	expectedMethods := []string{"salamu", "kua"}
+	if len(stmt.Methods) != len(expectedMethods) {
+		t.Fatalf("wrong number of methods. want=%d, got=%d", len(expectedMethods), len(stmt.Methods))
+	}
+	for i, name := range expectedMethods {
+		if stmt.Methods[i].Name.Value != name {
+			t.Errorf("method %d wrong. want=%s, got=%s", i, name, stmt.Methods[i].Name.Value)
+		}
+	}
+}
+
+func TestClassStatementSemicolon(t *testing.T) {
+	l := lexer.New(`muundo M { x() { 1 } }; andika(1)`)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Body does not contain %d statements. got=%d", 2, len(program.Statements))
+	}
+	if _, ok := program.Statements[0].(*ast.ClassStatement); !ok {
+		t.Fatalf("program.Statements[0] is not ast.ClassStatement, got=%T", program.Statements[0])
+	}
+}
+
+func TestClassParseErrors(t *testing.T) {
+	tests := []string{
+		`muundo { }`,
+		`muundo Mtu { salamu() { 1 }`,
+		`muundo Mtu { unda() { 1 } unda(a) { 2 } }`,
+		`muundo Mtu { salamu() { 1 } salamu() { 2 } }`,
+		`muundo Mtu { 5 }`,
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestSwitchExpression(t *testing.T) {
+	input := `badili (a) {
+	ikiwa 1 { andika("moja") }
+	ikiwa 2, 3 { andika("mbili au tatu") }
+	kawaida { andika("nyingine") }
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Body does not contain %d statements. got=%d", 1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement, got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.SwitchExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.SwitchExpression. got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, exp.Value, "a") {
+		return
+	}
+
+	if len(exp.Choices) != 3 {
+		t.Fatalf("exp.Choices does not contain 3 cases. got=%d", len(exp.Choices))
+	}
+
+	expectedValues := [][]int64{{1}, {2, 3}, {}}
+	for i, values := range expectedValues {
+		choice := exp.Choices[i]
+		if len(choice.Expr) != len(values) {
+			t.Errorf("case %d has wrong number of values. want=%d, got=%d", i, len(values), len(choice.Expr))
+			continue
+		}
+		for j, v := range values {
+			testIntegerLiteral(t, choice.Expr[j], v)
+		}
+	}
+
+	if !exp.Choices[2].Default {
+		t.Errorf("last case is not kawaida")
+	}
+}
+
+func TestSwitchParseErrors(t *testing.T) {
+	tests := []string{
+		`badili (a) { ikiwa 1 { 1 }`,
+		`badili (a) { kawaida { 1 } kawaida { 2 } }`,
+		`badili (a) { 1 { 1 } }`,
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	input := `jaribu { x / 0 } shika (kosa) { andika(kosa) }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Body does not contain %d statements. got=%d", 1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement, got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.TryExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.TryExpression. got=%T", stmt.Expression)
+	}
+
+	if len(exp.Block.Statements) != 1 {
+		t.Errorf("Block is not 1 statement. got=%d", len(exp.Block.Statements))
+	}
+
+	if !testIdentifier(t, exp.Identifier, "kosa") {
+		return
+	}
+
+	if len(exp.Catch.Statements) != 1 {
+		t.Errorf("Catch is not 1 statement. got=%d", len(exp.Catch.Statements))
+	}
+}
+
+func TestThrowStatement(t *testing.T) {
+	input := `tupa "hitilafu";`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Body does not contain %d statements. got=%d", 1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ThrowStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ThrowStatement, got=%T", program.Statements[0])
+	}
+
+	if stmt.Value.String() != "hitilafu" {
+		t.Errorf("stmt.Value wrong. got=%q", stmt.Value.String())
+	}
+}
+
+func TestImportStatement(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedName string
+		expectedPath string
+	}{
+		{`tumia "hesabu.nr"`, "hesabu", "hesabu.nr"},
+		{`tumia "lib/maneno.nr";`, "maneno", "lib/maneno.nr"},
+		{`tumia hesabu`, "hesabu", "hesabu"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Body does not contain %d statements. got=%d", 1, len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ImportStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ImportStatement, got=%T", program.Statements[0])
+		}
+
+		if stmt.Name.Value != tt.expectedName {
+			t.Errorf("stmt.Name wrong, expected=%q, got=%q", tt.expectedName, stmt.Name.Value)
+		}
+
+		if stmt.Path != tt.expectedPath {
+			t.Errorf("stmt.Path wrong, expected=%q, got=%q", tt.expectedPath, stmt.Path)
+		}
+	}
+}
+
+func TestPropertyExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hesabu.jumla", "(hesabu.jumla)"},
+		{"hesabu.jumla(1, 2)", "(hesabu.jumla)(1, 2)"},
+		{"-a.b * c", "((-(a.b)) * c)"},
+		{"a.b.c[0]", "(((a.b).c)[0])"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestSpawnExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"sambamba kazi(1, 2)", "sambamba kazi(1, 2)"},
+		{"fanya a = sambamba kazi()", "fanya a = sambamba kazi();"},
+		{"sambamba hesabu.jumla(1)", "sambamba (hesabu.jumla)(1)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	p := New(lexer.New("sambamba kazi"))
+	p.ParseProgram()
+	if len(p.Errors()) == 0 || !strings.Contains(p.Errors()[0], "Tulitegemea kuita unda baada ya 'sambamba'") {
+		t.Errorf("expected an error for a spawn without a call, got=%v", p.Errors())
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		names    []string
+	}{
+		{"linganisha (x) { 1 => \"moja\" }", "linganisha (x) { 1 => moja }", nil},
+		{"linganisha (x) { -1 => 0, 2.5 => 1, kweli => 2, tupu => 3, }", "linganisha (x) { (-1) => 0, 2.5 => 1, kweli => 2, tupu => 3 }", nil},
+		{"linganisha (x) { [a, _, ...b] => a }", "linganisha (x) { [a, _, ...b] => a }", []string{"a", "b"}},
+		{"linganisha (x) { {\"k\": [a], jina} => a }", "", []string{"a", "jina"}},
+		{"linganisha (x) { n kama (n > 1) => n * 2 }", "linganisha (x) { n kama ((n > 1)) => (n * 2) }", []string{"n"}},
+		{"linganisha (x) { _ => { 1; 2 } }", "linganisha (x) { _ => { 12 } }", nil},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		match, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.MatchExpression)
+		if !ok {
+			t.Fatalf("%q is not an *ast.MatchExpression", tt.input)
+		}
+		// the pairs of a dict pattern are printed in no particular order
+		if tt.expected != "" && program.String() != tt.expected {
+			t.Errorf("wrong program for %q. want=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+		var names []string
+		for _, name := range match.Arms[0].Names() {
+			names = append(names, name.Value)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.names, ",") {
+			t.Errorf("wrong names for %q. want=%v, got=%v", tt.input, tt.names, names)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{"linganisha (x) { a + 1 => 1 }", "Tulitegemea kupata =>"},
+		{"linganisha (x) { f() => 1 }", "Tulitegemea kupata =>"},
+		{"linganisha (x) { \"a\" + b => 1 }", "Tulitegemea kupata =>"},
+		{"linganisha (x) { (a) => 1 }", "Huwezi kulinganisha na ("},
+		{"linganisha (x) { [...a, b] => 1 }", "...a lazima iwe mwisho wa pattern"},
+		{"linganisha (x) { [a, a] => 1 }", "a imetajwa mara mbili ndani ya pattern"},
+		{"linganisha (x) { {\"x\": a, \"y\": [_, ...a]} => 1 }", "a imetajwa mara mbili ndani ya pattern"},
+		{"linganisha (x) { {a: 1} => 1 }", "Tulitegemea kupata ,"},
+		{"linganisha (x) { {[1]: 1} => 1 }", "Ufunguo wa pattern lazima uwe neno au namba, sio ["},
+		{"linganisha (x) { 1 => 1 2 => 2 }", "Tulitegemea kupata ,"},
+		{"linganisha (x) { 1 => 1", "Haukufunga LINGANISHA"},
+	}
+
+	for _, tt := range errors {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 || !strings.Contains(p.Errors()[0], tt.expected) {
+			t.Errorf("expected an error containing %q for %q, got=%v", tt.expected, tt.input, p.Errors())
+		}
+	}
+}
+
+func TestYieldExpression(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  string
+		generator bool
+	}{
+		{"unda() { toa 1 }", "unda() toa 1", true},
+		{"unda(n) { toa n + 1 }", "unda(n) toa (n + 1)", true},
+		{"(x) => { toa x }", "(x) => { toa x }", true},
+		{"unda() { unda() { toa 1 } }", "unda() unda() toa 1", false},
+		{"unda() { 1 }", "unda() 1", false},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		fn, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+		if !ok {
+			t.Fatalf("%q is not an *ast.FunctionLiteral", tt.input)
+		}
+		if fn.Generator != tt.generator {
+			t.Errorf("wrong Generator for %q. want=%t, got=%t", tt.input, tt.generator, fn.Generator)
+		}
+		if program.String() != tt.expected {
+			t.Errorf("wrong program for %q. want=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{"toa 1", "'toa' inatumika ndani ya unda tu"},
+		{"muundo M { unda() { toa 1 } }", "'unda' ya MUUNDO M haiwezi kutumia 'toa'"},
+	}
+
+	for _, tt := range errors {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 || !strings.Contains(p.Errors()[0], tt.expected) {
+			t.Errorf("expected an error containing %q for %q, got=%v", tt.expected, tt.input, p.Errors())
+		}
 	}
 }
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AvicennaJr/Nuru/evaluator"
+)
+
+// printProfile writes the report of --profile: every function that was
+// called, the slowest first, with how often it was called and for how long
+func printProfile(w io.Writer, profiles []evaluator.FunctionProfile, total time.Duration) {
+	fmt.Fprintf(w, "\nMuda wote: %s\n\n", total.Round(time.Microsecond))
+	if len(profiles) == 0 {
+		fmt.Fprintln(w, "Hakuna unda iliyoitwa")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MUDA\t%\tMIITO\tUNDA")
+	for _, p := range profiles {
+		percent := 0.0
+		if total > 0 {
+			percent = 100 * float64(p.Time) / float64(total)
+		}
+		where := fmt.Sprintf("Mstari %d", p.Position.Line)
+		if p.Position.File != "" {
+			where = p.Position.File + ":" + fmt.Sprint(p.Position.Line)
+		}
+		fmt.Fprintf(tw, "%s\t%.1f\t%d\t%s (%s)\n", p.Time.Round(time.Microsecond), percent, p.Calls, p.Name, where)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,93 @@
+package repl
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+// envCompleter completes keywords, builtins and the names defined in env.
+// Right after 'd[' it completes the keys of the dict d instead.
+func envCompleter(env *object.Environment) completer {
+	return func(line []rune, pos int) (int, []string) {
+		start := pos
+		for start > 0 && isNameRune(line[start-1]) {
+			start--
+		}
+
+		if keyStart, dict, ok := dictBeforeCursor(line, start, env); ok {
+			return keyStart, dictKeys(dict, string(line[keyStart:pos]))
+		}
+
+		word := string(line[start:pos])
+		if word == "" {
+			return start, nil
+		}
+
+		seen := make(map[string]bool)
+		var candidates []string
+		names := append(token.Keywords(), evaluator.BuiltinNames()...)
+		names = append(names, env.Names()...)
+		for _, name := range names {
+			if strings.HasPrefix(name, word) && !seen[name] {
+				seen[name] = true
+				candidates = append(candidates, name)
+			}
+		}
+		sort.Strings(candidates)
+		return start, candidates
+	}
+}
+
+// dictBeforeCursor looks for 'd[' or 'd["' just before start, where d is a
+// dict. It returns where the key starts, which is right after the '['.
+func dictBeforeCursor(line []rune, start int, env *object.Environment) (int, *object.Dict, bool) {
+	i := start
+	if i > 0 && line[i-1] == '"' {
+		i--
+	}
+	if i == 0 || line[i-1] != '[' {
+		return 0, nil, false
+	}
+	keyStart := i
+
+	end := i - 1
+	i = end
+	for i > 0 && isNameRune(line[i-1]) {
+		i--
+	}
+	if i == end {
+		return 0, nil, false
+	}
+
+	obj, ok := env.Get(string(line[i:end]))
+	if !ok {
+		return 0, nil, false
+	}
+	dict, ok := obj.(*object.Dict)
+	return keyStart, dict, ok
+}
+
+func dictKeys(dict *object.Dict, typed string) []string {
+	var keys []string
+	for _, pair := range dict.Pairs {
+		key := pair.Key.Inspect()
+		if str, ok := pair.Key.(*object.String); ok {
+			key = `"` + str.Value + `"`
+		}
+		key += "]"
+		if strings.HasPrefix(key, typed) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
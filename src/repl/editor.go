@@ -0,0 +1,313 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// errInterrupted is returned by readLine when Ctrl-C is pressed
+var errInterrupted = errors.New("imekatizwa")
+
+// completer returns the text that could replace line[start:pos]
+type completer func(line []rune, pos int) (start int, candidates []string)
+
+// editor is a small line editor for the REPL. It expects its input to come
+// from a terminal in raw mode, so it sees every key as it is pressed.
+//
+// Input can span several lines: while the code typed so far is incomplete,
+// Enter starts a new line instead of submitting it, and the arrow keys move
+// between the lines.
+type editor struct {
+	in        *bufio.Reader
+	out       io.Writer
+	complete  completer
+	highlight func(input string) string // colours the input, if set
+	history   []string
+
+	prompt     string
+	contPrompt string
+	lines      [][]rune
+	row, col   int
+	drawnRow   int // the row the terminal cursor was left on by refresh
+}
+
+func newEditor(in io.Reader, out io.Writer, complete completer) *editor {
+	return &editor{in: bufio.NewReader(in), out: out, complete: complete}
+}
+
+// readLine reads one input, letting the user move around it, go through
+// history and complete names with Tab. The input may span several lines.
+func (e *editor) readLine(prompt, contPrompt string) (string, error) {
+	e.prompt, e.contPrompt = prompt, contPrompt
+	e.lines, e.row, e.col, e.drawnRow = [][]rune{nil}, 0, 0, 0
+	historyPos := len(e.history)
+	draft := ""
+
+	e.refresh()
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			if needsMoreInput(e.text()) {
+				e.splitLine()
+				break
+			}
+			e.moveToEnd()
+			fmt.Fprint(e.out, "\r\n")
+			input := e.text()
+			if strings.TrimSpace(input) != "" {
+				e.history = append(e.history, input)
+			}
+			return input, nil
+		case 3: // Ctrl-C
+			e.moveToEnd()
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl-D
+			if e.text() == "" {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			e.delete()
+		case 127, 8: // Backspace
+			if e.left() {
+				e.delete()
+			}
+		case 1: // Ctrl-A
+			e.col = 0
+		case 5: // Ctrl-E
+			e.col = len(e.lines[e.row])
+		case '\t':
+			e.completeWord()
+		case 27:
+			switch e.readEscape() {
+			case "[A":
+				if e.row > 0 {
+					e.row--
+					e.col = minInt(e.col, len(e.lines[e.row]))
+				} else if historyPos > 0 {
+					if historyPos == len(e.history) {
+						draft = e.text()
+					}
+					historyPos--
+					e.setText(e.history[historyPos])
+				}
+			case "[B":
+				if e.row < len(e.lines)-1 {
+					e.row++
+					e.col = minInt(e.col, len(e.lines[e.row]))
+				} else if historyPos < len(e.history) {
+					historyPos++
+					if historyPos == len(e.history) {
+						e.setText(draft)
+					} else {
+						e.setText(e.history[historyPos])
+					}
+				}
+			case "[C":
+				e.right()
+			case "[D":
+				e.left()
+			case "[H", "OH", "[1~":
+				e.col = 0
+			case "[F", "OF", "[4~":
+				e.col = len(e.lines[e.row])
+			case "[3~":
+				e.delete()
+			}
+		default:
+			if r >= ' ' {
+				e.insert(string(r))
+			}
+		}
+		e.refresh()
+	}
+}
+
+// readEscape reads the rest of an escape sequence, like "[A" for the up arrow
+func (e *editor) readEscape() string {
+	r, _, err := e.in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return ""
+	}
+	seq := []rune{r}
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return ""
+		}
+		seq = append(seq, r)
+		if r < '0' || r > '9' {
+			return string(seq)
+		}
+	}
+}
+
+func (e *editor) text() string {
+	lines := make([]string, len(e.lines))
+	for i, line := range e.lines {
+		lines[i] = string(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// setText replaces the input and puts the cursor at its end
+func (e *editor) setText(s string) {
+	e.lines = nil
+	for _, line := range strings.Split(s, "\n") {
+		e.lines = append(e.lines, []rune(line))
+	}
+	e.row = len(e.lines) - 1
+	e.col = len(e.lines[e.row])
+}
+
+func (e *editor) insert(s string) {
+	text := []rune(s)
+	line := e.lines[e.row]
+	newLine := make([]rune, 0, len(line)+len(text))
+	newLine = append(newLine, line[:e.col]...)
+	newLine = append(newLine, text...)
+	e.lines[e.row] = append(newLine, line[e.col:]...)
+	e.col += len(text)
+}
+
+// splitLine breaks the current line in two at the cursor
+func (e *editor) splitLine() {
+	line := e.lines[e.row]
+	rest := append([]rune{}, line[e.col:]...)
+	e.lines[e.row] = line[:e.col]
+
+	e.lines = append(e.lines, nil)
+	copy(e.lines[e.row+2:], e.lines[e.row+1:])
+	e.lines[e.row+1] = rest
+	e.row, e.col = e.row+1, 0
+}
+
+// delete removes the character under the cursor, joining the next line
+// when the cursor is at the end of a line
+func (e *editor) delete() {
+	line := e.lines[e.row]
+	if e.col < len(line) {
+		e.lines[e.row] = append(line[:e.col], line[e.col+1:]...)
+		return
+	}
+	if e.row < len(e.lines)-1 {
+		e.lines[e.row] = append(line, e.lines[e.row+1]...)
+		e.lines = append(e.lines[:e.row+1], e.lines[e.row+2:]...)
+	}
+}
+
+// left moves the cursor back one character, onto the end of the previous
+// line if needed. It reports whether the cursor moved.
+func (e *editor) left() bool {
+	switch {
+	case e.col > 0:
+		e.col--
+	case e.row > 0:
+		e.row--
+		e.col = len(e.lines[e.row])
+	default:
+		return false
+	}
+	return true
+}
+
+func (e *editor) right() {
+	switch {
+	case e.col < len(e.lines[e.row]):
+		e.col++
+	case e.row < len(e.lines)-1:
+		e.row++
+		e.col = 0
+	}
+}
+
+// moveToEnd puts the terminal cursor after the last line, so whatever is
+// written next doesn't overwrite the input
+func (e *editor) moveToEnd() {
+	e.row = len(e.lines) - 1
+	e.col = len(e.lines[e.row])
+	e.refresh()
+}
+
+// completeWord fills in as much of the word before the cursor as all the
+// candidates agree on. If that adds nothing, the candidates are listed.
+func (e *editor) completeWord() {
+	if e.complete == nil {
+		return
+	}
+	start, candidates := e.complete(e.lines[e.row], e.col)
+	if len(candidates) == 0 {
+		return
+	}
+
+	typed := e.col - start
+	prefix := []rune(commonPrefix(candidates))
+	if len(prefix) > typed {
+		e.insert(string(prefix[typed:]))
+		return
+	}
+
+	if len(candidates) > 1 {
+		row, col := e.row, e.col
+		e.moveToEnd()
+		fmt.Fprint(e.out, "\r\n"+strings.Join(candidates, "  ")+"\r\n")
+		e.row, e.col, e.drawnRow = row, col, 0
+	}
+}
+
+// refresh redraws the input and puts the cursor back where it belongs
+func (e *editor) refresh() {
+	if e.drawnRow > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dA", e.drawnRow)
+	}
+	fmt.Fprint(e.out, "\r\x1b[J")
+
+	text := e.text()
+	if e.highlight != nil {
+		text = e.highlight(text)
+	}
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			fmt.Fprint(e.out, "\r\n")
+		}
+		fmt.Fprint(e.out, e.promptFor(i)+line)
+	}
+
+	if up := len(e.lines) - 1 - e.row; up > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dA", up)
+	}
+	fmt.Fprintf(e.out, "\r\x1b[%dC", len([]rune(e.promptFor(e.row)))+e.col)
+	e.drawnRow = e.row
+}
+
+func (e *editor) promptFor(row int) string {
+	if row == 0 {
+		return e.prompt
+	}
+	return e.contPrompt
+}
+
+func commonPrefix(words []string) string {
+	prefix := []rune(words[0])
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, string(prefix)) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return string(prefix)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,81 @@
+package repl
+
+import (
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+// colours used to highlight code as it is typed
+const (
+	keywordColour = 35
+	stringColour  = 33
+	numberColour  = 36
+	commentColour = 90
+)
+
+// highlight colours keywords, strings, numbers and comments in input. It is
+// driven by the lexer, so the colours always agree with how the code will be
+// read. Anything the lexer skips that isn't whitespace is a comment.
+func highlight(input string) string {
+	lineStarts := []int{0}
+	for i, ch := range []byte(input) {
+		if ch == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+
+	var out strings.Builder
+	end := 0
+	l := lexer.New(input)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		start := lineStarts[tok.Line-1] + tok.Column - 1
+		out.WriteString(colourGap(input[end:start]))
+
+		end = l.Offset()
+		out.WriteString(colourSpan(input[start:end], tokenColour(tok)))
+	}
+	out.WriteString(colourGap(input[end:]))
+
+	return out.String()
+}
+
+func tokenColour(tok token.Token) int {
+	switch tok.Type {
+	case token.STRING, token.TEMPLATE:
+		return stringColour
+	case token.INT, token.FLOAT:
+		return numberColour
+	case token.IDENT:
+		return 0
+	}
+	if token.LookupIdent(tok.Literal) == tok.Type {
+		return keywordColour
+	}
+	return 0
+}
+
+// colourGap colours the text between two tokens, which is whitespace and
+// comments
+func colourGap(gap string) string {
+	if strings.TrimSpace(gap) == "" {
+		return gap
+	}
+	return colourSpan(gap, commentColour)
+}
+
+// colourSpan colours each line of s on its own, so the input can still be
+// split into lines afterwards
+func colourSpan(s string, colour int) string {
+	if colour == 0 {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = colorfy(line, colour)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
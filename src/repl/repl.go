@@ -7,13 +7,17 @@ import (
 	"os"
 	"strings"
 
+	"github.com/AvicennaJr/Nuru/compiler"
 	"github.com/AvicennaJr/Nuru/evaluator"
 	"github.com/AvicennaJr/Nuru/lexer"
 	"github.com/AvicennaJr/Nuru/object"
 	"github.com/AvicennaJr/Nuru/parser"
+	"github.com/AvicennaJr/Nuru/token"
+	"github.com/AvicennaJr/Nuru/vm"
 )
 
 const PROMPT = ">>> "
+const CONT_PROMPT = "... "
 const ERROR_FACE = `
 	███████████████████████████
 	███████▀▀▀░░░░░░░▀▀▀███████
@@ -40,10 +44,12 @@ const ERROR_FACE = `
 
 `
 
-func Read(contents string) {
+// Read runs the contents of a file. The file name is only used to say where
+// errors happened.
+func Read(file, contents string) {
 	env := object.NewEnvironment()
 
-	l := lexer.New(contents)
+	l := lexer.NewFile(file, contents)
 	p := parser.New(l)
 
 	program := p.ParseProgram()
@@ -66,19 +72,49 @@ func Read(contents string) {
 
 }
 
+// ReadVM runs a program on the bytecode VM instead of the evaluator
+func ReadVM(file, contents string) {
+	l := lexer.NewFile(file, contents)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		fmt.Println(colorfy(ERROR_FACE, 31))
+		fmt.Println("Kuna Errors Zifuatazo:")
+
+		for _, msg := range p.Errors() {
+			fmt.Println("\t" + colorfy(msg, 31))
+		}
+		return
+	}
+
+	comp := compiler.New()
+	if err := comp.Compile(program); err != nil {
+		fmt.Println(colorfy("Kosa: "+err.Error(), 31))
+		return
+	}
+
+	machine := vm.New(comp.Bytecode())
+	evaluated := machine.Run()
+	if evaluated != nil {
+		if evaluated.Type() != object.NULL_OBJ {
+			fmt.Println(colorfy(evaluated.Inspect(), 32))
+		}
+	}
+}
+
 func Start(in io.Reader, out io.Writer) {
 
-	scanner := bufio.NewScanner(in)
 	env := object.NewEnvironment()
+	readLine := lineReader(in, out, env)
 
 	for {
-		fmt.Print(PROMPT)
-		scanned := scanner.Scan()
-		if !scanned {
+		line, ok := readLine()
+		if !ok {
 			return
 		}
 
-		line := scanner.Text()
 		if strings.TrimSpace(line) == "exit()" || strings.TrimSpace(line) == "toka()" {
 			fmt.Println("✨🅺🅰🆁🅸🅱🆄 🆃🅴🅽🅰✨")
 			os.Exit(0)
@@ -95,13 +131,76 @@ func Start(in io.Reader, out io.Writer) {
 		evaluated := evaluator.Eval(program, env)
 		if evaluated != nil {
 			if evaluated.Type() != object.NULL_OBJ {
-				io.WriteString(out, colorfy(evaluated.Inspect(), 32))
+				io.WriteString(out, colorfy(object.Pretty(evaluated), 32))
 				io.WriteString(out, "\n")
 			}
 		}
 	}
 }
 
+// lineReader returns a function that reads the next line typed by the user.
+// On a terminal lines are read with the line editor, which highlights the
+// code and completes names from env when Tab is pressed. Otherwise they are read as they come.
+func lineReader(in io.Reader, out io.Writer, env *object.Environment) func() (string, bool) {
+	if f, ok := in.(*os.File); ok {
+		if restore, err := makeRaw(int(f.Fd())); err == nil {
+			restore()
+
+			ed := newEditor(in, out, envCompleter(env))
+			ed.highlight = highlight
+			return func() (string, bool) {
+				for {
+					restore, err := makeRaw(int(f.Fd()))
+					if err != nil {
+						return "", false
+					}
+					line, err := ed.readLine(PROMPT, CONT_PROMPT)
+					restore()
+
+					if err == errInterrupted {
+						continue
+					}
+					return line, err == nil
+				}
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+	return func() (string, bool) {
+		fmt.Fprint(out, PROMPT)
+		if !scanner.Scan() {
+			return "", false
+		}
+		input := scanner.Text()
+
+		for needsMoreInput(input) {
+			fmt.Fprint(out, CONT_PROMPT)
+			if !scanner.Scan() {
+				break
+			}
+			input += "\n" + scanner.Text()
+		}
+		return input, true
+	}
+}
+
+// needsMoreInput reports whether input has brackets or a /* comment that are
+// still open, like a function whose body continues on the next line
+func needsMoreInput(input string) bool {
+	depth := 0
+	l := lexer.New(input)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		switch tok.Type {
+		case token.LBRACE, token.LPAREN, token.LBRACKET:
+			depth++
+		case token.RBRACE, token.RPAREN, token.RBRACKET:
+			depth--
+		}
+	}
+	return depth > 0 || len(l.Errors()) > 0
+}
+
 func printParseErrors(out io.Writer, errors []string) {
 	//io.WriteString(out, colorfy(ERROR_FACE, 31))
 	io.WriteString(out, "Kuna Errors Zifuatazo:\n")
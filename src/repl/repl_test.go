@@ -0,0 +1,136 @@
+package repl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+func testEnv(t *testing.T, input string) *object.Environment {
+	t.Helper()
+
+	env := object.NewEnvironment()
+	program := parser.New(lexer.New(input)).ParseProgram()
+	if result := evaluator.Eval(program, env); result != nil && result.Type() == object.ERROR_OBJ {
+		t.Fatalf("evaluation failed: %s", result.Inspect())
+	}
+	return env
+}
+
+func TestCompletion(t *testing.T) {
+	env := testEnv(t, `fanya jina = "Asha"; fanya jinsia = "ke"; fanya watu = {"asha": 1, "amani": 2, 3: 4}`)
+	complete := envCompleter(env)
+
+	tests := []struct {
+		line       string
+		start      int
+		candidates []string
+	}{
+		{"fan", 0, []string{"fanya"}},
+		{"andika(jin", 7, []string{"jina", "jinsia"}},
+		{"idadi(wat", 6, []string{"watu"}},
+		{"wat", 0, []string{"watu"}},
+		{"andi", 0, []string{"andika", "andika_faili"}},
+		{"watu[", 5, []string{`"amani"]`, `"asha"]`, "3]"}},
+		{`watu["as`, 5, []string{`"asha"]`}},
+		{"jina[", 5, nil},
+		{"x + ", 4, nil},
+	}
+
+	for _, tt := range tests {
+		line := []rune(tt.line)
+		start, candidates := complete(line, len(line))
+		if start != tt.start {
+			t.Errorf("%q: wrong start. want=%d, got=%d", tt.line, tt.start, start)
+		}
+		if strings.Join(candidates, " ") != strings.Join(tt.candidates, " ") {
+			t.Errorf("%q: wrong candidates. want=%q, got=%q", tt.line, tt.candidates, candidates)
+		}
+	}
+}
+
+func TestNeedsMoreInput(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"fanya x = 1", false},
+		{"fanya f = unda(x) {", true},
+		{"fanya f = unda(x) {\n  x + 1\n}", false},
+		{"andika(1,", true},
+		{"[1, [2,", true},
+		{`"{"`, false},
+		{"}", false},
+		{"/* maoni", true},
+		{"/* maoni */ x", false},
+	}
+
+	for _, tt := range tests {
+		if got := needsMoreInput(tt.input); got != tt.expected {
+			t.Errorf("needsMoreInput(%q): want=%t, got=%t", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestEditorReadLine(t *testing.T) {
+	env := testEnv(t, `fanya jina = "Asha"; fanya jinsia = "ke"; fanya watu = {"asha": 1}`)
+
+	tests := []struct {
+		keys     string
+		expected []string
+	}{
+		{"fanya x = 1\r", []string{"fanya x = 1"}},
+		{"fan\t x\r", []string{"fanya x"}},
+		{"watu[\t\r", []string{`watu["asha"]`}},
+		{"jin\ta\r", []string{"jina"}},
+		{"12\x1b[D0\r", []string{"102"}},
+		{"abc\x01x\x05y\r", []string{"xabcy"}},
+		{"abc\x7f\x7f\r", []string{"a"}},
+		{"ab\x1b[D\x1b[3~\r", []string{"a"}},
+		{"kwanza\rpili\r\x1b[A\x1b[A\r", []string{"kwanza", "pili", "kwanza"}},
+		{"kwanza\r\x1b[Ax\x1b[B\x1b[Ay\r", []string{"kwanza", "kwanzay"}},
+		{"fanya f = unda(x) {\rx + 1\r}\r", []string{"fanya f = unda(x) {\nx + 1\n}"}},
+		{"andika(1,\r2)\r", []string{"andika(1,\n2)"}},
+		{"kama (x) {\ra\r}\x1b[A\x1b[A\x05 b\x1b[B\x1b[B\r", []string{"kama (x) { b\na\n}"}},
+		{"f(\r\x7f)\r", []string{"f()"}},
+		{"[1,\r2]\r\x1b[A\r", []string{"[1,\n2]", "[1,\n2]"}},
+	}
+
+	for _, tt := range tests {
+		ed := newEditor(strings.NewReader(tt.keys), &bytes.Buffer{}, envCompleter(env))
+		for _, want := range tt.expected {
+			got, err := ed.readLine(PROMPT, CONT_PROMPT)
+			if err != nil {
+				t.Fatalf("%q: readLine failed: %s", tt.keys, err)
+			}
+			if got != want {
+				t.Errorf("%q: wrong line. want=%q, got=%q", tt.keys, want, got)
+			}
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fanya x = 5", colorfy("fanya", keywordColour) + " x = " + colorfy("5", numberColour)},
+		{`andika("habari", 2.5)`, "andika(" + colorfy(`"habari"`, stringColour) + ", " + colorfy("2.5", numberColour) + ")"},
+		{"x // maoni", "x" + colorfy(" // maoni", commentColour)},
+		{"/* moja\nmbili */ kweli", colorfy("/* moja", commentColour) + "\n" + colorfy("mbili */ ", commentColour) + colorfy("kweli", keywordColour)},
+		{"\"a\nb\"", colorfy("\"a", stringColour) + "\n" + colorfy("b\"", stringColour)},
+		{`"jina ni ${jina}"`, colorfy(`"jina ni ${jina}"`, stringColour)},
+	}
+
+	for _, tt := range tests {
+		if got := highlight(tt.input); got != tt.expected {
+			t.Errorf("highlight(%q): want=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd
+
+package repl
+
+import "errors"
+
+// makeRaw is not supported here, so the REPL falls back to reading whole lines
+func makeRaw(fd int) (func(), error) {
+	return nil, errors.New("terminal haiwezi kuwekwa kwenye raw mode")
+}
@@ -0,0 +1,63 @@
+// Package tester runs the tests in Nuru test files, the way 'nuru jaribu'
+// does. A test is a function defined at the top of the file whose name starts
+// with "jaribu_". It fails if it returns an error, usually from thibitisha.
+package tester
+
+import (
+	"strings"
+	"time"
+
+	"github.com/AvicennaJr/Nuru/ast"
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+// Prefix starts the name of every test function
+const Prefix = "jaribu_"
+
+// Result is how one test went
+type Result struct {
+	Name     string
+	Err      *object.Error // nil if the test passed
+	Duration time.Duration
+}
+
+// Names returns the names of the tests in program, in the order they are defined
+func Names(program *ast.Program) []string {
+	var names []string
+	for _, stmt := range program.Statements {
+		let, ok := stmt.(*ast.LetStatement)
+		if !ok || !strings.HasPrefix(let.Name.Value, Prefix) {
+			continue
+		}
+		if _, ok := let.Value.(*ast.FunctionLiteral); ok {
+			names = append(names, let.Name.Value)
+		}
+	}
+	return names
+}
+
+// Run runs every test in program. Each test gets a fresh environment in which
+// the whole file is run first, so one test can't change what another sees.
+func Run(program *ast.Program) []Result {
+	var results []Result
+	for _, name := range Names(program) {
+		start := time.Now()
+		err := runTest(program, name)
+		results = append(results, Result{Name: name, Err: err, Duration: time.Since(start)})
+	}
+	return results
+}
+
+func runTest(program *ast.Program, name string) *object.Error {
+	env := object.NewEnvironment()
+	if err, ok := evaluator.Eval(program, env).(*object.Error); ok {
+		return err
+	}
+
+	fn, _ := env.Get(name)
+	if err, ok := evaluator.ApplyFunction(fn, nil).(*object.Error); ok {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package tester
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+func TestRun(t *testing.T) {
+	input := `
+fanya hesabu = 0
+fanya ongeza = unda() { hesabu = hesabu + 1; rudisha hesabu }
+
+fanya jaribu_kwanza = unda() { thibitisha_sawa(ongeza(), 1) }
+fanya jaribu_pili = unda() { thibitisha_sawa(ongeza(), 1, "mazingira mapya") }
+fanya jaribu_feli = unda() { thibitisha(sikweli, "imeshindwa") }
+fanya jaribu_hakuna = unda() { tupa "kosa" }
+fanya msaidizi = unda() { thibitisha(sikweli) }
+fanya jaribu_si_unda = 5
+`
+	program := parser.New(lexer.New(input)).ParseProgram()
+
+	names := Names(program)
+	if strings.Join(names, " ") != "jaribu_kwanza jaribu_pili jaribu_feli jaribu_hakuna" {
+		t.Fatalf("wrong test names, got=%q", names)
+	}
+
+	expected := map[string]string{
+		"jaribu_kwanza": "",
+		"jaribu_pili":   "",
+		"jaribu_feli":   "Uthibitisho umeshindwa: imeshindwa",
+		"jaribu_hakuna": "kosa",
+	}
+	results := Run(program)
+	if len(results) != len(expected) {
+		t.Fatalf("wrong number of results, got=%d", len(results))
+	}
+	for _, result := range results {
+		want := expected[result.Name]
+		if result.Err == nil {
+			if want != "" {
+				t.Errorf("%s: expected failure %q, but it passed", result.Name, want)
+			}
+			continue
+		}
+		if want == "" || !strings.Contains(result.Err.Message, want) {
+			t.Errorf("%s: wrong failure. want=%q, got=%q", result.Name, want, result.Err.Message)
+		}
+	}
+}
@@ -1,11 +1,32 @@
 package token
 
+import (
+	"fmt"
+	"sort"
+)
+
 type TokenType string
 
+// Position is where a token starts in the source. Lines and columns both
+// count from 1, and File is empty for code that did not come from a file.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	pos := fmt.Sprintf("Mstari %d, Safu %d", p.Line, p.Column)
+	if p.File != "" {
+		pos = p.File + ", " + pos
+	}
+	return pos
+}
+
 type Token struct {
 	Type    TokenType
 	Literal string
-	Line    int
+	Position
 }
 
 const (
@@ -13,10 +34,11 @@ const (
 	EOF     = "MWISHO"
 
 	// Identifiers + literals
-	IDENT  = "KITAMBULISHI"
-	INT    = "NAMBA"
-	STRING = "NENO"
-	FLOAT  = "DESIMALI"
+	IDENT    = "KITAMBULISHI"
+	INT      = "NAMBA"
+	STRING   = "NENO"
+	FLOAT    = "DESIMALI"
+	TEMPLATE = "KIOLEZO" // a string with ${...} interpolations
 
 	// Operators
 	ASSIGN          = "="
@@ -35,6 +57,12 @@ const (
 	NOT_EQ          = "!="
 	AND             = "&&"
 	OR              = "||"
+	BIT_AND         = "&"
+	BIT_OR          = "|"
+	BIT_XOR         = "^"
+	BIT_NOT         = "~"
+	SHIFT_LEFT      = "<<"
+	SHIFT_RIGHT     = ">>"
 	PLUS_ASSIGN     = "+="
 	PLUS_PLUS       = "++"
 	MINUS_ASSIGN    = "-="
@@ -43,6 +71,10 @@ const (
 	SLASH_ASSIGN    = "/="
 	MODULUS_ASSIGN  = "%="
 
+	// floor division, which always gives a whole number
+	BACKSLASH        = "\\"
+	BACKSLASH_ASSIGN = "\\="
+
 	//Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
@@ -53,10 +85,17 @@ const (
 	LBRACKET  = "["
 	RBRACKET  = "]"
 	COLON     = ":"
+	QUESTION  = "?"
+	COALESCE  = "??"
+	OPTIONAL  = "?[" // indexing that gives tupu when there is nothing to index
+	DOT       = "."
+	ELLIPSIS  = "..."
+	ARROW     = "=>"
 
 	// Keywords
 	FUNCTION = "FUNCTION"
 	LET      = "FANYA"
+	CONST    = "THABITI"
 	TRUE     = "KWELI"
 	FALSE    = "SIKWELI"
 	IF       = "KAMA"
@@ -71,26 +110,55 @@ const (
 	SWITCH   = "BADILI"
 	CASE     = "IKIWA"
 	DEFAULT  = "KAWAIDA"
+	TRY      = "JARIBU"
+	CATCH    = "SHIKA"
+	THROW    = "TUPA"
+	IMPORT   = "TUMIA"
+	CLASS    = "MUUNDO"
+	THIS     = "HII"
+	SPAWN    = "SAMBAMBA"
+	YIELD    = "TOA"
+	MATCH    = "LINGANISHA"
 )
 
 var keywords = map[string]TokenType{
-	"unda":    FUNCTION,
-	"fanya":   LET,
-	"kweli":   TRUE,
-	"sikweli": FALSE,
-	"kama":    IF,
-	"au":      ELSE,
-	"sivyo":   ELSE,
-	"wakati":  WHILE,
-	"rudisha": RETURN,
-	"vunja":   BREAK,
-	"endelea": CONTINUE,
-	"tupu":    NULL,
-	"ktk":     IN,
-	"kwa":     FOR,
-	"badili":  SWITCH,
-	"ikiwa":   CASE,
-	"kawaida": DEFAULT,
+	"unda":       FUNCTION,
+	"fanya":      LET,
+	"thabiti":    CONST,
+	"kweli":      TRUE,
+	"sikweli":    FALSE,
+	"kama":       IF,
+	"au":         ELSE,
+	"sivyo":      ELSE,
+	"wakati":     WHILE,
+	"rudisha":    RETURN,
+	"vunja":      BREAK,
+	"endelea":    CONTINUE,
+	"tupu":       NULL,
+	"ktk":        IN,
+	"kwa":        FOR,
+	"badili":     SWITCH,
+	"ikiwa":      CASE,
+	"kawaida":    DEFAULT,
+	"jaribu":     TRY,
+	"shika":      CATCH,
+	"tupa":       THROW,
+	"tumia":      IMPORT,
+	"muundo":     CLASS,
+	"hii":        THIS,
+	"sambamba":   SPAWN,
+	"toa":        YIELD,
+	"linganisha": MATCH,
+}
+
+// Keywords returns every keyword of the language, sorted
+func Keywords() []string {
+	names := make([]string, 0, len(keywords))
+	for name := range keywords {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func LookupIdent(ident string) TokenType {
@@ -0,0 +1,29 @@
+package vm
+
+import (
+	"github.com/AvicennaJr/Nuru/code"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/token"
+)
+
+type Frame struct {
+	cl          *object.Closure
+	ip          int
+	basePointer int
+}
+
+func NewFrame(cl *object.Closure, basePointer int) *Frame {
+	return &Frame{cl: cl, ip: -1, basePointer: basePointer}
+}
+
+func (f *Frame) Instructions() code.Instructions {
+	return f.cl.Fn.Instructions
+}
+
+// Position returns the source position of the instruction being executed
+func (f *Frame) Position() token.Position {
+	if f.ip < 0 || f.ip >= len(f.cl.Fn.Positions) {
+		return token.Position{}
+	}
+	return f.cl.Fn.Positions[f.ip]
+}
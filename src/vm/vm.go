@@ -0,0 +1,674 @@
+package vm
+
+import (
+	"strings"
+
+	"github.com/AvicennaJr/Nuru/code"
+	"github.com/AvicennaJr/Nuru/compiler"
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/object"
+)
+
+const (
+	StackSize   = 2048
+	GlobalsSize = 65536
+	MaxFrames   = 1024
+)
+
+var infixOperators = map[code.Opcode]string{
+	code.OpAdd:          "+",
+	code.OpSub:          "-",
+	code.OpMul:          "*",
+	code.OpDiv:          "/",
+	code.OpMod:          "%",
+	code.OpPow:          "**",
+	code.OpEqual:        "==",
+	code.OpNotEqual:     "!=",
+	code.OpLessThan:     "<",
+	code.OpLessEqual:    "<=",
+	code.OpGreaterThan:  ">",
+	code.OpGreaterEqual: ">=",
+	code.OpAnd:          "&&",
+	code.OpOr:           "||",
+	code.OpIn:           "ktk",
+	code.OpBitAnd:       "&",
+	code.OpBitOr:        "|",
+	code.OpBitXor:       "^",
+	code.OpShiftLeft:    "<<",
+	code.OpShiftRight:   ">>",
+	code.OpFloorDiv:     "\\",
+}
+
+var prefixOperators = map[code.Opcode]string{
+	code.OpMinus:  "-",
+	code.OpPlus:   "+",
+	code.OpBang:   "!",
+	code.OpBitNot: "~",
+}
+
+// iterator holds the object being looped over by 'kwa' on the stack
+type iterator struct {
+	iterable object.Iterable
+}
+
+func (i *iterator) Type() object.ObjectType { return "ITERATOR" }
+func (i *iterator) Inspect() string         { return "iterator" }
+
+type VM struct {
+	constants []object.Object
+
+	stack []object.Object
+	sp    int // always points to the next free slot. Top of stack is stack[sp-1]
+
+	globals     []object.Object
+	globalNames []string
+	builtins    []*object.Builtin
+
+	frames      []*Frame
+	framesIndex int
+
+	lastPopped object.Object
+}
+
+func New(bytecode *compiler.Bytecode) *VM {
+	mainFn := &object.CompiledFunction{Instructions: bytecode.Instructions, Positions: bytecode.Positions}
+	mainClosure := &object.Closure{Fn: mainFn}
+	mainFrame := NewFrame(mainClosure, 0)
+
+	frames := make([]*Frame, MaxFrames)
+	frames[0] = mainFrame
+
+	// the compiler numbers builtins in the same order
+	builtins := []*object.Builtin{}
+	for _, name := range evaluator.BuiltinNames() {
+		builtin, _ := evaluator.LookupBuiltin(name)
+		builtins = append(builtins, builtin)
+	}
+
+	return &VM{
+		constants:   bytecode.Constants,
+		stack:       make([]object.Object, StackSize),
+		sp:          0,
+		globals:     make([]object.Object, GlobalsSize),
+		globalNames: bytecode.GlobalNames,
+		builtins:    builtins,
+		frames:      frames,
+		framesIndex: 1,
+	}
+}
+
+func (vm *VM) currentFrame() *Frame {
+	return vm.frames[vm.framesIndex-1]
+}
+
+func (vm *VM) pushFrame(f *Frame) *object.Error {
+	if vm.framesIndex >= MaxFrames {
+		return vm.error("Umezidi kina cha kujiita (recursion) cha %d", MaxFrames)
+	}
+	vm.frames[vm.framesIndex] = f
+	vm.framesIndex++
+	return nil
+}
+
+func (vm *VM) popFrame() *Frame {
+	vm.framesIndex--
+	return vm.frames[vm.framesIndex]
+}
+
+// LastPoppedStackElem is the value of the last expression statement executed
+func (vm *VM) LastPoppedStackElem() object.Object {
+	return vm.lastPopped
+}
+
+// Run executes the program. Like evaluator.Eval it returns an *object.Error
+// when the program fails, otherwise the value the program ended with.
+func (vm *VM) Run() object.Object {
+	var ip int
+	var ins code.Instructions
+	var op code.Opcode
+
+	for vm.currentFrame().ip < len(vm.currentFrame().Instructions())-1 {
+		vm.currentFrame().ip++
+
+		ip = vm.currentFrame().ip
+		ins = vm.currentFrame().Instructions()
+		op = code.Opcode(ins[ip])
+
+		var err *object.Error
+
+		switch op {
+		case code.OpConstant:
+			constIndex := code.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			err = vm.push(vm.constants[constIndex])
+
+		case code.OpPop:
+			vm.lastPopped = vm.pop()
+
+		case code.OpAdd, code.OpSub, code.OpMul, code.OpDiv, code.OpMod, code.OpPow,
+			code.OpEqual, code.OpNotEqual, code.OpLessThan, code.OpLessEqual,
+			code.OpGreaterThan, code.OpGreaterEqual, code.OpAnd, code.OpOr, code.OpIn,
+			code.OpBitAnd, code.OpBitOr, code.OpBitXor, code.OpShiftLeft, code.OpShiftRight,
+			code.OpFloorDiv:
+			err = vm.executeBinaryOperation(op)
+
+		case code.OpMinus, code.OpPlus, code.OpBang, code.OpBitNot:
+			right := vm.pop()
+			err = vm.pushResult(evaluator.EvalPrefix(prefixOperators[op], right))
+
+		case code.OpTrue:
+			err = vm.push(evaluator.TRUE)
+
+		case code.OpFalse:
+			err = vm.push(evaluator.FALSE)
+
+		case code.OpNull:
+			err = vm.push(evaluator.NULL)
+
+		case code.OpJump:
+			pos := int(code.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip = pos - 1
+
+		case code.OpJumpNotTruthy:
+			pos := int(code.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip += 2
+
+			condition := vm.pop()
+			if !evaluator.IsTruthy(condition) {
+				vm.currentFrame().ip = pos - 1
+			}
+
+		case code.OpJumpNotNull:
+			pos := int(code.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip += 2
+
+			if vm.stack[vm.sp-1] != evaluator.NULL {
+				vm.currentFrame().ip = pos - 1
+			} else {
+				vm.pop()
+			}
+
+		case code.OpChainCompare:
+			operator := code.Opcode(code.ReadUint8(ins[ip+1:]))
+			vm.currentFrame().ip += 1
+
+			right := vm.stack[vm.sp-1]
+			left := vm.stack[vm.sp-2]
+			vm.stack[vm.sp-2] = right
+			vm.sp--
+			if err = vm.push(left); err == nil {
+				if err = vm.push(right); err == nil {
+					err = vm.executeBinaryOperation(operator)
+				}
+			}
+
+		case code.OpJumpNull:
+			pos := int(code.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip += 2
+
+			if vm.stack[vm.sp-1] == evaluator.NULL {
+				vm.currentFrame().ip = pos - 1
+			}
+
+		case code.OpSetGlobal:
+			globalIndex := code.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			vm.globals[globalIndex] = vm.pop()
+
+		case code.OpGetGlobal:
+			globalIndex := code.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+
+			val := vm.globals[globalIndex]
+			if val == nil {
+				// a global the program never set may be one of the
+				// evaluator's, like HOJA
+				if global, ok := evaluator.LookupGlobal(vm.globalName(int(globalIndex))); ok {
+					err = vm.push(global)
+					break
+				}
+				err = vm.error("Neno Halifahamiki: %s", vm.globalName(int(globalIndex)))
+				break
+			}
+			err = vm.push(val)
+
+		case code.OpSetLocal:
+			localIndex := code.ReadUint8(ins[ip+1:])
+			vm.currentFrame().ip += 1
+
+			frame := vm.currentFrame()
+			vm.stack[frame.basePointer+int(localIndex)] = vm.pop()
+
+		case code.OpGetLocal:
+			localIndex := code.ReadUint8(ins[ip+1:])
+			vm.currentFrame().ip += 1
+
+			frame := vm.currentFrame()
+			val := vm.stack[frame.basePointer+int(localIndex)]
+			if val == nil {
+				err = vm.error("Neno halina thamani bado")
+				break
+			}
+			err = vm.push(val)
+
+		case code.OpGetBuiltin:
+			builtinIndex := code.ReadUint8(ins[ip+1:])
+			vm.currentFrame().ip += 1
+
+			err = vm.push(vm.builtins[builtinIndex])
+
+		case code.OpGetFree:
+			freeIndex := code.ReadUint8(ins[ip+1:])
+			vm.currentFrame().ip += 1
+
+			err = vm.push(vm.currentFrame().cl.Free[freeIndex])
+
+		case code.OpCurrentClosure:
+			err = vm.push(vm.currentFrame().cl)
+
+		case code.OpArray:
+			numElements := int(code.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip += 2
+
+			elements := make([]object.Object, numElements)
+			copy(elements, vm.stack[vm.sp-numElements:vm.sp])
+			vm.sp = vm.sp - numElements
+
+			err = vm.push(&object.Array{Elements: elements})
+
+		case code.OpTemplate:
+			numParts := int(code.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip += 2
+
+			var out strings.Builder
+			for _, part := range vm.stack[vm.sp-numParts : vm.sp] {
+				if str, ok := part.(*object.String); ok {
+					out.WriteString(str.Value)
+				} else {
+					out.WriteString(part.Inspect())
+				}
+			}
+			vm.sp = vm.sp - numParts
+
+			err = vm.push(&object.String{Value: out.String()})
+
+		case code.OpDict:
+			numElements := int(code.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip += 2
+
+			var dict object.Object
+			dict, err = vm.buildDict(vm.sp-numElements, vm.sp)
+			if err != nil {
+				break
+			}
+			vm.sp = vm.sp - numElements
+
+			err = vm.push(dict)
+
+		case code.OpUnpack:
+			constIndex := code.ReadUint16(ins[ip+1:])
+			dict := code.ReadUint8(ins[ip+3:]) == 1
+			vm.currentFrame().ip += 3
+			err = vm.executeUnpack(vm.constants[constIndex].(*object.Array), dict)
+
+		case code.OpSpread:
+			var elements []object.Object
+			elements, err = evaluator.Spread(vm.pop())
+			if err != nil {
+				break
+			}
+			// a copy, so that changing the new array leaves the old one alone
+			err = vm.push(&object.Array{Elements: append([]object.Object{}, elements...)})
+
+		case code.OpIndex:
+			index := vm.pop()
+			left := vm.pop()
+			err = vm.pushResult(evaluator.EvalIndex(left, index))
+
+		case code.OpSlice:
+			step := vm.pop()
+			stop := vm.pop()
+			start := vm.pop()
+			left := vm.pop()
+			err = vm.pushResult(evaluator.EvalSlice(left, start, stop, step))
+
+		case code.OpSetIndex:
+			value := vm.pop()
+			index := vm.pop()
+			left := vm.pop()
+			err = vm.executeSetIndex(left, index, value)
+
+		case code.OpCall:
+			numArgs := code.ReadUint8(ins[ip+1:])
+			vm.currentFrame().ip += 1
+
+			err = vm.executeCall(int(numArgs))
+
+		case code.OpCallSpread:
+			args := vm.pop().(*object.Array)
+			for _, arg := range args.Elements {
+				if err = vm.push(arg); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				err = vm.executeCall(len(args.Elements))
+			}
+
+		case code.OpReturnValue:
+			returnValue := vm.pop()
+
+			if vm.framesIndex == 1 {
+				// 'rudisha' outside a function ends the program
+				return returnValue
+			}
+
+			frame := vm.popFrame()
+			vm.sp = frame.basePointer - 1
+
+			err = vm.push(returnValue)
+
+		case code.OpReturn:
+			if vm.framesIndex == 1 {
+				return evaluator.NULL
+			}
+
+			frame := vm.popFrame()
+			vm.sp = frame.basePointer - 1
+
+			err = vm.push(evaluator.NULL)
+
+		case code.OpClosure:
+			constIndex := code.ReadUint16(ins[ip+1:])
+			numFree := code.ReadUint8(ins[ip+3:])
+			vm.currentFrame().ip += 3
+
+			err = vm.pushClosure(int(constIndex), int(numFree))
+
+		case code.OpIterInit:
+			obj := vm.pop()
+			if _, ok := evaluator.IteratorMethod(obj, "ifuatayo"); ok {
+				err = vm.error("ifuatayo haitumiki na VM bado, tumia nuru bila --vm")
+				break
+			}
+			iterable, ok := evaluator.OwnIterator(obj).(object.Iterable)
+			if !ok {
+				err = vm.error("Huwezi kufanya operesheni hii na %s", obj.Type())
+				break
+			}
+			iterable.Reset()
+			err = vm.push(&iterator{iterable: iterable})
+
+		case code.OpIterNext:
+			end := int(code.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip += 2
+
+			it := vm.stack[vm.sp-1].(*iterator)
+			k, v := it.iterable.Next()
+			if k == nil || v == nil {
+				vm.currentFrame().ip = end - 1
+				break
+			}
+			if err = vm.push(k); err != nil {
+				break
+			}
+			err = vm.push(v)
+
+		case code.OpCollect:
+			n := int(code.ReadUint8(ins[ip+1:]))
+			vm.currentFrame().ip += 1
+
+			switch collected := vm.stack[vm.sp-n-2].(type) {
+			case *object.Array:
+				collected.Elements = append(collected.Elements, vm.stack[vm.sp-1])
+			case *object.Dict:
+				key, value := vm.stack[vm.sp-2], vm.stack[vm.sp-1]
+				hashKey, ok := key.(object.Hashable)
+				if !ok {
+					err = vm.error("Hashing imeshindikana: %s", key.Type())
+					break
+				}
+				collected.Pairs[hashKey.HashKey()] = object.DictPair{Key: key, Value: value}
+			}
+			vm.sp -= n
+
+		case code.OpIterEnd:
+			it := vm.pop().(*iterator)
+			it.iterable.Reset()
+		}
+
+		if err != nil {
+			if err.Position.Line == 0 {
+				err.Position = vm.currentFrame().Position()
+			}
+			return err
+		}
+	}
+
+	if vm.lastPopped == nil {
+		return evaluator.NULL
+	}
+	return vm.lastPopped
+}
+
+func (vm *VM) push(o object.Object) *object.Error {
+	if vm.sp >= StackSize {
+		return vm.error("Stack imejaa (stack overflow)")
+	}
+
+	vm.stack[vm.sp] = o
+	vm.sp++
+
+	return nil
+}
+
+// pushResult pushes the result of an operation done by the evaluator,
+// turning error objects into VM errors
+func (vm *VM) pushResult(o object.Object) *object.Error {
+	if err, ok := o.(*object.Error); ok {
+		return err
+	}
+	if o == nil {
+		o = evaluator.NULL
+	}
+	return vm.push(o)
+}
+
+func (vm *VM) pop() object.Object {
+	o := vm.stack[vm.sp-1]
+	vm.sp--
+	return o
+}
+
+func (vm *VM) error(format string, a ...interface{}) *object.Error {
+	return evaluator.NewError(format, a...)
+}
+
+func (vm *VM) globalName(index int) string {
+	if index < len(vm.globalNames) {
+		return vm.globalNames[index]
+	}
+	return "?"
+}
+
+func (vm *VM) executeBinaryOperation(op code.Opcode) *object.Error {
+	right := vm.pop()
+	left := vm.pop()
+
+	// integers are by far the most common operands, handle them here unless
+	// the result needs a BigInt
+	if l, ok := left.(*object.Integer); ok {
+		if r, ok := right.(*object.Integer); ok && !evaluator.IntegerOverflows(infixOperators[op], l.Value, r.Value) {
+			switch op {
+			case code.OpAdd:
+				return vm.push(&object.Integer{Value: l.Value + r.Value})
+			case code.OpSub:
+				return vm.push(&object.Integer{Value: l.Value - r.Value})
+			case code.OpMul:
+				return vm.push(&object.Integer{Value: l.Value * r.Value})
+			case code.OpLessThan:
+				return vm.push(nativeBoolToBooleanObject(l.Value < r.Value))
+			case code.OpLessEqual:
+				return vm.push(nativeBoolToBooleanObject(l.Value <= r.Value))
+			case code.OpGreaterThan:
+				return vm.push(nativeBoolToBooleanObject(l.Value > r.Value))
+			case code.OpGreaterEqual:
+				return vm.push(nativeBoolToBooleanObject(l.Value >= r.Value))
+			case code.OpEqual:
+				return vm.push(nativeBoolToBooleanObject(l.Value == r.Value))
+			case code.OpNotEqual:
+				return vm.push(nativeBoolToBooleanObject(l.Value != r.Value))
+			}
+		}
+	}
+
+	return vm.pushResult(evaluator.EvalInfix(infixOperators[op], left, right))
+}
+
+func (vm *VM) executeSetIndex(left, index, value object.Object) *object.Error {
+
+	switch obj := left.(type) {
+	case *object.Array:
+		idx, ok := index.(*object.Integer)
+		if !ok {
+			return vm.error("Tafadhali tumia number, sio: %s", index.Type())
+		}
+		i, ok := evaluator.AbsoluteIndex(idx.Value, len(obj.Elements))
+		if !ok {
+			return vm.error("Index imezidi idadi ya elements")
+		}
+		obj.Elements[i] = value
+	case *object.Dict:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return vm.error("Samahani, %s haitumiki kama key", index.Type())
+		}
+		obj.Pairs[key.HashKey()] = object.DictPair{Key: index, Value: value}
+	case *object.Tuple:
+		return vm.error("JOZI haiwezi kubadilishwa")
+	default:
+		return vm.error("%s haifanyi operesheni hii", left.Type())
+	}
+
+	return nil
+}
+
+func (vm *VM) buildDict(startIndex, endIndex int) (object.Object, *object.Error) {
+	pairs := make(map[object.HashKey]object.DictPair)
+
+	for i := startIndex; i < endIndex; i += 2 {
+		key := vm.stack[i]
+		value := vm.stack[i+1]
+
+		dictKey, ok := key.(object.Hashable)
+		if !ok {
+			return nil, vm.error("Hashing imeshindikana: %s", key.Type())
+		}
+
+		pairs[dictKey.HashKey()] = object.DictPair{Key: key, Value: value}
+	}
+
+	return &object.Dict{Pairs: pairs}, nil
+}
+
+func (vm *VM) executeCall(numArgs int) *object.Error {
+	callee := vm.stack[vm.sp-1-numArgs]
+
+	switch callee := callee.(type) {
+	case *object.Closure:
+		return vm.callClosure(callee, numArgs)
+	case *object.Builtin:
+		return vm.callBuiltin(callee, numArgs)
+	default:
+		return vm.error("Hii sio function: %s", callee.Type())
+	}
+}
+
+func (vm *VM) executeUnpack(names *object.Array, dict bool) *object.Error {
+	keys := make([]string, len(names.Elements))
+	for i, name := range names.Elements {
+		keys[i] = name.(*object.String).Value
+	}
+
+	values, err := evaluator.Destructure(vm.pop(), keys, dict)
+	if err != nil {
+		return err
+	}
+	for _, val := range values {
+		if err := vm.push(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vm *VM) callClosure(cl *object.Closure, numArgs int) *object.Error {
+	if cl.Fn.Variadic {
+		// the arguments after the fixed ones are replaced by an array of them
+		fixed := cl.Fn.NumParameters - 1
+		if numArgs < fixed {
+			return vm.error("Hoja hazilingani, tunahitaji angalau=%d, tumepewa=%d", fixed, numArgs)
+		}
+		rest := make([]object.Object, numArgs-fixed)
+		copy(rest, vm.stack[vm.sp-len(rest):vm.sp])
+		vm.sp -= len(rest)
+		vm.stack[vm.sp] = &object.Array{Elements: rest}
+		vm.sp++
+		numArgs = cl.Fn.NumParameters
+	}
+
+	if numArgs < cl.Fn.NumParameters {
+		return vm.error("Hoja hazilingani, tunahitaji=%d, tumepewa=%d", cl.Fn.NumParameters, numArgs)
+	}
+
+	// extra arguments are ignored, as they are by the evaluator
+	vm.sp -= numArgs - cl.Fn.NumParameters
+
+	frame := NewFrame(cl, vm.sp-cl.Fn.NumParameters)
+	if err := vm.pushFrame(frame); err != nil {
+		return err
+	}
+
+	newSp := frame.basePointer + cl.Fn.NumLocals
+	if newSp >= StackSize {
+		return vm.error("Stack imejaa (stack overflow)")
+	}
+	for i := frame.basePointer + cl.Fn.NumParameters; i < newSp; i++ {
+		vm.stack[i] = nil
+	}
+	vm.sp = newSp
+
+	return nil
+}
+
+func (vm *VM) callBuiltin(builtin *object.Builtin, numArgs int) *object.Error {
+	args := make([]object.Object, numArgs)
+	copy(args, vm.stack[vm.sp-numArgs:vm.sp])
+
+	result := builtin.Fn(args...)
+	vm.sp = vm.sp - numArgs - 1
+
+	return vm.pushResult(result)
+}
+
+func (vm *VM) pushClosure(constIndex, numFree int) *object.Error {
+	constant := vm.constants[constIndex]
+	function, ok := constant.(*object.CompiledFunction)
+	if !ok {
+		return vm.error("Hii sio function: %+v", constant)
+	}
+
+	free := make([]object.Object, numFree)
+	copy(free, vm.stack[vm.sp-numFree:vm.sp])
+	vm.sp = vm.sp - numFree
+
+	closure := &object.Closure{Fn: function, Free: free}
+	return vm.push(closure)
+}
+
+func nativeBoolToBooleanObject(native bool) *object.Boolean {
+	if native {
+		return evaluator.TRUE
+	}
+	return evaluator.FALSE
+}
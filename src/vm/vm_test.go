@@ -0,0 +1,173 @@
+package vm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AvicennaJr/Nuru/compiler"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+func testRun(t *testing.T, input string) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	comp := compiler.New()
+	if err := comp.Compile(program); err != nil {
+		t.Fatalf("compiler error: %s", err)
+	}
+
+	return New(comp.Bytecode()).Run()
+}
+
+func TestVMPrograms(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + 2 * 3", "7"},
+		{"7 / 2", "3.5"},
+		{"2 ** 10", "1024"},
+		{"2 ** 3 ** 2", "512"},
+		{"[6 & 3, 6 | 3, 6 ^ 3, ~5, 1 << 4, -16 >> 2]", "[2, 7, 5, -6, 16, -4]"},
+		{"1 << 64", "18446744073709551616"},
+		{"[7 \\ 2, -7 \\ 2, 7.5 \\ 2, -7.5 \\ 2, 2 ** 70 \\ 2 ** 69]", "[3, -4, 3, -4, 2]"},
+		{"fanya x = 17; x \\= 5; x", "3"},
+		{`fanya d = {"a": {"b": 2}}; fanya t = tupu; [d?["a"]?["b"], d?["x"]?["b"], t?[1 / 0], [1]?[3]]`, "[2, null, null, null]"},
+		{`fanya d = {"a": 1}; [d["a"] ?? 5, d["b"] ?? 5, tupu ?? tupu ?? 3, sikweli ?? 1, 1 ?? 1 / 0]`, "[1, 5, 3, sikweli, 1]"},
+		{`fanya x = 0; [x < 0 ? "hasi" : x == 0 ? "sifuri" : "chanya", kweli ? 1 : 1 / 0]`, "[sifuri, 1]"},
+		{"2 ** -2", "0.25"},
+		{"fanya f = (a, ...b) => a + idadi(b); fanya g = (x) => { fanya y = x * 2; y + 1 }; fanya ongeza = x => y => x + y; [f(1, 2, 3), g(3), ongeza(1)(2), ((x) => x * 2)(4), (() => 1)()]", "[3, 7, 3, 8, 1]"},
+		{"fanya fact = (n) => n <= 1 ? 1 : n * fact(n - 1); fact(20)", "2432902008176640000"},
+		{"fanya o = [-1, 2, 3]; [[x * 2 kwa x ktk o kama x > 0], [i kwa i, x ktk o kama x < 0], [[y kwa y ktk o[:2]] kwa x ktk o[:2]]]", "[[4, 6], [0], [[-1, 2], [-1, 2]]]"},
+		{"fanya d = {x: x * x kwa x ktk [1, 2, 3] kama x > 1}; [d[1], d[2], d[3]]", "[null, 4, 9]"},
+		{"fanya f = unda(n) { rudisha [x + n kwa x ktk [1, 5] kama x > n] }; f(2)", "[7]"},
+		{"fanya o = [1, 2]; fanya s = 0; kwa x ktk o { kwa y ktk o { s += x * y } }; s", "9"},
+		{`fanya o = [0, 1, 2, 3, 4, 5]; fanya t = tupu; [o[1:4], o[::-2], o[-2:], "habari"[:3], t?[1:]]`, "[[1, 2, 3], [5, 3, 1], [4, 5], hab, null]"},
+		{`fanya a = [1, 2, 3]; a[-1] = 9; a[-3] += 5; [a, a[-2], a[-4], "habari"[-2]]`, "[[6, 2, 9], 2, null, r]"},
+		{"fanya x = 5; [0 < x < 10, 0 < x < 3, 10 > x >= 5 > 4.5, 1 > 2 < 1 / 0]", "[kweli, sikweli, kweli, sikweli]"},
+		{"fanya f = unda(x) { 0 <= x < 10 }; [f(0), f(10)]", "[kweli, sikweli]"},
+		{"10 % 3", "1"},
+		{"-5 + 10", "5"},
+		{"!kweli", "sikweli"},
+		{`"a" + "b"`, "ab"},
+		{"1 < 2 && 2 < 3", "kweli"},
+		{"kama (1 > 2) { 10 } sivyo { 20 }", "20"},
+		{"fanya x = 5; x += 2; x", "7"},
+		{"fanya x = 5; x++; x", "6"},
+		{"fanya x = 5; fanya y = x--; [x, y]", "[4, 5]"},
+		{"fanya a = [1, 2, 3]; a[1] = 10; a", "[1, 10, 3]"},
+		{`fanya d = {"a": 1}; d["b"] = 2; d["b"]`, "2"},
+		{"[1, 2, 3][5]", "null"},
+		{"idadi([1, 2, 3])", "3"},
+		{"2 ktk [1, 2]", "kweli"},
+		{"fanya f = unda(a, b) { rudisha a + b }; f(1, 2)", "3"},
+		{"fanya adder = unda(x) { unda(y) { x + y } }; adder(2)(3)", "5"},
+		{"fanya fibo = unda(x) { kama (x < 2) { rudisha x }; rudisha fibo(x - 1) + fibo(x - 2) }; fibo(15)", "610"},
+		{`fanya x = 2; "x ni ${x * 2}"`, "x ni 4"},
+		{"fanya s = 0; kwa i ktk mpaka(1, 4) { s += i }; s", "6"},
+		{"seti([1, 2]) | seti([2, 3])", "seti([1, 2, 3])"},
+		{"seti([1, 2]) & seti([2, 3])", "seti([2])"},
+		{`fanya d = {jozi(1, 2): "a"}; d[jozi(1, 2)]`, "a"},
+		{`(baiti("a") + baiti([98]))[1]`, "98"},
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"[9223372036854775807 / 1, 9007199254740993 / 1, -9223372036854775808 / -1]", "[9223372036854775807, 9007199254740993, 9223372036854775808]"},
+		{`desimali("0.1") + desimali("0.2")`, "0.3"},
+		{"fanya f = unda(n) { kama (n < 2) { rudisha 1 }; rudisha n * f(n - 1) }; f(25)", "15511210043330985984000000"},
+		{"fanya i = 0; fanya s = 0; fanya { i++; kama (i == 2) { endelea }; s += i } wakati (i < 4); s", "8"},
+		{"fanya i = 0; wakati (i < 10) { i++ }; i", "10"},
+		{`fanya x = 2; "x ni ${x * 2}"`, "x ni 4"},
+		{"fanya i = 0; wakati (kweli) { i++; kama (i == 5) { vunja } }; i", "5"},
+		{"fanya i = 9223372036854775807; fanya j = i++; [j, i]", "[9223372036854775807, 9223372036854775808]"},
+		{"fanya s = 0; kwa v ktk [1, 2, 3, 4] { kama (v == 2) { endelea }; s += v }; s", "8"},
+		{"fanya s = 0; kwa i, v ktk [5, 6] { s += i }; s", "1"},
+		{`fanya s = ""; kwa k, v ktk {"a": 1} { s = k }; s`, "a"},
+		{"fanya f = unda() { fanya x = 1; kwa v ktk [1, 2] { x += v }; x }; f()", "4"},
+		{"idadi(HOJA)", "0"},
+		{`fanya [a, b] = [1, 2]; fanya {c} = {"c": 3}; [a, b, c]`, "[1, 2, 3]"},
+		{"fanya f = unda(o) { fanya [x, y] = o; x - y }; f([5, 3])", "2"},
+		{"fanya f = unda(a, b) { rudisha a / b, a % b }; fanya q, r = f(7, 2); [q, r]", "[3.5, 1]"},
+		{"fanya f = unda(a, ...b) { [a, b] }; [f(1), f(1, 2, 3)]", "[[1, []], [1, [2, 3]]]"},
+		{"fanya a = [1, 2]; fanya b = [0, ...a, 3, ...[]]; b[1] = 9; [a, b]", "[[1, 2], [0, 9, 2, 3]]"},
+		{"fanya f = unda(a, b, c) { [a, b, c] }; f(...[1], 2, ...jozi(3))", "[1, 2, 3]"},
+	}
+
+	for _, tt := range tests {
+		result := testRun(t, tt.input)
+		if result == nil {
+			t.Errorf("no result for %q", tt.input)
+			continue
+		}
+		if errObj, ok := result.(*object.Error); ok {
+			t.Errorf("unexpected error for %q: %s", tt.input, errObj.Message)
+			continue
+		}
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%s, got=%s", tt.input, tt.expected, result.Inspect())
+		}
+	}
+}
+
+func TestVMErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"bangi", "Neno Halifahamiki: bangi"},
+		{"\n5 / 0", "Huwezi kugawanya kwa sifuri"},
+		{"7 \\ 0", "Huwezi kugawanya kwa sifuri"},
+		{"fanya f = unda(a, b) { a }; f(1)", "Hoja hazilingani"},
+		{"fanya f = unda(a, b, ...c) { a }; f(1)", "Hoja hazilingani, tunahitaji angalau=2, tumepewa=1"},
+		{"fanya [a, b] = [1]", "Samahani, [a, b] inahitaji vitu 2, lakini imepewa 1"},
+		{"fanya f = unda(a) { a }; f(...5)", "Samahani, ... inahitaji ORODHA, sio NAMBA"},
+		{"fanya f = unda() { f() }; f()", "Umezidi kina cha kujiita"},
+		{"fanya a = [1]; a[-2] = 0", "Index imezidi idadi ya elements"},
+		{"[1][::0]", "Samahani, hatua ya kukata haiwezi kuwa sifuri"},
+		{"[x kwa x ktk 5]", "Huwezi kufanya operesheni hii na NAMBA"},
+		{"{[x]: 1 kwa x ktk [1]}", "Hashing imeshindikana: ORODHA"},
+		{"1 < 2 < kweli", "Aina Hazilingani: NAMBA < BOOLEAN"},
+		{`kwa x ktk {"ifuatayo": unda() { tupu }} {}`, "ifuatayo haitumiki na VM bado"},
+	}
+
+	for _, tt := range tests {
+		result := testRun(t, tt.input)
+		errObj, ok := result.(*object.Error)
+		if !ok {
+			t.Errorf("expected error for %q, got=%T (%+v)", tt.input, result, result)
+			continue
+		}
+		if !strings.Contains(errObj.Message, tt.expected) {
+			t.Errorf("wrong error for %q. want=%q, got=%q", tt.input, tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestVMErrorPositions(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedLine   int
+		expectedColumn int
+	}{
+		{"fanya x = 1\nx + kweli", 2, 3},
+		{"fanya f = unda(a) {\n    rudisha a + kweli\n}\nf(1)", 2, 15},
+		{"fanya x = 1\n  andika(soma_faili(1))", 2, 20},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testRun(t, tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if errObj.Position.Line != tt.expectedLine || errObj.Position.Column != tt.expectedColumn {
+			t.Errorf("wrong position for %q. want=%d:%d, got=%d:%d", tt.input,
+				tt.expectedLine, tt.expectedColumn, errObj.Position.Line, errObj.Position.Column)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+//go:build js && wasm
+
+// Command wasm is the Nuru interpreter built for the browser, where it runs
+// code for the playground without a server. Build it with 'make build_wasm'.
+// It sets a global 'nuru' object in JavaScript with a single function:
+//
+//	nuru.endesha(msimbo) // {matokeo, kosa}
+//
+// which runs msimbo as a fresh program and returns what it printed, and the
+// error that stopped it, if any.
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"syscall/js"
+
+	"github.com/AvicennaJr/Nuru/evaluator"
+	"github.com/AvicennaJr/Nuru/lexer"
+	"github.com/AvicennaJr/Nuru/object"
+	"github.com/AvicennaJr/Nuru/parser"
+)
+
+// colours are the terminal colour codes in messages, which a page can't show
+var colours = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func main() {
+	js.Global().Set("nuru", js.ValueOf(map[string]interface{}{
+		"endesha": js.FuncOf(endesha),
+	}))
+
+	// the functions above are only callable while the program runs
+	select {}
+}
+
+func endesha(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 || args[0].Type() != js.TypeString {
+		return result("", "Tumia: nuru.endesha(msimbo)")
+	}
+
+	var out bytes.Buffer
+	evaluator.Stdout = &out
+	evaluator.Stdin = strings.NewReader("")
+
+	p := parser.New(lexer.NewFile("playground.nr", args[0].String()))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return result(out.String(), strings.Join(p.Errors(), "\n"))
+	}
+
+	evaluated := evaluator.Eval(program, object.NewEnvironment())
+	if err, ok := evaluated.(*object.Error); ok {
+		return result(out.String(), err.Inspect())
+	}
+	if evaluated != nil && evaluated.Type() != object.NULL_OBJ {
+		out.WriteString(evaluated.Inspect() + "\n")
+	}
+	return result(out.String(), "")
+}
+
+func result(output, err string) interface{} {
+	return js.ValueOf(map[string]interface{}{
+		"matokeo": colours.ReplaceAllString(output, ""),
+		"kosa":    colours.ReplaceAllString(err, ""),
+	})
+}